@@ -0,0 +1,132 @@
+package build
+
+import (
+	"crypto/sha256"
+	"encoding/json"
+	"fmt"
+	"hash"
+	"io"
+	"os"
+	"path/filepath"
+
+	"github.com/pkg/errors"
+
+	"github.com/tilt-dev/tilt/pkg/model"
+)
+
+// Computes a digest of everything that goes into building an image target:
+// the build options, the image refs, and the contents of the input files
+// (the build context for Docker builds, the deps for custom builds).
+//
+// Files that match the filter (e.g., .dockerignore and `only`) are skipped,
+// because they don't affect the build.
+//
+// If two builds have the same digest, we assume they produce the same image.
+// This isn't true for non-hermetic builds (e.g., a Dockerfile step that
+// downloads the latest version of a package), so we only use the digest to
+// skip builds when Tilt restarts, not when the user asks for a rebuild.
+func ImageTargetDigest(iTarget model.ImageTarget, filter model.PathMatcher) (string, error) {
+	h := sha256.New()
+	_, err := fmt.Fprintf(h, "local:%s\ncluster:%s\n",
+		iTarget.Refs.LocalRef().String(), iTarget.Refs.ClusterRef().String())
+	if err != nil {
+		return "", err
+	}
+
+	var paths []string
+	switch bd := iTarget.BuildDetails.(type) {
+	case model.DockerBuild:
+		err = writeJSONDigest(h, bd.DockerImageSpec)
+		paths = []string{bd.Context}
+	case model.CustomBuild:
+		err = writeJSONDigest(h, bd)
+		paths = bd.Deps
+	default:
+		return "", fmt.Errorf("image %q has no valid buildDetails (neither "+
+			"DockerBuild nor CustomBuild)", iTarget.Refs.ConfigurationRef)
+	}
+	if err != nil {
+		return "", err
+	}
+
+	for _, p := range paths {
+		err := writePathDigest(h, p, filter)
+		if err != nil {
+			return "", errors.Wrapf(err, "digesting %s", p)
+		}
+	}
+	return fmt.Sprintf("sha256:%x", h.Sum(nil)), nil
+}
+
+func writeJSONDigest(h hash.Hash, v interface{}) error {
+	data, err := json.Marshal(v)
+	if err != nil {
+		return err
+	}
+	_, err = h.Write(append(data, '\n'))
+	return err
+}
+
+// Writes the path, type, permissions, and contents of every file
+// under root (in lexical order) to the hash.
+func writePathDigest(h hash.Hash, root string, filter model.PathMatcher) error {
+	_, err := os.Lstat(root)
+	if os.IsNotExist(err) {
+		_, err := fmt.Fprintf(h, "missing:%s\n", root)
+		return err
+	}
+
+	return filepath.Walk(root, func(path string, info os.FileInfo, err error) error {
+		if err != nil {
+			return err
+		}
+
+		if path != root {
+			if info.IsDir() {
+				skip, err := filter.MatchesEntireDir(path)
+				if err != nil {
+					return err
+				}
+				if skip {
+					return filepath.SkipDir
+				}
+			}
+
+			matches, err := filter.Matches(path)
+			if err != nil {
+				return err
+			}
+			if matches {
+				return nil
+			}
+		}
+
+		_, err = fmt.Fprintf(h, "%s:%s:%d\n", path, info.Mode().Type(), info.Mode().Perm())
+		if err != nil {
+			return err
+		}
+
+		switch {
+		case info.Mode()&os.ModeSymlink != 0:
+			target, err := os.Readlink(path)
+			if err != nil {
+				return err
+			}
+			_, err = fmt.Fprintf(h, "%s\n", target)
+			return err
+		case info.Mode().IsRegular():
+			_, err = fmt.Fprintf(h, "%d\n", info.Size())
+			if err != nil {
+				return err
+			}
+			f, err := os.Open(path)
+			if err != nil {
+				return err
+			}
+			defer func() { _ = f.Close() }()
+			_, err = io.Copy(h, f)
+			return err
+		}
+		return nil
+	})
+}
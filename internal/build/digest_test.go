@@ -0,0 +1,83 @@
+package build
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/tilt-dev/tilt/internal/container"
+	"github.com/tilt-dev/tilt/internal/testutils/tempdir"
+	"github.com/tilt-dev/tilt/internal/xdg"
+	"github.com/tilt-dev/tilt/pkg/apis/core/v1alpha1"
+	"github.com/tilt-dev/tilt/pkg/model"
+)
+
+func TestImageTargetDigest(t *testing.T) {
+	f := tempdir.NewTempDirFixture(t)
+	defer f.TearDown()
+
+	f.WriteFile("src/main.go", "package main")
+	f.WriteFile("src/ignored.log", "log line 1")
+
+	iTarget := model.MustNewImageTarget(container.MustParseSelector("gcr.io/foo/bar")).
+		WithDockerImage(v1alpha1.DockerImageSpec{
+			DockerfileContents: "FROM alpine",
+			Context:            f.JoinPath("src"),
+		})
+	filter, err := model.NewSimpleFileMatcher(f.JoinPath("src", "ignored.log"))
+	require.NoError(t, err)
+
+	d1, err := ImageTargetDigest(iTarget, filter)
+	require.NoError(t, err)
+
+	d2, err := ImageTargetDigest(iTarget, filter)
+	require.NoError(t, err)
+	assert.Equal(t, d1, d2)
+
+	// Ignored files don't affect the digest.
+	f.WriteFile("src/ignored.log", "log line 2")
+	d3, err := ImageTargetDigest(iTarget, filter)
+	require.NoError(t, err)
+	assert.Equal(t, d1, d3)
+
+	// Context files do.
+	f.WriteFile("src/main.go", "package main\n\nfunc main() {}")
+	d4, err := ImageTargetDigest(iTarget, filter)
+	require.NoError(t, err)
+	assert.NotEqual(t, d1, d4)
+
+	// So do build args.
+	iTarget = iTarget.WithDockerImage(v1alpha1.DockerImageSpec{
+		DockerfileContents: "FROM alpine",
+		Context:            f.JoinPath("src"),
+		Args:               []string{"FOO=bar"},
+	})
+	d5, err := ImageTargetDigest(iTarget, filter)
+	require.NoError(t, err)
+	assert.NotEqual(t, d4, d5)
+}
+
+func TestImageDigestCache(t *testing.T) {
+	f := tempdir.NewTempDirFixture(t)
+	defer f.TearDown()
+
+	refs := container.TaggedRefs{
+		LocalRef:   container.MustParseNamedTagged("localhost:5000/foo:tilt-1234"),
+		ClusterRef: container.MustParseNamedTagged("registry:5000/foo:tilt-1234"),
+	}
+
+	cache := NewImageDigestCache(xdg.FakeBase{Dir: f.Path()})
+	_, ok := cache.Get("sha256:abc")
+	assert.False(t, ok)
+
+	require.NoError(t, cache.Put("sha256:abc", refs, time.Now()))
+
+	// The cache persists across processes.
+	cache = NewImageDigestCache(xdg.FakeBase{Dir: f.Path()})
+	actual, ok := cache.Get("sha256:abc")
+	require.True(t, ok)
+	assert.Equal(t, refs.LocalRef.String(), actual.LocalRef.String())
+	assert.Equal(t, refs.ClusterRef.String(), actual.ClusterRef.String())
+}
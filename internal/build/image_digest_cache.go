@@ -0,0 +1,132 @@
+package build
+
+import (
+	"encoding/json"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/tilt-dev/tilt/internal/container"
+	"github.com/tilt-dev/tilt/internal/xdg"
+)
+
+const imageDigestCacheFile = "build/image-digests.json"
+
+// Old entries are dropped when the cache grows past this size.
+const maxImageDigestCacheEntries = 500
+
+type imageDigestCacheEntry struct {
+	LocalRef   string    `json:"localRef"`
+	ClusterRef string    `json:"clusterRef"`
+	Time       time.Time `json:"time"`
+}
+
+// Remembers the image built from each set of build inputs (by digest),
+// so that Tilt can reuse images built in previous sessions.
+//
+// The cache is stored as a JSON file under the XDG cache directory,
+// and shared by all Tilt processes.
+type ImageDigestCache struct {
+	mu   sync.Mutex
+	path string
+}
+
+func NewImageDigestCache(base xdg.Base) *ImageDigestCache {
+	// If we can't create the cache dir, the cache is disabled.
+	path, _ := base.CacheFile(imageDigestCacheFile)
+	return &ImageDigestCache{path: path}
+}
+
+// Returns the refs of the image built from inputs with the given digest, if any.
+func (c *ImageDigestCache) Get(digest string) (container.TaggedRefs, bool) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	entry, ok := c.read()[digest]
+	if !ok {
+		return container.TaggedRefs{}, false
+	}
+
+	localRef, err := container.ParseNamedTagged(entry.LocalRef)
+	if err != nil {
+		return container.TaggedRefs{}, false
+	}
+	clusterRef, err := container.ParseNamedTagged(entry.ClusterRef)
+	if err != nil {
+		return container.TaggedRefs{}, false
+	}
+	return container.TaggedRefs{LocalRef: localRef, ClusterRef: clusterRef}, true
+}
+
+// Records the refs of the image built from inputs with the given digest.
+func (c *ImageDigestCache) Put(digest string, refs container.TaggedRefs, now time.Time) error {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	if c.path == "" {
+		return nil
+	}
+
+	entries := c.read()
+	entries[digest] = imageDigestCacheEntry{
+		LocalRef:   refs.LocalRef.String(),
+		ClusterRef: refs.ClusterRef.String(),
+		Time:       now,
+	}
+
+	if len(entries) > maxImageDigestCacheEntries {
+		digests := make([]string, 0, len(entries))
+		for d := range entries {
+			digests = append(digests, d)
+		}
+		sort.Slice(digests, func(i, j int) bool {
+			return entries[digests[i]].Time.Before(entries[digests[j]].Time)
+		})
+		for _, d := range digests[:len(entries)-maxImageDigestCacheEntries] {
+			delete(entries, d)
+		}
+	}
+
+	data, err := json.Marshal(entries)
+	if err != nil {
+		return err
+	}
+
+	// Write to a temp file and rename, so that concurrent Tilt processes
+	// never see a partially-written file.
+	tmp, err := ioutil.TempFile(filepath.Dir(c.path), filepath.Base(c.path)+".*.tmp")
+	if err != nil {
+		return err
+	}
+	_, err = tmp.Write(data)
+	closeErr := tmp.Close()
+	if err == nil {
+		err = closeErr
+	}
+	if err != nil {
+		_ = os.Remove(tmp.Name())
+		return err
+	}
+	return os.Rename(tmp.Name(), c.path)
+}
+
+// Reads the cache file. A missing or corrupt cache is treated as empty.
+func (c *ImageDigestCache) read() map[string]imageDigestCacheEntry {
+	entries := make(map[string]imageDigestCacheEntry)
+	if c.path == "" {
+		return entries
+	}
+
+	data, err := ioutil.ReadFile(c.path)
+	if err != nil {
+		return entries
+	}
+	_ = json.Unmarshal(data, &entries)
+	if entries == nil {
+		entries = make(map[string]imageDigestCacheEntry)
+	}
+	return entries
+}
@@ -13,6 +13,7 @@ The APIs of these commands may change frequently.
 	}
 
 	addCommand(result, newTiltfileResultCmd())
+	result.AddCommand(newAlphaTiltfileCmd())
 	addCommand(result, newUpdogCmd())
 	addCommand(result, newGetCmd())
 	addCommand(result, newApiresourcesCmd())
@@ -3,23 +3,37 @@ package cli
 import (
 	"context"
 	"fmt"
+	"io"
 	"log"
+	"os"
 	"time"
 
 	"github.com/fatih/color"
 	"github.com/mattn/go-colorable"
 	"github.com/spf13/cobra"
+	"k8s.io/apimachinery/pkg/api/equality"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 
 	"github.com/tilt-dev/tilt/internal/analytics"
+	"github.com/tilt-dev/tilt/internal/engine/session"
 	"github.com/tilt-dev/tilt/internal/hud/prompt"
 	"github.com/tilt-dev/tilt/internal/store"
+	"github.com/tilt-dev/tilt/pkg/apis/core/v1alpha1"
 	"github.com/tilt-dev/tilt/pkg/logger"
 	"github.com/tilt-dev/tilt/pkg/model"
 )
 
+var ciTimeoutFlag time.Duration
+var ciResourcesFlag []string
+var ciLabelsFlag []string
+var ciTestsOnlyFlag bool
+var ciMaxFailuresFlag int32
+
 type ciCmd struct {
 	fileName             string
 	outputSnapshotOnExit string
+	outputJSON           string
+	outputJUnit          string
 }
 
 func (c *ciCmd) name() model.TiltSubcommand { return "ci" }
@@ -48,11 +62,27 @@ See blog post for additional information: https://blog.tilt.dev/2020/04/16/how-t
 	addDevServerFlags(cmd)
 	addTiltfileFlag(cmd, &c.fileName)
 	addKubeContextFlag(cmd)
+	addLogHistoryFlags(cmd)
 
 	cmd.Flags().BoolVar(&logActionsFlag, "logactions", false, "log all actions and state changes")
 	cmd.Flags().Lookup("logactions").Hidden = true
 	cmd.Flags().StringVar(&c.outputSnapshotOnExit, "output-snapshot-on-exit", "",
 		"If specified, Tilt will dump a snapshot of its state to the specified path when it exits")
+	cmd.Flags().StringVar(&c.outputJSON, "output-json", "",
+		"If specified, Tilt will write a JSON summary of the state of each target to the specified path when it exits")
+	cmd.Flags().StringVar(&c.outputJUnit, "output-junit", "",
+		"If specified, Tilt will write a JUnit XML summary of the state of each target to the specified path when it exits")
+
+	cmd.Flags().DurationVar(&ciTimeoutFlag, "timeout", 0,
+		"If non-zero, fail if the session hasn't finished within this duration (e.g., 30m)")
+	cmd.Flags().StringSliceVar(&ciResourcesFlag, "wait-for-resource", nil,
+		"Only wait for these resources to become healthy. May be repeated.")
+	cmd.Flags().StringSliceVar(&ciLabelsFlag, "wait-for-label", nil,
+		"Only wait for resources with one of these labels to become healthy. May be repeated.")
+	cmd.Flags().BoolVar(&ciTestsOnlyFlag, "tests-only", false,
+		"Only wait for test() resources to complete")
+	cmd.Flags().Int32Var(&ciMaxFailuresFlag, "max-failures", 0,
+		"The number of resource failures to tolerate. Each tolerated failure retries the resource.")
 
 	return cmd
 }
@@ -92,6 +122,10 @@ func (c *ciCmd) run(ctx context.Context, args []string) error {
 		defer cmdCIDeps.Snapshotter.WriteSnapshot(ctx, c.outputSnapshotOnExit)
 	}
 
+	if c.outputJSON != "" || c.outputJUnit != "" {
+		defer c.writeReports(ctx, cmdCIDeps.SessionController)
+	}
+
 	err = upper.Start(ctx, args, cmdCIDeps.TiltBuild,
 		c.fileName, store.TerminalModeStream, a.UserOpt(), cmdCIDeps.Token,
 		string(cmdCIDeps.CloudAddress))
@@ -101,3 +135,45 @@ func (c *ciCmd) run(ctx context.Context, args []string) error {
 	}
 	return err
 }
+
+func (c *ciCmd) writeReports(ctx context.Context, sc *session.Controller) {
+	s := sc.Session()
+	if s == nil {
+		logger.Get(ctx).Infof("No session summary to write: Tilt exited before the session started")
+		return
+	}
+
+	writeReport := func(path string, write func(w io.Writer, s *v1alpha1.Session) error) {
+		if path == "" {
+			return
+		}
+		f, err := os.Create(path)
+		if err != nil {
+			logger.Get(ctx).Infof("Error writing session summary: %v", err)
+			return
+		}
+		defer func() { _ = f.Close() }()
+
+		err = write(f, s)
+		if err != nil {
+			logger.Get(ctx).Infof("Error writing session summary: %v", err)
+		}
+	}
+
+	writeReport(c.outputJSON, session.WriteJSONReport)
+	writeReport(c.outputJUnit, session.WriteJUnitReport)
+}
+
+func provideSessionCISpec() *v1alpha1.SessionCISpec {
+	spec := &v1alpha1.SessionCISpec{
+		Timeout:     metav1.Duration{Duration: ciTimeoutFlag},
+		Resources:   ciResourcesFlag,
+		Labels:      ciLabelsFlag,
+		TestsOnly:   ciTestsOnlyFlag,
+		MaxFailures: ciMaxFailuresFlag,
+	}
+	if equality.Semantic.DeepEqual(spec, &v1alpha1.SessionCISpec{}) {
+		return nil
+	}
+	return spec
+}
@@ -70,12 +70,14 @@ up-to-date in real-time. Think 'docker build && kubectl apply' or 'docker-compos
 	addCommand(rootCmd, newApplyCmd())
 	addCommand(rootCmd, newCreateCmd())
 	addCommand(rootCmd, newPatchCmd())
+	addCommand(rootCmd, newWaitCmd())
+	addCommand(rootCmd, newTriggerCmd())
 	addCommand(rootCmd, &demoCmd{})
 
 	rootCmd.AddCommand(analytics.NewCommand())
 	rootCmd.AddCommand(newDumpCmd(rootCmd))
-	rootCmd.AddCommand(newTriggerCmd())
 	rootCmd.AddCommand(newAlphaCmd())
+	rootCmd.AddCommand(newExtCmd())
 
 	globalFlags := rootCmd.PersistentFlags()
 	globalFlags.BoolVarP(&debug, "debug", "d", false, "Enable debug logging")
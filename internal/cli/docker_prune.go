@@ -22,12 +22,14 @@ type dockerPruneCmd struct {
 
 type dpDeps struct {
 	dCli docker.Client
+	rCli dockerprune.RegistryClient
 	tfl  tiltfile.TiltfileLoader
 }
 
-func newDPDeps(dCli docker.Client, tfl tiltfile.TiltfileLoader) dpDeps {
+func newDPDeps(dCli docker.Client, rCli dockerprune.RegistryClient, tfl tiltfile.TiltfileLoader) dpDeps {
 	return dpDeps{
 		dCli: dCli,
+		rCli: rCli,
 		tfl:  tfl,
 	}
 }
@@ -66,10 +68,16 @@ func (c *dockerPruneCmd) run(ctx context.Context, args []string) error {
 
 	imgSelectors := model.LocalRefSelectorsForManifests(tlr.Manifests)
 
-	dp := dockerprune.NewDockerPruner(deps.dCli)
+	dp := dockerprune.NewDockerPruner(deps.dCli, deps.rCli)
 
 	// TODO: print the commands being run
 	dp.Prune(ctx, tlr.DockerPruneSettings.MaxAge, tlr.DockerPruneSettings.KeepRecent, imgSelectors)
 
+	if tlr.DockerPruneSettings.Registry {
+		// Outside of `tilt up`, we don't know which images are deployed,
+		// so we can't tell which images are safe to delete from the registry.
+		l.Infof("[Docker Prune] skipping the registry: only `tilt up` prunes it, because it knows which images are deployed")
+	}
+
 	return nil
 }
@@ -13,6 +13,7 @@ import (
 	ctrltiltfile "github.com/tilt-dev/tilt/internal/controllers/apis/tiltfile"
 	"github.com/tilt-dev/tilt/internal/k8s"
 	"github.com/tilt-dev/tilt/internal/localexec"
+	"github.com/tilt-dev/tilt/pkg/apis/core/v1alpha1"
 	"github.com/tilt-dev/tilt/pkg/logger"
 	"github.com/tilt-dev/tilt/pkg/model"
 )
@@ -99,7 +100,7 @@ func (c *downCmd) down(ctx context.Context, downDeps DownDeps, args []string) er
 }
 
 func deleteK8sEntities(ctx context.Context, manifests []model.Manifest, downDeps DownDeps, deleteNamespaces bool) error {
-	entities, deleteCmds, err := k8sToDelete(manifests...)
+	entities, deleteCmds, helmReleases, err := k8sToDelete(manifests...)
 	if err != nil {
 		return errors.Wrap(err, "Parsing manifest YAML")
 	}
@@ -139,6 +140,14 @@ func deleteK8sEntities(ctx context.Context, manifests []model.Manifest, downDeps
 		}
 	}
 
+	for _, release := range helmReleases {
+		logger.Get(ctx).Infof("Uninstalling Helm release %s", release.ReleaseName)
+		err := downDeps.kClient.UninstallHelmRelease(ctx, release, v1alpha1.KubernetesApplyTimeoutDefault)
+		if err != nil {
+			return errors.Wrapf(err, "Uninstalling Helm release %s", release.ReleaseName)
+		}
+	}
+
 	for i := range deleteCmds {
 		if err := localexec.OneShotToLogger(ctx, downDeps.execer, deleteCmds[i]); err != nil {
 			return errors.Wrapf(err, "Deleting k8s entities for cmd: %s", deleteCmds[i].String())
@@ -148,9 +157,10 @@ func deleteK8sEntities(ctx context.Context, manifests []model.Manifest, downDeps
 	return nil
 }
 
-func k8sToDelete(manifests ...model.Manifest) ([]k8s.K8sEntity, []model.Cmd, error) {
+func k8sToDelete(manifests ...model.Manifest) ([]k8s.K8sEntity, []model.Cmd, []v1alpha1.KubernetesHelmRelease, error) {
 	var allEntities []k8s.K8sEntity
 	var deleteCmds []model.Cmd
+	var helmReleases []v1alpha1.KubernetesHelmRelease
 	for _, m := range manifests {
 		if !m.IsK8s() {
 			continue
@@ -163,13 +173,15 @@ func k8sToDelete(manifests ...model.Manifest) ([]k8s.K8sEntity, []model.Cmd, err
 				Dir:  kt.DeleteCmd.Dir,
 				Env:  kt.DeleteCmd.Env,
 			})
+		} else if kt.HelmRelease != nil {
+			helmReleases = append(helmReleases, *kt.HelmRelease)
 		} else {
 			entities, err := k8s.ParseYAMLFromString(kt.YAML)
 			if err != nil {
-				return nil, nil, err
+				return nil, nil, nil, err
 			}
 			allEntities = append(allEntities, entities...)
 		}
 	}
-	return allEntities, deleteCmds, nil
+	return allEntities, deleteCmds, helmReleases, nil
 }
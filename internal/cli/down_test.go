@@ -124,6 +124,28 @@ func TestDownK8sDeleteCmd(t *testing.T) {
 	}
 }
 
+func TestDownK8sHelmRelease(t *testing.T) {
+	f := newDownFixture(t)
+	defer f.TearDown()
+
+	kaSpec := v1alpha1.KubernetesApplySpec{
+		HelmRelease: &v1alpha1.KubernetesHelmRelease{ReleaseName: "fe", Chart: "/path/to/chart"},
+	}
+
+	kt, err := k8s.NewTarget("fe", kaSpec, model.PodReadinessIgnore, nil)
+	require.NoError(t, err, "Failed to make KubernetesTarget")
+	m := model.Manifest{Name: "fe"}.WithDeployTarget(kt)
+
+	f.tfl.Result = tiltfile.TiltfileLoadResult{Manifests: []model.Manifest{m}}
+	err = f.cmd.down(f.ctx, f.deps, nil)
+	require.NoError(t, err)
+
+	if assert.Len(t, f.kCli.UninstalledHelmReleases, 1) {
+		assert.Equal(t, "fe", f.kCli.UninstalledHelmReleases[0].ReleaseName)
+	}
+	assert.Empty(t, f.kCli.DeletedYaml)
+}
+
 func TestDownK8sDeleteCmd_Error(t *testing.T) {
 	f := newDownFixture(t)
 	defer f.TearDown()
@@ -161,6 +183,20 @@ func TestDownDCFails(t *testing.T) {
 	}
 }
 
+func TestDownK8sAndDC(t *testing.T) {
+	f := newDownFixture(t)
+	defer f.TearDown()
+
+	manifests := append(newK8sManifest(), newDCManifest()...)
+	f.tfl.Result = tiltfile.TiltfileLoadResult{Manifests: manifests}
+	f.dcc.DownError = fmt.Errorf("GARBLEGARBLE")
+	err := f.cmd.down(f.ctx, f.deps, nil)
+	if assert.Error(t, err) {
+		assert.Contains(t, err.Error(), "GARBLEGARBLE")
+	}
+	assert.Contains(t, f.kCli.DeletedYaml, "sancho")
+}
+
 func TestDownArgs(t *testing.T) {
 	f := newDownFixture(t)
 	defer f.TearDown()
@@ -0,0 +1,333 @@
+package cli
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"path/filepath"
+	"time"
+
+	"github.com/pkg/errors"
+	"github.com/spf13/cobra"
+
+	"github.com/tilt-dev/tilt/internal/analytics"
+	ctrltiltfile "github.com/tilt-dev/tilt/internal/controllers/apis/tiltfile"
+	"github.com/tilt-dev/tilt/internal/controllers/apiset"
+	"github.com/tilt-dev/tilt/internal/controllers/core/extensionrepo"
+	engineanalytics "github.com/tilt-dev/tilt/internal/engine/analytics"
+	"github.com/tilt-dev/tilt/internal/tiltfile"
+	"github.com/tilt-dev/tilt/internal/tiltfile/tiltextension"
+	"github.com/tilt-dev/tilt/internal/xdg"
+	"github.com/tilt-dev/tilt/pkg/apis/core/v1alpha1"
+	"github.com/tilt-dev/tilt/pkg/model"
+)
+
+func newExtCmd() *cobra.Command {
+	result := &cobra.Command{
+		Use:   "ext",
+		Short: "Manage the extensions locked in tilt_modules/extensions.lock",
+		Long: `Manage the extensions locked in tilt_modules/extensions.lock.
+
+The lock file records the commit and a content hash of every extension
+your Tiltfile loads. It lives in tilt_modules, next to your Tiltfile.
+Check it in, and Tilt will refuse to load an extension whose contents don't match it.
+
+Tilt never writes the lock file on its own. Run tilt ext vendor to lock
+new extensions, and tilt ext update to upgrade them.
+`,
+	}
+
+	addCommand(result, newExtVendorCmd())
+	addCommand(result, newExtUpdateCmd())
+
+	return result
+}
+
+// Resolves the tilt_modules store next to the given Tiltfile.
+func extStore(fileName string) (*tiltextension.LocalStore, error) {
+	absPath, err := filepath.Abs(fileName)
+	if err != nil {
+		return nil, err
+	}
+	return tiltextension.NewLocalStore(filepath.Dir(absPath)), nil
+}
+
+func newExtFetcher() (*tiltextension.GithubFetcher, error) {
+	dlr, err := tiltextension.NewTempDirDownloader()
+	if err != nil {
+		return nil, err
+	}
+	return tiltextension.NewGithubFetcher(dlr), nil
+}
+
+type extVendorCmd struct {
+	fileName string
+}
+
+var _ tiltCmd = &extVendorCmd{}
+
+func newExtVendorCmd() *extVendorCmd {
+	return &extVendorCmd{}
+}
+
+func (c *extVendorCmd) name() model.TiltSubcommand { return "ext" }
+
+func (c *extVendorCmd) register() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "vendor",
+		Short: "Fetch every locked extension into tilt_modules, and lock new ones",
+		Long: `Fetch every ext:// extension in tilt_modules/extensions.lock
+into tilt_modules, at its locked commit. Check out every locked
+extension repo into ~/.tilt-dev/tilt_modules, at its locked commit.
+
+Then load the Tiltfile, and lock every extension and extension repo
+that it uses but that isn't in the lock file yet, at its latest version.
+
+Once vendored, the Tiltfile loads its extensions without network access.
+Extensions that are already present and match the lock are left alone.
+`,
+		Args: cobra.NoArgs,
+	}
+
+	addTiltfileFlag(cmd, &c.fileName)
+	return cmd
+}
+
+func (c *extVendorCmd) run(ctx context.Context, args []string) error {
+	a := analytics.Get(ctx)
+	cmdTags := engineanalytics.CmdTags(map[string]string{})
+	a.Incr("cmd.ext-vendor", cmdTags.AsMap())
+	defer a.Flush(time.Second)
+
+	store, err := extStore(c.fileName)
+	if err != nil {
+		return err
+	}
+	fetcher, err := newExtFetcher()
+	if err != nil {
+		return err
+	}
+
+	fetched, err := tiltextension.Vendor(ctx, store, fetcher)
+	for _, name := range fetched {
+		fmt.Printf("Vendored extension %s\n", name)
+	}
+	if err != nil {
+		return err
+	}
+
+	fetchedRepos, err := vendorRepos(store)
+	if err != nil {
+		return err
+	}
+
+	tlr, err := loadTiltfileForExt(ctx, a, c.fileName)
+	if err != nil {
+		return err
+	}
+	if tlr.Error != nil {
+		return fmt.Errorf("Locking new extensions: Tiltfile failed to load: %v", tlr.Error)
+	}
+
+	// Vendor cleans up the fetcher's checkout, so start a new one.
+	fetcher, err = newExtFetcher()
+	if err != nil {
+		return err
+	}
+	locked, err := tiltextension.LockNew(ctx, store, fetcher, tlr.Extensions)
+	for _, l := range locked {
+		fmt.Printf("Locked extension %s at %s\n", l.Name, l.CheckoutRef)
+	}
+	if err != nil {
+		return err
+	}
+
+	lockedRepos, err := lockRepos(store, tlr, false, nil)
+	if err != nil {
+		return err
+	}
+	if len(lockedRepos) > 0 {
+		_, err := vendorRepos(store)
+		if err != nil {
+			return err
+		}
+	}
+
+	if len(fetched) == 0 && len(fetchedRepos) == 0 && len(locked) == 0 && len(lockedRepos) == 0 {
+		fmt.Println("All extensions are locked and up to date")
+	}
+	return nil
+}
+
+type extUpdateCmd struct {
+	fileName string
+}
+
+var _ tiltCmd = &extUpdateCmd{}
+
+func newExtUpdateCmd() *extUpdateCmd {
+	return &extUpdateCmd{}
+}
+
+func (c *extUpdateCmd) name() model.TiltSubcommand { return "ext" }
+
+func (c *extUpdateCmd) register() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "update [NAME...]",
+		Short: "Upgrade extensions to their latest version and lock them",
+		Long: `Upgrade extensions to their latest version, and record them
+in tilt_modules/extensions.lock.
+
+NAME can be an ext:// extension, or an Extension or ExtensionRepo
+that the Tiltfile registers. If no names are given, updates every
+extension and extension repo that the Tiltfile uses, or that is locked.
+`,
+		Example: `
+# Upgrade every extension
+tilt ext update
+
+# Upgrade the restart_process extension
+tilt ext update restart_process
+`,
+	}
+
+	addTiltfileFlag(cmd, &c.fileName)
+	return cmd
+}
+
+func (c *extUpdateCmd) run(ctx context.Context, args []string) error {
+	a := analytics.Get(ctx)
+	cmdTags := engineanalytics.CmdTags(map[string]string{})
+	a.Incr("cmd.ext-update", cmdTags.AsMap())
+	defer a.Flush(time.Second)
+
+	store, err := extStore(c.fileName)
+	if err != nil {
+		return err
+	}
+
+	// The Tiltfile may fail to load because an extension doesn't match the lock,
+	// which is what we're here to fix. So we lock whatever it got to.
+	tlr, err := loadTiltfileForExt(ctx, a, c.fileName)
+	if err != nil {
+		return err
+	}
+	if tlr.Error != nil {
+		_, _ = fmt.Fprintf(os.Stderr, "Warning: Tiltfile failed to load, so some extensions may not be updated: %v\n", tlr.Error)
+	}
+
+	// Split the names into API objects and ext:// extensions.
+	var extNames []string
+	for _, name := range args {
+		if !isExtensionObject(tlr.ObjectSet, name) {
+			extNames = append(extNames, name)
+		}
+	}
+
+	// With no names, update the ext:// extensions that are locked,
+	// and the ones that the Tiltfile loaded.
+	if len(args) == 0 {
+		lock, err := tiltextension.ReadLockFile(store.LockFilePath())
+		if err != nil {
+			return err
+		}
+		seen := map[string]bool{}
+		for _, locked := range lock.Extensions {
+			if locked.RepoName == "" && !seen[locked.Name] {
+				seen[locked.Name] = true
+				extNames = append(extNames, locked.Name)
+			}
+		}
+		for _, name := range tlr.Extensions {
+			if !seen[name] {
+				seen[name] = true
+				extNames = append(extNames, name)
+			}
+		}
+	}
+
+	if len(extNames) > 0 {
+		fetcher, err := newExtFetcher()
+		if err != nil {
+			return err
+		}
+		updated, err := tiltextension.Update(ctx, store, fetcher, extNames)
+		for _, locked := range updated {
+			fmt.Printf("Locked extension %s at %s\n", locked.Name, locked.CheckoutRef)
+		}
+		if err != nil {
+			return err
+		}
+	}
+
+	_, err = lockRepos(store, tlr, true, args)
+	if err != nil {
+		return err
+	}
+	fmt.Println("The new versions take effect the next time the Tiltfile loads")
+	return nil
+}
+
+// Checks out every locked extension repo where the ExtensionRepo controller
+// looks for it, so that the Tiltfile loads them without network access.
+func vendorRepos(store *tiltextension.LocalStore) ([]string, error) {
+	dir, err := xdg.NewTiltDevBase().DataFile(extensionrepo.TiltModulesRelDir)
+	if err != nil {
+		return nil, err
+	}
+
+	fetched, err := tiltextension.VendorRepos(tiltextension.NewDirDownloader(dir), store.LockFilePath())
+	for _, name := range fetched {
+		fmt.Printf("Vendored extension repo %s\n", name)
+	}
+	return fetched, err
+}
+
+// Loads the Tiltfile, to find the extensions that it uses.
+func loadTiltfileForExt(ctx context.Context, a *analytics.TiltAnalytics, fileName string) (tiltfile.TiltfileLoadResult, error) {
+	deps, err := wireTiltfileResult(ctx, a, "ext")
+	if err != nil {
+		return tiltfile.TiltfileLoadResult{}, errors.Wrap(err, "wiring dependencies")
+	}
+	return deps.tfl.Load(ctx, ctrltiltfile.MainTiltfile(fileName, nil)), nil
+}
+
+// Whether the name is an ExtensionRepo or Extension that the Tiltfile registers.
+func isExtensionObject(set apiset.ObjectSet, name string) bool {
+	_, isRepo := set.GetSetForType(&v1alpha1.ExtensionRepo{})[name]
+	_, isExt := set.GetSetForType(&v1alpha1.Extension{})[name]
+	return isRepo || isExt
+}
+
+// Locks the Tiltfile's extension repos, and writes the lock file if any changed.
+func lockRepos(store *tiltextension.LocalStore, tlr tiltfile.TiltfileLoadResult, upgrade bool, names []string) ([]tiltextension.LockedRepo, error) {
+	if len(tlr.ObjectSet.GetSetForType(&v1alpha1.ExtensionRepo{})) == 0 {
+		return nil, nil
+	}
+
+	lockPath := store.LockFilePath()
+	lock, err := tiltextension.ReadLockFile(lockPath)
+	if err != nil {
+		return nil, err
+	}
+
+	dlr, err := tiltextension.NewTempDirDownloader()
+	if err != nil {
+		return nil, err
+	}
+	defer func() {
+		_ = os.RemoveAll(dlr.RootDir())
+	}()
+
+	locked, err := tiltextension.LockRepos(dlr, tlr.ObjectSet, &lock, upgrade, names)
+	if err != nil {
+		return nil, err
+	}
+	for _, repo := range locked {
+		fmt.Printf("Locked extension repo %s at %s\n", repo.Name, repo.CheckoutRef)
+	}
+	if len(locked) == 0 {
+		return nil, nil
+	}
+	return locked, tiltextension.WriteLockFile(lockPath, lock)
+}
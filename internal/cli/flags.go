@@ -18,6 +18,9 @@ var defaultNamespace = ""
 var webHostFlag = ""
 var webPortFlag = 0
 var namespaceOverride = ""
+var logHistoryDirFlag = ""
+var logHistoryMaxBytesFlag int64 = 100 * 1000 * 1000
+var logHistoryMaxDaysFlag = 7
 
 func readEnvDefaults() error {
 	envPort := os.Getenv("TILT_PORT")
@@ -77,3 +80,10 @@ func ProvideKubeContextOverride() k8s.KubeContextOverride {
 func ProvideNamespaceOverride() k8s.NamespaceOverride {
 	return k8s.NamespaceOverride(namespaceOverride)
 }
+
+// For commands that run the engine and keep logs.
+func addLogHistoryFlags(cmd *cobra.Command) {
+	cmd.Flags().StringVar(&logHistoryDirFlag, "log-history-dir", "", "If specified, logs that are truncated from memory are archived to this directory, so that they can still be searched with 'tilt logs' and the web UI")
+	cmd.Flags().Int64Var(&logHistoryMaxBytesFlag, "log-history-max-bytes", logHistoryMaxBytesFlag, "Maximum number of bytes of archived logs to keep in --log-history-dir. Set to 0 for no limit.")
+	cmd.Flags().IntVar(&logHistoryMaxDaysFlag, "log-history-max-days", logHistoryMaxDaysFlag, "Maximum number of days of archived logs to keep in --log-history-dir. Set to 0 for no limit.")
+}
@@ -2,19 +2,31 @@ package cli
 
 import (
 	"context"
+	"fmt"
 	"log"
+	"regexp"
 	"time"
 
 	"github.com/spf13/cobra"
 
+	"github.com/tilt-dev/tilt/internal/hud"
 	"github.com/tilt-dev/tilt/internal/hud/server"
+	"github.com/tilt-dev/tilt/pkg/logger"
 	"github.com/tilt-dev/tilt/pkg/model"
+	"github.com/tilt-dev/tilt/pkg/model/logstore"
 
 	"github.com/tilt-dev/tilt/internal/analytics"
 )
 
 type logsCmd struct {
-	follow bool // if true, follow logs (otherwise print current logs and exit)
+	follow  bool          // if true, follow logs (otherwise print current logs and exit)
+	since   time.Duration // if set, only print logs newer than this
+	tail    int           // if non-negative, only print this many lines of existing logs
+	level   string
+	source  string
+	grep    string
+	json    bool
+	history bool
 }
 
 func (c *logsCmd) name() model.TiltSubcommand { return "logs" }
@@ -29,11 +41,25 @@ func (c *logsCmd) register() *cobra.Command {
 By default, looks for a running Tilt instance on localhost:10350
 (this is configurable with the --port and --host flags).
 `,
+		Example: `  # Print the last 100 lines of errors and warnings from the frontend build
+  tilt logs frontend --source=build --level=warn --tail=100
+
+  # Search all the logs from the last day, including logs archived to disk
+  tilt logs --history --since=24h --grep='connection refused'
+
+  # Stream the last 5 minutes of logs that mention a request ID, as JSON
+  tilt logs -f --since=5m --grep='req-[0-9a-f]+' --json | jq .text`,
 	}
 
 	cmd.Flags().BoolVarP(&c.follow, "follow", "f", false, "If true, stream the requested logs; otherwise, print the requested logs at the current moment in time, then exit.")
+	cmd.Flags().DurationVar(&c.since, "since", 0, "Only print logs newer than a relative duration like 5s, 2m, or 3h. Defaults to all logs.")
+	cmd.Flags().IntVar(&c.tail, "tail", -1, "Lines of existing logs to print. Defaults to -1, which prints all lines. With --follow, new logs are always printed.")
+	cmd.Flags().StringVar(&c.level, "level", "", "Only print logs at least this severe (one of: warn, error)")
+	cmd.Flags().StringVar(&c.source, "source", "", "Only print logs from this source (one of: build, runtime)")
+	cmd.Flags().StringVar(&c.grep, "grep", "", "Only print lines that match this regular expression")
+	cmd.Flags().BoolVar(&c.json, "json", false, "Print each line as a JSON object with the time, resource, span, level, and text")
+	cmd.Flags().BoolVar(&c.history, "history", false, "Also print logs that Tilt has truncated from memory and archived to disk (with 'tilt up --log-history-dir'). Can't be used with --follow.")
 
-	// TODO: log level flags
 	addConnectServerFlags(cmd)
 	return cmd
 }
@@ -41,17 +67,80 @@ By default, looks for a running Tilt instance on localhost:10350
 func (c *logsCmd) run(ctx context.Context, args []string) error {
 	a := analytics.Get(ctx)
 
-	a.Incr("cmd.logs", nil)
+	a.Incr("cmd.logs", map[string]string{
+		"follow": fmt.Sprintf("%t", c.follow),
+		"json":   fmt.Sprintf("%t", c.json),
+	})
 	defer a.Flush(time.Second)
 
 	if ok, reason := analytics.IsAnalyticsDisabledFromEnv(); ok {
 		log.Printf("Tilt analytics disabled: %s", reason)
 	}
 
+	options, err := c.streamOptions(args, time.Now())
+	if err != nil {
+		return err
+	}
+
 	logDeps, err := wireLogsDeps(ctx, a, "logs")
 	if err != nil {
 		return err
 	}
 
-	return server.StreamLogs(ctx, c.follow, logDeps.url, args, logDeps.printer)
+	var printer server.LogPrinter = logDeps.printer
+	if c.json {
+		printer = hud.NewJSONPrinter(logDeps.stdout)
+	}
+	return server.StreamLogs(ctx, c.follow, logDeps.url, options, printer)
+}
+
+// Converts the command-line flags to options for the log streamer.
+func (c *logsCmd) streamOptions(resources []string, now time.Time) (server.LogStreamOptions, error) {
+	if c.history && c.follow {
+		return server.LogStreamOptions{}, fmt.Errorf("--history can't be used with --follow")
+	}
+
+	q := logstore.Query{History: c.history}
+	if len(resources) > 0 {
+		q.ManifestNames = make(model.ManifestNameSet, len(resources))
+		for _, r := range resources {
+			q.ManifestNames[model.ManifestName(r)] = true
+		}
+	}
+
+	if c.since < 0 {
+		return server.LogStreamOptions{}, fmt.Errorf("--since must be positive")
+	}
+	if c.since > 0 {
+		q.Since = now.Add(-c.since)
+	}
+
+	switch c.level {
+	case "":
+	case "warn", "warning":
+		q.Level = logger.WarnLvl
+	case "error":
+		q.Level = logger.ErrorLvl
+	default:
+		return server.LogStreamOptions{}, fmt.Errorf("invalid --level %q (must be one of: warn, error)", c.level)
+	}
+
+	var err error
+	q.Source, err = logstore.ParseSource(c.source)
+	if err != nil {
+		return server.LogStreamOptions{}, err
+	}
+
+	if c.grep != "" {
+		q.Regexp, err = regexp.Compile(c.grep)
+		if err != nil {
+			return server.LogStreamOptions{}, fmt.Errorf("invalid --grep: %v", err)
+		}
+	}
+
+	return server.LogStreamOptions{
+		Query:          q,
+		Tail:           c.tail,
+		SuppressPrefix: c.json,
+	}, nil
 }
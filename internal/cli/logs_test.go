@@ -0,0 +1,55 @@
+package cli
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/tilt-dev/tilt/pkg/logger"
+	"github.com/tilt-dev/tilt/pkg/model"
+	"github.com/tilt-dev/tilt/pkg/model/logstore"
+)
+
+func TestLogsStreamOptions(t *testing.T) {
+	now := time.Date(2021, time.May, 1, 12, 0, 0, 0, time.UTC)
+	cmd := logsCmd{}
+	cmd.register()
+	cmd.since = 5 * time.Minute
+	cmd.level = "warn"
+	cmd.source = "build"
+	cmd.grep = "req-[0-9]+"
+	cmd.json = true
+
+	options, err := cmd.streamOptions([]string{"fe"}, now)
+	require.NoError(t, err)
+
+	q := options.Query
+	assert.Equal(t, model.ManifestNameSet{"fe": true}, q.ManifestNames)
+	assert.Equal(t, now.Add(-5*time.Minute), q.Since)
+	assert.Equal(t, logger.WarnLvl, q.Level)
+	assert.Equal(t, logstore.SourceBuild, q.Source)
+	assert.Equal(t, "req-[0-9]+", q.Regexp.String())
+	assert.Equal(t, -1, options.Tail)
+	assert.True(t, options.SuppressPrefix)
+}
+
+func TestLogsStreamOptionsInvalid(t *testing.T) {
+	for _, tc := range []struct {
+		name     string
+		cmd      logsCmd
+		expected string
+	}{
+		{"level", logsCmd{level: "info"}, "invalid --level"},
+		{"source", logsCmd{source: "pod"}, "invalid log source"},
+		{"grep", logsCmd{grep: "("}, "invalid --grep"},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			_, err := tc.cmd.streamOptions(nil, time.Now())
+			if assert.Error(t, err) {
+				assert.Contains(t, err.Error(), tc.expected)
+			}
+		})
+	}
+}
@@ -0,0 +1,157 @@
+package cli
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"path/filepath"
+	"time"
+
+	"github.com/pkg/errors"
+	"github.com/spf13/cobra"
+	"k8s.io/cli-runtime/pkg/genericclioptions"
+
+	"github.com/tilt-dev/tilt/internal/analytics"
+	engineanalytics "github.com/tilt-dev/tilt/internal/engine/analytics"
+	"github.com/tilt-dev/tilt/internal/tiltfile"
+	"github.com/tilt-dev/tilt/pkg/logger"
+	"github.com/tilt-dev/tilt/pkg/model"
+)
+
+func newAlphaTiltfileCmd() *cobra.Command {
+	result := &cobra.Command{
+		Use:   "tiltfile",
+		Short: "Work with Tiltfiles",
+	}
+
+	addCommand(result, newTiltfileTestCmd())
+
+	return result
+}
+
+type tiltfileTestCmd struct {
+	streams genericclioptions.IOStreams
+	update  bool
+}
+
+var _ tiltCmd = &tiltfileTestCmd{}
+
+func newTiltfileTestCmd() *tiltfileTestCmd {
+	return &tiltfileTestCmd{
+		streams: genericclioptions.IOStreams{Out: os.Stdout, ErrOut: os.Stderr, In: os.Stdin},
+	}
+}
+
+func (c *tiltfileTestCmd) name() model.TiltSubcommand { return "tiltfile-test" }
+
+func (c *tiltfileTestCmd) register() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "test [PATH...]",
+		Short: "Run Tiltfile unit tests",
+		Long: `Run Tiltfile unit tests.
+
+A unit test is a Tiltfile that ends in _test.tilt. It usually loads the Tiltfile
+under test, then checks the result with the assertion builtins:
+
+  assert.equals(expected, actual, msg='')
+  assert.contains(container, item, msg='')
+  assert.fails(fn, error='', msg='')
+
+Tests never deploy anything, and never run local() or helm(). Fake their
+results with:
+
+  fake.local(command, stdout='', error='')
+  fake.helm(paths, yaml)
+
+Other builtins behave the way they do in tilt up. In particular, kustomize()
+fetches remote bases, docker_compose() runs docker-compose config, and
+load('ext://...') fetches extensions.
+
+If a test has a golden file next to it (foo_test.tilt => foo_test.golden),
+the manifests, image targets, and Kubernetes YAML that the test loads
+must match it. Run with --update to (re)write the golden files.
+
+PATH can be a test file, or a directory to search for tests. Defaults to the current directory.`,
+		Example: `  # Run every test under the current directory
+  tilt alpha tiltfile test
+
+  # Regenerate the golden file of one test
+  tilt alpha tiltfile test --update ./deploy/frontend_test.tilt`,
+	}
+
+	cmd.Flags().BoolVar(&c.update, "update", false, "Write the results of the tests to their golden files, instead of comparing them")
+	addKubeContextFlag(cmd)
+
+	return cmd
+}
+
+func (c *tiltfileTestCmd) run(ctx context.Context, args []string) error {
+	a := analytics.Get(ctx)
+	cmdTags := engineanalytics.CmdTags(map[string]string{
+		"update": fmt.Sprintf("%t", c.update),
+	})
+	a.Incr("cmd.tiltfile-test", cmdTags.AsMap())
+	defer a.Flush(time.Second)
+
+	paths := args
+	if len(paths) == 0 {
+		paths = []string{"."}
+	}
+	tests, err := tiltfile.FindUnitTests(paths)
+	if err != nil {
+		return err
+	}
+	if len(tests) == 0 {
+		return fmt.Errorf("no Tiltfile tests found (tests end in %s)", tiltfile.UnitTestSuffix)
+	}
+
+	deps, err := wireTiltfileResult(ctx, a, "alpha tiltfile test")
+	if err != nil {
+		return errors.Wrap(err, "wiring dependencies")
+	}
+
+	failed := 0
+	for _, test := range tests {
+		// Only show the test's logs if it fails.
+		l := logger.NewDeferredLogger(ctx)
+		result := tiltfile.RunUnitTest(logger.WithLogger(ctx, l), deps.tfl, test, c.update)
+		if !result.Failed() {
+			fmt.Fprintf(c.streams.Out, "PASS  %s\n", c.displayPath(test))
+			if result.Updated {
+				fmt.Fprintf(c.streams.Out, "      wrote %s\n", c.displayPath(result.GoldenPath))
+			}
+			continue
+		}
+
+		failed++
+		fmt.Fprintf(c.streams.Out, "FAIL  %s\n", c.displayPath(test))
+		if result.Error != nil {
+			l.SetOutput(logger.NewLogger(l.Level(), c.streams.ErrOut))
+			fmt.Fprintf(c.streams.Out, "%v\n", result.Error)
+		} else {
+			fmt.Fprintf(c.streams.Out, "Result doesn't match %s (run with --update to accept it):\n%s",
+				c.displayPath(result.GoldenPath), result.GoldenDiff)
+		}
+	}
+
+	if failed > 0 {
+		return fmt.Errorf("%d of %d Tiltfile tests failed", failed, len(tests))
+	}
+	return nil
+}
+
+func (c *tiltfileTestCmd) displayPath(path string) string {
+	wd, err := os.Getwd()
+	if err != nil {
+		return path
+	}
+	absPath, err := filepath.Abs(path)
+	if err != nil {
+		return path
+	}
+	rel, err := filepath.Rel(wd, absPath)
+	if err != nil {
+		return path
+	}
+	return rel
+}
@@ -1,39 +1,396 @@
 package cli
 
 import (
+	"context"
+	"encoding/json"
+	"errors"
 	"fmt"
+	"sort"
+	"strings"
+	"sync"
+	"time"
 
 	"github.com/spf13/cobra"
+	"k8s.io/apimachinery/pkg/labels"
+	ctrlclient "sigs.k8s.io/controller-runtime/pkg/client"
 
+	"github.com/tilt-dev/tilt/internal/analytics"
+	engineanalytics "github.com/tilt-dev/tilt/internal/engine/analytics"
+	"github.com/tilt-dev/tilt/internal/hud/server"
+	"github.com/tilt-dev/tilt/pkg/apis/core/v1alpha1"
 	"github.com/tilt-dev/tilt/pkg/model"
+	"github.com/tilt-dev/tilt/pkg/model/logstore"
+	proto_webview "github.com/tilt-dev/tilt/pkg/webview"
 )
 
-func newTriggerCmd() *cobra.Command {
+type triggerCmd struct {
+	selector string
+	wait     bool
+	timeout  time.Duration
+}
+
+var _ tiltCmd = &triggerCmd{}
+
+func newTriggerCmd() *triggerCmd {
+	return &triggerCmd{}
+}
+
+func (c *triggerCmd) name() model.TiltSubcommand { return "trigger" }
+
+func (c *triggerCmd) register() *cobra.Command {
 	cmd := &cobra.Command{
-		Use:   "trigger [RESOURCE_NAME]",
-		Short: "Trigger an update for the specified resource",
-		Long: `Trigger an update for the specified resource.
+		Use:   "trigger [RESOURCE_NAME...] [-l LABEL]",
+		Short: "Trigger an update for the specified resources",
+		Long: `Trigger an update for the specified resources.
 
-If the resource has Trigger Mode: Manual and has pending changes, this command will cause those pending changes to be applied.
+If a resource has Trigger Mode: Manual and has pending changes, this command will cause those pending changes to be applied.
 
 Otherwise, this command will force a full rebuild.
+
+With --wait, streams the logs of the triggered builds, and exits with a non-zero
+status if any of them fail.
 `,
-		Args: cobra.ExactArgs(1),
-		Run:  triggerUpdate,
+		Example: `  # Rebuild the frontend
+  tilt trigger frontend
+
+  # Rebuild every resource labeled 'backend', and wait for the builds to finish
+  tilt trigger -l backend --wait --timeout=5m`,
 	}
+
+	cmd.Flags().StringVarP(&c.selector, "selector", "l", "",
+		"Selector (label query) to filter resources on, supports '=', '==', and '!='.(e.g. -l key1=value1,key2=value2)")
+	cmd.Flags().BoolVar(&c.wait, "wait", false,
+		"Wait for the triggered builds to finish. Exits with a non-zero status if any build fails.")
+	cmd.Flags().DurationVar(&c.timeout, "timeout", 0,
+		"With --wait, how long to wait for the builds before giving up. Zero means wait forever.")
 	addConnectServerFlags(cmd)
 	return cmd
 }
 
-func triggerUpdate(cmd *cobra.Command, args []string) {
-	resource := args[0]
+func (c *triggerCmd) run(ctx context.Context, args []string) error {
+	a := analytics.Get(ctx)
+	cmdTags := engineanalytics.CmdTags(map[string]string{
+		"wait":     fmt.Sprintf("%t", c.wait),
+		"selector": fmt.Sprintf("%t", c.selector != ""),
+	})
+	a.Incr("cmd.trigger", cmdTags.AsMap())
+	defer a.Flush(time.Second)
+
+	if len(args) == 0 && c.selector == "" {
+		return fmt.Errorf("must specify at least one resource name or a label selector (-l)")
+	}
+	if c.timeout < 0 {
+		return fmt.Errorf("--timeout must be positive")
+	}
+	if c.timeout > 0 && !c.wait {
+		return fmt.Errorf("--timeout only applies with --wait")
+	}
+
+	names := args
+	var resources map[string]*v1alpha1.UIResource
+	if c.selector != "" || c.wait {
+		client, err := newAPIClient(ctx)
+		if err != nil {
+			return err
+		}
+		names, resources, err = c.resolveResources(ctx, client, args)
+		if err != nil {
+			return err
+		}
+	}
+
+	if !c.wait {
+		c.trigger(names)
+		return nil
+	}
+
+	// Disabled resources don't build, so we'd wait for them forever.
+	var disabled []string
+	for _, name := range names {
+		if isDisabled(resources[name]) {
+			disabled = append(disabled, name)
+		}
+	}
+	if len(disabled) > 0 {
+		return fmt.Errorf("can't wait for disabled resources: %s. Enable them first",
+			strings.Join(disabled, ", "))
+	}
+
+	logDeps, err := wireLogsDeps(ctx, a, "trigger")
+	if err != nil {
+		return err
+	}
+
+	if c.timeout > 0 {
+		var cancel context.CancelFunc
+		ctx, cancel = context.WithTimeout(ctx, c.timeout)
+		defer cancel()
+	}
+	streamCtx, cancelStream := context.WithCancel(ctx)
+	defer cancelStream()
 
-	// TODO(maia): this should probably be the triggerPayload struct, but seems
-	//   like a lot of code to move over (to avoid import cycles) for one call.
-	payload := []byte(fmt.Sprintf(`{"manifest_names":[%q], "build_reason": %d}`, resource, model.BuildReasonFlagTriggerCLI))
+	waiter := newBuildWaiter(resources, logDeps.printer)
+	streamErr := make(chan error, 1)
+	go func() {
+		streamErr <- server.StreamView(streamCtx, logDeps.url, waiter)
+	}()
+
+	c.trigger(names)
+
+	select {
+	case <-waiter.done:
+		cancelStream()
+	case err := <-streamErr:
+		if err == nil {
+			err = fmt.Errorf("lost connection to Tilt")
+		}
+		return err
+	case <-ctx.Done():
+		return c.waitInterruptedErr(ctx, waiter.pending())
+	}
+
+	return waiter.err()
+}
+
+// Explains why we stopped waiting before the builds finished.
+func (c *triggerCmd) waitInterruptedErr(ctx context.Context, pending []string) error {
+	if errors.Is(ctx.Err(), context.DeadlineExceeded) {
+		return fmt.Errorf("timed out after %s waiting for %s to finish building",
+			c.timeout, strings.Join(pending, ", "))
+	}
+	return fmt.Errorf("canceled while waiting for %s to finish building",
+		strings.Join(pending, ", "))
+}
+
+func isDisabled(r *v1alpha1.UIResource) bool {
+	return r.Status.DisableStatus.DisabledCount > 0
+}
+
+// Posts the names to the trigger endpoint.
+func (c *triggerCmd) trigger(names []string) {
+	payload, err := json.Marshal(map[string]interface{}{
+		"manifest_names": names,
+		"build_reason":   model.BuildReasonFlagTriggerCLI,
+	})
+	if err != nil {
+		cmdFail(err)
+	}
 
 	body := apiPostJson("trigger", payload)
 	_ = body.Close()
 
-	fmt.Printf("Successfully triggered update for resource: %q\n", resource)
+	for _, name := range names {
+		fmt.Printf("Successfully triggered update for resource: %q\n", name)
+	}
+}
+
+// Combines the resource names with the resources that match the selector.
+//
+// Returns the names in order, and the current state of each resource.
+func (c *triggerCmd) resolveResources(ctx context.Context, client ctrlclient.Client, args []string) (
+	[]string, map[string]*v1alpha1.UIResource, error) {
+	selector := labels.Everything()
+	if c.selector != "" {
+		var err error
+		selector, err = labels.Parse(c.selector)
+		if err != nil {
+			return nil, nil, fmt.Errorf("invalid selector: %v", err)
+		}
+	}
+
+	var list v1alpha1.UIResourceList
+	err := client.List(ctx, &list)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	all := make(map[string]*v1alpha1.UIResource, len(list.Items))
+	for i := range list.Items {
+		all[list.Items[i].Name] = &list.Items[i]
+	}
+
+	var names []string
+	resources := make(map[string]*v1alpha1.UIResource)
+	for _, name := range args {
+		r, ok := all[name]
+		if !ok {
+			return nil, nil, fmt.Errorf("no resource found with name %q", name)
+		}
+		if resources[name] == nil {
+			names = append(names, name)
+			resources[name] = r
+		}
+	}
+
+	if c.selector != "" {
+		var matches []string
+		for name, r := range all {
+			if selector.Matches(labels.Set(r.Labels)) && resources[name] == nil {
+				matches = append(matches, name)
+				resources[name] = r
+			}
+		}
+		if len(matches) == 0 && len(args) == 0 {
+			return nil, nil, fmt.Errorf("no resources match selector %q", c.selector)
+		}
+		sort.Strings(matches)
+		names = append(names, matches...)
+	}
+
+	return names, resources, nil
+}
+
+// Follows the builds kicked off by `tilt trigger --wait`, and prints their logs.
+//
+// A resource's triggered build is the first build that starts after the
+// builds that were running or finished when we triggered it.
+type buildWaiter struct {
+	mu sync.Mutex
+
+	// The start time of each resource's latest build before we triggered it.
+	baselines map[string]time.Time
+
+	spans   map[logstore.SpanID]bool
+	results map[string]v1alpha1.UIBuildTerminated
+
+	// Resources that were disabled before their triggered build finished.
+	disabled map[string]bool
+
+	logs *server.LogStreamer
+	done chan struct{}
+}
+
+var _ server.ViewHandler = &buildWaiter{}
+
+func newBuildWaiter(resources map[string]*v1alpha1.UIResource, printer server.LogPrinter) *buildWaiter {
+	w := &buildWaiter{
+		baselines: make(map[string]time.Time, len(resources)),
+		spans:     make(map[logstore.SpanID]bool),
+		results:   make(map[string]v1alpha1.UIBuildTerminated, len(resources)),
+		disabled:  make(map[string]bool),
+		done:      make(chan struct{}),
+	}
+
+	mns := make(model.ManifestNameSet, len(resources))
+	for name, r := range resources {
+		w.baselines[name] = latestBuildStart(r)
+		mns[model.ManifestName(name)] = true
+	}
+
+	w.logs = server.NewFilteredLogStreamer(server.LogStreamOptions{
+		Query:      logstore.Query{ManifestNames: mns},
+		Tail:       -1,
+		SpanFilter: w.isTriggeredSpan,
+	}, printer)
+	return w
+}
+
+func latestBuildStart(r *v1alpha1.UIResource) time.Time {
+	var result time.Time
+	if r.Status.CurrentBuild != nil {
+		result = r.Status.CurrentBuild.StartTime.Time
+	}
+	if len(r.Status.BuildHistory) > 0 && r.Status.BuildHistory[0].StartTime.After(result) {
+		result = r.Status.BuildHistory[0].StartTime.Time
+	}
+	return result
+}
+
+func (w *buildWaiter) Handle(v *proto_webview.View) error {
+	w.mu.Lock()
+	for _, r := range v.UiResources {
+		w.updateResource(r)
+	}
+	w.mu.Unlock()
+
+	// Resources are updated first, so that we know about the build spans
+	// before we see their logs.
+	err := w.logs.Handle(v)
+	if err != nil {
+		return err
+	}
+
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	if len(w.results)+len(w.disabled) == len(w.baselines) {
+		select {
+		case <-w.done:
+		default:
+			close(w.done)
+		}
+	}
+	return nil
+}
+
+func (w *buildWaiter) updateResource(r *v1alpha1.UIResource) {
+	baseline, ok := w.baselines[r.Name]
+	if !ok {
+		return
+	}
+	if _, ok := w.results[r.Name]; ok || w.disabled[r.Name] {
+		return
+	}
+
+	if r.Status.CurrentBuild != nil && r.Status.CurrentBuild.StartTime.After(baseline) {
+		w.spans[logstore.SpanID(r.Status.CurrentBuild.SpanID)] = true
+	}
+
+	// The build history is in reverse-chronological order, so the triggered
+	// build is the oldest build after the baseline.
+	for i := len(r.Status.BuildHistory) - 1; i >= 0; i-- {
+		b := r.Status.BuildHistory[i]
+		if b.StartTime.After(baseline) {
+			w.spans[logstore.SpanID(b.SpanID)] = true
+			w.results[r.Name] = b
+			return
+		}
+	}
+
+	// A resource disabled mid-build never finishes its build.
+	if isDisabled(r) {
+		w.disabled[r.Name] = true
+	}
+}
+
+func (w *buildWaiter) isTriggeredSpan(spanID logstore.SpanID) bool {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	return w.spans[spanID]
+}
+
+// The resources whose triggered builds haven't finished, in order.
+func (w *buildWaiter) pending() []string {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	var result []string
+	for name := range w.baselines {
+		if _, ok := w.results[name]; !ok && !w.disabled[name] {
+			result = append(result, name)
+		}
+	}
+	sort.Strings(result)
+	return result
+}
+
+// Summarizes the failed builds, if any.
+func (w *buildWaiter) err() error {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	var failures []string
+	for name, b := range w.results {
+		if b.Error != "" {
+			failures = append(failures, fmt.Sprintf("%s: %s", name, b.Error))
+		}
+	}
+	for name := range w.disabled {
+		failures = append(failures, fmt.Sprintf("%s: resource was disabled", name))
+	}
+	if len(failures) == 0 {
+		return nil
+	}
+	sort.Strings(failures)
+	if len(failures) == 1 {
+		return fmt.Errorf("build failed for %s", failures[0])
+	}
+	return fmt.Errorf("%d builds failed:\n  %s", len(failures), strings.Join(failures, "\n  "))
 }
@@ -0,0 +1,227 @@
+package cli
+
+import (
+	"bytes"
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	"github.com/tilt-dev/tilt/internal/hud"
+	"github.com/tilt-dev/tilt/pkg/apis/core/v1alpha1"
+	proto_webview "github.com/tilt-dev/tilt/pkg/webview"
+)
+
+func TestTriggerResolveSelector(t *testing.T) {
+	f := newServerFixture(t)
+	defer f.TearDown()
+
+	for name, label := range map[string]string{"api": "backend", "db": "backend", "web": "frontend"} {
+		r := &v1alpha1.UIResource{ObjectMeta: metav1.ObjectMeta{
+			Name:   name,
+			Labels: map[string]string{label: label},
+		}}
+		require.NoError(t, f.client.Create(f.ctx, r))
+	}
+
+	c := newTriggerCmd()
+	c.selector = "backend"
+	names, resources, err := c.resolveResources(f.ctx, f.client, nil)
+	require.NoError(t, err)
+	assert.Equal(t, []string{"api", "db"}, names)
+	assert.Len(t, resources, 2)
+
+	names, _, err = c.resolveResources(f.ctx, f.client, []string{"web", "db"})
+	require.NoError(t, err)
+	assert.Equal(t, []string{"web", "db", "api"}, names)
+
+	_, _, err = c.resolveResources(f.ctx, f.client, []string{"nope"})
+	if assert.Error(t, err) {
+		assert.Contains(t, err.Error(), `no resource found with name "nope"`)
+	}
+
+	c.selector = "mobile"
+	_, _, err = c.resolveResources(f.ctx, f.client, nil)
+	if assert.Error(t, err) {
+		assert.Contains(t, err.Error(), `no resources match selector "mobile"`)
+	}
+}
+
+func TestTriggerWaitInterrupted(t *testing.T) {
+	c := newTriggerCmd()
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	err := c.waitInterruptedErr(ctx, []string{"api", "db"})
+	if assert.Error(t, err) {
+		assert.Equal(t, "canceled while waiting for api, db to finish building", err.Error())
+	}
+
+	c.timeout = time.Minute
+	ctx, cancel = context.WithTimeout(context.Background(), 0)
+	defer cancel()
+	<-ctx.Done()
+	err = c.waitInterruptedErr(ctx, []string{"api"})
+	if assert.Error(t, err) {
+		assert.Equal(t, "timed out after 1m0s waiting for api to finish building", err.Error())
+	}
+}
+
+func TestBuildWaiterSuccess(t *testing.T) {
+	f := newBuildWaiterFixture(t)
+
+	f.handle(f.building("build:2"), f.logs("build:1", "old build"), f.logs("build:2", "new build"))
+	f.assertNotDone()
+	assert.Equal(t, "new build\n", f.out.String())
+
+	f.handle(f.finished("build:2", ""))
+	f.assertDone()
+	assert.NoError(t, f.waiter.err())
+}
+
+func TestBuildWaiterFailure(t *testing.T) {
+	f := newBuildWaiterFixture(t)
+
+	// If the build starts and finishes between views, we still see its logs.
+	f.handle(f.finished("build:2", "compile error"), f.logs("build:2", "syntax error on line 1"))
+	f.assertDone()
+	assert.Equal(t, "syntax error on line 1\n", f.out.String())
+
+	err := f.waiter.err()
+	if assert.Error(t, err) {
+		assert.Equal(t, "build failed for api: compile error", err.Error())
+	}
+}
+
+func TestBuildWaiterIgnoresRunningBuild(t *testing.T) {
+	f := newBuildWaiterFixture(t)
+	f.resource.Status.CurrentBuild = &v1alpha1.UIBuildRunning{
+		StartTime: metav1.NewMicroTime(f.start.Add(time.Second)),
+		SpanID:    "build:2",
+	}
+	f.waiter = newBuildWaiter(map[string]*v1alpha1.UIResource{"api": f.resource}, f.printer)
+
+	// The build that was running when we triggered finishes.
+	f.handle(f.finished("build:2", "stale error"))
+	f.assertNotDone()
+	assert.Equal(t, []string{"api"}, f.waiter.pending())
+
+	f.start = f.start.Add(time.Second)
+	f.handle(f.finished("build:3", ""))
+	f.assertDone()
+	assert.NoError(t, f.waiter.err())
+}
+
+func TestBuildWaiterDisabled(t *testing.T) {
+	f := newBuildWaiterFixture(t)
+
+	f.handle(f.building("build:2"))
+	f.assertNotDone()
+
+	r := f.resource.DeepCopy()
+	r.Status.DisableStatus.DisabledCount = 1
+	f.handle(r)
+	f.assertDone()
+	assert.Empty(t, f.waiter.pending())
+
+	err := f.waiter.err()
+	if assert.Error(t, err) {
+		assert.Equal(t, "build failed for api: resource was disabled", err.Error())
+	}
+}
+
+type buildWaiterFixture struct {
+	t          *testing.T
+	out        *bytes.Buffer
+	printer    *hud.IncrementalPrinter
+	resource   *v1alpha1.UIResource
+	waiter     *buildWaiter
+	start      time.Time
+	history    []v1alpha1.UIBuildTerminated
+	checkpoint int32
+}
+
+func newBuildWaiterFixture(t *testing.T) *buildWaiterFixture {
+	out := bytes.NewBuffer(nil)
+	printer := hud.NewIncrementalPrinter(hud.Stdout(out))
+	start := time.Now()
+	history := []v1alpha1.UIBuildTerminated{{
+		StartTime: metav1.NewMicroTime(start),
+		SpanID:    "build:1",
+	}}
+	r := &v1alpha1.UIResource{
+		ObjectMeta: metav1.ObjectMeta{Name: "api"},
+		Status:     v1alpha1.UIResourceStatus{BuildHistory: history},
+	}
+	return &buildWaiterFixture{
+		t:        t,
+		out:      out,
+		printer:  printer,
+		resource: r,
+		waiter:   newBuildWaiter(map[string]*v1alpha1.UIResource{"api": r}, printer),
+		start:    start,
+		history:  history,
+	}
+}
+
+// A view where a new build is running.
+func (f *buildWaiterFixture) building(spanID string) *v1alpha1.UIResource {
+	r := f.resource.DeepCopy()
+	r.Status.CurrentBuild = &v1alpha1.UIBuildRunning{
+		StartTime: metav1.NewMicroTime(f.start.Add(time.Second)),
+		SpanID:    spanID,
+	}
+	return r
+}
+
+// A view where a new build has finished.
+func (f *buildWaiterFixture) finished(spanID string, buildErr string) *v1alpha1.UIResource {
+	b := v1alpha1.UIBuildTerminated{
+		StartTime:  metav1.NewMicroTime(f.start.Add(time.Second)),
+		FinishTime: metav1.NewMicroTime(f.start.Add(2 * time.Second)),
+		SpanID:     spanID,
+		Error:      buildErr,
+	}
+	f.history = append([]v1alpha1.UIBuildTerminated{b}, f.history...)
+
+	r := f.resource.DeepCopy()
+	r.Status.BuildHistory = append([]v1alpha1.UIBuildTerminated{}, f.history...)
+	return r
+}
+
+func (f *buildWaiterFixture) logs(spanID string, text string) *proto_webview.LogList {
+	ll := &proto_webview.LogList{
+		Spans:          map[string]*proto_webview.LogSpan{spanID: {ManifestName: "api"}},
+		Segments:       []*proto_webview.LogSegment{{SpanId: spanID, Text: text + "\n"}},
+		FromCheckpoint: f.checkpoint,
+		ToCheckpoint:   f.checkpoint + 1,
+	}
+	f.checkpoint++
+	return ll
+}
+
+func (f *buildWaiterFixture) handle(r *v1alpha1.UIResource, logs ...*proto_webview.LogList) {
+	require.NoError(f.t, f.waiter.Handle(&proto_webview.View{UiResources: []*v1alpha1.UIResource{r}}))
+	for _, ll := range logs {
+		require.NoError(f.t, f.waiter.Handle(&proto_webview.View{LogList: ll}))
+	}
+}
+
+func (f *buildWaiterFixture) assertDone() {
+	select {
+	case <-f.waiter.done:
+	default:
+		f.t.Fatal("expected the waiter to be done")
+	}
+}
+
+func (f *buildWaiterFixture) assertNotDone() {
+	select {
+	case <-f.waiter.done:
+		f.t.Fatal("expected the waiter to still be waiting")
+	default:
+	}
+}
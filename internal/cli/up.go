@@ -14,6 +14,7 @@ import (
 	"k8s.io/klog/v2"
 
 	"github.com/tilt-dev/tilt/internal/analytics"
+	"github.com/tilt-dev/tilt/internal/containerupdate"
 	engineanalytics "github.com/tilt-dev/tilt/internal/engine/analytics"
 	"github.com/tilt-dev/tilt/internal/hud/prompt"
 	"github.com/tilt-dev/tilt/internal/store"
@@ -21,6 +22,7 @@ import (
 	"github.com/tilt-dev/tilt/pkg/assets"
 	"github.com/tilt-dev/tilt/pkg/logger"
 	"github.com/tilt-dev/tilt/pkg/model"
+	"github.com/tilt-dev/tilt/pkg/model/logstore"
 	"github.com/tilt-dev/tilt/web"
 )
 
@@ -29,6 +31,7 @@ var webModeFlag model.WebMode = model.DefaultWebMode
 const DefaultWebDevPort = 46764
 
 var updateModeFlag string = string(liveupdates.UpdateModeAuto)
+var liveUpdateHelperImageFlag string = string(containerupdate.DefaultHelperImage)
 var webDevPort = 0
 var logActionsFlag bool = false
 
@@ -72,6 +75,8 @@ local resources--i.e. those using serve_cmd--are terminated when you exit Tilt.
 
 	cmd.Flags().StringVar(&updateModeFlag, "update-mode", string(liveupdates.UpdateModeAuto),
 		fmt.Sprintf("Control the strategy Tilt uses for updating instances. Possible values: %v", liveupdates.AllUpdateModes))
+	cmd.Flags().StringVar(&liveUpdateHelperImageFlag, "live-update-helper-image", string(containerupdate.DefaultHelperImage),
+		"Image of the helper container that Tilt runs (with the SYS_PTRACE capability) to live-update containers that don't have tar or rm")
 	cmd.Flags().BoolVar(&c.hud, "hud", true, "If true, tilt will open in HUD mode.")
 	cmd.Flags().BoolVar(&c.legacy, "legacy", false, "If true, tilt will open in legacy terminal mode.")
 	cmd.Flags().BoolVar(&c.stream, "stream", false, "If true, tilt will stream logs in the terminal.")
@@ -81,6 +86,7 @@ local resources--i.e. those using serve_cmd--are terminated when you exit Tilt.
 	addTiltfileFlag(cmd, &c.fileName)
 	addKubeContextFlag(cmd)
 	addNamespaceFlag(cmd)
+	addLogHistoryFlags(cmd)
 	cmd.Flags().Lookup("logactions").Hidden = true
 	cmd.Flags().StringVar(&c.outputSnapshotOnExit, "output-snapshot-on-exit", "", "If specified, Tilt will dump a snapshot of its state to the specified path when it exits")
 
@@ -183,10 +189,24 @@ func provideUpdateModeFlag() liveupdates.UpdateModeFlag {
 	return liveupdates.UpdateModeFlag(updateModeFlag)
 }
 
+func provideLiveUpdateHelperImage() containerupdate.HelperImage {
+	return containerupdate.HelperImage(liveUpdateHelperImageFlag)
+}
+
 func provideLogActions() store.LogActionsFlag {
 	return store.LogActionsFlag(logActionsFlag)
 }
 
+func provideLogHistory() (*logstore.History, error) {
+	if logHistoryDirFlag == "" {
+		return nil, nil
+	}
+	return logstore.NewHistory(logHistoryDirFlag, logstore.HistoryOptions{
+		MaxBytes: logHistoryMaxBytesFlag,
+		MaxAge:   time.Duration(logHistoryMaxDaysFlag) * 24 * time.Hour,
+	})
+}
+
 func provideWebMode(b model.TiltBuild) (model.WebMode, error) {
 	switch webModeFlag {
 	case model.LocalWebMode, model.ProdWebMode, model.PrecompiledWebMode:
@@ -2,12 +2,17 @@ package cli
 
 import (
 	"bytes"
+	"context"
 	"fmt"
 	"io"
 	"io/ioutil"
 	"net/http"
 	"os"
 	"strings"
+
+	ctrlclient "sigs.k8s.io/controller-runtime/pkg/client"
+
+	"github.com/tilt-dev/tilt/pkg/apis/core/v1alpha1"
 )
 
 func apiHost() string {
@@ -62,3 +67,18 @@ func failWithNonOKResponse(url string, res *http.Response) {
 	_ = res.Body.Close()
 	cmdFail(fmt.Errorf("Request to %s failed with status %q: %s", url, res.Status, body))
 }
+
+// Creates a typed client for the API server of the running Tilt.
+func newAPIClient(ctx context.Context) (ctrlclient.Client, error) {
+	getter, err := wireClientGetter(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	config, err := getter.ToRESTConfig()
+	if err != nil {
+		return nil, err
+	}
+
+	return ctrlclient.New(config, ctrlclient.Options{Scheme: v1alpha1.NewScheme()})
+}
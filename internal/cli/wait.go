@@ -0,0 +1,395 @@
+/*
+Adapted from
+https://github.com/kubernetes/kubectl/tree/master/pkg/cmd/wait
+*/
+
+/*
+Copyright 2018 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package cli
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"os"
+	"reflect"
+	"strings"
+	"time"
+
+	"github.com/spf13/cobra"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/fields"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/util/wait"
+	"k8s.io/apimachinery/pkg/watch"
+	"k8s.io/cli-runtime/pkg/genericclioptions"
+	"k8s.io/cli-runtime/pkg/resource"
+	"k8s.io/client-go/dynamic"
+	watchtools "k8s.io/client-go/tools/watch"
+	"k8s.io/client-go/util/jsonpath"
+	"k8s.io/kubectl/pkg/cmd/get"
+	cmdwait "k8s.io/kubectl/pkg/cmd/wait"
+
+	"github.com/tilt-dev/tilt/internal/analytics"
+	engineanalytics "github.com/tilt-dev/tilt/internal/engine/analytics"
+	"github.com/tilt-dev/tilt/pkg/model"
+)
+
+type waitCmd struct {
+	flags *cmdwait.WaitFlags
+}
+
+var _ tiltCmd = &waitCmd{}
+
+func newWaitCmd() *waitCmd {
+	streams := genericclioptions.IOStreams{Out: os.Stdout, ErrOut: os.Stderr, In: os.Stdin}
+	return &waitCmd{
+		flags: cmdwait.NewWaitFlags(nil, streams),
+	}
+}
+
+func (c *waitCmd) name() model.TiltSubcommand { return "wait" }
+
+func (c *waitCmd) register() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:                   "wait ([-f FILENAME] | TYPE/NAME | TYPE [(-l label | --all)]) [--for=delete|--for=condition=NAME|--for=jsonpath=EXPR=VALUE]",
+		DisableFlagsInUseLine: true,
+		Short:                 "Wait for a specific condition on one or many resources",
+		Long: `Wait for a specific condition on one or many resources.
+
+The command takes multiple resources and waits until the specified condition
+is seen in the Status field of every given resource.
+
+Works with any resource type in the Tilt API (Cmd, Session, UIResource,
+KubernetesApply, etc.). See 'tilt api-resources' for the full list.
+
+Exits with a non-zero status if the condition isn't met before the timeout.
+`,
+		Example: `  # Wait for the resource "api" to become ready
+  tilt wait --for=condition=Ready uiresource/api
+
+  # Wait for all resources with the label "frontend" to become ready
+  tilt wait --for=condition=Ready uiresource -l frontend
+
+  # Wait for a field to have a specific value
+  tilt wait --for=jsonpath='{.status.runtimeStatus}'=ok uiresource/api
+
+  # Wait for the cmd "my-server" to be deleted, with a timeout of 60s
+  tilt wait --for=delete cmd/my-server --timeout=60s`,
+	}
+
+	flags := c.flags
+	flags.PrintFlags.AddFlags(cmd)
+	flags.ResourceBuilderFlags.AddFlags(cmd.Flags())
+	cmd.Flags().DurationVar(&flags.Timeout, "timeout", flags.Timeout,
+		"The length of time to wait before giving up. Zero means check once and don't wait, negative means wait for a week.")
+	cmd.Flags().StringVar(&flags.ForCondition, "for", flags.ForCondition,
+		"The condition to wait on: [delete|condition=condition-name|jsonpath=expression[=value]]. "+
+			"The default status value of condition-name is true, you can set false with condition=condition-name=false. "+
+			"If no value is given for a jsonpath expression, waits until the expression matches a non-empty value.")
+	addConnectServerFlags(cmd)
+	return cmd
+}
+
+func (c *waitCmd) run(ctx context.Context, args []string) error {
+	a := analytics.Get(ctx)
+	cmdTags := engineanalytics.CmdTags(map[string]string{})
+	a.Incr("cmd.wait", cmdTags.AsMap())
+	defer a.Flush(time.Second)
+
+	getter, err := wireClientGetter(ctx)
+	if err != nil {
+		return err
+	}
+	c.flags.RESTClientGetter = getter
+
+	o, err := c.toOptions(ctx, args)
+	if err != nil {
+		return err
+	}
+	return o.RunWait()
+}
+
+// Converts the CLI flags to runtime options.
+//
+// This mirrors WaitFlags.ToOptions, but with our own condition functions,
+// so that we can support jsonpath conditions and cancellation.
+func (c *waitCmd) toOptions(ctx context.Context, args []string) (*cmdwait.WaitOptions, error) {
+	flags := c.flags
+	printer, err := flags.PrintFlags.ToPrinter()
+	if err != nil {
+		return nil, err
+	}
+	builder := flags.ResourceBuilderFlags.ToBuilder(flags.RESTClientGetter, args)
+	clientConfig, err := flags.RESTClientGetter.ToRESTConfig()
+	if err != nil {
+		return nil, err
+	}
+	dynamicClient, err := dynamic.NewForConfig(clientConfig)
+	if err != nil {
+		return nil, err
+	}
+	conditionFn, err := conditionFuncFor(ctx, flags.ForCondition, flags.ErrOut)
+	if err != nil {
+		return nil, err
+	}
+
+	effectiveTimeout := flags.Timeout
+	if effectiveTimeout < 0 {
+		effectiveTimeout = 168 * time.Hour
+	}
+
+	return &cmdwait.WaitOptions{
+		ResourceFinder: builder,
+		DynamicClient:  dynamicClient,
+		Timeout:        effectiveTimeout,
+		ForCondition:   flags.ForCondition,
+
+		Printer:     printer,
+		ConditionFn: conditionFn,
+		IOStreams:   flags.IOStreams,
+	}, nil
+}
+
+func conditionFuncFor(ctx context.Context, condition string, errOut io.Writer) (cmdwait.ConditionFunc, error) {
+	if strings.ToLower(condition) == "delete" {
+		return cmdwait.IsDeleted, nil
+	}
+
+	if strings.HasPrefix(condition, "condition=") {
+		conditionName := condition[len("condition="):]
+		conditionValue := "true"
+		if equalsIndex := strings.Index(conditionName, "="); equalsIndex != -1 {
+			conditionValue = conditionName[equalsIndex+1:]
+			conditionName = conditionName[0:equalsIndex]
+		}
+		if conditionName == "" {
+			return nil, fmt.Errorf("condition name must be provided: %q", condition)
+		}
+
+		return objectWait{
+			ctx:    ctx,
+			errOut: errOut,
+			check: func(obj *unstructured.Unstructured) (bool, error) {
+				return isConditionMet(obj, conditionName, conditionValue)
+			},
+		}.isMet, nil
+	}
+
+	if strings.HasPrefix(condition, "jsonpath=") {
+		expr, value, hasValue, err := parseJSONPathCondition(condition[len("jsonpath="):])
+		if err != nil {
+			return nil, err
+		}
+
+		relaxed, err := get.RelaxedJSONPathExpression(expr)
+		if err != nil {
+			return nil, err
+		}
+		parser := jsonpath.New("wait").AllowMissingKeys(true)
+		err = parser.Parse(relaxed)
+		if err != nil {
+			return nil, fmt.Errorf("parsing jsonpath %q: %v", expr, err)
+		}
+
+		return objectWait{
+			ctx:    ctx,
+			errOut: errOut,
+			check: func(obj *unstructured.Unstructured) (bool, error) {
+				return isJSONPathMet(obj, parser, value, hasValue)
+			},
+		}.isMet, nil
+	}
+
+	return nil, fmt.Errorf("unrecognized condition: %q", condition)
+}
+
+// Splits a jsonpath condition into the expression and the expected value.
+//
+// Accepts both '{.status.phase}=Running' and '.status.phase=Running'.
+func parseJSONPathCondition(cond string) (expr string, value string, hasValue bool, err error) {
+	if strings.HasPrefix(cond, "{") {
+		end := strings.LastIndex(cond, "}")
+		if end == -1 {
+			return "", "", false, fmt.Errorf("unterminated jsonpath expression: %q", cond)
+		}
+		expr, rest := cond[:end+1], cond[end+1:]
+		if rest == "" {
+			return expr, "", false, nil
+		}
+		if !strings.HasPrefix(rest, "=") {
+			return "", "", false, fmt.Errorf("jsonpath condition must be of the form {expression}=value: %q", cond)
+		}
+		return expr, rest[1:], true, nil
+	}
+
+	equalsIndex := strings.Index(cond, "=")
+	if equalsIndex == -1 {
+		return cond, "", false, nil
+	}
+	return cond[:equalsIndex], cond[equalsIndex+1:], true, nil
+}
+
+func isConditionMet(obj *unstructured.Unstructured, conditionName, conditionValue string) (bool, error) {
+	conditions, found, err := unstructured.NestedSlice(obj.Object, "status", "conditions")
+	if err != nil {
+		return false, err
+	}
+	if !found {
+		return false, nil
+	}
+	for _, conditionUncast := range conditions {
+		condition, ok := conditionUncast.(map[string]interface{})
+		if !ok {
+			continue
+		}
+		name, found, err := unstructured.NestedString(condition, "type")
+		if !found || err != nil || !strings.EqualFold(name, conditionName) {
+			continue
+		}
+		status, found, err := unstructured.NestedString(condition, "status")
+		if !found || err != nil {
+			continue
+		}
+		return strings.EqualFold(status, conditionValue), nil
+	}
+	return false, nil
+}
+
+func isJSONPathMet(obj *unstructured.Unstructured, parser *jsonpath.JSONPath, value string, hasValue bool) (bool, error) {
+	results, err := parser.FindResults(obj.Object)
+	if err != nil {
+		return false, err
+	}
+
+	found := false
+	for _, result := range results {
+		for _, r := range result {
+			if !r.IsValid() || (r.Kind() == reflect.Interface && r.IsNil()) {
+				return false, nil
+			}
+			actual := fmt.Sprintf("%v", r.Interface())
+			if hasValue && actual != value {
+				return false, nil
+			}
+			if !hasValue && actual == "" {
+				return false, nil
+			}
+			found = true
+		}
+	}
+	return found, nil
+}
+
+// Watches a single object until the check passes.
+type objectWait struct {
+	ctx    context.Context
+	errOut io.Writer
+	check  func(obj *unstructured.Unstructured) (bool, error)
+}
+
+func (w objectWait) isMet(info *resource.Info, o *cmdwait.WaitOptions) (runtime.Object, bool, error) {
+	endTime := time.Now().Add(o.Timeout)
+	for {
+		if len(info.Name) == 0 {
+			return info.Object, false, fmt.Errorf("resource name must be provided")
+		}
+
+		nameSelector := fields.OneTermEqualSelector("metadata.name", info.Name).String()
+
+		var gottenObj *unstructured.Unstructured
+		// List with a name field selector to get the current resourceVersion to watch from (not the object's resourceVersion)
+		gottenObjList, err := o.DynamicClient.Resource(info.Mapping.Resource).Namespace(info.Namespace).
+			List(w.ctx, metav1.ListOptions{FieldSelector: nameSelector})
+
+		resourceVersion := ""
+		switch {
+		case err != nil:
+			return info.Object, false, err
+		case len(gottenObjList.Items) != 1:
+			resourceVersion = gottenObjList.GetResourceVersion()
+		default:
+			gottenObj = &gottenObjList.Items[0]
+			met, err := w.check(gottenObj)
+			if met {
+				return gottenObj, true, nil
+			}
+			if err != nil {
+				return gottenObj, false, err
+			}
+			resourceVersion = gottenObjList.GetResourceVersion()
+		}
+
+		watchOptions := metav1.ListOptions{}
+		watchOptions.FieldSelector = nameSelector
+		watchOptions.ResourceVersion = resourceVersion
+		objWatch, err := o.DynamicClient.Resource(info.Mapping.Resource).Namespace(info.Namespace).
+			Watch(w.ctx, watchOptions)
+		if err != nil {
+			return gottenObj, false, err
+		}
+
+		timeout := time.Until(endTime)
+		errWaitTimeoutWithName := fmt.Errorf("%s on %s/%s", wait.ErrWaitTimeout.Error(), info.Mapping.Resource.Resource, info.Name)
+		if timeout < 0 {
+			// we're out of time
+			objWatch.Stop()
+			return gottenObj, false, errWaitTimeoutWithName
+		}
+
+		ctx, cancel := watchtools.ContextWithOptionalTimeout(w.ctx, timeout)
+		watchEvent, err := watchtools.UntilWithoutRetry(ctx, objWatch, w.isMetByEvent)
+		cancel()
+		switch {
+		case err == nil:
+			return watchEvent.Object, true, nil
+		case err == watchtools.ErrWatchClosed:
+			continue
+		case err == wait.ErrWaitTimeout:
+			if w.ctx.Err() != nil {
+				return gottenObj, false, w.ctx.Err()
+			}
+			if watchEvent != nil {
+				return watchEvent.Object, false, errWaitTimeoutWithName
+			}
+			return gottenObj, false, errWaitTimeoutWithName
+		default:
+			return gottenObj, false, err
+		}
+	}
+}
+
+func (w objectWait) isMetByEvent(event watch.Event) (bool, error) {
+	if event.Type == watch.Error {
+		// keep waiting in the event we see an error - we expect the watch to be closed by
+		// the server
+		err := apierrors.FromObject(event.Object)
+		_, _ = fmt.Fprintf(w.errOut, "error: An error occurred while waiting for the condition to be satisfied: %v", err)
+		return false, nil
+	}
+	if event.Type == watch.Deleted {
+		// this will chain back out, result in another get and an return false back up the chain
+		return false, nil
+	}
+	obj, ok := event.Object.(*unstructured.Unstructured)
+	if !ok {
+		return false, nil
+	}
+	return w.check(obj)
+}
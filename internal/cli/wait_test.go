@@ -0,0 +1,127 @@
+package cli
+
+import (
+	"bytes"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	"github.com/tilt-dev/tilt/pkg/apis/core/v1alpha1"
+)
+
+func TestWaitForCondition(t *testing.T) {
+	f := newServerFixture(t)
+	defer f.TearDown()
+
+	r := &v1alpha1.UIResource{ObjectMeta: metav1.ObjectMeta{Name: "api"}}
+	require.NoError(t, f.client.Create(f.ctx, r))
+
+	go func() {
+		time.Sleep(100 * time.Millisecond)
+		r.Status.Conditions = []v1alpha1.UIResourceCondition{
+			{Type: v1alpha1.UIResourceReady, Status: metav1.ConditionTrue},
+		}
+		_ = f.client.Status().Update(f.ctx, r)
+	}()
+
+	out := bytes.NewBuffer(nil)
+	wait := newWaitCmd()
+	cmd := wait.register()
+	wait.flags.IOStreams.Out = out
+	require.NoError(t, cmd.Flags().Set("for", "condition=Ready"))
+	require.NoError(t, cmd.Flags().Set("timeout", "5s"))
+
+	err := wait.run(f.ctx, []string{"uiresource/api"})
+	require.NoError(t, err)
+	assert.Contains(t, out.String(), "uiresource.tilt.dev/api condition met")
+}
+
+func TestWaitForJSONPath(t *testing.T) {
+	f := newServerFixture(t)
+	defer f.TearDown()
+
+	c := &v1alpha1.Cmd{
+		ObjectMeta: metav1.ObjectMeta{Name: "my-sleep"},
+		Spec:       v1alpha1.CmdSpec{Args: []string{"sleep", "1"}},
+	}
+	require.NoError(t, f.client.Create(f.ctx, c))
+
+	go func() {
+		time.Sleep(100 * time.Millisecond)
+		c.Status.Running = &v1alpha1.CmdStateRunning{PID: 1234}
+		_ = f.client.Status().Update(f.ctx, c)
+	}()
+
+	out := bytes.NewBuffer(nil)
+	wait := newWaitCmd()
+	cmd := wait.register()
+	wait.flags.IOStreams.Out = out
+	require.NoError(t, cmd.Flags().Set("for", "jsonpath={.status.running.pid}=1234"))
+	require.NoError(t, cmd.Flags().Set("timeout", "5s"))
+
+	err := wait.run(f.ctx, []string{"cmd/my-sleep"})
+	require.NoError(t, err)
+	assert.Contains(t, out.String(), "cmd.tilt.dev/my-sleep condition met")
+}
+
+func TestWaitTimeout(t *testing.T) {
+	f := newServerFixture(t)
+	defer f.TearDown()
+
+	r := &v1alpha1.UIResource{ObjectMeta: metav1.ObjectMeta{Name: "api"}}
+	require.NoError(t, f.client.Create(f.ctx, r))
+
+	wait := newWaitCmd()
+	cmd := wait.register()
+	wait.flags.IOStreams.Out = bytes.NewBuffer(nil)
+	require.NoError(t, cmd.Flags().Set("for", "condition=Ready"))
+	require.NoError(t, cmd.Flags().Set("timeout", "200ms"))
+
+	err := wait.run(f.ctx, []string{"uiresource/api"})
+	if assert.Error(t, err) {
+		assert.Contains(t, err.Error(), "timed out waiting for the condition on uiresources/api")
+	}
+}
+
+func TestWaitUnrecognizedCondition(t *testing.T) {
+	f := newServerFixture(t)
+	defer f.TearDown()
+
+	wait := newWaitCmd()
+	cmd := wait.register()
+	require.NoError(t, cmd.Flags().Set("for", "ready"))
+
+	err := wait.run(f.ctx, []string{"uiresource/api"})
+	if assert.Error(t, err) {
+		assert.Contains(t, err.Error(), `unrecognized condition: "ready"`)
+	}
+}
+
+func TestParseJSONPathCondition(t *testing.T) {
+	for _, tc := range []struct {
+		cond     string
+		expr     string
+		value    string
+		hasValue bool
+	}{
+		{"{.status.phase}=Running", "{.status.phase}", "Running", true},
+		{"{.status.phase}", "{.status.phase}", "", false},
+		{".status.phase=Running", ".status.phase", "Running", true},
+		{".status.phase", ".status.phase", "", false},
+		{"{.status.conditions[?(@.type==\"Ready\")].status}=True", "{.status.conditions[?(@.type==\"Ready\")].status}", "True", true},
+	} {
+		t.Run(tc.cond, func(t *testing.T) {
+			expr, value, hasValue, err := parseJSONPathCondition(tc.cond)
+			require.NoError(t, err)
+			assert.Equal(t, tc.expr, expr)
+			assert.Equal(t, tc.value, value)
+			assert.Equal(t, tc.hasValue, hasValue)
+		})
+	}
+
+	_, _, _, err := parseJSONPathCondition("{.status.phase")
+	assert.Error(t, err)
+}
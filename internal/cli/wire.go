@@ -116,16 +116,20 @@ var BaseWireSet = wire.NewSet(
 	wire.Value(openurl.OpenURL(openurl.BrowserOpen)),
 
 	provideLogActions,
+	provideLogHistory,
+	provideSessionCISpec,
 	store.NewStore,
 	wire.Bind(new(store.RStore), new(*store.Store)),
 
 	dockerprune.NewDockerPruner,
+	dockerprune.NewRegistryClient,
 
 	provideTiltInfo,
 	engine.NewUpper,
 	engineanalytics.NewAnalyticsUpdater,
 	engineanalytics.ProvideAnalyticsReporter,
 	provideUpdateModeFlag,
+	provideLiveUpdateHelperImage,
 	fsevent.ProvideWatcherMaker,
 	fsevent.ProvideTimerMaker,
 
@@ -200,11 +204,12 @@ func wireCmdCI(ctx context.Context, analytics *analytics.TiltAnalytics, subcomma
 }
 
 type CmdCIDeps struct {
-	Upper        engine.Upper
-	TiltBuild    model.TiltBuild
-	Token        token.Token
-	CloudAddress cloudurl.Address
-	Snapshotter  *cloud.Snapshotter
+	Upper             engine.Upper
+	TiltBuild         model.TiltBuild
+	Token             token.Token
+	CloudAddress      cloudurl.Address
+	Snapshotter       *cloud.Snapshotter
+	SessionController *session.Controller
 }
 
 func wireCmdUpdog(ctx context.Context,
@@ -317,12 +322,14 @@ func wireLogsDeps(ctx context.Context, tiltAnalytics *analytics.TiltAnalytics, s
 type LogsDeps struct {
 	url     model.WebURL
 	printer *hud.IncrementalPrinter
+	stdout  hud.Stdout
 }
 
-func ProvideLogsDeps(u model.WebURL, p *hud.IncrementalPrinter) LogsDeps {
+func ProvideLogsDeps(u model.WebURL, p *hud.IncrementalPrinter, stdout hud.Stdout) LogsDeps {
 	return LogsDeps{
 		url:     u,
 		printer: p,
+		stdout:  stdout,
 	}
 }
 
@@ -27,6 +27,7 @@ import (
 	"github.com/tilt-dev/tilt/internal/controllers"
 	"github.com/tilt-dev/tilt/internal/controllers/core/cmd"
 	"github.com/tilt-dev/tilt/internal/controllers/core/configmap"
+	"github.com/tilt-dev/tilt/internal/controllers/core/dockerimage"
 	"github.com/tilt-dev/tilt/internal/controllers/core/extension"
 	"github.com/tilt-dev/tilt/internal/controllers/core/extensionrepo"
 	"github.com/tilt-dev/tilt/internal/controllers/core/filewatch"
@@ -114,7 +115,8 @@ func wireTiltfileResult(ctx context.Context, analytics2 *analytics.TiltAnalytics
 	localexecEnv := localexec.DefaultEnv(webPort, webHost)
 	processExecer := localexec.NewProcessExecer(localexecEnv)
 	defaults := _wireDefaultsValue
-	tiltfileLoader := tiltfile.ProvideTiltfileLoader(analytics2, client, plugin, versionPlugin, configPlugin, dockerComposeClient, webHost, processExecer, defaults, env)
+	base := xdg.NewTiltDevBase()
+	tiltfileLoader := tiltfile.ProvideTiltfileLoader(analytics2, client, plugin, versionPlugin, configPlugin, dockerComposeClient, webHost, processExecer, defaults, env, base)
 	cliCmdTiltfileResultDeps := newTiltfileResultDeps(tiltfileLoader)
 	return cliCmdTiltfileResultDeps, nil
 }
@@ -151,6 +153,7 @@ func wireDockerPrune(ctx context.Context, analytics2 *analytics.TiltAnalytics, s
 		return dpDeps{}, err
 	}
 	switchCli := docker.ProvideSwitchCli(clusterClient, localClient)
+	registryClient := dockerprune.NewRegistryClient(switchCli)
 	plugin := k8scontext.NewPlugin(kubeContext, env)
 	tiltBuild := provideTiltInfo()
 	versionPlugin := version.NewPlugin(tiltBuild)
@@ -161,8 +164,9 @@ func wireDockerPrune(ctx context.Context, analytics2 *analytics.TiltAnalytics, s
 	localexecEnv := localexec.DefaultEnv(webPort, webHost)
 	processExecer := localexec.NewProcessExecer(localexecEnv)
 	defaults := _wireDefaultsValue
-	tiltfileLoader := tiltfile.ProvideTiltfileLoader(analytics2, client, plugin, versionPlugin, configPlugin, dockerComposeClient, webHost, processExecer, defaults, env)
-	cliDpDeps := newDPDeps(switchCli, tiltfileLoader)
+	base := xdg.NewTiltDevBase()
+	tiltfileLoader := tiltfile.ProvideTiltfileLoader(analytics2, client, plugin, versionPlugin, configPlugin, dockerComposeClient, webHost, processExecer, defaults, env, base)
+	cliDpDeps := newDPDeps(switchCli, registryClient, tiltfileLoader)
 	return cliDpDeps, nil
 }
 
@@ -284,7 +288,7 @@ func wireCmdUp(ctx context.Context, analytics3 *analytics.TiltAnalytics, cmdTags
 	configPlugin := config.NewPlugin(subcommand)
 	dockerComposeClient := dockercompose.NewDockerComposeClient(localEnv)
 	defaults := _wireDefaultsValue
-	tiltfileLoader := tiltfile.ProvideTiltfileLoader(analytics3, client, plugin, versionPlugin, configPlugin, dockerComposeClient, webHost, processExecer, defaults, k8sEnv)
+	tiltfileLoader := tiltfile.ProvideTiltfileLoader(analytics3, client, plugin, versionPlugin, configPlugin, dockerComposeClient, webHost, processExecer, defaults, k8sEnv, base)
 	buildSource := tiltfile2.NewBuildSource()
 	engineMode := _wireEngineModeValue
 	tiltfileReconciler := tiltfile2.NewReconciler(storeStore, tiltfileLoader, switchCli, deferredClient, scheme, buildSource, engineMode)
@@ -294,8 +298,9 @@ func wireCmdUp(ctx context.Context, analytics3 *analytics.TiltAnalytics, cmdTags
 	if err != nil {
 		return CmdUpDeps{}, err
 	}
-	dockerUpdater := containerupdate.NewDockerUpdater(switchCli)
-	execUpdater := containerupdate.NewExecUpdater(client)
+	helperImage := provideLiveUpdateHelperImage()
+	dockerUpdater := containerupdate.NewDockerUpdater(switchCli, helperImage)
+	execUpdater := containerupdate.NewExecUpdater(client, helperImage)
 	liveupdatesUpdateModeFlag := provideUpdateModeFlag()
 	updateMode, err := liveupdates.ProvideUpdateMode(liveupdatesUpdateModeFlag, kubeContext, clusterEnv)
 	if err != nil {
@@ -303,25 +308,27 @@ func wireCmdUp(ctx context.Context, analytics3 *analytics.TiltAnalytics, cmdTags
 	}
 	liveupdateReconciler := liveupdate.NewReconciler(storeStore, dockerUpdater, execUpdater, updateMode, kubeContext, deferredClient, scheme)
 	configmapReconciler := configmap.NewReconciler(deferredClient, storeStore)
-	v := controllers.ProvideControllers(controller, cmdController, podlogstreamController, reconciler, kubernetesapplyReconciler, uisessionReconciler, uiresourceReconciler, uibuttonReconciler, portforwardReconciler, tiltfileReconciler, togglebuttonReconciler, extensionReconciler, extensionrepoReconciler, liveupdateReconciler, configmapReconciler)
+	buildClock := build.ProvideClock()
+	clusterName := k8s.ProvideClusterName(ctx, apiConfig)
+	kindLoader := buildcontrol.NewKINDLoader(k8sEnv, clusterName)
+	dockerimageReconciler := dockerimage.NewReconciler(deferredClient, storeStore, scheme, dockerBuilder, buildClock, client, k8sEnv, kubeContext, kindLoader)
+	v := controllers.ProvideControllers(controller, cmdController, podlogstreamController, reconciler, kubernetesapplyReconciler, uisessionReconciler, uiresourceReconciler, uibuttonReconciler, portforwardReconciler, tiltfileReconciler, togglebuttonReconciler, extensionReconciler, extensionrepoReconciler, liveupdateReconciler, configmapReconciler, dockerimageReconciler)
 	controllerBuilder := controllers.NewControllerBuilder(tiltServerControllerManager, v)
 	v2 := provideClock()
 	renderer := hud.NewRenderer(v2)
 	openURL := _wireOpenURLValue
-	headsUpDisplay := hud.NewHud(renderer, webURL, analytics3, openURL)
+	headsUpDisplay := hud.NewHud(renderer, webURL, analytics3, openURL, deferredClient)
 	stdout := hud.ProvideStdout()
 	incrementalPrinter := hud.NewIncrementalPrinter(stdout)
 	terminalStream := hud.NewTerminalStream(incrementalPrinter, storeStore)
 	openInput := _wireOpenInputValue
 	terminalPrompt := prompt.NewTerminalPrompt(analytics3, openInput, openURL, stdout, webHost, webURL)
 	serviceWatcher := k8swatch.NewServiceWatcher(client, ownerFetcher, namespace)
-	buildClock := build.ProvideClock()
 	liveUpdateBuildAndDeployer := buildcontrol.NewLiveUpdateBuildAndDeployer(liveupdateReconciler, buildClock)
 	execCustomBuilder := build.NewExecCustomBuilder(switchCli, buildClock)
-	clusterName := k8s.ProvideClusterName(ctx, apiConfig)
-	kindLoader := buildcontrol.NewKINDLoader(k8sEnv, clusterName)
-	imageBuildAndDeployer := buildcontrol.NewImageBuildAndDeployer(dockerBuilder, execCustomBuilder, client, k8sEnv, kubeContext, analytics3, buildClock, kindLoader, deferredClient, kubernetesapplyReconciler)
-	imageBuilder := buildcontrol.NewImageBuilder(dockerBuilder, execCustomBuilder)
+	imageDigestCache := build.NewImageDigestCache(base)
+	imageBuilder := buildcontrol.NewImageBuilder(dockerBuilder, execCustomBuilder, imageDigestCache, buildClock)
+	imageBuildAndDeployer := buildcontrol.NewImageBuildAndDeployer(dockerBuilder, imageBuilder, client, k8sEnv, kubeContext, analytics3, buildClock, kindLoader, deferredClient, kubernetesapplyReconciler)
 	dockerComposeBuildAndDeployer := buildcontrol.NewDockerComposeBuildAndDeployer(dockerComposeClient, switchCli, imageBuilder, buildClock)
 	localTargetBuildAndDeployer := buildcontrol.NewLocalTargetBuildAndDeployer(buildClock, deferredClient, cmdController)
 	buildOrder := engine.DefaultBuildOrder(liveUpdateBuildAndDeployer, imageBuildAndDeployer, dockerComposeBuildAndDeployer, localTargetBuildAndDeployer, updateMode, k8sEnv, runtime)
@@ -337,15 +344,21 @@ func wireCmdUp(ctx context.Context, analytics3 *analytics.TiltAnalytics, cmdTags
 	analyticsUpdater := analytics2.NewAnalyticsUpdater(analytics3, cmdTags, engineMode)
 	eventWatchManager := k8swatch.NewEventWatchManager(client, ownerFetcher, namespace)
 	cloudStatusManager := cloud.NewStatusManager(httpClient, clock)
-	dockerPruner := dockerprune.NewDockerPruner(switchCli)
+	registryClient := dockerprune.NewRegistryClient(switchCli)
+	dockerPruner := dockerprune.NewDockerPruner(switchCli, registryClient)
 	telemetryController := telemetry.NewController(buildClock, spanCollector)
 	serverController := local.NewServerController(deferredClient)
 	podMonitor := k8srollout.NewPodMonitor()
-	sessionController := session.NewController(deferredClient, engineMode)
+	sessionCISpec := provideSessionCISpec()
+	sessionController := session.NewController(deferredClient, engineMode, sessionCISpec)
 	subscriber := uisession2.NewSubscriber(deferredClient)
 	uiresourceSubscriber := uiresource2.NewSubscriber(deferredClient)
-	v3 := engine.ProvideSubscribers(headsUpServerController, tiltServerControllerManager, controllerBuilder, headsUpDisplay, terminalStream, terminalPrompt, serviceWatcher, buildController, configsController, triggerQueueSubscriber, eventWatcher, dockerComposeLogManager, analyticsReporter, analyticsUpdater, eventWatchManager, cloudStatusManager, dockerPruner, telemetryController, serverController, podMonitor, sessionController, subscriber, uiresourceSubscriber)
-	upper, err := engine.NewUpper(ctx, storeStore, v3)
+	v3 := engine.ProvideSubscribers(headsUpServerController, tiltServerControllerManager, controllerBuilder, headsUpDisplay, terminalStream, terminalPrompt, serviceWatcher, buildController, configsController, triggerQueueSubscriber, eventWatcher, dockerComposeLogManager, analyticsReporter, analyticsUpdater, eventWatchManager, cloudStatusManager, dockerPruner, telemetryController, serverController, podMonitor, sessionController, subscriber, uiresourceSubscriber, liveupdateReconciler)
+	history, err := provideLogHistory()
+	if err != nil {
+		return CmdUpDeps{}, err
+	}
+	upper, err := engine.NewUpper(ctx, storeStore, v3, history)
 	if err != nil {
 		return CmdUpDeps{}, err
 	}
@@ -491,7 +504,7 @@ func wireCmdCI(ctx context.Context, analytics3 *analytics.TiltAnalytics, subcomm
 	configPlugin := config.NewPlugin(subcommand)
 	dockerComposeClient := dockercompose.NewDockerComposeClient(localEnv)
 	defaults := _wireDefaultsValue
-	tiltfileLoader := tiltfile.ProvideTiltfileLoader(analytics3, client, plugin, versionPlugin, configPlugin, dockerComposeClient, webHost, processExecer, defaults, k8sEnv)
+	tiltfileLoader := tiltfile.ProvideTiltfileLoader(analytics3, client, plugin, versionPlugin, configPlugin, dockerComposeClient, webHost, processExecer, defaults, k8sEnv, base)
 	buildSource := tiltfile2.NewBuildSource()
 	engineMode := _wireStoreEngineModeValue
 	tiltfileReconciler := tiltfile2.NewReconciler(storeStore, tiltfileLoader, switchCli, deferredClient, scheme, buildSource, engineMode)
@@ -501,8 +514,9 @@ func wireCmdCI(ctx context.Context, analytics3 *analytics.TiltAnalytics, subcomm
 	if err != nil {
 		return CmdCIDeps{}, err
 	}
-	dockerUpdater := containerupdate.NewDockerUpdater(switchCli)
-	execUpdater := containerupdate.NewExecUpdater(client)
+	helperImage := provideLiveUpdateHelperImage()
+	dockerUpdater := containerupdate.NewDockerUpdater(switchCli, helperImage)
+	execUpdater := containerupdate.NewExecUpdater(client, helperImage)
 	liveupdatesUpdateModeFlag := provideUpdateModeFlag()
 	updateMode, err := liveupdates.ProvideUpdateMode(liveupdatesUpdateModeFlag, kubeContext, clusterEnv)
 	if err != nil {
@@ -510,25 +524,27 @@ func wireCmdCI(ctx context.Context, analytics3 *analytics.TiltAnalytics, subcomm
 	}
 	liveupdateReconciler := liveupdate.NewReconciler(storeStore, dockerUpdater, execUpdater, updateMode, kubeContext, deferredClient, scheme)
 	configmapReconciler := configmap.NewReconciler(deferredClient, storeStore)
-	v := controllers.ProvideControllers(controller, cmdController, podlogstreamController, reconciler, kubernetesapplyReconciler, uisessionReconciler, uiresourceReconciler, uibuttonReconciler, portforwardReconciler, tiltfileReconciler, togglebuttonReconciler, extensionReconciler, extensionrepoReconciler, liveupdateReconciler, configmapReconciler)
+	buildClock := build.ProvideClock()
+	clusterName := k8s.ProvideClusterName(ctx, apiConfig)
+	kindLoader := buildcontrol.NewKINDLoader(k8sEnv, clusterName)
+	dockerimageReconciler := dockerimage.NewReconciler(deferredClient, storeStore, scheme, dockerBuilder, buildClock, client, k8sEnv, kubeContext, kindLoader)
+	v := controllers.ProvideControllers(controller, cmdController, podlogstreamController, reconciler, kubernetesapplyReconciler, uisessionReconciler, uiresourceReconciler, uibuttonReconciler, portforwardReconciler, tiltfileReconciler, togglebuttonReconciler, extensionReconciler, extensionrepoReconciler, liveupdateReconciler, configmapReconciler, dockerimageReconciler)
 	controllerBuilder := controllers.NewControllerBuilder(tiltServerControllerManager, v)
 	v2 := provideClock()
 	renderer := hud.NewRenderer(v2)
 	openURL := _wireOpenURLValue
-	headsUpDisplay := hud.NewHud(renderer, webURL, analytics3, openURL)
+	headsUpDisplay := hud.NewHud(renderer, webURL, analytics3, openURL, deferredClient)
 	stdout := hud.ProvideStdout()
 	incrementalPrinter := hud.NewIncrementalPrinter(stdout)
 	terminalStream := hud.NewTerminalStream(incrementalPrinter, storeStore)
 	openInput := _wireOpenInputValue
 	terminalPrompt := prompt.NewTerminalPrompt(analytics3, openInput, openURL, stdout, webHost, webURL)
 	serviceWatcher := k8swatch.NewServiceWatcher(client, ownerFetcher, namespace)
-	buildClock := build.ProvideClock()
 	liveUpdateBuildAndDeployer := buildcontrol.NewLiveUpdateBuildAndDeployer(liveupdateReconciler, buildClock)
 	execCustomBuilder := build.NewExecCustomBuilder(switchCli, buildClock)
-	clusterName := k8s.ProvideClusterName(ctx, apiConfig)
-	kindLoader := buildcontrol.NewKINDLoader(k8sEnv, clusterName)
-	imageBuildAndDeployer := buildcontrol.NewImageBuildAndDeployer(dockerBuilder, execCustomBuilder, client, k8sEnv, kubeContext, analytics3, buildClock, kindLoader, deferredClient, kubernetesapplyReconciler)
-	imageBuilder := buildcontrol.NewImageBuilder(dockerBuilder, execCustomBuilder)
+	imageDigestCache := build.NewImageDigestCache(base)
+	imageBuilder := buildcontrol.NewImageBuilder(dockerBuilder, execCustomBuilder, imageDigestCache, buildClock)
+	imageBuildAndDeployer := buildcontrol.NewImageBuildAndDeployer(dockerBuilder, imageBuilder, client, k8sEnv, kubeContext, analytics3, buildClock, kindLoader, deferredClient, kubernetesapplyReconciler)
 	dockerComposeBuildAndDeployer := buildcontrol.NewDockerComposeBuildAndDeployer(dockerComposeClient, switchCli, imageBuilder, buildClock)
 	localTargetBuildAndDeployer := buildcontrol.NewLocalTargetBuildAndDeployer(buildClock, deferredClient, cmdController)
 	buildOrder := engine.DefaultBuildOrder(liveUpdateBuildAndDeployer, imageBuildAndDeployer, dockerComposeBuildAndDeployer, localTargetBuildAndDeployer, updateMode, k8sEnv, runtime)
@@ -545,15 +561,21 @@ func wireCmdCI(ctx context.Context, analytics3 *analytics.TiltAnalytics, subcomm
 	analyticsUpdater := analytics2.NewAnalyticsUpdater(analytics3, cmdTags, engineMode)
 	eventWatchManager := k8swatch.NewEventWatchManager(client, ownerFetcher, namespace)
 	cloudStatusManager := cloud.NewStatusManager(httpClient, clock)
-	dockerPruner := dockerprune.NewDockerPruner(switchCli)
+	registryClient := dockerprune.NewRegistryClient(switchCli)
+	dockerPruner := dockerprune.NewDockerPruner(switchCli, registryClient)
 	telemetryController := telemetry.NewController(buildClock, spanCollector)
 	serverController := local.NewServerController(deferredClient)
 	podMonitor := k8srollout.NewPodMonitor()
-	sessionController := session.NewController(deferredClient, engineMode)
+	sessionCISpec := provideSessionCISpec()
+	sessionController := session.NewController(deferredClient, engineMode, sessionCISpec)
 	subscriber := uisession2.NewSubscriber(deferredClient)
 	uiresourceSubscriber := uiresource2.NewSubscriber(deferredClient)
-	v3 := engine.ProvideSubscribers(headsUpServerController, tiltServerControllerManager, controllerBuilder, headsUpDisplay, terminalStream, terminalPrompt, serviceWatcher, buildController, configsController, triggerQueueSubscriber, eventWatcher, dockerComposeLogManager, analyticsReporter, analyticsUpdater, eventWatchManager, cloudStatusManager, dockerPruner, telemetryController, serverController, podMonitor, sessionController, subscriber, uiresourceSubscriber)
-	upper, err := engine.NewUpper(ctx, storeStore, v3)
+	v3 := engine.ProvideSubscribers(headsUpServerController, tiltServerControllerManager, controllerBuilder, headsUpDisplay, terminalStream, terminalPrompt, serviceWatcher, buildController, configsController, triggerQueueSubscriber, eventWatcher, dockerComposeLogManager, analyticsReporter, analyticsUpdater, eventWatchManager, cloudStatusManager, dockerPruner, telemetryController, serverController, podMonitor, sessionController, subscriber, uiresourceSubscriber, liveupdateReconciler)
+	history, err := provideLogHistory()
+	if err != nil {
+		return CmdCIDeps{}, err
+	}
+	upper, err := engine.NewUpper(ctx, storeStore, v3, history)
 	if err != nil {
 		return CmdCIDeps{}, err
 	}
@@ -563,11 +585,12 @@ func wireCmdCI(ctx context.Context, analytics3 *analytics.TiltAnalytics, subcomm
 	}
 	snapshotter := cloud.NewSnapshotter(storeStore, deferredClient)
 	cmdCIDeps := CmdCIDeps{
-		Upper:        upper,
-		TiltBuild:    tiltBuild,
-		Token:        tokenToken,
-		CloudAddress: address,
-		Snapshotter:  snapshotter,
+		Upper:             upper,
+		TiltBuild:         tiltBuild,
+		Token:             tokenToken,
+		CloudAddress:      address,
+		Snapshotter:       snapshotter,
+		SessionController: sessionController,
 	}
 	return cmdCIDeps, nil
 }
@@ -695,7 +718,7 @@ func wireCmdUpdog(ctx context.Context, analytics3 *analytics.TiltAnalytics, cmdT
 	configPlugin := config.NewPlugin(subcommand)
 	dockerComposeClient := dockercompose.NewDockerComposeClient(localEnv)
 	defaults := _wireDefaultsValue
-	tiltfileLoader := tiltfile.ProvideTiltfileLoader(analytics3, k8sClient, plugin, versionPlugin, configPlugin, dockerComposeClient, webHost, processExecer, defaults, k8sEnv)
+	tiltfileLoader := tiltfile.ProvideTiltfileLoader(analytics3, k8sClient, plugin, versionPlugin, configPlugin, dockerComposeClient, webHost, processExecer, defaults, k8sEnv, base)
 	buildSource := tiltfile2.NewBuildSource()
 	engineMode := _wireEngineModeValue2
 	tiltfileReconciler := tiltfile2.NewReconciler(storeStore, tiltfileLoader, switchCli, deferredClient, scheme, buildSource, engineMode)
@@ -705,8 +728,9 @@ func wireCmdUpdog(ctx context.Context, analytics3 *analytics.TiltAnalytics, cmdT
 	if err != nil {
 		return CmdUpdogDeps{}, err
 	}
-	dockerUpdater := containerupdate.NewDockerUpdater(switchCli)
-	execUpdater := containerupdate.NewExecUpdater(k8sClient)
+	helperImage := provideLiveUpdateHelperImage()
+	dockerUpdater := containerupdate.NewDockerUpdater(switchCli, helperImage)
+	execUpdater := containerupdate.NewExecUpdater(k8sClient, helperImage)
 	liveupdatesUpdateModeFlag := provideUpdateModeFlag()
 	updateMode, err := liveupdates.ProvideUpdateMode(liveupdatesUpdateModeFlag, kubeContext, clusterEnv)
 	if err != nil {
@@ -714,14 +738,22 @@ func wireCmdUpdog(ctx context.Context, analytics3 *analytics.TiltAnalytics, cmdT
 	}
 	liveupdateReconciler := liveupdate.NewReconciler(storeStore, dockerUpdater, execUpdater, updateMode, kubeContext, deferredClient, scheme)
 	configmapReconciler := configmap.NewReconciler(deferredClient, storeStore)
-	v := controllers.ProvideControllers(controller, cmdController, podlogstreamController, reconciler, kubernetesapplyReconciler, uisessionReconciler, uiresourceReconciler, uibuttonReconciler, portforwardReconciler, tiltfileReconciler, togglebuttonReconciler, extensionReconciler, extensionrepoReconciler, liveupdateReconciler, configmapReconciler)
+	buildClock := build.ProvideClock()
+	clusterName := k8s.ProvideClusterName(ctx, apiConfig)
+	kindLoader := buildcontrol.NewKINDLoader(k8sEnv, clusterName)
+	dockerimageReconciler := dockerimage.NewReconciler(deferredClient, storeStore, scheme, dockerBuilder, buildClock, k8sClient, k8sEnv, kubeContext, kindLoader)
+	v := controllers.ProvideControllers(controller, cmdController, podlogstreamController, reconciler, kubernetesapplyReconciler, uisessionReconciler, uiresourceReconciler, uibuttonReconciler, portforwardReconciler, tiltfileReconciler, togglebuttonReconciler, extensionReconciler, extensionrepoReconciler, liveupdateReconciler, configmapReconciler, dockerimageReconciler)
 	controllerBuilder := controllers.NewControllerBuilder(tiltServerControllerManager, v)
 	stdout := hud.ProvideStdout()
 	incrementalPrinter := hud.NewIncrementalPrinter(stdout)
 	terminalStream := hud.NewTerminalStream(incrementalPrinter, storeStore)
 	cliUpdogSubscriber := provideUpdogSubscriber(objects, deferredClient)
 	v2 := provideUpdogCmdSubscribers(headsUpServerController, tiltServerControllerManager, controllerBuilder, terminalStream, cliUpdogSubscriber)
-	upper, err := engine.NewUpper(ctx, storeStore, v2)
+	history, err := provideLogHistory()
+	if err != nil {
+		return CmdUpdogDeps{}, err
+	}
+	upper, err := engine.NewUpper(ctx, storeStore, v2, history)
 	if err != nil {
 		return CmdUpdogDeps{}, err
 	}
@@ -947,7 +979,8 @@ func wireDownDeps(ctx context.Context, tiltAnalytics *analytics.TiltAnalytics, s
 	localexecEnv := localexec.DefaultEnv(webPort, webHost)
 	processExecer := localexec.NewProcessExecer(localexecEnv)
 	defaults := _wireDefaultsValue
-	tiltfileLoader := tiltfile.ProvideTiltfileLoader(tiltAnalytics, k8sClient, plugin, versionPlugin, configPlugin, dockerComposeClient, webHost, processExecer, defaults, env)
+	base := xdg.NewTiltDevBase()
+	tiltfileLoader := tiltfile.ProvideTiltfileLoader(tiltAnalytics, k8sClient, plugin, versionPlugin, configPlugin, dockerComposeClient, webHost, processExecer, defaults, env, base)
 	downDeps := ProvideDownDeps(tiltfileLoader, dockerComposeClient, k8sClient, processExecer)
 	return downDeps, nil
 }
@@ -961,7 +994,7 @@ func wireLogsDeps(ctx context.Context, tiltAnalytics *analytics.TiltAnalytics, s
 	}
 	stdout := hud.ProvideStdout()
 	incrementalPrinter := hud.NewIncrementalPrinter(stdout)
-	logsDeps := ProvideLogsDeps(webURL, incrementalPrinter)
+	logsDeps := ProvideLogsDeps(webURL, incrementalPrinter, stdout)
 	return logsDeps, nil
 }
 
@@ -1035,7 +1068,10 @@ var K8sWireSet = wire.NewSet(k8s.ProvideEnv, k8s.ProvideClusterName, k8s.Provide
 	ProvideNamespaceOverride)
 
 var BaseWireSet = wire.NewSet(
-	K8sWireSet, tiltfile.WireSet, git.ProvideGitRemote, localexec.DefaultEnv, localexec.NewProcessExecer, wire.Bind(new(localexec.Execer), new(*localexec.ProcessExecer)), docker.SwitchWireSet, dockercompose.NewDockerComposeClient, clockwork.NewRealClock, engine.DeployerWireSet, engine.NewBuildController, local.NewServerController, kubernetesdiscovery.NewContainerRestartDetector, k8swatch.NewServiceWatcher, k8swatch.NewEventWatchManager, uisession2.NewSubscriber, uiresource2.NewSubscriber, configs.NewConfigsController, configs.NewTriggerQueueSubscriber, telemetry.NewController, dcwatch.NewEventWatcher, runtimelog.NewDockerComposeLogManager, cloud.WireSet, cloudurl.ProvideAddress, k8srollout.NewPodMonitor, telemetry.NewStartTracker, session.NewController, build.ProvideClock, provideClock, hud.WireSet, prompt.WireSet, wire.Value(openurl.OpenURL(openurl.BrowserOpen)), provideLogActions, store.NewStore, wire.Bind(new(store.RStore), new(*store.Store)), dockerprune.NewDockerPruner, provideTiltInfo, engine.NewUpper, analytics2.NewAnalyticsUpdater, analytics2.ProvideAnalyticsReporter, provideUpdateModeFlag, fsevent.ProvideWatcherMaker, fsevent.ProvideTimerMaker, controllers.WireSet, provideWebVersion,
+	K8sWireSet, tiltfile.WireSet, git.ProvideGitRemote, localexec.DefaultEnv, localexec.NewProcessExecer, wire.Bind(new(localexec.Execer), new(*localexec.ProcessExecer)), docker.SwitchWireSet, dockercompose.NewDockerComposeClient, clockwork.NewRealClock, engine.DeployerWireSet, engine.NewBuildController, local.NewServerController, kubernetesdiscovery.NewContainerRestartDetector, k8swatch.NewServiceWatcher, k8swatch.NewEventWatchManager, uisession2.NewSubscriber, uiresource2.NewSubscriber, configs.NewConfigsController, configs.NewTriggerQueueSubscriber, telemetry.NewController, dcwatch.NewEventWatcher, runtimelog.NewDockerComposeLogManager, cloud.WireSet, cloudurl.ProvideAddress, k8srollout.NewPodMonitor, telemetry.NewStartTracker, session.NewController, build.ProvideClock, provideClock, hud.WireSet, prompt.WireSet, wire.Value(openurl.OpenURL(openurl.BrowserOpen)), provideLogActions,
+	provideLogHistory,
+	provideSessionCISpec, store.NewStore, wire.Bind(new(store.RStore), new(*store.Store)), dockerprune.NewDockerPruner, dockerprune.NewRegistryClient, provideTiltInfo, engine.NewUpper, analytics2.NewAnalyticsUpdater, analytics2.ProvideAnalyticsReporter, provideUpdateModeFlag,
+	provideLiveUpdateHelperImage, fsevent.ProvideWatcherMaker, fsevent.ProvideTimerMaker, controllers.WireSet, provideWebVersion,
 	provideWebMode,
 	provideWebURL,
 	provideWebPort,
@@ -1060,11 +1096,12 @@ type CmdUpDeps struct {
 }
 
 type CmdCIDeps struct {
-	Upper        engine.Upper
-	TiltBuild    model.TiltBuild
-	Token        token.Token
-	CloudAddress cloudurl.Address
-	Snapshotter  *cloud.Snapshotter
+	Upper             engine.Upper
+	TiltBuild         model.TiltBuild
+	Token             token.Token
+	CloudAddress      cloudurl.Address
+	Snapshotter       *cloud.Snapshotter
+	SessionController *session.Controller
 }
 
 type CmdUpdogDeps struct {
@@ -1098,12 +1135,14 @@ func ProvideDownDeps(
 type LogsDeps struct {
 	url     model.WebURL
 	printer *hud.IncrementalPrinter
+	stdout  hud.Stdout
 }
 
-func ProvideLogsDeps(u model.WebURL, p *hud.IncrementalPrinter) LogsDeps {
+func ProvideLogsDeps(u model.WebURL, p *hud.IncrementalPrinter, stdout hud.Stdout) LogsDeps {
 	return LogsDeps{
 		url:     u,
 		printer: p,
+		stdout:  stdout,
 	}
 }
 
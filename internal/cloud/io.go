@@ -1,6 +1,7 @@
 package cloud
 
 import (
+	"bytes"
 	"context"
 	"io"
 	"os"
@@ -12,6 +13,7 @@ import (
 	"github.com/tilt-dev/tilt/internal/hud/webview"
 	"github.com/tilt-dev/tilt/internal/store"
 	"github.com/tilt-dev/tilt/pkg/logger"
+	"github.com/tilt-dev/tilt/pkg/model"
 	proto_webview "github.com/tilt-dev/tilt/pkg/webview"
 )
 
@@ -40,7 +42,25 @@ func (s *Snapshotter) WriteSnapshot(ctx context.Context, path string) {
 		return
 	}
 
-	err = WriteSnapshotTo(ctx, &proto_webview.Snapshot{View: view}, f)
+	state := s.st.RLockState()
+	secrets := model.SecretSet{}
+	secrets.AddAll(state.Secrets)
+	s.st.RUnlockState()
+
+	buf := &bytes.Buffer{}
+	err = WriteSnapshotTo(ctx, &proto_webview.Snapshot{View: view}, buf)
+	if err != nil {
+		logger.Get(ctx).Errorf("Writing snapshot to file: %v", err)
+		return
+	}
+
+	scrubbed, err := secrets.ScrubJSON(buf.Bytes())
+	if err != nil {
+		logger.Get(ctx).Errorf("Writing snapshot to file: %v", err)
+		return
+	}
+
+	_, err = f.Write(scrubbed)
 	if err != nil {
 		logger.Get(ctx).Errorf("Writing snapshot to file: %v", err)
 		return
@@ -70,7 +70,19 @@ func TestWriteSnapshotTo(t *testing.T) {
         "status": {
           "runtimeStatus": "not_applicable",
           "updateStatus": "pending",
-          "order": 1
+          "order": 1,
+          "conditions": [
+            {
+              "type": "UpToDate",
+              "status": "False",
+              "reason": "UpdatePending"
+            },
+            {
+              "type": "Ready",
+              "status": "False",
+              "reason": "UpdatePending"
+            }
+          ]
         }
       }
     ]
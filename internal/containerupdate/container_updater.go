@@ -4,11 +4,21 @@ import (
 	"context"
 	"io"
 
+	"github.com/tilt-dev/tilt/internal/container"
 	"github.com/tilt-dev/tilt/internal/store/liveupdates"
+	"github.com/tilt-dev/tilt/pkg/apis/core/v1alpha1"
 	"github.com/tilt-dev/tilt/pkg/model"
 )
 
 type ContainerUpdater interface {
 	UpdateContainer(ctx context.Context, cInfo liveupdates.Container,
 		archiveToCopy io.Reader, filesToDelete []string, cmds []model.Cmd, hotReload bool) error
+
+	// How files were last copied into the container, or empty if they
+	// haven't been copied yet.
+	FileTransport(cInfo liveupdates.Container) v1alpha1.LiveUpdateFileTransport
+
+	// Forget what we learned about a container that's no longer live-updated
+	// (e.g., because it was replaced).
+	ForgetContainer(id container.ID)
 }
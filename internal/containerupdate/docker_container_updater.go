@@ -5,7 +5,10 @@ import (
 	"context"
 	"fmt"
 	"io"
+	"io/ioutil"
+	"path"
 
+	mobycontainer "github.com/docker/docker/api/types/container"
 	"github.com/pkg/errors"
 
 	"github.com/tilt-dev/tilt/internal/build"
@@ -13,18 +16,21 @@ import (
 	"github.com/tilt-dev/tilt/internal/docker"
 	"github.com/tilt-dev/tilt/internal/k8s"
 	"github.com/tilt-dev/tilt/internal/store/liveupdates"
+	"github.com/tilt-dev/tilt/pkg/apis/core/v1alpha1"
 	"github.com/tilt-dev/tilt/pkg/logger"
 	"github.com/tilt-dev/tilt/pkg/model"
 )
 
 type DockerUpdater struct {
-	dCli docker.Client
+	dCli        docker.Client
+	helperImage HelperImage
+	transports  fileTransports
 }
 
 var _ ContainerUpdater = &DockerUpdater{}
 
-func NewDockerUpdater(dCli docker.Client) *DockerUpdater {
-	return &DockerUpdater{dCli: dCli}
+func NewDockerUpdater(dCli docker.Client, helperImage HelperImage) *DockerUpdater {
+	return &DockerUpdater{dCli: dCli, helperImage: helperImage}
 }
 
 func (cu *DockerUpdater) WillBuildToKubeContext(kctx k8s.KubeContext) bool {
@@ -35,20 +41,12 @@ func (cu *DockerUpdater) UpdateContainer(ctx context.Context, cInfo liveupdates.
 	archiveToCopy io.Reader, filesToDelete []string, cmds []model.Cmd, hotReload bool) error {
 	l := logger.Get(ctx)
 
-	err := cu.rmPathsFromContainer(ctx, cInfo.ContainerID, filesToDelete)
+	err := cu.rmPathsFromContainer(ctx, cInfo, filesToDelete)
 	if err != nil {
 		return errors.Wrap(err, "rmPathsFromContainer")
 	}
 
-	// Use `tar` to unpack the files into the container.
-	//
-	// Although docker has a copy API, it's buggy and not well-maintained
-	// (whereas the Exec API is part of the CRI and much more battle-tested).
-	// Discussion:
-	// https://github.com/tilt-dev/tilt/issues/3708
-	err = cu.dCli.ExecInContainer(ctx, cInfo.ContainerID, model.Cmd{
-		Argv: tarArgv(),
-	}, archiveToCopy, l.Writer(logger.InfoLvl))
+	err = cu.copyToContainer(ctx, cInfo, archiveToCopy)
 	if err != nil {
 		return errors.Wrap(err, "copying files")
 	}
@@ -75,14 +73,77 @@ func (cu *DockerUpdater) UpdateContainer(ctx context.Context, cInfo liveupdates.
 	return nil
 }
 
-func (cu *DockerUpdater) rmPathsFromContainer(ctx context.Context, cID container.ID, paths []string) error {
+func (cu *DockerUpdater) FileTransport(cInfo liveupdates.Container) v1alpha1.LiveUpdateFileTransport {
+	return cu.transports.get(cInfo.ContainerID)
+}
+
+func (cu *DockerUpdater) ForgetContainer(id container.ID) {
+	cu.transports.forget(id)
+}
+
+// Use `tar` to unpack the files into the container.
+//
+// Although docker has a copy API, it's buggy and not well-maintained
+// (whereas the Exec API is part of the CRI and much more battle-tested).
+// Discussion:
+// https://github.com/tilt-dev/tilt/issues/3708
+//
+// But if the container image doesn't have `tar` (e.g., distroless images),
+// the copy API is our only option.
+func (cu *DockerUpdater) copyToContainer(ctx context.Context, cInfo liveupdates.Container, archive io.Reader) error {
+	l := logger.Get(ctx)
+	cID := cInfo.ContainerID
+	transport := cu.transports.get(cID)
+	if transport == v1alpha1.LiveUpdateFileTransportDockerCopy {
+		return cu.dCli.CopyToContainerRoot(ctx, cID, archive)
+	}
+
+	var data []byte
+	if transport == "" && archive != nil {
+		// We don't know yet if the container has tar, so hold onto
+		// the archive in case we need to send it again.
+		var err error
+		data, err = ioutil.ReadAll(archive)
+		if err != nil {
+			return err
+		}
+		archive = bytes.NewReader(data)
+	}
+
+	out := bytes.NewBuffer(nil)
+	err := cu.dCli.ExecInContainer(ctx, cID, model.Cmd{
+		Argv: tarArgv(),
+	}, archive, io.MultiWriter(l.Writer(logger.InfoLvl), out))
+	if err == nil {
+		cu.transports.set(cID, v1alpha1.LiveUpdateFileTransportTar)
+		return nil
+	}
+
+	if transport != "" || !isExecutableNotFound(out, err) {
+		return err
+	}
+
+	l.Infof("Container %s doesn't have \"tar\". Falling back to the Docker copy API", cInfo.DisplayName())
+	err = cu.dCli.CopyToContainerRoot(ctx, cID, bytes.NewReader(data))
+	if err != nil {
+		return err
+	}
+	cu.transports.set(cID, v1alpha1.LiveUpdateFileTransportDockerCopy)
+	return nil
+}
+
+func (cu *DockerUpdater) rmPathsFromContainer(ctx context.Context, cInfo liveupdates.Container, paths []string) error {
 	if len(paths) == 0 {
 		return nil
 	}
 
 	out := bytes.NewBuffer(nil)
-	err := cu.dCli.ExecInContainer(ctx, cID, model.Cmd{Argv: makeRmCmd(paths)}, nil, out)
+	err := cu.dCli.ExecInContainer(ctx, cInfo.ContainerID, model.Cmd{Argv: makeRmCmd("/", paths)}, nil, out)
 	if err != nil {
+		if isExecutableNotFound(out, err) {
+			// The copy API can't delete files, so delete them from a helper container instead.
+			return cu.rmPathsWithHelper(ctx, cInfo, paths)
+		}
 		if docker.IsExitError(err) {
 			return fmt.Errorf("Error deleting files from container: %s", out.String())
 		}
@@ -91,8 +152,59 @@ func (cu *DockerUpdater) rmPathsFromContainer(ctx context.Context, cID container
 	return nil
 }
 
-func makeRmCmd(paths []string) []string {
+// Deletes files from a container that doesn't have `rm` (e.g., distroless images)
+// with a short-lived helper container that shares its PID namespace.
+//
+// Like the ExecUpdater's helper, it sees the container's filesystem through the root
+// of the container's main process, and needs SYS_PTRACE to do so.
+func (cu *DockerUpdater) rmPathsWithHelper(ctx context.Context, cInfo liveupdates.Container, paths []string) error {
+	l := logger.Get(ctx)
+	l.Infof("Container %s doesn't have \"rm\". Falling back to a helper container "+
+		"(image %s, with the SYS_PTRACE capability)", cInfo.DisplayName(), cu.helperImage)
+
+	ref, err := container.ParseNamed(string(cu.helperImage))
+	if err != nil {
+		return fmt.Errorf("Error deleting files from container: parsing helper image: %v", err)
+	}
+
+	// Only pull the helper image the first time we need it.
+	_, _, err = cu.dCli.ImageInspectWithRaw(ctx, ref.String())
+	pull := err != nil
+
+	out := bytes.NewBuffer(nil)
+	result, err := cu.dCli.Run(ctx, docker.RunConfig{
+		Image:   ref,
+		Pull:    pull,
+		Cmd:     makeRmCmd(helperRoot, paths),
+		PidMode: mobycontainer.PidMode(fmt.Sprintf("container:%s", cInfo.ContainerID)),
+		CapAdd:  []string{"SYS_PTRACE"},
+		Stdout:  out,
+		Stderr:  out,
+	})
+	if err != nil {
+		return errors.Wrap(err, "Error deleting files from container: starting helper container")
+	}
+	defer func() {
+		err := result.Close()
+		if err != nil {
+			l.Debugf("Removing helper container %s: %v", result.ContainerID, err)
+		}
+	}()
+
+	status, err := result.Wait()
+	if err != nil {
+		return errors.Wrap(err, "Error deleting files from container")
+	}
+	if status != 0 {
+		return fmt.Errorf("Error deleting files from container: %s", out.String())
+	}
+	return nil
+}
+
+func makeRmCmd(root string, paths []string) []string {
 	cmd := []string{"rm", "-rf"}
-	cmd = append(cmd, paths...)
+	for _, p := range paths {
+		cmd = append(cmd, path.Join(root, p))
+	}
 	return cmd
 }
@@ -3,6 +3,7 @@ package containerupdate
 import (
 	"bytes"
 	"context"
+	"io/ioutil"
 	"strings"
 	"testing"
 
@@ -14,6 +15,7 @@ import (
 	"github.com/tilt-dev/tilt/internal/build"
 
 	"github.com/tilt-dev/tilt/internal/docker"
+	"github.com/tilt-dev/tilt/pkg/apis/core/v1alpha1"
 	"github.com/tilt-dev/tilt/pkg/model"
 )
 
@@ -46,6 +48,68 @@ func TestUpdateInContainerCopiesAndRmsFiles(t *testing.T) {
 	}
 }
 
+func TestUpdateContainerWithoutTarUsesCopyAPI(t *testing.T) {
+	f := newDCUFixture(t)
+	f.dCli.MissingExecutables = map[string]bool{"tar": true}
+
+	err := f.dcu.UpdateContainer(f.ctx, TestContainerInfo, bytes.NewBufferString("hello world"), nil, nil, true)
+	if err != nil {
+		f.t.Fatal(err)
+	}
+
+	assert.Equal(f.t, 0, f.dCli.CopyCount)
+	assert.Equal(f.t, 1, f.dCli.CopyAPICount)
+	if assert.NotNil(f.t, f.dCli.CopyContent) {
+		content, err := ioutil.ReadAll(f.dCli.CopyContent)
+		assert.NoError(f.t, err)
+		assert.Equal(f.t, "hello world", string(content))
+	}
+	assert.Equal(f.t, v1alpha1.LiveUpdateFileTransportDockerCopy, f.dcu.FileTransport(TestContainerInfo))
+
+	// Once we know the container doesn't have tar, don't try it again.
+	f.dCli.MissingExecutables = nil
+	err = f.dcu.UpdateContainer(f.ctx, TestContainerInfo, bytes.NewBufferString("goodbye"), nil, nil, true)
+	if err != nil {
+		f.t.Fatal(err)
+	}
+	assert.Equal(f.t, 0, f.dCli.CopyCount)
+	assert.Equal(f.t, 2, f.dCli.CopyAPICount)
+}
+
+func TestUpdateContainerWithoutRmRunsHelperContainer(t *testing.T) {
+	f := newDCUFixture(t)
+	f.dCli.MissingExecutables = map[string]bool{"rm": true}
+
+	err := f.dcu.UpdateContainer(f.ctx, TestContainerInfo, bytes.NewBufferString("hello world"),
+		[]string{"/src/deleted"}, nil, true)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if assert.Len(f.t, f.dCli.RunConfigs, 1) {
+		run := f.dCli.RunConfigs[0]
+		assert.Equal(f.t, "docker.io/my-registry/busybox", run.Image.String())
+		assert.True(f.t, run.Pull)
+		assert.Equal(f.t, []string{"rm", "-rf", "/proc/1/root/src/deleted"}, run.Cmd)
+		assert.Equal(f.t, "container:"+string(docker.TestContainer), string(run.PidMode))
+		assert.Equal(f.t, []string{"SYS_PTRACE"}, run.CapAdd)
+	}
+	assert.Equal(f.t, 1, f.dCli.CopyCount)
+}
+
+func TestUpdateContainerWithoutRmHelperContainerFails(t *testing.T) {
+	f := newDCUFixture(t)
+	f.dCli.MissingExecutables = map[string]bool{"rm": true}
+	f.dCli.RunExitCode = 1
+
+	err := f.dcu.UpdateContainer(f.ctx, TestContainerInfo, bytes.NewBufferString("hello world"),
+		[]string{"/src/deleted"}, nil, true)
+	if assert.Error(f.t, err) {
+		assert.Contains(f.t, err.Error(), "Error deleting files from container")
+	}
+	assert.Equal(f.t, 0, f.dCli.CopyCount)
+}
+
 func TestUpdateContainerExecsRuns(t *testing.T) {
 	f := newDCUFixture(t)
 
@@ -115,7 +179,7 @@ type dockerContainerUpdaterFixture struct {
 
 func newDCUFixture(t testing.TB) *dockerContainerUpdaterFixture {
 	fakeCli := docker.NewFakeClient()
-	cu := &DockerUpdater{dCli: fakeCli}
+	cu := NewDockerUpdater(fakeCli, "my-registry/busybox")
 	ctx, _, _ := testutils.CtxAndAnalyticsForTest()
 
 	return &dockerContainerUpdaterFixture{
@@ -5,23 +5,45 @@ import (
 	"context"
 	"fmt"
 	"io"
+	"io/ioutil"
+	"path"
 	"strings"
 
+	v1 "k8s.io/api/core/v1"
+
 	"github.com/tilt-dev/tilt/internal/build"
+	"github.com/tilt-dev/tilt/internal/container"
 	"github.com/tilt-dev/tilt/internal/k8s"
 	"github.com/tilt-dev/tilt/internal/store/liveupdates"
+	"github.com/tilt-dev/tilt/pkg/apis/core/v1alpha1"
 	"github.com/tilt-dev/tilt/pkg/logger"
 	"github.com/tilt-dev/tilt/pkg/model"
 )
 
+// If the container doesn't have tar, we copy files with a helper container
+// that shares its process namespace. The helper sees the container's
+// filesystem through the root of the container's main process.
+const helperRoot = "/proc/1/root"
+
+// The image of the helper container. Must have `tar`, `rm`, and `sleep`.
+// Docker containers only use it to delete files, since the copy API can copy without tar.
+//
+// Set with `tilt up --live-update-helper-image`, e.g., for clusters
+// that can only pull from a private registry.
+type HelperImage string
+
+const DefaultHelperImage HelperImage = "busybox:1.34"
+
 type ExecUpdater struct {
-	kCli k8s.Client
+	kCli        k8s.Client
+	helperImage HelperImage
+	transports  fileTransports
 }
 
 var _ ContainerUpdater = &ExecUpdater{}
 
-func NewExecUpdater(kCli k8s.Client) *ExecUpdater {
-	return &ExecUpdater{kCli: kCli}
+func NewExecUpdater(kCli k8s.Client, helperImage HelperImage) *ExecUpdater {
+	return &ExecUpdater{kCli: kCli, helperImage: helperImage}
 }
 
 func (cu *ExecUpdater) UpdateContainer(ctx context.Context, cInfo liveupdates.Container,
@@ -37,23 +59,23 @@ func (cu *ExecUpdater) UpdateContainer(ctx context.Context, cInfo liveupdates.Co
 
 	// delete files (if any)
 	if len(filesToDelete) > 0 {
-		buf := bytes.NewBuffer(nil)
-		rmWriter := io.MultiWriter(w, buf)
-		err := cu.kCli.Exec(ctx,
-			cInfo.PodID, cInfo.ContainerName, cInfo.Namespace,
-			append([]string{"rm", "-rf"}, filesToDelete...), nil, rmWriter, rmWriter)
+		rmArgv := func(root string) []string {
+			argv := []string{"rm", "-rf"}
+			for _, f := range filesToDelete {
+				argv = append(argv, path.Join(root, f))
+			}
+			return argv
+		}
+		err := cu.execFileCmd(ctx, cInfo, rmArgv, nil, false)
 		if err != nil {
-			return fmt.Errorf("removing old files: %v", handleK8sExecError(buf, err))
+			return fmt.Errorf("removing old files: %v", err)
 		}
 	}
 
 	// copy files to container
-	buf := bytes.NewBuffer(nil)
-	tarWriter := io.MultiWriter(w, buf)
-	err := cu.kCli.Exec(ctx, cInfo.PodID, cInfo.ContainerName, cInfo.Namespace,
-		tarArgv(), archiveToCopy, tarWriter, tarWriter)
+	err := cu.execFileCmd(ctx, cInfo, tarArgvAtRoot, archiveToCopy, true)
 	if err != nil {
-		return fmt.Errorf("copying changed files: %v", handleK8sExecError(buf, err))
+		return fmt.Errorf("copying changed files: %v", err)
 	}
 
 	// run commands
@@ -70,6 +92,113 @@ func (cu *ExecUpdater) UpdateContainer(ctx context.Context, cInfo liveupdates.Co
 	return nil
 }
 
+func (cu *ExecUpdater) FileTransport(cInfo liveupdates.Container) v1alpha1.LiveUpdateFileTransport {
+	return cu.transports.get(cInfo.ContainerID)
+}
+
+func (cu *ExecUpdater) ForgetContainer(id container.ID) {
+	cu.transports.forget(id)
+}
+
+// Runs a command that modifies the container filesystem (like tar or rm).
+//
+// If the container image doesn't have the command, runs it in an ephemeral
+// helper container instead. Only the copy step records which transport worked,
+// because an image can have rm without tar. Once the copy needs the helper,
+// every later command goes straight to it.
+func (cu *ExecUpdater) execFileCmd(ctx context.Context, cInfo liveupdates.Container,
+	argv func(root string) []string, stdin io.Reader, isCopy bool) error {
+	w := logger.Get(ctx).Writer(logger.InfoLvl)
+	transport := cu.transports.get(cInfo.ContainerID)
+
+	if transport != v1alpha1.LiveUpdateFileTransportEphemeralContainer {
+		var retryStdin func() io.Reader
+		if transport == "" && stdin != nil {
+			// We don't know yet if the container has the command, so hold onto
+			// the input in case we need to send it again.
+			data, err := ioutil.ReadAll(stdin)
+			if err != nil {
+				return err
+			}
+			stdin = bytes.NewReader(data)
+			retryStdin = func() io.Reader { return bytes.NewReader(data) }
+		}
+
+		buf := bytes.NewBuffer(nil)
+		out := io.MultiWriter(w, buf)
+		err := cu.kCli.Exec(ctx, cInfo.PodID, cInfo.ContainerName, cInfo.Namespace,
+			argv("/"), stdin, out, out)
+		if err == nil {
+			if isCopy {
+				cu.transports.set(cInfo.ContainerID, v1alpha1.LiveUpdateFileTransportTar)
+			}
+			return nil
+		}
+
+		// If tar has worked in this container before, it didn't go missing.
+		if (isCopy && transport != "") || !isExecutableNotFound(buf, err) {
+			return handleK8sExecError(buf, err)
+		}
+
+		logger.Get(ctx).Infof("Container %s doesn't have %q. Falling back to a helper container "+
+			"(image %s, with the SYS_PTRACE capability)", cInfo.DisplayName(), argv("/")[0], cu.helperImage)
+		stdin = nil
+		if retryStdin != nil {
+			stdin = retryStdin()
+		}
+	}
+
+	helper := helperContainer(cInfo.ContainerName, cu.helperImage)
+	err := cu.kCli.EnsureEphemeralContainer(ctx, cInfo.PodID, cInfo.Namespace, helper)
+	if err != nil {
+		return fmt.Errorf("starting helper container: %v\n"+
+			"Copying files to containers without tar requires a cluster that supports ephemeral containers, "+
+			"and allows them to add the SYS_PTRACE capability (the Pod Security 'baseline' and 'restricted' "+
+			"policies don't). Alternatively, add tar to the container image", err)
+	}
+	if isCopy {
+		cu.transports.set(cInfo.ContainerID, v1alpha1.LiveUpdateFileTransportEphemeralContainer)
+	}
+
+	buf := bytes.NewBuffer(nil)
+	out := io.MultiWriter(w, buf)
+	err = cu.kCli.Exec(ctx, cInfo.PodID, container.Name(helper.Name), cInfo.Namespace,
+		argv(helperRoot), stdin, out, out)
+	if err != nil {
+		return handleK8sExecError(buf, err)
+	}
+	return nil
+}
+
+// An ephemeral container with tar that can see the filesystem of the given container.
+//
+// The helper only gets one capability beyond the runtime's defaults: SYS_PTRACE.
+// The kernel requires it to read /proc/1/root of a process owned by another user
+// (the helper runs as the image's default user, which is usually root, while the
+// target often isn't). The helper doesn't run anything besides sleep, and the
+// tar and rm commands that Tilt execs in it. Ephemeral containers can't be
+// removed, so the helper lives as long as the pod.
+func helperContainer(target container.Name, image HelperImage) v1.EphemeralContainer {
+	name := fmt.Sprintf("tilt-sync-%s", target)
+	if len(name) > 63 {
+		name = name[:63]
+	}
+	return v1.EphemeralContainer{
+		EphemeralContainerCommon: v1.EphemeralContainerCommon{
+			Name:    name,
+			Image:   string(image),
+			Command: []string{"sleep", "2147483647"},
+			SecurityContext: &v1.SecurityContext{
+				// Needed to access the filesystem of a process owned by another user (see above).
+				Capabilities: &v1.Capabilities{
+					Add: []v1.Capability{"SYS_PTRACE"},
+				},
+			},
+		},
+		TargetContainerName: target.String(),
+	}
+}
+
 func handleK8sExecError(out *bytes.Buffer, err error) error {
 	msg := strings.ToLower(fmt.Sprintf("%s\n%s", out.String(), err.Error()))
 	if strings.Contains(msg, "permission denied") || strings.Contains(msg, "cannot open") {
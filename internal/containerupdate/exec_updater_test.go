@@ -16,6 +16,7 @@ import (
 
 	"github.com/tilt-dev/tilt/internal/k8s"
 	"github.com/tilt-dev/tilt/internal/testutils"
+	"github.com/tilt-dev/tilt/pkg/apis/core/v1alpha1"
 	"github.com/tilt-dev/tilt/pkg/model"
 )
 
@@ -131,6 +132,112 @@ func TestUpdateContainerPermissionDenied(t *testing.T) {
 	assert.Equal(t, 1, len(f.kCli.ExecCalls))
 }
 
+func TestUpdateContainerWithoutTarUsesHelperContainer(t *testing.T) {
+	f := newExecFixture(t)
+
+	f.kCli.ExecOutputs = []io.Reader{strings.NewReader(
+		`OCI runtime exec failed: exec failed: container_linux.go:380: starting container process caused: exec: "tar": executable file not found in $PATH: unknown`)}
+	f.kCli.ExecErrors = []error{exec.CodeExitError{Err: fmt.Errorf("command terminated with exit code 126"), Code: 126}}
+
+	err := f.ecu.UpdateContainer(f.ctx, TestContainerInfo, newReader("hello world"), nil, nil, true)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if assert.Len(t, f.kCli.EphemeralContainers, 1) {
+		call := f.kCli.EphemeralContainers[0]
+		assert.Equal(t, TestContainerInfo.PodID, call.PID)
+		assert.Equal(t, "my-container", call.Container.TargetContainerName)
+		assert.Equal(t, "tilt-sync-my-container", call.Container.Name)
+		assert.Equal(t, "my-registry/busybox", call.Container.Image)
+	}
+
+	if assert.Len(t, f.kCli.ExecCalls, 2) {
+		call := f.kCli.ExecCalls[1]
+		assert.Equal(t, "tilt-sync-my-container", call.CName.String())
+		assert.Equal(t, []string{"tar", "-C", "/proc/1/root", "-x", "-f", "-"}, call.Cmd)
+		assert.Equal(t, []byte("hello world"), call.Stdin)
+	}
+	assert.Equal(t, v1alpha1.LiveUpdateFileTransportEphemeralContainer, f.ecu.FileTransport(TestContainerInfo))
+
+	// Subsequent updates go straight to the helper.
+	err = f.ecu.UpdateContainer(f.ctx, TestContainerInfo, newReader("goodbye"), toDelete, nil, true)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if assert.Len(t, f.kCli.ExecCalls, 4) {
+		assert.Equal(t, []string{"rm", "-rf", "/proc/1/root/foo/delete_me", "/proc/1/root/bar/me_too"},
+			f.kCli.ExecCalls[2].Cmd)
+		assert.Equal(t, "tilt-sync-my-container", f.kCli.ExecCalls[2].CName.String())
+		assert.Equal(t, []byte("goodbye"), f.kCli.ExecCalls[3].Stdin)
+		assert.Equal(t, "tilt-sync-my-container", f.kCli.ExecCalls[3].CName.String())
+	}
+}
+
+func TestUpdateContainerWithRmButWithoutTarUsesHelperContainer(t *testing.T) {
+	f := newExecFixture(t)
+
+	// rm succeeds, but tar is missing.
+	f.kCli.ExecOutputs = []io.Reader{strings.NewReader(""), strings.NewReader(
+		`OCI runtime exec failed: exec failed: container_linux.go:380: starting container process caused: exec: "tar": executable file not found in $PATH: unknown`)}
+	f.kCli.ExecErrors = []error{nil, exec.CodeExitError{Err: fmt.Errorf("command terminated with exit code 126"), Code: 126}}
+
+	err := f.ecu.UpdateContainer(f.ctx, TestContainerInfo, newReader("hello world"), toDelete, nil, true)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	assert.Len(t, f.kCli.EphemeralContainers, 1)
+	if assert.Len(t, f.kCli.ExecCalls, 3) {
+		assert.Equal(t, "rm", f.kCli.ExecCalls[0].Cmd[0])
+		assert.Equal(t, "my-container", f.kCli.ExecCalls[0].CName.String())
+		assert.Equal(t, "tar", f.kCli.ExecCalls[1].Cmd[0])
+		assert.Equal(t, "my-container", f.kCli.ExecCalls[1].CName.String())
+		assert.Equal(t, "tilt-sync-my-container", f.kCli.ExecCalls[2].CName.String())
+		assert.Equal(t, []byte("hello world"), f.kCli.ExecCalls[2].Stdin)
+	}
+	assert.Equal(t, v1alpha1.LiveUpdateFileTransportEphemeralContainer, f.ecu.FileTransport(TestContainerInfo))
+}
+
+func TestUpdateContainerWithoutRmUsesHelperContainer(t *testing.T) {
+	f := newExecFixture(t)
+
+	// rm is missing, but tar works.
+	f.kCli.ExecOutputs = []io.Reader{strings.NewReader(
+		`OCI runtime exec failed: exec failed: container_linux.go:380: starting container process caused: exec: "rm": executable file not found in $PATH: unknown`)}
+	f.kCli.ExecErrors = []error{exec.CodeExitError{Err: fmt.Errorf("command terminated with exit code 126"), Code: 126}}
+
+	err := f.ecu.UpdateContainer(f.ctx, TestContainerInfo, newReader("hello world"), toDelete, nil, true)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if assert.Len(t, f.kCli.ExecCalls, 3) {
+		assert.Equal(t, []string{"rm", "-rf", "/proc/1/root/foo/delete_me", "/proc/1/root/bar/me_too"},
+			f.kCli.ExecCalls[1].Cmd)
+		assert.Equal(t, "tilt-sync-my-container", f.kCli.ExecCalls[1].CName.String())
+		assert.Equal(t, "tar", f.kCli.ExecCalls[2].Cmd[0])
+		assert.Equal(t, "my-container", f.kCli.ExecCalls[2].CName.String())
+	}
+	assert.Equal(t, v1alpha1.LiveUpdateFileTransportTar, f.ecu.FileTransport(TestContainerInfo))
+}
+
+func TestUpdateContainerWithTarReportsTransport(t *testing.T) {
+	f := newExecFixture(t)
+
+	assert.Equal(t, v1alpha1.LiveUpdateFileTransport(""), f.ecu.FileTransport(TestContainerInfo))
+	err := f.ecu.UpdateContainer(f.ctx, TestContainerInfo, newReader("hello world"), nil, nil, true)
+	if err != nil {
+		t.Fatal(err)
+	}
+	assert.Equal(t, v1alpha1.LiveUpdateFileTransportTar, f.ecu.FileTransport(TestContainerInfo))
+	assert.Len(t, f.kCli.EphemeralContainers, 0)
+
+	f.ecu.ForgetContainer(TestContainerInfo.ContainerID)
+	assert.Equal(t, v1alpha1.LiveUpdateFileTransport(""), f.ecu.FileTransport(TestContainerInfo))
+}
+
 type execUpdaterFixture struct {
 	t    testing.TB
 	ctx  context.Context
@@ -140,9 +247,7 @@ type execUpdaterFixture struct {
 
 func newExecFixture(t testing.TB) *execUpdaterFixture {
 	fakeCli := k8s.NewFakeK8sClient(t)
-	cu := &ExecUpdater{
-		kCli: fakeCli,
-	}
+	cu := NewExecUpdater(fakeCli, "my-registry/busybox")
 	ctx, _, _ := testutils.CtxAndAnalyticsForTest()
 
 	return &execUpdaterFixture{
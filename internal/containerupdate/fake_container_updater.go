@@ -4,7 +4,9 @@ import (
 	"context"
 	"io"
 
+	"github.com/tilt-dev/tilt/internal/container"
 	"github.com/tilt-dev/tilt/internal/store/liveupdates"
+	"github.com/tilt-dev/tilt/pkg/apis/core/v1alpha1"
 	"github.com/tilt-dev/tilt/pkg/model"
 )
 
@@ -45,3 +47,10 @@ func (cu *FakeContainerUpdater) UpdateContainer(ctx context.Context, cInfo liveu
 	}
 	return err
 }
+
+func (cu *FakeContainerUpdater) FileTransport(cInfo liveupdates.Container) v1alpha1.LiveUpdateFileTransport {
+	return ""
+}
+
+func (cu *FakeContainerUpdater) ForgetContainer(id container.ID) {
+}
@@ -1,5 +1,55 @@
 package containerupdate
 
+import (
+	"bytes"
+	"fmt"
+	"strings"
+	"sync"
+
+	"github.com/tilt-dev/tilt/internal/container"
+	"github.com/tilt-dev/tilt/pkg/apis/core/v1alpha1"
+)
+
 func tarArgv() []string {
-	return []string{"tar", "-C", "/", "-x", "-f", "-"}
+	return tarArgvAtRoot("/")
+}
+
+func tarArgvAtRoot(root string) []string {
+	return []string{"tar", "-C", root, "-x", "-f", "-"}
+}
+
+// Returns true if an exec failed because the binary isn't in the container
+// image (e.g., distroless and scratch images don't have tar or rm).
+func isExecutableNotFound(out *bytes.Buffer, err error) bool {
+	msg := strings.ToLower(fmt.Sprintf("%s\n%s", out.String(), err.Error()))
+	return strings.Contains(msg, "executable file not found") ||
+		(strings.Contains(msg, "exec:") && strings.Contains(msg, "no such file or directory"))
+}
+
+// Remembers how we copied files into each container, so that we only
+// need to detect a missing tar binary once.
+type fileTransports struct {
+	mu          sync.Mutex
+	byContainer map[container.ID]v1alpha1.LiveUpdateFileTransport
+}
+
+func (t *fileTransports) get(id container.ID) v1alpha1.LiveUpdateFileTransport {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	return t.byContainer[id]
+}
+
+func (t *fileTransports) set(id container.ID, transport v1alpha1.LiveUpdateFileTransport) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	if t.byContainer == nil {
+		t.byContainer = make(map[container.ID]v1alpha1.LiveUpdateFileTransport)
+	}
+	t.byContainer[id] = transport
+}
+
+func (t *fileTransports) forget(id container.ID) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	delete(t.byContainer, id)
 }
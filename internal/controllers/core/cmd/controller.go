@@ -83,11 +83,17 @@ func NewController(ctx context.Context, execer Execer, proberManager ProberManag
 	}
 }
 
+// Restarts from a RestartPolicy are delayed by an exponential backoff,
+// starting at restartBackoffInitial and capped at restartBackoffMax.
+//
+// If the command stays up longer than restartBackoffMax, the backoff resets.
+const restartBackoffInitial = time.Second
+const restartBackoffMax = 5 * time.Minute
+
 func (c *Controller) Reconcile(ctx context.Context, req ctrl.Request) (ctrl.Result, error) {
 	c.reconcileMu.Lock()
 	defer c.reconcileMu.Unlock()
-	err := c.reconcile(ctx, req.NamespacedName)
-	return ctrl.Result{}, err
+	return c.reconcile(ctx, req.NamespacedName)
 }
 
 // Stop the command, and wait for it to finish before continuing.
@@ -150,23 +156,23 @@ func (c *Controller) lastRestartEvent(restartOn *RestartOnSpec, restartObjs rest
 	return cur, inputs
 }
 
-func (c *Controller) reconcile(ctx context.Context, name types.NamespacedName) error {
+func (c *Controller) reconcile(ctx context.Context, name types.NamespacedName) (ctrl.Result, error) {
 	cmd := &Cmd{}
 	err := c.client.Get(ctx, name, cmd)
 	c.indexer.OnReconcile(name, cmd)
 	if err != nil && !apierrors.IsNotFound(err) {
-		return fmt.Errorf("cmd reconcile: %v", err)
+		return ctrl.Result{}, fmt.Errorf("cmd reconcile: %v", err)
 	}
 
 	if apierrors.IsNotFound(err) || cmd.ObjectMeta.DeletionTimestamp != nil {
 		c.stop(name)
 		delete(c.procs, name)
-		return nil
+		return ctrl.Result{}, nil
 	}
 
 	disableStatus, err := configmap.MaybeNewDisableStatus(ctx, c.client, cmd.Spec.DisableSource, cmd.Status.DisableStatus)
 	if err != nil {
-		return err
+		return ctrl.Result{}, err
 	}
 
 	if disableStatus != cmd.Status.DisableStatus {
@@ -180,19 +186,19 @@ func (c *Controller) reconcile(ctx context.Context, name types.NamespacedName) e
 	if disableStatus.Disabled {
 		c.stop(name)
 		delete(c.procs, name)
-		return nil
+		return ctrl.Result{}, nil
 	}
 
 	if cmd.Annotations[v1alpha1.AnnotationManagedBy] == "local_resource" {
 		// Until resource dependencies are expressed in the API,
 		// we can't use reconciliation to deploy Cmd objects
 		// that are part of local_resource.
-		return nil
+		return ctrl.Result{}, nil
 	}
 
 	restartObjs, err := restarton.FetchObjects(ctx, c.client, cmd.Spec.RestartOn, cmd.Spec.StartOn)
 	if err != nil {
-		return err
+		return ctrl.Result{}, err
 	}
 
 	lastRestartEventTime, _ := c.lastRestartEvent(cmd.Spec.RestartOn, restartObjs)
@@ -227,10 +233,61 @@ func (c *Controller) reconcile(ctx context.Context, name types.NamespacedName) e
 	} else if execSpecChanged || restartOnTriggered || startOnTriggered {
 		// Otherwise, any change, new start event, or new restart event
 		// should restart the process to pick up changes.
-		_ = c.runInternal(ctx, cmd, restartObjs)
+		_ = c.runInternal(ctx, cmd, restartObjs, 0)
+	} else if hasExistingProc {
+		// If the process exited on its own, the restart policy decides
+		// whether to bring it back.
+		return c.maybeRestart(ctx, cmd, proc, restartObjs), nil
+	}
+
+	return ctrl.Result{}, nil
+}
+
+// Restarts the command if it exited on its own and its RestartPolicy says so.
+//
+// If the restart is waiting on a backoff, returns a result that requeues
+// the Cmd when the backoff expires.
+func (c *Controller) maybeRestart(ctx context.Context, cmd *Cmd, proc *currentProcess, restartObjs restarton.Objects) ctrl.Result {
+	exit := proc.currentExit()
+	if exit == nil || !shouldRestart(cmd.Spec.RestartPolicy, exit.code) {
+		return ctrl.Result{}
+	}
+
+	ctx = store.MustObjectLogHandler(ctx, c.st, cmd)
+	if cmd.Spec.MaxRestarts > 0 && proc.restarts >= cmd.Spec.MaxRestarts {
+		if !exit.announced {
+			exit.announced = true
+			logger.Get(ctx).Errorf("Not restarting cmd %s: already restarted %d times",
+				model.Cmd{Argv: cmd.Spec.Args}, proc.restarts)
+		}
+		return ctrl.Result{}
 	}
 
-	return nil
+	backoff := proc.nextBackoff(exit)
+	restartAt := exit.finishedAt.Add(backoff)
+	now := c.clock.Now()
+	if now.Before(restartAt) {
+		if !exit.announced {
+			exit.announced = true
+			logger.Get(ctx).Infof("Restarting cmd %s in %s (restart policy: %s)",
+				model.Cmd{Argv: cmd.Spec.Args}, backoff, cmd.Spec.RestartPolicy)
+		}
+		return ctrl.Result{RequeueAfter: restartAt.Sub(now)}
+	}
+
+	proc.backoff = backoff
+	_ = c.runInternal(ctx, cmd, restartObjs, proc.restarts+1)
+	return ctrl.Result{}
+}
+
+func shouldRestart(policy v1alpha1.CmdRestartPolicy, exitCode int) bool {
+	switch policy {
+	case v1alpha1.CmdRestartPolicyAlways:
+		return true
+	case v1alpha1.CmdRestartPolicyOnFailure:
+		return exitCode != 0
+	}
+	return false
 }
 
 // Forces the command to run now.
@@ -241,7 +298,7 @@ func (c *Controller) reconcile(ctx context.Context, name types.NamespacedName) e
 // Blocks until the command is finished, then returns its status.
 func (c *Controller) ForceRun(ctx context.Context, cmd *v1alpha1.Cmd) (*v1alpha1.CmdStatus, error) {
 	c.reconcileMu.Lock()
-	doneCh := c.runInternal(ctx, cmd, restarton.Objects{})
+	doneCh := c.runInternal(ctx, cmd, restarton.Objects{}, 0)
 	c.reconcileMu.Unlock()
 
 	select {
@@ -294,10 +351,14 @@ type input struct {
 // The filewatches and buttons are needed for bookkeeping on how the command
 // was triggered.
 //
+// restarts is the number of times the command has been restarted by its
+// RestartPolicy, or 0 if this is a fresh start.
+//
 // Returns a channel that closes when the Cmd is finished.
 func (c *Controller) runInternal(ctx context.Context,
 	cmd *v1alpha1.Cmd,
-	restartObjs restarton.Objects) (doneCh chan struct{}) {
+	restartObjs restarton.Objects,
+	restarts int32) (doneCh chan struct{}) {
 	name := types.NamespacedName{Name: cmd.Name}
 	proc, ok := c.procs[name]
 	if ok {
@@ -313,6 +374,11 @@ func (c *Controller) runInternal(ctx context.Context,
 
 	proc.spec = cmd.Spec
 	proc.isServer = cmd.ObjectMeta.Annotations[local.AnnotationOwnerKind] == "CmdServer"
+	proc.restarts = restarts
+	if restarts == 0 {
+		proc.backoff = 0
+	}
+	proc.setExit(nil)
 
 	var startInputs, restartInputs []input
 
@@ -332,6 +398,7 @@ func (c *Controller) runInternal(ctx context.Context,
 		status.Waiting = &CmdStateWaiting{}
 		status.Terminated = nil
 		status.Ready = false
+		status.Restarts = restarts
 	}, stillHasSameProcNum)
 
 	ctx = store.MustObjectLogHandler(ctx, c.st, cmd)
@@ -362,7 +429,7 @@ func (c *Controller) runInternal(ctx context.Context,
 		proc.probeWorker = probeWorker
 	}
 
-	startedAt := apis.NewMicroTime(c.clock.Now())
+	startTime := c.clock.Now()
 
 	env := append([]string{}, spec.Env...)
 	for _, input := range mergedInputs {
@@ -374,10 +441,10 @@ func (c *Controller) runInternal(ctx context.Context,
 		Dir:  spec.Dir,
 		Env:  env,
 	}
-	statusCh := c.execer.Start(ctx, cmdModel, logger.Get(ctx).Writer(logger.InfoLvl))
+	statusCh := c.execer.Start(ctx, cmdModel, stopPolicyFromSpec(spec), logger.Get(ctx).Writer(logger.InfoLvl))
 	proc.doneCh = make(chan struct{})
 
-	go c.processStatuses(ctx, statusCh, proc, name, startedAt, stillHasSameProcNum)
+	go c.processStatuses(ctx, statusCh, proc, name, startTime, stillHasSameProcNum)
 
 	return proc.doneCh
 }
@@ -505,10 +572,12 @@ func (c *Controller) processStatuses(
 	statusCh chan statusAndMetadata,
 	proc *currentProcess,
 	name types.NamespacedName,
-	startedAt metav1.MicroTime,
+	startTime time.Time,
 	stillHasSameProcNum func() bool) {
 	defer close(proc.doneCh)
 
+	startedAt := apis.NewMicroTime(startTime)
+
 	var initProbeWorker sync.Once
 
 	for sm := range statusCh {
@@ -522,6 +591,16 @@ func (c *Controller) processStatuses(
 				logger.Get(ctx).Errorf("Server exited with exit code 0")
 			}
 
+			// If we canceled the process, it didn't exit on its own,
+			// so the restart policy doesn't apply.
+			if ctx.Err() == nil {
+				proc.setExit(&processExit{
+					code:       sm.exitCode,
+					startedAt:  startTime,
+					finishedAt: c.clock.Now(),
+				})
+			}
+
 			c.updateStatus(name, func(status *CmdStatus) {
 				status.Waiting = nil
 				status.Running = nil
@@ -587,9 +666,48 @@ type currentProcess struct {
 	lastRestartOnEventTime time.Time
 	lastStartOnEventTime   time.Time
 
+	// Bookkeeping for the restart policy.
+	restarts int32
+	backoff  time.Duration
+	exit     *processExit
+
 	mu sync.Mutex
 }
 
+// Describes a process that exited on its own.
+type processExit struct {
+	code       int
+	startedAt  time.Time
+	finishedAt time.Time
+
+	// Whether we've logged what the restart policy is going to do about it.
+	announced bool
+}
+
+func (p *currentProcess) setExit(exit *processExit) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	p.exit = exit
+}
+
+func (p *currentProcess) currentExit() *processExit {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	return p.exit
+}
+
+// The backoff to wait before restarting the process after the given exit.
+func (p *currentProcess) nextBackoff(exit *processExit) time.Duration {
+	if p.backoff == 0 || exit.finishedAt.Sub(exit.startedAt) >= restartBackoffMax {
+		return restartBackoffInitial
+	}
+	backoff := 2 * p.backoff
+	if backoff > restartBackoffMax {
+		backoff = restartBackoffMax
+	}
+	return backoff
+}
+
 func (p *currentProcess) stillHasSameProcNum() func() bool {
 	s := p.currentProcNum()
 	return func() bool {
@@ -181,6 +181,128 @@ func TestFailure(t *testing.T) {
 	f.assertLogMessage("foo", "cmd true exited with code 5")
 }
 
+func TestRestartPolicyOnFailure(t *testing.T) {
+	f := newFixture(t)
+
+	c := model.ToHostCmdInDir("true", ".")
+	localTarget := model.NewLocalTarget("foo", model.Cmd{}, c, nil).
+		WithServeRestartPolicy(v1alpha1.CmdRestartPolicyOnFailure)
+	f.resourceFromTarget("foo", localTarget, time.Unix(1, 0))
+	f.step()
+	f.assertCmdMatches("foo-serve-1", func(cmd *Cmd) bool {
+		return cmd.Status.Running != nil
+	})
+
+	err := f.fe.stop("true", 5)
+	require.NoError(t, err)
+	f.assertCmdMatches("foo-serve-1", func(cmd *Cmd) bool {
+		return cmd.Status.Terminated != nil && cmd.Status.Terminated.ExitCode == 5
+	})
+
+	result := f.reconcileCmdResult("foo-serve-1")
+	assert.Equal(t, time.Second, result.RequeueAfter)
+	f.assertLogMessage("foo", "Restarting cmd true in 1s (restart policy: OnFailure)")
+
+	f.clock.Advance(time.Second)
+	result = f.reconcileCmdResult("foo-serve-1")
+	assert.Equal(t, time.Duration(0), result.RequeueAfter)
+	f.assertCmdMatches("foo-serve-1", func(cmd *Cmd) bool {
+		return cmd.Status.Running != nil && cmd.Status.Restarts == 1
+	})
+
+	// The backoff doubles on each consecutive restart.
+	err = f.fe.stop("true", 5)
+	require.NoError(t, err)
+	f.assertCmdMatches("foo-serve-1", func(cmd *Cmd) bool {
+		return cmd.Status.Terminated != nil
+	})
+	result = f.reconcileCmdResult("foo-serve-1")
+	assert.Equal(t, 2*time.Second, result.RequeueAfter)
+
+	f.clock.Advance(2 * time.Second)
+	_ = f.reconcileCmdResult("foo-serve-1")
+	f.assertCmdMatches("foo-serve-1", func(cmd *Cmd) bool {
+		return cmd.Status.Running != nil && cmd.Status.Restarts == 2
+	})
+}
+
+func TestRestartPolicyOnFailureIgnoresSuccess(t *testing.T) {
+	f := newFixture(t)
+
+	c := model.ToHostCmdInDir("true", ".")
+	localTarget := model.NewLocalTarget("foo", model.Cmd{}, c, nil).
+		WithServeRestartPolicy(v1alpha1.CmdRestartPolicyOnFailure)
+	f.resourceFromTarget("foo", localTarget, time.Unix(1, 0))
+	f.step()
+	f.assertCmdMatches("foo-serve-1", func(cmd *Cmd) bool {
+		return cmd.Status.Running != nil
+	})
+
+	err := f.fe.stop("true", 0)
+	require.NoError(t, err)
+	f.assertCmdMatches("foo-serve-1", func(cmd *Cmd) bool {
+		return cmd.Status.Terminated != nil
+	})
+
+	result := f.reconcileCmdResult("foo-serve-1")
+	assert.Equal(t, ctrl.Result{}, result)
+	f.fe.RequireNoKnownProcess(t, "true")
+}
+
+func TestRestartPolicyMaxRestarts(t *testing.T) {
+	f := newFixture(t)
+
+	f.resource("foo", "true", ".", time.Unix(1, 0))
+	f.step()
+	f.assertCmdMatches("foo-serve-1", func(cmd *Cmd) bool {
+		return cmd.Status.Running != nil
+	})
+
+	f.updateSpec("foo-serve-1", func(spec *v1alpha1.CmdSpec) {
+		spec.RestartPolicy = v1alpha1.CmdRestartPolicyAlways
+		spec.MaxRestarts = 1
+	})
+	f.reconcileCmd("foo-serve-1")
+	f.assertCmdMatches("foo-serve-1", func(cmd *Cmd) bool {
+		return cmd.Status.Running != nil
+	})
+
+	err := f.fe.stop("true", 0)
+	require.NoError(t, err)
+	f.assertCmdMatches("foo-serve-1", func(cmd *Cmd) bool {
+		return cmd.Status.Terminated != nil
+	})
+	f.clock.Advance(time.Second)
+	f.reconcileCmd("foo-serve-1")
+	f.assertCmdMatches("foo-serve-1", func(cmd *Cmd) bool {
+		return cmd.Status.Running != nil && cmd.Status.Restarts == 1
+	})
+
+	err = f.fe.stop("true", 0)
+	require.NoError(t, err)
+	f.assertCmdMatches("foo-serve-1", func(cmd *Cmd) bool {
+		return cmd.Status.Terminated != nil
+	})
+	f.clock.Advance(time.Minute)
+	result := f.reconcileCmdResult("foo-serve-1")
+	assert.Equal(t, ctrl.Result{}, result)
+	f.assertLogMessage("foo", "Not restarting cmd true: already restarted 1 times")
+	f.fe.RequireNoKnownProcess(t, "true")
+}
+
+func TestRestartBackoffResetsAfterLongRun(t *testing.T) {
+	start := time.Unix(0, 0)
+	proc := &currentProcess{}
+	assert.Equal(t, time.Second, proc.nextBackoff(&processExit{startedAt: start, finishedAt: start}))
+
+	proc.backoff = 4 * time.Minute
+	assert.Equal(t, restartBackoffMax, proc.nextBackoff(&processExit{startedAt: start, finishedAt: start}))
+
+	proc.backoff = time.Minute
+	assert.Equal(t, restartBackoffInitial,
+		proc.nextBackoff(&processExit{startedAt: start, finishedAt: start.Add(restartBackoffMax)}))
+}
+
 func TestUniqueSpanIDs(t *testing.T) {
 	f := newFixture(t)
 
@@ -548,6 +670,45 @@ func TestDisableCmd(t *testing.T) {
 	})
 }
 
+func TestStopSignal(t *testing.T) {
+	f := newFixture(t)
+
+	cmd := &Cmd{
+		ObjectMeta: metav1.ObjectMeta{
+			Name: "cmd-1",
+		},
+		Spec: v1alpha1.CmdSpec{
+			Args:                   []string{"myserver"},
+			StopSignal:             "SIGINT",
+			TerminationGracePeriod: metav1.Duration{Duration: time.Minute},
+			DisableSource: &v1alpha1.DisableSource{
+				ConfigMap: &v1alpha1.ConfigMapDisableSource{
+					Name: "disable-cmd-1",
+					Key:  "isDisabled",
+				},
+			},
+		},
+	}
+	err := f.Client.Create(f.Context(), cmd)
+	require.NoError(t, err)
+
+	f.setDisabled(cmd.Name, false)
+	f.requireCmdMatchesInAPI(cmd.Name, func(cmd *Cmd) bool {
+		return cmd.Status.Running != nil
+	})
+
+	f.fe.mu.Lock()
+	stop := f.fe.processes["myserver"].stop
+	f.fe.mu.Unlock()
+	assert.Equal(t, stopPolicy{signal: "SIGINT", gracePeriod: time.Minute}, stop)
+
+	f.setDisabled(cmd.Name, true)
+	f.requireCmdMatchesInAPI(cmd.Name, func(cmd *Cmd) bool {
+		return cmd.Status.Terminated != nil &&
+			cmd.Status.Terminated.Reason == "killed by SIGINT"
+	})
+}
+
 func TestReenable(t *testing.T) {
 	f := newFixture(t)
 
@@ -951,8 +1112,13 @@ func (f *fixture) triggerButton(name string, ts time.Time) {
 }
 
 func (f *fixture) reconcileCmd(name string) {
-	_, err := f.c.Reconcile(f.Context(), ctrl.Request{NamespacedName: types.NamespacedName{Name: name}})
+	_ = f.reconcileCmdResult(name)
+}
+
+func (f *fixture) reconcileCmdResult(name string) ctrl.Result {
+	result, err := f.c.Reconcile(f.Context(), ctrl.Request{NamespacedName: types.NamespacedName{Name: name}})
 	require.NoError(f.T(), err)
+	return result
 }
 
 func (f *fixture) updateSpec(name string, update func(spec *v1alpha1.CmdSpec)) {
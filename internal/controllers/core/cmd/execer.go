@@ -13,6 +13,7 @@ import (
 	"github.com/stretchr/testify/require"
 
 	"github.com/tilt-dev/tilt/internal/localexec"
+	"github.com/tilt-dev/tilt/pkg/apis/core/v1alpha1"
 	"github.com/tilt-dev/tilt/pkg/logger"
 	"github.com/tilt-dev/tilt/pkg/model"
 	"github.com/tilt-dev/tilt/pkg/procutil"
@@ -20,16 +21,36 @@ import (
 
 var DefaultGracePeriod = 30 * time.Second
 
+const DefaultStopSignal = "SIGTERM"
+
+// Describes how to stop a process when its context is canceled.
+type stopPolicy struct {
+	// The signal sent to the process group. Defaults to SIGTERM.
+	signal string
+
+	// How long to wait for the process to exit before sending SIGKILL.
+	// Defaults to the execer's grace period.
+	gracePeriod time.Duration
+}
+
+func stopPolicyFromSpec(spec v1alpha1.CmdSpec) stopPolicy {
+	return stopPolicy{
+		signal:      spec.StopSignal,
+		gracePeriod: spec.TerminationGracePeriod.Duration,
+	}
+}
+
 type Execer interface {
 	// Returns a channel to pull status updates from. After the process exists
 	// (and transmits its final status), the channel is closed.
-	Start(ctx context.Context, cmd model.Cmd, w io.Writer) chan statusAndMetadata
+	Start(ctx context.Context, cmd model.Cmd, stop stopPolicy, w io.Writer) chan statusAndMetadata
 }
 
 type fakeExecProcess struct {
 	exitCh    chan int
 	workdir   string
 	env       []string
+	stop      stopPolicy
 	startTime time.Time
 }
 
@@ -45,7 +66,7 @@ func NewFakeExecer() *FakeExecer {
 	}
 }
 
-func (e *FakeExecer) Start(ctx context.Context, cmd model.Cmd, w io.Writer) chan statusAndMetadata {
+func (e *FakeExecer) Start(ctx context.Context, cmd model.Cmd, stop stopPolicy, w io.Writer) chan statusAndMetadata {
 	e.mu.Lock()
 	_, ok := e.processes[cmd.String()]
 	e.mu.Unlock()
@@ -62,12 +83,13 @@ func (e *FakeExecer) Start(ctx context.Context, cmd model.Cmd, w io.Writer) chan
 		workdir:   cmd.Dir,
 		startTime: time.Now(),
 		env:       cmd.Env,
+		stop:      stop,
 	}
 	e.mu.Unlock()
 
 	statusCh := make(chan statusAndMetadata)
 	go func() {
-		fakeRun(ctx, cmd, w, statusCh, exitCh)
+		fakeRun(ctx, cmd, stop, w, statusCh, exitCh)
 
 		e.mu.Lock()
 		delete(e.processes, cmd.String())
@@ -93,7 +115,7 @@ func (e *FakeExecer) stop(cmd string, exitCode int) error {
 	return nil
 }
 
-func fakeRun(ctx context.Context, cmd model.Cmd, w io.Writer, statusCh chan statusAndMetadata, exitCh chan int) {
+func fakeRun(ctx context.Context, cmd model.Cmd, stop stopPolicy, w io.Writer, statusCh chan statusAndMetadata, exitCh chan int) {
 	defer close(statusCh)
 
 	_, _ = fmt.Fprintf(w, "Starting cmd %v\n", cmd)
@@ -104,7 +126,7 @@ func fakeRun(ctx context.Context, cmd model.Cmd, w io.Writer, statusCh chan stat
 	case <-ctx.Done():
 		_, _ = fmt.Fprintf(w, "cmd %v canceled\n", cmd)
 		// this was cleaned up by the controller, so it's not an error
-		statusCh <- statusAndMetadata{status: Done, exitCode: 0}
+		statusCh <- statusAndMetadata{status: Done, exitCode: 0, reason: killedReason(stop.signalOrDefault())}
 	case exitCode := <-exitCh:
 		_, _ = fmt.Fprintf(w, "cmd %v exited with code %d\n", cmd, exitCode)
 		// even an exit code of 0 is an error, because services aren't supposed to exit!
@@ -138,17 +160,21 @@ func NewProcessExecer(localEnv *localexec.Env) *processExecer {
 	}
 }
 
-func (e *processExecer) Start(ctx context.Context, cmd model.Cmd, w io.Writer) chan statusAndMetadata {
+func (e *processExecer) Start(ctx context.Context, cmd model.Cmd, stop stopPolicy, w io.Writer) chan statusAndMetadata {
 	statusCh := make(chan statusAndMetadata)
 
+	if stop.gracePeriod == 0 {
+		stop.gracePeriod = e.gracePeriod
+	}
+
 	go func() {
-		e.processRun(ctx, cmd, w, statusCh)
+		e.processRun(ctx, cmd, stop, w, statusCh)
 	}()
 
 	return statusCh
 }
 
-func (e *processExecer) processRun(ctx context.Context, cmd model.Cmd, w io.Writer, statusCh chan statusAndMetadata) {
+func (e *processExecer) processRun(ctx context.Context, cmd model.Cmd, stop stopPolicy, w io.Writer, statusCh chan statusAndMetadata) {
 	defer close(statusCh)
 
 	logger.Get(ctx).Infof("Running cmd: %s", cmd.String())
@@ -226,46 +252,66 @@ func (e *processExecer) processRun(ctx context.Context, cmd model.Cmd, w io.Writ
 		}
 		statusCh <- statusAndMetadata{status: status, pid: pid, exitCode: exitCode, reason: reason}
 	case <-ctx.Done():
-		e.killProcess(ctx, c, processExitCh)
-		statusCh <- statusAndMetadata{status: Done, pid: pid, reason: "killed", exitCode: 137}
+		reason := e.killProcess(ctx, c, stop, processExitCh)
+		statusCh <- statusAndMetadata{status: Done, pid: pid, reason: reason, exitCode: 137}
 	}
 }
 
-func (e *processExecer) killProcess(ctx context.Context, c *exec.Cmd, processExitCh chan error) {
-	logger.Get(ctx).Debugf("About to gracefully shut down process %d", c.Process.Pid)
-	err := procutil.GracefullyShutdownProcess(c.Process)
+// Stops the process group with the stop signal, and kills it if it doesn't
+// exit within the grace period.
+//
+// Returns a reason that describes how the process was killed.
+func (e *processExecer) killProcess(ctx context.Context, c *exec.Cmd, stop stopPolicy, processExitCh chan error) string {
+	sig := stop.signalOrDefault()
+	logger.Get(ctx).Debugf("About to gracefully shut down process %d with %s", c.Process.Pid, sig)
+	err := procutil.SignalProcessGroup(c.Process, sig)
 	if err != nil {
 		logger.Get(ctx).Debugf("Unable to gracefully kill process %d, sending SIGKILL to the process group: %v", c.Process.Pid, err)
 		procutil.KillProcessGroup(c)
-		return
+		return killedReason("SIGKILL")
+	}
+	if sig == "SIGKILL" {
+		return killedReason(sig)
 	}
 
-	// we wait 30 seconds to give the process enough time to finish doing any cleanup.
-	// this is the same timeout that Kubernetes uses
-	// TODO(dmiller): make this configurable via the Tiltfile
-	infoCh := time.After(e.gracePeriod / 20)
-	moreInfoCh := time.After(e.gracePeriod / 3)
-	finalCh := time.After(e.gracePeriod)
+	// By default, we wait 30 seconds to give the process enough time to finish
+	// doing any cleanup. This is the same timeout that Kubernetes uses.
+	gracePeriod := stop.gracePeriod
+	infoCh := time.After(gracePeriod / 20)
+	moreInfoCh := time.After(gracePeriod / 3)
+	finalCh := time.After(gracePeriod)
 
 	select {
 	case <-infoCh:
-		logger.Get(ctx).Infof("Waiting %s for process to exit... (pid: %d)", e.gracePeriod, c.Process.Pid)
+		logger.Get(ctx).Infof("Waiting %s for process to exit... (pid: %d)", gracePeriod, c.Process.Pid)
 	case <-processExitCh:
-		return
+		return killedReason(sig)
 	}
 
 	select {
 	case <-moreInfoCh:
 		logger.Get(ctx).Infof("Still waiting on exit... (pid: %d)", c.Process.Pid)
 	case <-processExitCh:
-		return
+		return killedReason(sig)
 	}
 
 	select {
 	case <-finalCh:
 		logger.Get(ctx).Infof("Time is up! Sending %d a kill signal", c.Process.Pid)
 		procutil.KillProcessGroup(c)
+		return fmt.Sprintf("killed by SIGKILL after %s grace period", gracePeriod)
 	case <-processExitCh:
-		return
+		return killedReason(sig)
 	}
 }
+
+func (p stopPolicy) signalOrDefault() string {
+	if p.signal == "" {
+		return DefaultStopSignal
+	}
+	return p.signal
+}
+
+func killedReason(sig string) string {
+	return fmt.Sprintf("killed by %s", sig)
+}
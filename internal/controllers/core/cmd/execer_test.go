@@ -91,6 +91,49 @@ sleep 100
 	f.assertLogContains("cleanup time")
 }
 
+func TestShutdownWithStopSignal(t *testing.T) {
+	if runtime.GOOS == "windows" {
+		t.Skip("no signals on windows")
+	}
+	f := newProcessExecFixture(t)
+	defer f.tearDown()
+
+	cmd := `
+trap 'echo "got SIGINT"; exit 1' INT
+printf 'trap %s\n' installed
+sleep 100
+`
+	f.startWithStopPolicy(cmd, stopPolicy{signal: "SIGINT"})
+	f.waitForStatus(Running)
+	f.waitForLog("trap installed")
+	f.cancel()
+
+	sm := f.waitForStatus(Done)
+	assert.Equal(t, "killed by SIGINT", sm.reason)
+	f.assertLogContains("got SIGINT")
+}
+
+func TestShutdownAfterGracePeriod(t *testing.T) {
+	if runtime.GOOS == "windows" {
+		t.Skip("no signals on windows")
+	}
+	f := newProcessExecFixture(t)
+	defer f.tearDown()
+
+	cmd := `
+trap '' TERM
+printf 'trap %s\n' installed
+sleep 100
+`
+	f.startWithStopPolicy(cmd, stopPolicy{gracePeriod: 200 * time.Millisecond})
+	f.waitForStatus(Running)
+	f.waitForLog("trap installed")
+	f.cancel()
+
+	sm := f.waitForStatus(Done)
+	assert.Equal(t, "killed by SIGKILL after 200ms grace period", sm.reason)
+}
+
 func TestPrintsLogs(t *testing.T) {
 	f := newProcessExecFixture(t)
 	defer f.tearDown()
@@ -228,13 +271,19 @@ func (f *processExecFixture) tearDown() {
 
 func (f *processExecFixture) startMalformedCommand() {
 	c := model.Cmd{Argv: []string{"\""}, Dir: "."}
-	f.statusCh = f.execer.Start(f.ctx, c, f.testWriter)
+	f.statusCh = f.execer.Start(f.ctx, c, stopPolicy{}, f.testWriter)
 }
 
 func (f *processExecFixture) startWithWorkdir(cmd string, workdir string) {
 	c := model.ToHostCmd(cmd)
 	c.Dir = workdir
-	f.statusCh = f.execer.Start(f.ctx, c, f.testWriter)
+	f.statusCh = f.execer.Start(f.ctx, c, stopPolicy{}, f.testWriter)
+}
+
+func (f *processExecFixture) startWithStopPolicy(cmd string, stop stopPolicy) {
+	c := model.ToHostCmd(cmd)
+	c.Dir = "."
+	f.statusCh = f.execer.Start(f.ctx, c, stop, f.testWriter)
 }
 
 func (f *processExecFixture) start(cmd string) {
@@ -245,7 +294,7 @@ func (f *processExecFixture) assertCmdSucceeds() {
 	f.waitForStatus(Done)
 }
 
-func (f *processExecFixture) waitForStatus(expectedStatus status) {
+func (f *processExecFixture) waitForStatus(expectedStatus status) statusAndMetadata {
 	deadlineCh := time.After(2 * time.Second)
 	for {
 		select {
@@ -254,15 +303,15 @@ func (f *processExecFixture) waitForStatus(expectedStatus status) {
 				f.t.Fatal("statusCh closed")
 			}
 			if expectedStatus == sm.status {
-				return
+				return sm
 			}
 			if sm.status == Error {
 				f.t.Error("Unexpected Error")
-				return
+				return sm
 			}
 			if sm.status == Done {
 				f.t.Error("Unexpected Done")
-				return
+				return sm
 			}
 		case <-deadlineCh:
 			f.t.Fatal("Timed out waiting for cmd sm")
@@ -270,6 +319,20 @@ func (f *processExecFixture) waitForStatus(expectedStatus status) {
 	}
 }
 
+// Note that the "Running cmd" log line includes the command itself,
+// so s shouldn't appear verbatim in the command.
+func (f *processExecFixture) waitForLog(s string) {
+	f.t.Helper()
+	timeout := time.After(time.Second)
+	for !strings.Contains(f.testWriter.String(), s) {
+		select {
+		case <-timeout:
+			f.t.Fatalf("never saw %q in process output", s)
+		case <-time.After(20 * time.Millisecond):
+		}
+	}
+}
+
 func (f *processExecFixture) assertLogContains(s string) {
 	require.Contains(f.t, f.testWriter.String(), s)
 }
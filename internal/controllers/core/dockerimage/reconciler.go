@@ -0,0 +1,327 @@
+package dockerimage
+
+import (
+	"context"
+	"fmt"
+	"sync"
+
+	"github.com/docker/distribution/reference"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/types"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/builder"
+	ctrlclient "sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/handler"
+	"sigs.k8s.io/controller-runtime/pkg/reconcile"
+	"sigs.k8s.io/controller-runtime/pkg/source"
+
+	"github.com/tilt-dev/tilt/internal/build"
+	"github.com/tilt-dev/tilt/internal/container"
+	"github.com/tilt-dev/tilt/internal/controllers/apicmp"
+	"github.com/tilt-dev/tilt/internal/controllers/indexer"
+	"github.com/tilt-dev/tilt/internal/dockerignore"
+	"github.com/tilt-dev/tilt/internal/engine/buildcontrol"
+	"github.com/tilt-dev/tilt/internal/k8s"
+	"github.com/tilt-dev/tilt/internal/store"
+	"github.com/tilt-dev/tilt/pkg/apis"
+	"github.com/tilt-dev/tilt/pkg/apis/core/v1alpha1"
+	"github.com/tilt-dev/tilt/pkg/model"
+)
+
+var imageMapGVK = v1alpha1.SchemeGroupVersion.WithKind("ImageMap")
+
+const (
+	WaitingReasonImageMapNotFound = "waiting-for-imagemap"
+	BuildingReasonSpecChanged     = "spec-changed"
+)
+
+// Manages the DockerImage API object.
+//
+// Builds the image with the Docker daemon whenever the spec changes,
+// pushes it to the cluster (if an ImageMap needs it), then publishes the
+// result to the DockerImage status and to the ImageMap it points to (if any).
+type Reconciler struct {
+	client      ctrlclient.Client
+	indexer     *indexer.Indexer
+	st          store.RStore
+	db          build.DockerBuilder
+	clock       build.Clock
+	k8sClient   k8s.Client
+	env         k8s.Env
+	kubeContext k8s.KubeContext
+	kl          buildcontrol.KINDLoader
+
+	mu sync.Mutex
+
+	// Protected by the mutex.
+	results map[types.NamespacedName]*result
+}
+
+var _ reconcile.Reconciler = &Reconciler{}
+
+func NewReconciler(
+	client ctrlclient.Client,
+	st store.RStore,
+	scheme *runtime.Scheme,
+	db build.DockerBuilder,
+	clock build.Clock,
+	k8sClient k8s.Client,
+	env k8s.Env,
+	kubeContext k8s.KubeContext,
+	kl buildcontrol.KINDLoader,
+) *Reconciler {
+	return &Reconciler{
+		client:      client,
+		indexer:     indexer.NewIndexer(scheme, indexDockerImage),
+		st:          st,
+		db:          db,
+		clock:       clock,
+		k8sClient:   k8sClient,
+		env:         env,
+		kubeContext: kubeContext,
+		kl:          kl,
+		results:     make(map[types.NamespacedName]*result),
+	}
+}
+
+func (r *Reconciler) CreateBuilder(mgr ctrl.Manager) (*builder.Builder, error) {
+	b := ctrl.NewControllerManagedBy(mgr).
+		For(&v1alpha1.DockerImage{}).
+		Watches(&source.Kind{Type: &v1alpha1.ImageMap{}},
+			handler.EnqueueRequestsFromMapFunc(r.indexer.Enqueue))
+
+	return b, nil
+}
+
+func (r *Reconciler) Reconcile(ctx context.Context, req ctrl.Request) (ctrl.Result, error) {
+	nn := req.NamespacedName
+	var obj v1alpha1.DockerImage
+	err := r.client.Get(ctx, nn, &obj)
+	r.indexer.OnReconcile(nn, &obj)
+	if err != nil && !apierrors.IsNotFound(err) {
+		return ctrl.Result{}, err
+	}
+
+	if apierrors.IsNotFound(err) || !obj.ObjectMeta.DeletionTimestamp.IsZero() {
+		r.mu.Lock()
+		delete(r.results, nn)
+		r.mu.Unlock()
+		return ctrl.Result{}, nil
+	}
+
+	if obj.Annotations[v1alpha1.AnnotationManagedBy] != "" {
+		// Images managed by the buildcontrol engine are built
+		// by the ImageBuildAndDeployer.
+		return ctrl.Result{}, nil
+	}
+
+	var im *v1alpha1.ImageMap
+	if obj.Spec.ImageMap != "" {
+		im = &v1alpha1.ImageMap{}
+		err := r.client.Get(ctx, types.NamespacedName{Name: obj.Spec.ImageMap}, im)
+		if err != nil {
+			if !apierrors.IsNotFound(err) {
+				return ctrl.Result{}, err
+			}
+			return ctrl.Result{}, r.maybeUpdateStatus(ctx, &obj, v1alpha1.DockerImageStatus{
+				Waiting: &v1alpha1.DockerImageStateWaiting{Reason: WaitingReasonImageMapNotFound},
+			})
+		}
+	}
+
+	r.mu.Lock()
+	lastResult, ok := r.results[nn]
+	r.mu.Unlock()
+
+	if ok && apicmp.DeepEqual(lastResult.spec, obj.Spec) {
+		if im != nil && lastResult.status.Completed != nil && lastResult.status.Completed.Error == "" &&
+			im.Status.Image != lastResult.imageMapStatus.Image {
+			// The image was built, but someone else overwrote the ImageMap
+			// (or it was re-created). Re-publish our result.
+			err := r.updateImageMap(ctx, im, lastResult.imageMapStatus)
+			if err != nil {
+				return ctrl.Result{}, err
+			}
+		}
+		return ctrl.Result{}, r.maybeUpdateStatus(ctx, &obj, lastResult.status)
+	}
+
+	ctx = store.MustObjectLogHandler(ctx, r.st, &obj)
+	res := r.forceBuild(ctx, &obj)
+
+	r.mu.Lock()
+	r.results[nn] = res
+	r.mu.Unlock()
+
+	if im != nil && res.status.Completed != nil && res.status.Completed.Error == "" {
+		err := r.updateImageMap(ctx, im, res.imageMapStatus)
+		if err != nil {
+			return ctrl.Result{}, err
+		}
+	}
+
+	return ctrl.Result{}, r.maybeUpdateStatus(ctx, &obj, res.status)
+}
+
+// Build the image unconditionally, and return the result.
+//
+// Status updates for the in-progress build are written to the apiserver
+// as a side-effect.
+func (r *Reconciler) forceBuild(ctx context.Context, obj *v1alpha1.DockerImage) *result {
+	spec := obj.Spec
+	startTime := apis.NowMicro()
+	res := &result{spec: *spec.DeepCopy()}
+
+	_ = r.maybeUpdateStatus(ctx, obj, v1alpha1.DockerImageStatus{
+		Ref: obj.Status.Ref,
+		Building: &v1alpha1.DockerImageStateBuilding{
+			Reason:    BuildingReasonSpecChanged,
+			StartedAt: startTime,
+		},
+	})
+
+	refs, err := r.build(ctx, spec)
+	if err != nil {
+		res.status = v1alpha1.DockerImageStatus{
+			Ref: obj.Status.Ref,
+			Completed: &v1alpha1.DockerImageStateCompleted{
+				Error:      err.Error(),
+				StartedAt:  startTime,
+				FinishedAt: apis.NowMicro(),
+			},
+		}
+		return res
+	}
+
+	res.status = v1alpha1.DockerImageStatus{
+		Ref: refs.LocalRef.String(),
+		Completed: &v1alpha1.DockerImageStateCompleted{
+			StartedAt:  startTime,
+			FinishedAt: apis.NowMicro(),
+		},
+	}
+	res.imageMapStatus = v1alpha1.ImageMapStatus{
+		Image:          refs.ClusterRef.String(),
+		BuildStartTime: &startTime,
+	}
+	return res
+}
+
+func (r *Reconciler) build(ctx context.Context, spec v1alpha1.DockerImageSpec) (refs container.TaggedRefs, err error) {
+	ref, err := container.ParseNamed(spec.Ref)
+	if err != nil {
+		return container.TaggedRefs{}, fmt.Errorf("parsing ref: %v", err)
+	}
+
+	// Tag the image for the cluster's local registry (if it has one),
+	// so that the ImageMap points at an image the cluster can pull.
+	registry := r.k8sClient.LocalRegistry(ctx)
+	refSet, err := container.NewRefSet(container.NewRefSelector(ref), registry)
+	if err != nil {
+		return container.TaggedRefs{}, err
+	}
+
+	filter, err := dockerignore.NewDockerIgnoreTester(spec.Context)
+	if err != nil {
+		return container.TaggedRefs{}, fmt.Errorf("reading .dockerignore: %v", err)
+	}
+
+	ps := build.NewPipelineState(ctx, 2, r.clock)
+	defer func() { ps.End(ctx, err) }()
+
+	ps.StartPipelineStep(ctx, "Building Dockerfile: [%s]", container.FamiliarString(ref))
+	refs, err = r.db.BuildImage(ctx, ps, refSet, model.DockerBuild{DockerImageSpec: spec}, filter)
+	ps.EndPipelineStep(ctx)
+	if err != nil {
+		return container.TaggedRefs{}, err
+	}
+
+	err = r.push(ctx, ps, spec, registry, refs.LocalRef)
+	if err != nil {
+		return container.TaggedRefs{}, err
+	}
+	return refs, nil
+}
+
+// Make the image available to the cluster, the same way the
+// ImageBuildAndDeployer does for images in the Tiltfile.
+func (r *Reconciler) push(ctx context.Context, ps *build.PipelineState, spec v1alpha1.DockerImageSpec, registry container.Registry, ref reference.NamedTagged) error {
+	ps.StartPipelineStep(ctx, "Pushing %s", container.FamiliarString(ref))
+	defer ps.EndPipelineStep(ctx)
+
+	if spec.ImageMap == "" {
+		ps.Printf(ctx, "Skipping push: image is not used by an ImageMap")
+		return nil
+	} else if r.env == k8s.EnvNone {
+		ps.Printf(ctx, "Skipping push: no Kubernetes cluster")
+		return nil
+	} else if r.db.WillBuildToKubeContext(r.kubeContext) {
+		ps.Printf(ctx, "Skipping push: building on cluster's container runtime")
+		return nil
+	}
+
+	isKIND := r.env == k8s.EnvKIND5 || r.env == k8s.EnvKIND6
+	if isKIND && registry.Empty() {
+		ps.Printf(ctx, "Loading image to KIND")
+		err := r.kl.LoadToKIND(ps.AttachLogger(ctx), ref)
+		if err != nil {
+			return fmt.Errorf("Error loading image to KIND: %v", err)
+		}
+		return nil
+	}
+
+	ps.Printf(ctx, "Pushing with Docker client")
+	return r.db.PushImage(ps.AttachLogger(ctx), ref)
+}
+
+func (r *Reconciler) updateImageMap(ctx context.Context, im *v1alpha1.ImageMap, status v1alpha1.ImageMapStatus) error {
+	if apicmp.DeepEqual(im.Status, status) {
+		return nil
+	}
+
+	update := im.DeepCopy()
+	update.Status = status
+	err := r.client.Status().Update(ctx, update)
+	if err != nil {
+		return fmt.Errorf("updating ImageMap: %v", err)
+	}
+	return nil
+}
+
+func (r *Reconciler) maybeUpdateStatus(ctx context.Context, obj *v1alpha1.DockerImage, newStatus v1alpha1.DockerImageStatus) error {
+	if apicmp.DeepEqual(obj.Status, newStatus) {
+		return nil
+	}
+
+	update := obj.DeepCopy()
+	update.Status = *(newStatus.DeepCopy())
+	err := r.client.Status().Update(ctx, update)
+	if err != nil {
+		return err
+	}
+	obj.Status = update.Status
+	obj.ResourceVersion = update.ResourceVersion
+	return nil
+}
+
+// Find all the objects we need to watch based on the DockerImage model.
+func indexDockerImage(obj ctrlclient.Object) []indexer.Key {
+	di := obj.(*v1alpha1.DockerImage)
+	if di.Spec.ImageMap == "" {
+		return nil
+	}
+	return []indexer.Key{
+		{
+			Name: types.NamespacedName{Name: di.Spec.ImageMap},
+			GVK:  imageMapGVK,
+		},
+	}
+}
+
+// Keeps track of the last build we ran.
+type result struct {
+	spec           v1alpha1.DockerImageSpec
+	status         v1alpha1.DockerImageStatus
+	imageMapStatus v1alpha1.ImageMapStatus
+}
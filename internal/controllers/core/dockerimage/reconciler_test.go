@@ -0,0 +1,202 @@
+package dockerimage
+
+import (
+	"context"
+	"fmt"
+	"testing"
+
+	"github.com/docker/distribution/reference"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+
+	"github.com/tilt-dev/tilt/internal/build"
+	"github.com/tilt-dev/tilt/internal/container"
+	"github.com/tilt-dev/tilt/internal/controllers/fake"
+	"github.com/tilt-dev/tilt/internal/docker"
+	"github.com/tilt-dev/tilt/internal/dockerfile"
+	"github.com/tilt-dev/tilt/internal/k8s"
+	"github.com/tilt-dev/tilt/internal/store"
+	"github.com/tilt-dev/tilt/internal/testutils/tempdir"
+	"github.com/tilt-dev/tilt/pkg/apis/core/v1alpha1"
+)
+
+func TestBuildAndPublishToImageMap(t *testing.T) {
+	f := newFixture(t)
+
+	f.Create(&v1alpha1.ImageMap{
+		ObjectMeta: metav1.ObjectMeta{Name: "my-image"},
+		Spec:       v1alpha1.ImageMapSpec{Selector: "my-image"},
+	})
+	f.createDockerImage("my-image", "my-image")
+
+	var di v1alpha1.DockerImage
+	f.MustGet(types.NamespacedName{Name: "my-image"}, &di)
+	require.NotNil(t, di.Status.Completed)
+	assert.Equal(t, "", di.Status.Completed.Error)
+	assert.Regexp(t, "^docker.io/library/my-image:tilt-[0-9a-f]+$", di.Status.Ref)
+	assert.Equal(t, 1, f.dCli.BuildCount)
+	assert.Equal(t, 1, f.dCli.PushCount)
+	assert.Equal(t, di.Status.Ref, f.dCli.PushImage)
+
+	var im v1alpha1.ImageMap
+	f.MustGet(types.NamespacedName{Name: "my-image"}, &im)
+	assert.Equal(t, di.Status.Ref, im.Status.Image)
+	assert.NotNil(t, im.Status.BuildStartTime)
+}
+
+func TestPushToClusterRegistry(t *testing.T) {
+	f := newFixture(t)
+	f.kCli.Registry = container.MustNewRegistryWithHostFromCluster("localhost:5000", "registry:5000")
+
+	f.Create(&v1alpha1.ImageMap{
+		ObjectMeta: metav1.ObjectMeta{Name: "my-image"},
+		Spec:       v1alpha1.ImageMapSpec{Selector: "my-image"},
+	})
+	f.createDockerImage("my-image", "my-image")
+
+	var di v1alpha1.DockerImage
+	f.MustGet(types.NamespacedName{Name: "my-image"}, &di)
+	assert.Regexp(t, "^localhost:5000/my-image:tilt-[0-9a-f]+$", di.Status.Ref)
+	assert.Equal(t, 1, f.dCli.PushCount)
+	assert.Equal(t, di.Status.Ref, f.dCli.PushImage)
+
+	var im v1alpha1.ImageMap
+	f.MustGet(types.NamespacedName{Name: "my-image"}, &im)
+	assert.Regexp(t, "^registry:5000/my-image:tilt-[0-9a-f]+$", im.Status.Image)
+}
+
+func TestLoadToKINDWithoutRegistry(t *testing.T) {
+	f := newFixture(t)
+	f.r.env = k8s.EnvKIND6
+
+	f.Create(&v1alpha1.ImageMap{
+		ObjectMeta: metav1.ObjectMeta{Name: "my-image"},
+		Spec:       v1alpha1.ImageMapSpec{Selector: "my-image"},
+	})
+	f.createDockerImage("my-image", "my-image")
+
+	var di v1alpha1.DockerImage
+	f.MustGet(types.NamespacedName{Name: "my-image"}, &di)
+	require.NotNil(t, di.Status.Completed)
+	assert.Equal(t, "", di.Status.Completed.Error)
+	assert.Equal(t, 1, f.kl.loadCount)
+	assert.Equal(t, 0, f.dCli.PushCount)
+}
+
+func TestNoPushWithoutImageMap(t *testing.T) {
+	f := newFixture(t)
+
+	f.createDockerImage("my-image", "")
+
+	var di v1alpha1.DockerImage
+	f.MustGet(types.NamespacedName{Name: "my-image"}, &di)
+	require.NotNil(t, di.Status.Completed)
+	assert.Equal(t, 1, f.dCli.BuildCount)
+	assert.Equal(t, 0, f.dCli.PushCount)
+}
+
+func TestWaitingForImageMap(t *testing.T) {
+	f := newFixture(t)
+
+	f.createDockerImage("my-image", "my-image")
+
+	var di v1alpha1.DockerImage
+	f.MustGet(types.NamespacedName{Name: "my-image"}, &di)
+	require.NotNil(t, di.Status.Waiting)
+	assert.Equal(t, WaitingReasonImageMapNotFound, di.Status.Waiting.Reason)
+	assert.Equal(t, 0, f.dCli.BuildCount)
+
+	f.Create(&v1alpha1.ImageMap{
+		ObjectMeta: metav1.ObjectMeta{Name: "my-image"},
+		Spec:       v1alpha1.ImageMapSpec{Selector: "my-image"},
+	})
+	f.MustReconcile(types.NamespacedName{Name: "my-image"})
+
+	f.MustGet(types.NamespacedName{Name: "my-image"}, &di)
+	assert.Nil(t, di.Status.Waiting)
+	require.NotNil(t, di.Status.Completed)
+	assert.Equal(t, 1, f.dCli.BuildCount)
+}
+
+func TestNoRebuildOnSameSpec(t *testing.T) {
+	f := newFixture(t)
+
+	f.createDockerImage("my-image", "")
+	assert.Equal(t, 1, f.dCli.BuildCount)
+
+	f.MustReconcile(types.NamespacedName{Name: "my-image"})
+	assert.Equal(t, 1, f.dCli.BuildCount)
+
+	var di v1alpha1.DockerImage
+	f.MustGet(types.NamespacedName{Name: "my-image"}, &di)
+	di.Spec.Args = []string{"FOO=bar"}
+	f.Update(&di)
+	assert.Equal(t, 2, f.dCli.BuildCount)
+}
+
+func TestBuildError(t *testing.T) {
+	f := newFixture(t)
+
+	f.dCli.BuildErrorToThrow = fmt.Errorf("no space left on device")
+	f.createDockerImage("my-image", "")
+
+	var di v1alpha1.DockerImage
+	f.MustGet(types.NamespacedName{Name: "my-image"}, &di)
+	require.NotNil(t, di.Status.Completed)
+	assert.Contains(t, di.Status.Completed.Error, "no space left on device")
+	assert.Equal(t, "", di.Status.Ref)
+}
+
+type fixture struct {
+	*fake.ControllerFixture
+	*tempdir.TempDirFixture
+	r    *Reconciler
+	dCli *docker.FakeClient
+	kCli *k8s.FakeK8sClient
+	kl   *fakeKINDLoader
+}
+
+func newFixture(t *testing.T) *fixture {
+	cfb := fake.NewControllerFixtureBuilder(t)
+	tf := tempdir.NewTempDirFixture(t)
+	t.Cleanup(tf.TearDown)
+
+	dCli := docker.NewFakeClient()
+	db := build.NewDockerImageBuilder(dCli, dockerfile.Labels{})
+	kCli := k8s.NewFakeK8sClient(t)
+	kl := &fakeKINDLoader{}
+	r := NewReconciler(cfb.Client, store.NewTestingStore(), v1alpha1.NewScheme(), db, build.ProvideClock(),
+		kCli, k8s.EnvGKE, k8s.KubeContext("gke_my-project"), kl)
+
+	return &fixture{
+		ControllerFixture: cfb.Build(r),
+		TempDirFixture:    tf,
+		r:                 r,
+		dCli:              dCli,
+		kCli:              kCli,
+		kl:                kl,
+	}
+}
+
+func (f *fixture) createDockerImage(name string, imageMap string) {
+	f.Create(&v1alpha1.DockerImage{
+		ObjectMeta: metav1.ObjectMeta{Name: name},
+		Spec: v1alpha1.DockerImageSpec{
+			Ref:                name,
+			DockerfileContents: "FROM alpine",
+			Context:            f.Path(),
+			ImageMap:           imageMap,
+		},
+	})
+}
+
+type fakeKINDLoader struct {
+	loadCount int
+}
+
+func (kl *fakeKINDLoader) LoadToKIND(ctx context.Context, ref reference.NamedTagged) error {
+	kl.loadCount++
+	return nil
+}
@@ -22,6 +22,8 @@ import (
 	"github.com/tilt-dev/tilt/internal/controllers/apicmp"
 	"github.com/tilt-dev/tilt/internal/controllers/indexer"
 	"github.com/tilt-dev/tilt/internal/ospath"
+	"github.com/tilt-dev/tilt/internal/tiltfile/tiltextension"
+	"github.com/tilt-dev/tilt/pkg/apis"
 	"github.com/tilt-dev/tilt/pkg/apis/core/v1alpha1"
 	"github.com/tilt-dev/tilt/pkg/logger"
 )
@@ -95,8 +97,20 @@ func (r *Reconciler) Reconcile(ctx context.Context, request reconcile.Request) (
 		return r.updateError(ctx, &ext, fmt.Sprintf("no extension tiltfile found at %s", absPath))
 	}
 
+	contentHash, err := tiltextension.HashDir(filepath.Dir(absPath))
+	if err != nil {
+		return r.updateError(ctx, &ext, fmt.Sprintf("loading: %v", err))
+	}
+
+	if ext.Spec.ContentHash != "" && ext.Spec.ContentHash != contentHash {
+		return r.updateError(ctx, &ext, fmt.Sprintf(
+			"content hash %s doesn't match extensions.lock (expected %s). To upgrade it, run `tilt ext update %s`",
+			contentHash, ext.Spec.ContentHash, ext.Name))
+	}
+
 	update, changed, err := r.updateStatus(ctx, &ext, func(status *v1alpha1.ExtensionStatus) {
 		status.Path = absPath
+		status.ContentHash = contentHash
 		status.Error = ""
 	})
 	if err != nil {
@@ -114,6 +128,8 @@ func (r *Reconciler) Reconcile(ctx context.Context, request reconcile.Request) (
 		repoType := "http"
 		if strings.HasPrefix(repo.Spec.URL, "file://") {
 			repoType = "file"
+		} else if apis.IsGitRemoteURL(repo.Spec.URL) {
+			repoType = "git"
 		}
 		r.analytics.Incr("api.extension.load", map[string]string{
 			"ext_path":      ext.Spec.RepoPath,
@@ -145,6 +161,7 @@ func (r *Reconciler) updateError(ctx context.Context, ext *v1alpha1.Extension, e
 	update := ext.DeepCopy()
 	update.Status.Error = errorMsg
 	update.Status.Path = ""
+	update.Status.ContentHash = ""
 
 	if apicmp.DeepEqual(update.Status, ext.Status) {
 		// We don't need to worry about managing the child object, because
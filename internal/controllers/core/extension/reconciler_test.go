@@ -15,6 +15,7 @@ import (
 	tiltanalytics "github.com/tilt-dev/tilt/internal/analytics"
 	"github.com/tilt-dev/tilt/internal/controllers/fake"
 	"github.com/tilt-dev/tilt/internal/testutils/tempdir"
+	"github.com/tilt-dev/tilt/internal/tiltfile/tiltextension"
 	"github.com/tilt-dev/tilt/pkg/apis/core/v1alpha1"
 )
 
@@ -61,6 +62,28 @@ func TestDefault(t *testing.T) {
 	})
 }
 
+func TestNestedRepoPath(t *testing.T) {
+	f := newFixture(t)
+	f.setupRepo()
+	p := f.JoinPath("my-repo", "exts", "platform", "my-ext", "Tiltfile")
+	f.WriteFile(p, "print('hello-world')")
+
+	ext := v1alpha1.Extension{
+		ObjectMeta: metav1.ObjectMeta{
+			Name: "my-repo:my-ext",
+		},
+		Spec: v1alpha1.ExtensionSpec{
+			RepoName: "my-repo",
+			RepoPath: "exts/platform/my-ext",
+		},
+	}
+	f.Create(&ext)
+
+	f.MustGet(types.NamespacedName{Name: "my-repo:my-ext"}, &ext)
+	require.Equal(t, "", ext.Status.Error)
+	require.Equal(t, p, ext.Status.Path)
+}
+
 func TestCleanupTiltfile(t *testing.T) {
 	f := newFixture(t)
 	f.setupRepo()
@@ -136,6 +159,54 @@ func TestChangeArgs(t *testing.T) {
 	require.Equal(t, []string{"--namespace=foo"}, tf.Spec.Args)
 }
 
+func TestContentHash(t *testing.T) {
+	f := newFixture(t)
+	f.setupRepo()
+
+	hash, err := tiltextension.HashDir(f.JoinPath("my-repo", "my-ext"))
+	require.NoError(t, err)
+
+	ext := v1alpha1.Extension{
+		ObjectMeta: metav1.ObjectMeta{
+			Name: "ext",
+		},
+		Spec: v1alpha1.ExtensionSpec{
+			RepoName:    "my-repo",
+			RepoPath:    "my-ext",
+			ContentHash: hash,
+		},
+	}
+	f.Create(&ext)
+
+	f.MustGet(types.NamespacedName{Name: "ext"}, &ext)
+	assert.Equal(t, "", ext.Status.Error)
+	assert.Equal(t, hash, ext.Status.ContentHash)
+}
+
+func TestContentHashMismatch(t *testing.T) {
+	f := newFixture(t)
+	f.setupRepo()
+
+	ext := v1alpha1.Extension{
+		ObjectMeta: metav1.ObjectMeta{
+			Name: "ext",
+		},
+		Spec: v1alpha1.ExtensionSpec{
+			RepoName:    "my-repo",
+			RepoPath:    "my-ext",
+			ContentHash: "sha256:locked",
+		},
+	}
+	f.Create(&ext)
+
+	f.MustGet(types.NamespacedName{Name: "ext"}, &ext)
+	assert.Contains(t, ext.Status.Error, "doesn't match extensions.lock (expected sha256:locked)")
+	assert.Equal(t, "", ext.Status.ContentHash)
+
+	var tf v1alpha1.Tiltfile
+	assert.False(t, f.Get(types.NamespacedName{Name: "ext"}, &tf))
+}
+
 type fixture struct {
 	*fake.ControllerFixture
 	*tempdir.TempDirFixture
@@ -1,8 +1,10 @@
 package extensionrepo
 
 import (
+	"bytes"
 	"context"
 	"fmt"
+	"io"
 	"os"
 	"path/filepath"
 	"strings"
@@ -17,13 +19,15 @@ import (
 	"sigs.k8s.io/controller-runtime/pkg/reconcile"
 
 	"github.com/tilt-dev/tilt/internal/controllers/apicmp"
+	"github.com/tilt-dev/tilt/internal/git"
 	"github.com/tilt-dev/tilt/internal/xdg"
 	"github.com/tilt-dev/tilt/pkg/apis"
 	"github.com/tilt-dev/tilt/pkg/apis/core/v1alpha1"
 	"github.com/tilt-dev/tilt/pkg/logger"
 )
 
-const tiltModulesRelDir = "tilt_modules"
+// The directory under ~/.tilt-dev where extension repos are checked out.
+const TiltModulesRelDir = "tilt_modules"
 
 type Downloader interface {
 	DestinationPath(pkg string) string
@@ -34,7 +38,12 @@ type Downloader interface {
 
 type Reconciler struct {
 	ctrlClient ctrlclient.Client
-	dlr        Downloader
+
+	// Downloads go-get style import paths (e.g., https://github.com/org/repo).
+	dlr Downloader
+
+	// Clones arbitrary git remotes (e.g., git@example.com:org/repo.git).
+	gitDlr Downloader
 
 	repoStates map[types.NamespacedName]*repoState
 }
@@ -47,13 +56,14 @@ func (r *Reconciler) CreateBuilder(mgr ctrl.Manager) (*builder.Builder, error) {
 }
 
 func NewReconciler(ctrlClient ctrlclient.Client, base xdg.Base) (*Reconciler, error) {
-	dlrPath, err := base.DataFile(tiltModulesRelDir)
+	dlrPath, err := base.DataFile(TiltModulesRelDir)
 	if err != nil {
 		return nil, fmt.Errorf("creating extensionrepo controller: %v", err)
 	}
 	return &Reconciler{
 		ctrlClient: ctrlClient,
 		dlr:        get.NewDownloader(dlrPath),
+		gitDlr:     git.NewDownloader(dlrPath),
 		repoStates: make(map[types.NamespacedName]*repoState),
 	}, nil
 }
@@ -111,12 +121,12 @@ func (r *Reconciler) Reconcile(ctx context.Context, request reconcile.Request) (
 	}
 
 	// Check that the URL is valid.
-	importPath, err := getDownloaderImportPath(&repo)
+	dlr, importPath, err := r.downloaderForRepo(&repo)
 	if err != nil {
 		return r.updateError(ctx, &repo, fmt.Sprintf("invalid: %v", err))
 	}
 
-	return r.reconcileDownloaderRepo(ctx, nn, &repo, importPath, state)
+	return r.reconcileDownloaderRepo(ctx, nn, &repo, dlr, importPath, state)
 }
 
 // Reconcile a repo that lives on disk, and shouldn't otherwise be modified.
@@ -152,13 +162,18 @@ func (r *Reconciler) reconcileFileRepo(ctx context.Context, repo *v1alpha1.Exten
 // Reconcile a repo that we need to fetch remotely, and store
 // under ~/.tilt-dev.
 func (r *Reconciler) reconcileDownloaderRepo(ctx context.Context, nn types.NamespacedName,
-	repo *v1alpha1.ExtensionRepo, importPath string, state *repoState) (reconcile.Result, error) {
-	getDlr, ok := r.dlr.(*get.Downloader)
-	if ok {
-		getDlr.Stderr = logger.Get(ctx).Writer(logger.InfoLvl)
+	repo *v1alpha1.ExtensionRepo, dlr Downloader, importPath string, state *repoState) (reconcile.Result, error) {
+	// go-get prints the output of failed commands to stderr, rather than
+	// returning it, so capture it for the error message.
+	stderr := bytes.NewBuffer(nil)
+	switch dlr := dlr.(type) {
+	case *get.Downloader:
+		dlr.Stderr = io.MultiWriter(logger.Get(ctx).Writer(logger.InfoLvl), stderr)
+	case *git.Downloader:
+		dlr.Stderr = logger.Get(ctx).Writer(logger.InfoLvl)
 	}
 
-	destPath := r.dlr.DestinationPath(importPath)
+	destPath := dlr.DestinationPath(importPath)
 	_, err := os.Stat(destPath)
 	if err != nil && !os.IsNotExist(err) {
 		return ctrl.Result{}, err
@@ -171,6 +186,16 @@ func (r *Reconciler) reconcileDownloaderRepo(ctx context.Context, nn types.Names
 		return ctrl.Result{}, nil
 	}
 
+	// If the repo is pinned to a commit that's already checked out (e.g., from
+	// tilt_modules/extensions.lock), there's nothing to download.
+	// This lets pinned repos load without network access.
+	if exists && repo.Spec.Ref != "" {
+		ref, err := dlr.HeadRef(importPath)
+		if err == nil && ref == repo.Spec.Ref {
+			return r.updateDownloaded(ctx, repo, destPath, ref, state)
+		}
+	}
+
 	lastFetch := state.lastFetch
 	lastBackoff := state.backoff
 	if time.Since(lastFetch) < lastBackoff {
@@ -180,10 +205,11 @@ func (r *Reconciler) reconcileDownloaderRepo(ctx context.Context, nn types.Names
 
 	state.lastFetch = time.Now()
 
-	_, err = r.dlr.Download(importPath)
+	_, err = dlr.Download(importPath)
 	if err != nil {
 		backoff := state.nextBackoff()
-		backoffMsg := fmt.Sprintf("download error: waiting %s before retrying. Original error: %v", backoff, err)
+		backoffMsg := fmt.Sprintf("download error: waiting %s before retrying. Original error: %v",
+			backoff, withStderr(err, stderr))
 		_, updateErr := r.updateError(ctx, repo, backoffMsg)
 		if updateErr != nil {
 			return ctrl.Result{}, updateErr
@@ -192,24 +218,31 @@ func (r *Reconciler) reconcileDownloaderRepo(ctx context.Context, nn types.Names
 		return ctrl.Result{RequeueAfter: backoff}, nil
 	}
 
-	info, err := os.Stat(destPath)
-	if err != nil {
-		return ctrl.Result{}, err
-	}
-
 	if repo.Spec.Ref != "" {
-		err := r.dlr.RefSync(importPath, repo.Spec.Ref)
+		stderr.Reset()
+		err := dlr.RefSync(importPath, repo.Spec.Ref)
 		if err != nil {
-			return r.updateError(ctx, repo, fmt.Sprintf("sync to ref %s: %v", repo.Spec.Ref, err))
+			return r.updateError(ctx, repo, fmt.Sprintf("sync to ref %s: %v", repo.Spec.Ref, withStderr(err, stderr)))
 		}
 	}
 
-	ref, err := r.dlr.HeadRef(importPath)
+	stderr.Reset()
+	ref, err := dlr.HeadRef(importPath)
 	if err != nil {
-		return r.updateError(ctx, repo, fmt.Sprintf("determining head: %v", err))
+		return r.updateError(ctx, repo, fmt.Sprintf("determining head: %v", withStderr(err, stderr)))
+	}
+
+	return r.updateDownloaded(ctx, repo, destPath, ref, state)
+}
+
+// Update the status of a repo that has been successfully downloaded to destPath.
+func (r *Reconciler) updateDownloaded(ctx context.Context, repo *v1alpha1.ExtensionRepo,
+	destPath string, ref string, state *repoState) (reconcile.Result, error) {
+	info, err := os.Stat(destPath)
+	if err != nil {
+		return ctrl.Result{}, err
 	}
 
-	// Update the status.
 	state.backoff = 0
 	state.lastSuccessfulDestPath = destPath
 
@@ -251,16 +284,31 @@ func (r *Reconciler) updateError(ctx context.Context, repo *v1alpha1.ExtensionRe
 	return ctrl.Result{}, err
 }
 
-func getDownloaderImportPath(repo *v1alpha1.ExtensionRepo) (string, error) {
-	// TODO(nick): Add file URLs
+// Picks the downloader for the repo URL, and the path to download with it.
+//
+// Git remotes are cloned with git directly. Other web URLs are
+// treated as go-get style import paths.
+func (r *Reconciler) downloaderForRepo(repo *v1alpha1.ExtensionRepo) (Downloader, string, error) {
 	url := repo.Spec.URL
+	if apis.IsGitRemoteURL(url) {
+		return r.gitDlr, url, nil
+	}
 	if strings.HasPrefix(url, "https://") {
-		return strings.TrimPrefix(url, "https://"), nil
+		return r.dlr, strings.TrimPrefix(url, "https://"), nil
 	}
 	if strings.HasPrefix(url, "http://") {
-		return strings.TrimPrefix(url, "http://"), nil
+		return r.dlr, strings.TrimPrefix(url, "http://"), nil
+	}
+	return nil, "", fmt.Errorf("URL must start with 'https://', 'ssh://', or 'git@': %v", url)
+}
+
+// Adds the captured stderr of a failed command to its error.
+func withStderr(err error, stderr *bytes.Buffer) error {
+	msg := strings.TrimSpace(stderr.String())
+	if msg == "" {
+		return err
 	}
-	return "", fmt.Errorf("URL must start with 'https://': %v", url)
+	return fmt.Errorf("%v\n%s", err, msg)
 }
 
 type repoState struct {
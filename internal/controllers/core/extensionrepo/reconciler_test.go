@@ -33,8 +33,8 @@ func TestInvalidRepo(t *testing.T) {
 	}
 	f.Create(&repo)
 	f.MustGet(key, &repo)
-	assert.Equal(t, "invalid: URL must start with 'https://': x", repo.Status.Error)
-	assert.Equal(t, "extensionrepo default: invalid: URL must start with 'https://': x\n", f.Stdout())
+	assert.Equal(t, "invalid: URL must start with 'https://', 'ssh://', or 'git@': x", repo.Status.Error)
+	assert.Equal(t, "extensionrepo default: invalid: URL must start with 'https://', 'ssh://', or 'git@': x\n", f.Stdout())
 }
 
 func TestUnknown(t *testing.T) {
@@ -127,11 +127,86 @@ func TestRepoSync(t *testing.T) {
 	assert.Equal(t, "other-ref", f.dlr.lastRefSync)
 }
 
+func TestPinnedRepoAlreadyCheckedOut(t *testing.T) {
+	f := newFixture(t)
+	f.dlr.headRef = "locked-ref"
+
+	destPath := f.dlr.DestinationPath("github.com/tilt-dev/tilt-extensions")
+	require.NoError(t, os.MkdirAll(destPath, os.FileMode(0755)))
+
+	key := types.NamespacedName{Name: "default"}
+	repo := v1alpha1.ExtensionRepo{
+		ObjectMeta: metav1.ObjectMeta{
+			Name: key.Name,
+		},
+		Spec: v1alpha1.ExtensionRepoSpec{
+			URL: "https://github.com/tilt-dev/tilt-extensions",
+			Ref: "locked-ref",
+		},
+	}
+	f.Create(&repo)
+	f.MustGet(key, &repo)
+	require.Equal(t, repo.Status.Error, "")
+	assert.Equal(t, destPath, repo.Status.Path)
+	assert.Equal(t, "locked-ref", repo.Status.CheckoutRef)
+	assert.Equal(t, 0, f.dlr.downloadCount)
+}
+
+func TestGitRemote(t *testing.T) {
+	for _, url := range []string{
+		"git@git.example.com:platform/tilt-extensions.git",
+		"ssh://git@git.example.com:2222/platform/tilt-extensions.git",
+		"https://git.example.com/platform/tilt-extensions.git",
+	} {
+		t.Run(url, func(t *testing.T) {
+			f := newFixture(t)
+			key := types.NamespacedName{Name: "default"}
+			repo := v1alpha1.ExtensionRepo{
+				ObjectMeta: metav1.ObjectMeta{
+					Name: key.Name,
+				},
+				Spec: v1alpha1.ExtensionRepoSpec{
+					URL: url,
+					Ref: "v1.0",
+				},
+			}
+			f.Create(&repo)
+			f.MustGet(key, &repo)
+			require.Equal(t, "", repo.Status.Error)
+			assert.Equal(t, f.gitDlr.DestinationPath(url), repo.Status.Path)
+			assert.Equal(t, "fake-head", repo.Status.CheckoutRef)
+			assert.Equal(t, 1, f.gitDlr.downloadCount)
+			assert.Equal(t, "v1.0", f.gitDlr.lastRefSync)
+			assert.Equal(t, 0, f.dlr.downloadCount)
+		})
+	}
+}
+
+func TestGitRemoteError(t *testing.T) {
+	f := newFixture(t)
+	f.gitDlr.downloadError = fmt.Errorf("git clone: exit status 128\n" +
+		"git@git.example.com: Permission denied (publickey).")
+	key := types.NamespacedName{Name: "default"}
+	repo := v1alpha1.ExtensionRepo{
+		ObjectMeta: metav1.ObjectMeta{
+			Name: key.Name,
+		},
+		Spec: v1alpha1.ExtensionRepoSpec{
+			URL: "git@git.example.com:platform/tilt-extensions.git",
+		},
+	}
+	f.Create(&repo)
+	f.MustGet(key, &repo)
+	assert.Contains(t, repo.Status.Error, "download error: waiting 5s before retrying")
+	assert.Contains(t, repo.Status.Error, "Permission denied (publickey).")
+}
+
 type fixture struct {
 	*fake.ControllerFixture
-	r    *Reconciler
-	dlr  *fakeDownloader
-	base xdg.FakeBase
+	r      *Reconciler
+	dlr    *fakeDownloader
+	gitDlr *fakeDownloader
+	base   xdg.FakeBase
 }
 
 func newFixture(t *testing.T) *fixture {
@@ -147,10 +222,14 @@ func newFixture(t *testing.T) *fixture {
 	dlr := &fakeDownloader{base: base, headRef: "fake-head"}
 	r.dlr = dlr
 
+	gitDlr := &fakeDownloader{base: base, headRef: "fake-head"}
+	r.gitDlr = gitDlr
+
 	return &fixture{
 		ControllerFixture: cfb.Build(r),
 		r:                 r,
 		dlr:               dlr,
+		gitDlr:            gitDlr,
 		base:              base,
 	}
 }
@@ -163,6 +163,7 @@ func (c *Controller) addOrReplace(ctx context.Context, st store.RStore, name typ
 	fw.Status.LastEventTime = metav1.MicroTime{}
 	fw.Status.MonitorStartTime = metav1.NowMicro()
 	fw.Status.Error = ""
+	fw.Status.Backend = string(notify.Backend())
 
 	if err := c.Client.Status().Update(ctx, fw); err != nil {
 		_ = notify.Close()
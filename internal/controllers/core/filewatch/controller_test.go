@@ -252,6 +252,7 @@ func TestController_Reconcile_Create(t *testing.T) {
 
 	f.MustGet(key, fw)
 	assert.NotZero(t, fw.Status.MonitorStartTime, "Filesystem monitor was not started")
+	assert.Equal(t, string(watch.BackendNative), fw.Status.Backend)
 }
 
 // TestController_Reconcile_Delete peeks into internal/unexported portions of the controller to inspect the actual
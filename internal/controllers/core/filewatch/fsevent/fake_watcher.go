@@ -147,6 +147,10 @@ func (w *FakeWatcher) Events() chan watch.FileEvent {
 	return w.outboundCh
 }
 
+func (w *FakeWatcher) Backend() watch.Backend {
+	return watch.BackendNative
+}
+
 func (w *FakeWatcher) TotalEventCount() uint64 {
 	return atomic.LoadUint64(&w.eventCount)
 }
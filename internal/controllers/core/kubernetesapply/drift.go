@@ -0,0 +1,300 @@
+package kubernetesapply
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"time"
+
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/types"
+	ctrl "sigs.k8s.io/controller-runtime"
+
+	"github.com/tilt-dev/tilt/internal/controllers/apicmp"
+	"github.com/tilt-dev/tilt/internal/k8s"
+	"github.com/tilt-dev/tilt/internal/store/kubernetesapplys"
+	"github.com/tilt-dev/tilt/pkg/apis"
+	"github.com/tilt-dev/tilt/pkg/apis/core/v1alpha1"
+	"github.com/tilt-dev/tilt/pkg/logger"
+	"github.com/tilt-dev/tilt/pkg/model"
+)
+
+// How often we compare the objects in the cluster against the last applied result.
+//
+// We poll rather than watch because the applied objects can be of any kind
+// (including CRDs) in any namespace, and a watch would need an informer for
+// each kind and namespace. Drift detection is opt-in, and each check is one
+// GET per applied object, so polling is cheap by comparison.
+const driftCheckInterval = 10 * time.Second
+
+// Compares the objects in the cluster against the last applied result,
+// and updates the Drifted condition.
+//
+// Returns when we should check again.
+func (r *Reconciler) checkDrift(ctx context.Context, nn types.NamespacedName, ka *v1alpha1.KubernetesApply,
+	imageMaps map[types.NamespacedName]*v1alpha1.ImageMap) (ctrl.Result, error) {
+	if ka.Spec.DriftDetection == nil {
+		return ctrl.Result{}, nil
+	}
+
+	r.mu.Lock()
+	result, ok := r.results[nn]
+	r.mu.Unlock()
+
+	// Nothing to compare against until the current spec has been applied successfully.
+	if !ok || result.Status.Error != "" || result.Status.ResultYAML == "" ||
+		!apicmp.DeepEqual(ka.Spec, result.Spec) {
+		return ctrl.Result{}, nil
+	}
+
+	applied, err := k8s.ParseYAMLFromString(result.Status.ResultYAML)
+	if err != nil {
+		return ctrl.Result{}, err
+	}
+
+	shapes, err := newAppliedShapes(ka.Spec.YAML)
+	if err != nil {
+		return ctrl.Result{}, err
+	}
+
+	// Use a min component count of 2 for computing names,
+	// so that the resource type appears
+	displayNames := k8s.UniqueNames(applied, 2)
+	var drifted []string
+	for i, e := range applied {
+		displayName := displayNames[i]
+		live, err := r.k8sClient.GetByReference(ctx, e.ToObjectReference())
+		if err != nil {
+			if apierrors.IsNotFound(err) {
+				drifted = append(drifted, fmt.Sprintf("%s (deleted)", displayName))
+				continue
+			}
+
+			// The cluster might be temporarily unavailable, so try again later.
+			logger.Get(ctx).Debugf("Checking %s for drift: %v", displayName, err)
+			return ctrl.Result{RequeueAfter: driftCheckInterval}, nil
+		}
+
+		shape, err := shapes.forEntity(e)
+		if err != nil {
+			return ctrl.Result{}, err
+		}
+
+		isDrifted, err := hasDrifted(e, live, shape)
+		if err != nil {
+			return ctrl.Result{}, err
+		}
+		if isDrifted {
+			drifted = append(drifted, displayName)
+		}
+	}
+
+	wasDrifted := isDriftedCondition(ka.Status)
+	var changed bool
+	if len(drifted) > 0 {
+		msg := fmt.Sprintf("Objects changed in the cluster since the last apply: %s", strings.Join(drifted, ", "))
+		changed = setDriftedCondition(&ka.Status, metav1.ConditionTrue, "ClusterChanged", msg)
+	} else {
+		changed = setDriftedCondition(&ka.Status, metav1.ConditionFalse, "InSync", "")
+	}
+
+	if changed {
+		err := r.ctrlClient.Status().Update(ctx, ka)
+		if err != nil {
+			return ctrl.Result{}, err
+		}
+	}
+
+	if len(drifted) == 0 || wasDrifted {
+		return ctrl.Result{RequeueAfter: driftCheckInterval}, nil
+	}
+
+	// Log a single warning, so that it shows up as one warning in the UI.
+	l := logger.Get(ctx)
+	l.Warnf("Kubernetes objects have drifted from the last apply:\n→ %s", strings.Join(drifted, "\n→ "))
+
+	if !ka.Spec.DriftDetection.Reapply {
+		return ctrl.Result{RequeueAfter: driftCheckInterval}, nil
+	}
+
+	mn := ka.Annotations[v1alpha1.AnnotationManifest]
+	if ka.Annotations[v1alpha1.AnnotationManagedBy] != "" {
+		// The buildcontrol engine applies objects it manages, so ask it to rebuild.
+		if mn != "" {
+			l.Infof("Reapplying %s to fix drift", mn)
+			r.st.Dispatch(kubernetesapplys.NewKubernetesApplyReapplyAction(model.ManifestName(mn)))
+		}
+		return ctrl.Result{RequeueAfter: driftCheckInterval}, nil
+	}
+
+	l.Infof("Reapplying to fix drift")
+	_, err = r.ForceApply(ctx, nn, ka.Spec, imageMaps)
+	if err != nil {
+		return ctrl.Result{}, err
+	}
+	return ctrl.Result{RequeueAfter: driftCheckInterval}, nil
+}
+
+// Checks whether the live object differs from the applied object.
+//
+// Servers and controllers routinely fill in fields after an apply (e.g., a
+// PersistentVolumeClaim's volumeName), so we only compare the fields in the
+// shape of what Tilt applied. We compare against the apply result, rather than
+// the YAML itself, so that the server's normalization (e.g., of quantities)
+// doesn't count as drift.
+func hasDrifted(applied, live k8s.K8sEntity, shape map[string]interface{}) (bool, error) {
+	if applied.Meta().GetResourceVersion() != "" &&
+		applied.Meta().GetResourceVersion() == live.Meta().GetResourceVersion() {
+		return false, nil
+	}
+
+	appliedFields, err := driftFields(applied)
+	if err != nil {
+		return false, err
+	}
+	liveFields, err := driftFields(live)
+	if err != nil {
+		return false, err
+	}
+	return !apicmp.DeepEqual(projectFields(appliedFields, shape), projectFields(liveFields, shape)), nil
+}
+
+// Labels and the other top-level fields (e.g., spec and data) of an object.
+//
+// We skip the rest of metadata and status, which servers update routinely.
+func driftFields(e k8s.K8sEntity) (map[string]interface{}, error) {
+	e = e.DeepCopy()
+	e.Clean()
+	fields, err := runtime.DefaultUnstructuredConverter.ToUnstructured(e.Obj)
+	if err != nil {
+		return nil, err
+	}
+
+	delete(fields, "metadata")
+	delete(fields, "status")
+	if labels := e.Labels(); len(labels) > 0 {
+		fields["labels"] = labels
+	}
+	return fields, nil
+}
+
+// Keeps only the parts of the value that appear in the shape.
+//
+// List items are matched by index. Items beyond the end of the shape's list
+// are kept, so that adding to a list Tilt applied still counts as drift.
+func projectFields(value interface{}, shape interface{}) interface{} {
+	switch shape := shape.(type) {
+	case map[string]interface{}:
+		m, ok := value.(map[string]interface{})
+		if !ok {
+			return value
+		}
+		result := make(map[string]interface{}, len(shape))
+		for k, sub := range shape {
+			v, ok := m[k]
+			if ok {
+				result[k] = projectFields(v, sub)
+			}
+		}
+		return result
+	case []interface{}:
+		list, ok := value.([]interface{})
+		if !ok {
+			return value
+		}
+		result := make([]interface{}, len(list))
+		for i, v := range list {
+			if i < len(shape) {
+				result[i] = projectFields(v, shape[i])
+			} else {
+				result[i] = v
+			}
+		}
+		return result
+	}
+	return value
+}
+
+type shapeKey struct {
+	Kind      string
+	Namespace string
+	Name      string
+}
+
+// The fields of each object in the YAML that Tilt applied.
+type appliedShapes map[shapeKey]k8s.K8sEntity
+
+func newAppliedShapes(yaml string) (appliedShapes, error) {
+	shapes := make(appliedShapes)
+	if yaml == "" {
+		return shapes, nil
+	}
+
+	entities, err := k8s.ParseYAMLFromString(yaml)
+	if err != nil {
+		return nil, err
+	}
+	for _, e := range entities {
+		shapes[shapeKey{Kind: e.GVK().Kind, Namespace: e.Meta().GetNamespace(), Name: e.Name()}] = e
+	}
+	return shapes, nil
+}
+
+// Returns the shape of the given applied object.
+//
+// The YAML often leaves out the namespace. Helm releases and custom deploy
+// commands don't have YAML at all, so we fall back to the apply result, which
+// still leaves out any fields that controllers filled in after the apply.
+func (s appliedShapes) forEntity(e k8s.K8sEntity) (map[string]interface{}, error) {
+	shape, ok := s[shapeKey{Kind: e.GVK().Kind, Namespace: e.Meta().GetNamespace(), Name: e.Name()}]
+	if !ok {
+		shape, ok = s[shapeKey{Kind: e.GVK().Kind, Name: e.Name()}]
+	}
+	if !ok {
+		shape = e
+	}
+	return driftFields(shape)
+}
+
+func isDriftedCondition(status v1alpha1.KubernetesApplyStatus) bool {
+	for _, c := range status.Conditions {
+		if c.Type == v1alpha1.KubernetesApplyConditionDrifted {
+			return c.Status == metav1.ConditionTrue
+		}
+	}
+	return false
+}
+
+// Updates the Drifted condition in place.
+//
+// Returns true if the condition changed.
+func setDriftedCondition(status *v1alpha1.KubernetesApplyStatus, s metav1.ConditionStatus, reason, msg string) bool {
+	c := v1alpha1.KubernetesApplyCondition{
+		Type:    v1alpha1.KubernetesApplyConditionDrifted,
+		Status:  s,
+		Reason:  reason,
+		Message: msg,
+	}
+
+	for i, existing := range status.Conditions {
+		if existing.Type != c.Type {
+			continue
+		}
+		if existing.Status == c.Status && existing.Reason == c.Reason && existing.Message == c.Message {
+			return false
+		}
+
+		c.LastTransitionTime = existing.LastTransitionTime
+		if existing.Status != c.Status {
+			c.LastTransitionTime = apis.NowMicro()
+		}
+		status.Conditions[i] = c
+		return true
+	}
+
+	c.LastTransitionTime = apis.NowMicro()
+	status.Conditions = append(status.Conditions, c)
+	return true
+}
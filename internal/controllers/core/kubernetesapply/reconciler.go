@@ -10,6 +10,7 @@ import (
 	"github.com/pkg/errors"
 	v1 "k8s.io/api/core/v1"
 	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/runtime"
 	"k8s.io/apimachinery/pkg/types"
 	ctrl "sigs.k8s.io/controller-runtime"
@@ -37,8 +38,9 @@ import (
 )
 
 type deleteSpec struct {
-	entities  []k8s.K8sEntity
-	deleteCmd *v1alpha1.KubernetesApplyCmd
+	entities    []k8s.K8sEntity
+	deleteCmd   *v1alpha1.KubernetesApplyCmd
+	helmRelease *v1alpha1.KubernetesHelmRelease
 }
 
 type Reconciler struct {
@@ -146,7 +148,7 @@ func (r *Reconciler) Reconcile(ctx context.Context, request reconcile.Request) (
 		// TODO(nick): Like with other reconcilers, there should always
 		// be a reason why we're not deploying, and we should update the
 		// Status field of KubernetesApply with that reason.
-		return ctrl.Result{}, nil
+		return r.checkDrift(ctx, nn, &ka, imageMaps)
 	}
 
 	// Update the apiserver with the result of this deploy.
@@ -155,6 +157,9 @@ func (r *Reconciler) Reconcile(ctx context.Context, request reconcile.Request) (
 		return ctrl.Result{}, err
 	}
 
+	if ka.Spec.DriftDetection != nil {
+		return ctrl.Result{RequeueAfter: driftCheckInterval}, nil
+	}
 	return ctrl.Result{}, nil
 }
 
@@ -259,6 +264,14 @@ func (r *Reconciler) ForceApply(
 		return status, err
 	}
 
+	if spec.DriftDetection != nil {
+		// Keep the drift condition across applies, so that we can tell when it transitions.
+		status.Conditions = ka.Status.Conditions
+		if status.Error == "" {
+			setDriftedCondition(&status, metav1.ConditionFalse, "InSync", "")
+		}
+	}
+
 	ka.Status = status
 	err = r.ctrlClient.Status().Update(ctx, &ka)
 	if err != nil {
@@ -303,6 +316,11 @@ func (r *Reconciler) forceApplyHelper(
 		if err != nil {
 			return errorStatus(err), nil
 		}
+	} else if spec.HelmRelease != nil {
+		deployed, err = r.runHelmDeploy(ctx, spec, imageMaps)
+		if err != nil {
+			return errorStatus(err), nil
+		}
 	} else {
 		deployed, err = r.runCmdDeploy(ctx, spec)
 		if err != nil {
@@ -335,7 +353,11 @@ func (r *Reconciler) runYAMLDeploy(ctx context.Context, spec v1alpha1.Kubernetes
 	ctx = r.indentLogger(ctx)
 	l := logger.Get(ctx)
 
-	l.Infof("Applying via kubectl:")
+	if spec.ApplyMode == v1alpha1.KubernetesApplyModeServerSide {
+		l.Infof("Applying via server-side apply:")
+	} else {
+		l.Infof("Applying via kubectl:")
+	}
 
 	// Use a min component count of 2 for computing names,
 	// so that the resource type appears
@@ -349,7 +371,12 @@ func (r *Reconciler) runYAMLDeploy(ctx context.Context, spec v1alpha1.Kubernetes
 		timeout = v1alpha1.KubernetesApplyTimeoutDefault
 	}
 
-	deployed, err := r.k8sClient.Upsert(ctx, newK8sEntities, timeout)
+	var deployed []k8s.K8sEntity
+	if spec.ApplyMode == v1alpha1.KubernetesApplyModeServerSide {
+		deployed, err = r.k8sClient.ServerSideApply(ctx, newK8sEntities, timeout)
+	} else {
+		deployed, err = r.k8sClient.Upsert(ctx, newK8sEntities, timeout)
+	}
 	if err != nil {
 		return nil, err
 	}
@@ -357,6 +384,40 @@ func (r *Reconciler) runYAMLDeploy(ctx context.Context, spec v1alpha1.Kubernetes
 	return deployed, nil
 }
 
+func (r *Reconciler) runHelmDeploy(ctx context.Context, spec v1alpha1.KubernetesApplySpec, imageMaps map[types.NamespacedName]*v1alpha1.ImageMap) ([]k8s.K8sEntity, error) {
+	release := *spec.HelmRelease
+	timeout := spec.Timeout.Duration
+	if timeout == 0 {
+		timeout = v1alpha1.KubernetesApplyTimeoutDefault
+	}
+
+	ctx = r.indentLogger(ctx)
+	l := logger.Get(ctx)
+	l.Infof("Installing Helm release %s from chart %s", release.ReleaseName, release.Chart)
+
+	// Inject images into the rendered chart, the same way we inject them into YAML.
+	//
+	// Helm doesn't post-render hooks, so images in hooks aren't replaced.
+	postRender := func(entities []k8s.K8sEntity) ([]k8s.K8sEntity, error) {
+		return r.injectEntities(imageMaps, spec, entities)
+	}
+
+	deployed, err := r.k8sClient.UpsertHelmRelease(ctx, release, timeout, postRender)
+	if err != nil {
+		return nil, err
+	}
+
+	l.Infof("Installed Helm release %s:", release.ReleaseName)
+
+	// Use a min component count of 2 for computing names,
+	// so that the resource type appears
+	displayNames := k8s.UniqueNames(deployed, 2)
+	for _, displayName := range displayNames {
+		l.Infof("→ %s", displayName)
+	}
+	return deployed, nil
+}
+
 func (r *Reconciler) runCmdDeploy(ctx context.Context, spec v1alpha1.KubernetesApplySpec) ([]k8s.K8sEntity, error) {
 	timeout := spec.Timeout.Duration
 	if timeout == 0 {
@@ -397,12 +458,19 @@ func (r *Reconciler) indentLogger(ctx context.Context) context.Context {
 func (r *Reconciler) createEntitiesToDeploy(ctx context.Context,
 	imageMaps map[types.NamespacedName]*v1alpha1.ImageMap,
 	spec v1alpha1.KubernetesApplySpec) ([]k8s.K8sEntity, error) {
-	newK8sEntities := []k8s.K8sEntity{}
-
 	entities, err := k8s.ParseYAMLFromString(spec.YAML)
 	if err != nil {
 		return nil, err
 	}
+	return r.injectEntities(imageMaps, spec, entities)
+}
+
+// Injects labels and images into the entities we're about to deploy.
+func (r *Reconciler) injectEntities(
+	imageMaps map[types.NamespacedName]*v1alpha1.ImageMap,
+	spec v1alpha1.KubernetesApplySpec,
+	entities []k8s.K8sEntity) ([]k8s.K8sEntity, error) {
+	newK8sEntities := []k8s.K8sEntity{}
 
 	locators, err := k8s.ParseImageLocators(spec.ImageLocators)
 	if err != nil {
@@ -537,6 +605,21 @@ func (r *Reconciler) updateResult(nn types.NamespacedName, result *Result) delet
 		return deleteSpec{deleteCmd: existing.Spec.DeleteCmd}
 	}
 
+	if existing.Spec.HelmRelease != nil {
+		// Helm deletes objects that are no longer in the release, so we only
+		// need to clean up if the release itself went away.
+		var current *v1alpha1.KubernetesHelmRelease
+		if result != nil {
+			current = result.Spec.HelmRelease
+		}
+		if current == nil ||
+			current.ReleaseName != existing.Spec.HelmRelease.ReleaseName ||
+			current.Namespace != existing.Spec.HelmRelease.Namespace {
+			return deleteSpec{helmRelease: existing.Spec.HelmRelease}
+		}
+		return deleteSpec{}
+	}
+
 	// Go through all the results, and check to see which objects
 	// we're not managing anymore.
 	// TODO(milas): in the case that the KA object was deleted, should we respect `tilt.dev/down-policy`?
@@ -555,7 +638,7 @@ func (r *Reconciler) updateResult(nn types.NamespacedName, result *Result) delet
 }
 
 func (r *Reconciler) bestEffortDelete(ctx context.Context, toDelete deleteSpec) {
-	if len(toDelete.entities) == 0 && toDelete.deleteCmd == nil {
+	if len(toDelete.entities) == 0 && toDelete.deleteCmd == nil && toDelete.helmRelease == nil {
 		return
 	}
 
@@ -582,6 +665,14 @@ func (r *Reconciler) bestEffortDelete(ctx context.Context, toDelete deleteSpec)
 			l.Errorf("Error garbage collecting Kubernetes resources: %v", err)
 		}
 	}
+
+	if toDelete.helmRelease != nil {
+		l.Infof("→ Helm release %s", toDelete.helmRelease.ReleaseName)
+		err := r.k8sClient.UninstallHelmRelease(ctx, *toDelete.helmRelease, v1alpha1.KubernetesApplyTimeoutDefault)
+		if err != nil {
+			l.Errorf("Error garbage collecting Kubernetes resources: %v", err)
+		}
+	}
 }
 
 var imGVK = v1alpha1.SchemeGroupVersion.WithKind("ImageMap")
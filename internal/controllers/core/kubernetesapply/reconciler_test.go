@@ -4,12 +4,15 @@ import (
 	"errors"
 	"fmt"
 	"reflect"
+	"strings"
 	"testing"
 	"time"
 
 	"github.com/davecgh/go-spew/spew"
 	"github.com/google/uuid"
 	"github.com/stretchr/testify/require"
+	appsv1 "k8s.io/api/apps/v1"
+	v1 "k8s.io/api/core/v1"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/types"
 	"sigs.k8s.io/controller-runtime/pkg/reconcile"
@@ -24,9 +27,12 @@ import (
 	"github.com/tilt-dev/tilt/internal/k8s/testyaml"
 	"github.com/tilt-dev/tilt/internal/localexec"
 	"github.com/tilt-dev/tilt/internal/store"
+	"github.com/tilt-dev/tilt/internal/store/kubernetesapplys"
 	"github.com/tilt-dev/tilt/internal/timecmp"
 	"github.com/tilt-dev/tilt/pkg/apis"
 	"github.com/tilt-dev/tilt/pkg/apis/core/v1alpha1"
+	"github.com/tilt-dev/tilt/pkg/logger"
+	"github.com/tilt-dev/tilt/pkg/model"
 )
 
 func TestImageIndexing(t *testing.T) {
@@ -434,6 +440,304 @@ func TestIgnoreManagedObjects(t *testing.T) {
 	assert.Equal(f.T(), result, ka.Status)
 }
 
+func TestServerSideApply(t *testing.T) {
+	f := newFixture(t)
+	ka := v1alpha1.KubernetesApply{
+		ObjectMeta: metav1.ObjectMeta{
+			Name: "a",
+		},
+		Spec: v1alpha1.KubernetesApplySpec{
+			YAML:      testyaml.SanchoYAML,
+			ApplyMode: v1alpha1.KubernetesApplyModeServerSide,
+		},
+	}
+	f.Create(&ka)
+
+	f.MustReconcile(types.NamespacedName{Name: "a"})
+	assert.Contains(f.T(), f.kClient.Yaml, "name: sancho")
+	assert.True(f.T(), f.kClient.LastUpsertServerSide)
+
+	f.MustGet(types.NamespacedName{Name: "a"}, &ka)
+	assert.Contains(f.T(), ka.Status.ResultYAML, "name: sancho")
+}
+
+func TestDriftDetection(t *testing.T) {
+	f := newFixture(t)
+	nn := types.NamespacedName{Name: "a"}
+	ka := v1alpha1.KubernetesApply{
+		ObjectMeta: metav1.ObjectMeta{
+			Name: "a",
+		},
+		Spec: v1alpha1.KubernetesApplySpec{
+			YAML:           testyaml.SanchoYAML,
+			DriftDetection: &v1alpha1.KubernetesDriftDetection{},
+		},
+	}
+	f.Create(&ka)
+
+	result := f.MustReconcile(nn)
+	assert.Equal(t, driftCheckInterval, result.RequeueAfter)
+	f.kClient.Inject(f.kClient.LastUpsertResult...)
+
+	result = f.MustReconcile(nn)
+	assert.Equal(t, driftCheckInterval, result.RequeueAfter)
+	f.MustGet(nn, &ka)
+	assertDrifted(t, ka, metav1.ConditionFalse)
+
+	// Scale the deployment behind Tilt's back.
+	f.st.ClearActions()
+	f.kClient.Yaml = ""
+	f.kClient.Inject(scaled(t, f.kClient.LastUpsertResult[0], 3))
+
+	f.MustReconcile(nn)
+	f.MustGet(nn, &ka)
+	c := assertDrifted(t, ka, metav1.ConditionTrue)
+	assert.Contains(t, c.Message, "sancho:deployment")
+	assert.Contains(t, f.warnings(), "Kubernetes objects have drifted from the last apply")
+	assert.Equal(t, "", f.kClient.Yaml, "should not reapply without reapply=True")
+
+	// Only warn when the objects first drift.
+	f.st.ClearActions()
+	f.MustReconcile(nn)
+	assert.Empty(t, f.warnings())
+}
+
+const driftClaimYAML = `
+apiVersion: v1
+kind: PersistentVolumeClaim
+metadata:
+  name: claim
+spec:
+  storageClassName: manual
+  accessModes:
+    - ReadWriteOnce
+  resources:
+    requests:
+      storage: 1Gi
+---
+apiVersion: v1
+kind: ServiceAccount
+metadata:
+  name: builder
+`
+
+func TestDriftDetectionIgnoresFieldsFilledInByControllers(t *testing.T) {
+	f := newFixture(t)
+	nn := types.NamespacedName{Name: "a"}
+	ka := v1alpha1.KubernetesApply{
+		ObjectMeta: metav1.ObjectMeta{
+			Name: "a",
+		},
+		Spec: v1alpha1.KubernetesApplySpec{
+			YAML:           driftClaimYAML,
+			DriftDetection: &v1alpha1.KubernetesDriftDetection{Reapply: true},
+		},
+	}
+	f.Create(&ka)
+	f.MustReconcile(nn)
+	require.Len(t, f.kClient.LastUpsertResult, 2)
+
+	// Bind the claim and add a token secret, like the controllers in the cluster do.
+	claim := f.kClient.LastUpsertResult[0].DeepCopy()
+	claim.Obj.(*v1.PersistentVolumeClaim).Spec.VolumeName = "pvc-1234"
+	sa := f.kClient.LastUpsertResult[1].DeepCopy()
+	sa.Obj.(*v1.ServiceAccount).Secrets = []v1.ObjectReference{{Name: "builder-token-abcde"}}
+	f.kClient.Inject(claim, sa)
+
+	f.kClient.Yaml = ""
+	f.MustReconcile(nn)
+	f.MustGet(nn, &ka)
+	assertDrifted(t, ka, metav1.ConditionFalse)
+	assert.Equal(t, "", f.kClient.Yaml, "should not reapply")
+
+	// Changing a field that Tilt applied is still drift.
+	claim = claim.DeepCopy()
+	claim.Obj.(*v1.PersistentVolumeClaim).Spec.AccessModes = []v1.PersistentVolumeAccessMode{v1.ReadWriteMany}
+	f.kClient.Inject(claim)
+
+	f.MustReconcile(nn)
+	assert.Contains(t, f.kClient.Yaml, "name: claim")
+}
+
+func TestDriftDetectionDeletedObject(t *testing.T) {
+	f := newFixture(t)
+	nn := types.NamespacedName{Name: "a"}
+	ka := v1alpha1.KubernetesApply{
+		ObjectMeta: metav1.ObjectMeta{
+			Name: "a",
+		},
+		Spec: v1alpha1.KubernetesApplySpec{
+			YAML:           testyaml.SanchoYAML,
+			DriftDetection: &v1alpha1.KubernetesDriftDetection{},
+		},
+	}
+	f.Create(&ka)
+
+	f.MustReconcile(nn)
+	f.MustReconcile(nn)
+	f.MustGet(nn, &ka)
+	c := assertDrifted(t, ka, metav1.ConditionTrue)
+	assert.Contains(t, c.Message, "sancho:deployment (deleted)")
+}
+
+func TestDriftDetectionReapply(t *testing.T) {
+	f := newFixture(t)
+	nn := types.NamespacedName{Name: "a"}
+	ka := v1alpha1.KubernetesApply{
+		ObjectMeta: metav1.ObjectMeta{
+			Name: "a",
+		},
+		Spec: v1alpha1.KubernetesApplySpec{
+			YAML:           testyaml.SanchoYAML,
+			DriftDetection: &v1alpha1.KubernetesDriftDetection{Reapply: true},
+		},
+	}
+	f.Create(&ka)
+
+	f.MustReconcile(nn)
+	f.kClient.Inject(scaled(t, f.kClient.LastUpsertResult[0], 3))
+
+	f.kClient.Yaml = ""
+	f.MustReconcile(nn)
+	assert.Contains(t, f.kClient.Yaml, "name: sancho")
+
+	// The reapply resets the condition.
+	f.MustGet(nn, &ka)
+	assertDrifted(t, ka, metav1.ConditionFalse)
+}
+
+func TestDriftDetectionReapplyManagedObjects(t *testing.T) {
+	f := newFixture(t)
+	nn := types.NamespacedName{Name: "a"}
+	ka := v1alpha1.KubernetesApply{
+		ObjectMeta: metav1.ObjectMeta{
+			Name: "a",
+			Annotations: map[string]string{
+				v1alpha1.AnnotationManagedBy: "buildcontrol",
+				v1alpha1.AnnotationManifest:  "sancho",
+			},
+		},
+		Spec: v1alpha1.KubernetesApplySpec{
+			YAML:           testyaml.SanchoYAML,
+			DriftDetection: &v1alpha1.KubernetesDriftDetection{Reapply: true},
+		},
+	}
+	f.Create(&ka)
+
+	_, err := f.r.ForceApply(f.Context(), nn, ka.Spec, nil)
+	require.NoError(t, err)
+	f.kClient.Inject(scaled(t, f.kClient.LastUpsertResult[0], 3))
+
+	f.kClient.Yaml = ""
+	f.MustReconcile(nn)
+	assert.Equal(t, "", f.kClient.Yaml, "buildcontrol should reapply managed objects")
+
+	action := f.st.WaitForAction(t, reflect.TypeOf(kubernetesapplys.KubernetesApplyReapplyAction{}))
+	assert.Equal(t, model.ManifestName("sancho"), action.(kubernetesapplys.KubernetesApplyReapplyAction).ManifestName)
+}
+
+func TestHelmRelease(t *testing.T) {
+	f := newFixture(t)
+	f.kClient.HelmReleaseYAML = testyaml.SanchoYAML
+	release := &v1alpha1.KubernetesHelmRelease{
+		ReleaseName: "sancho",
+		Chart:       "/path/to/chart",
+		Set:         []string{"replicas=2"},
+	}
+	ka := v1alpha1.KubernetesApply{
+		ObjectMeta: metav1.ObjectMeta{
+			Name: "a",
+		},
+		Spec: v1alpha1.KubernetesApplySpec{
+			HelmRelease: release,
+		},
+	}
+	f.Create(&ka)
+
+	f.MustReconcile(types.NamespacedName{Name: "a"})
+	assert.Equal(t, release, f.kClient.LastHelmRelease)
+
+	// Make sure the rendered objects went through the same injection as YAML.
+	assert.Contains(t, f.kClient.Yaml, "app.kubernetes.io/managed-by: tilt")
+
+	f.MustGet(types.NamespacedName{Name: "a"}, &ka)
+	assert.Equal(t, "", ka.Status.Error)
+	assert.Contains(t, ka.Status.ResultYAML, "name: sancho")
+	assert.Contains(t, ka.Status.ResultYAML, "uid:")
+}
+
+func TestHelmReleaseError(t *testing.T) {
+	f := newFixture(t)
+	f.kClient.HelmReleaseYAML = testyaml.SanchoYAML
+	f.kClient.UpsertError = errors.New("helm upgrade sancho (rolled back to the previous revision): oh no")
+	ka := v1alpha1.KubernetesApply{
+		ObjectMeta: metav1.ObjectMeta{
+			Name: "a",
+		},
+		Spec: v1alpha1.KubernetesApplySpec{
+			HelmRelease: &v1alpha1.KubernetesHelmRelease{ReleaseName: "sancho", Chart: "/path/to/chart"},
+		},
+	}
+	f.Create(&ka)
+
+	f.MustReconcile(types.NamespacedName{Name: "a"})
+	f.MustGet(types.NamespacedName{Name: "a"}, &ka)
+	assert.Contains(t, ka.Status.Error, "rolled back to the previous revision): oh no")
+}
+
+func TestHelmReleaseUninstallOnDelete(t *testing.T) {
+	f := newFixture(t)
+	f.kClient.HelmReleaseYAML = testyaml.SanchoYAML
+	ka := v1alpha1.KubernetesApply{
+		ObjectMeta: metav1.ObjectMeta{
+			Name: "a",
+		},
+		Spec: v1alpha1.KubernetesApplySpec{
+			HelmRelease: &v1alpha1.KubernetesHelmRelease{ReleaseName: "sancho", Chart: "/path/to/chart"},
+		},
+	}
+	f.Create(&ka)
+	f.MustReconcile(types.NamespacedName{Name: "a"})
+
+	f.Delete(&ka)
+	f.MustReconcile(types.NamespacedName{Name: "a"})
+	if assert.Len(t, f.kClient.UninstalledHelmReleases, 1) {
+		assert.Equal(t, "sancho", f.kClient.UninstalledHelmReleases[0].ReleaseName)
+	}
+	assert.Empty(t, f.kClient.DeletedYaml, "helm should delete the objects in the release")
+}
+
+func TestHelmReleaseUninstallOnRename(t *testing.T) {
+	f := newFixture(t)
+	f.kClient.HelmReleaseYAML = testyaml.SanchoYAML
+	ka := v1alpha1.KubernetesApply{
+		ObjectMeta: metav1.ObjectMeta{
+			Name: "a",
+		},
+		Spec: v1alpha1.KubernetesApplySpec{
+			HelmRelease: &v1alpha1.KubernetesHelmRelease{ReleaseName: "sancho", Chart: "/path/to/chart"},
+		},
+	}
+	f.Create(&ka)
+	f.MustReconcile(types.NamespacedName{Name: "a"})
+
+	// Changing the values upgrades the release in place.
+	f.MustGet(types.NamespacedName{Name: "a"}, &ka)
+	ka.Spec.HelmRelease.Set = []string{"replicas=2"}
+	f.Update(&ka)
+	f.MustReconcile(types.NamespacedName{Name: "a"})
+	assert.Empty(t, f.kClient.UninstalledHelmReleases)
+
+	f.MustGet(types.NamespacedName{Name: "a"}, &ka)
+	ka.Spec.HelmRelease.ReleaseName = "sancho-2"
+	f.Update(&ka)
+	f.MustReconcile(types.NamespacedName{Name: "a"})
+	assert.Equal(t, "sancho-2", f.kClient.LastHelmRelease.ReleaseName)
+	if assert.Len(t, f.kClient.UninstalledHelmReleases, 1) {
+		assert.Equal(t, "sancho", f.kClient.UninstalledHelmReleases[0].ReleaseName)
+	}
+}
+
 type fixture struct {
 	*fake.ControllerFixture
 	r       *Reconciler
@@ -486,3 +790,36 @@ func (f *fixture) createApplyCmd(name string, yaml string) (v1alpha1.KubernetesA
 		Args: []string{name},
 	}, yamlOut
 }
+
+// Returns the warnings logged since the last ClearActions().
+func (f *fixture) warnings() string {
+	var sb strings.Builder
+	for _, a := range f.st.Actions() {
+		la, ok := a.(store.LogAction)
+		if ok && la.Level() == logger.WarnLvl {
+			sb.Write(la.Message())
+		}
+	}
+	return sb.String()
+}
+
+func assertDrifted(t *testing.T, ka v1alpha1.KubernetesApply, status metav1.ConditionStatus) v1alpha1.KubernetesApplyCondition {
+	t.Helper()
+	for _, c := range ka.Status.Conditions {
+		if c.Type == v1alpha1.KubernetesApplyConditionDrifted {
+			assert.Equal(t, status, c.Status)
+			return c
+		}
+	}
+	t.Fatalf("KubernetesApply %s has no Drifted condition", ka.Name)
+	return v1alpha1.KubernetesApplyCondition{}
+}
+
+// Returns a copy of the deployment with a different number of replicas.
+func scaled(t *testing.T, e k8s.K8sEntity, replicas int32) k8s.K8sEntity {
+	e = e.DeepCopy()
+	d, ok := e.Obj.(*appsv1.Deployment)
+	require.True(t, ok, "expected a Deployment, got %T", e.Obj)
+	d.Spec.Replicas = &replicas
+	return e
+}
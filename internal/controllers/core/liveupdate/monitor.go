@@ -3,6 +3,7 @@ package liveupdate
 import (
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 
+	"github.com/tilt-dev/tilt/internal/container"
 	"github.com/tilt-dev/tilt/pkg/apis/core/v1alpha1"
 )
 
@@ -22,6 +23,10 @@ type monitor struct {
 	lastKubernetesApplyStatus *v1alpha1.KubernetesApplyStatus
 	lastTriggerQueue          *v1alpha1.ConfigMap
 
+	// The container ID for the Docker Compose service (possibly empty
+	// if the container hasn't started). Nil if we haven't looked yet.
+	lastDockerComposeContainerID *container.ID
+
 	// History of source file changes.
 	sources map[string]*monitorSource
 
@@ -52,9 +52,10 @@ var reasonObjectNotFound = "ObjectNotFound"
 
 // Manages the LiveUpdate API object.
 type Reconciler struct {
-	client  ctrlclient.Client
-	indexer *indexer.Indexer
-	store   store.RStore
+	client   ctrlclient.Client
+	indexer  *indexer.Indexer
+	requeuer *indexer.Requeuer
+	store    store.RStore
 
 	ExecUpdater   containerupdate.ContainerUpdater
 	DockerUpdater containerupdate.ContainerUpdater
@@ -69,6 +70,7 @@ type Reconciler struct {
 }
 
 var _ reconcile.Reconciler = &Reconciler{}
+var _ store.Subscriber = &Reconciler{}
 
 // Dependency-inject a live update reconciler.
 func NewReconciler(
@@ -86,6 +88,7 @@ func NewReconciler(
 		kubeContext:   kubeContext,
 		client:        client,
 		indexer:       indexer.NewIndexer(scheme, indexLiveUpdate),
+		requeuer:      indexer.NewRequeuer(),
 		store:         st,
 		startedTime:   apis.NowMicro(),
 		monitors:      make(map[string]*monitor),
@@ -105,6 +108,7 @@ func NewFakeReconciler(
 		kubeContext:   k8s.KubeContext("fake-context"),
 		client:        client,
 		indexer:       indexer.NewIndexer(scheme, indexLiveUpdate),
+		requeuer:      indexer.NewRequeuer(),
 		store:         st,
 		startedTime:   apis.NowMicro(),
 		monitors:      make(map[string]*monitor),
@@ -124,6 +128,12 @@ func (r *Reconciler) Reconcile(ctx context.Context, req ctrl.Request) (ctrl.Resu
 
 	if apierrors.IsNotFound(err) || lu.ObjectMeta.DeletionTimestamp != nil {
 		r.store.Dispatch(liveupdates.NewLiveUpdateDeleteAction(req.Name))
+		monitor, ok := r.monitors[req.Name]
+		if ok {
+			for key := range monitor.containers {
+				r.forgetMonitorContainer(monitor, key)
+			}
+		}
 		delete(r.monitors, req.Name)
 		return ctrl.Result{}, nil
 	}
@@ -162,12 +172,14 @@ func (r *Reconciler) Reconcile(ctx context.Context, req ctrl.Request) (ctrl.Resu
 		return ctrl.Result{}, err
 	}
 
+	hasDockerComposeChanges := r.reconcileDockerComposeService(lu, monitor)
+
 	hasTriggerQueueChanges, err := r.reconcileTriggerQueue(ctx, monitor)
 	if err != nil {
 		return ctrl.Result{}, err
 	}
 
-	if hasFileChanges || hasKubernetesChanges || hasTriggerQueueChanges {
+	if hasFileChanges || hasKubernetesChanges || hasDockerComposeChanges || hasTriggerQueueChanges {
 		monitor.hasChangesToSync = true
 	}
 
@@ -204,15 +216,27 @@ func (r *Reconciler) shouldLogFailureReason(obj *v1alpha1.LiveUpdateStateFailed)
 
 // Check for some invalid states.
 func (r *Reconciler) ensureSelectorValid(lu *v1alpha1.LiveUpdate) *v1alpha1.LiveUpdateStateFailed {
-	selector := lu.Spec.Selector.Kubernetes
-	if selector == nil {
-		return createFailedState(lu, "Invalid", "No valid selector")
+	kSelector := lu.Spec.Selector.Kubernetes
+	dcSelector := lu.Spec.Selector.DockerCompose
+	if kSelector != nil && dcSelector != nil {
+		return createFailedState(lu, "Invalid", "Only one of Kubernetes or DockerCompose selectors may be set")
 	}
 
-	if selector.DiscoveryName == "" {
-		return createFailedState(lu, "Invalid", "Kubernetes selector requires DiscoveryName")
+	if kSelector != nil {
+		if kSelector.DiscoveryName == "" {
+			return createFailedState(lu, "Invalid", "Kubernetes selector requires DiscoveryName")
+		}
+		return nil
 	}
-	return nil
+
+	if dcSelector != nil {
+		if dcSelector.Service == "" {
+			return createFailedState(lu, "Invalid", "DockerCompose selector requires Service")
+		}
+		return nil
+	}
+
+	return createFailedState(lu, "Invalid", "No valid selector")
 }
 
 // If the failure state has changed, log it and write it to the apiserver.
@@ -418,6 +442,66 @@ func (r *Reconciler) reconcileKubernetesResource(ctx context.Context, monitor *m
 	return changed, nil
 }
 
+// Find the container running the service in the DockerCompose selector.
+// Returns true if the container has changed since we last looked.
+//
+// The engine tracks the service's container from Docker Compose events
+// and deploys, so we read it from the state of the manifest.
+func (r *Reconciler) reconcileDockerComposeService(lu *v1alpha1.LiveUpdate, monitor *monitor) bool {
+	if monitor.spec.Selector.DockerCompose == nil {
+		return false
+	}
+
+	var cID container.ID
+	mn := model.ManifestName(lu.Annotations[v1alpha1.AnnotationManifest])
+	state := r.store.RLockState()
+	ms, ok := state.ManifestState(mn)
+	if ok {
+		cID = ms.DCRuntimeState().ContainerID
+	}
+	r.store.RUnlockState()
+
+	changed := monitor.lastDockerComposeContainerID == nil ||
+		*monitor.lastDockerComposeContainerID != cID
+	monitor.lastDockerComposeContainerID = &cID
+	return changed
+}
+
+// Docker Compose containers aren't API objects yet, so we can't watch them.
+// Instead, re-queue any LiveUpdate whose service container in the engine
+// state no longer matches the one it last saw.
+func (r *Reconciler) OnChange(ctx context.Context, st store.RStore, summary store.ChangeSummary) error {
+	r.mu.Lock()
+	lastSeen := make(map[string]container.ID)
+	manifests := make(map[string]model.ManifestName)
+	for name, monitor := range r.monitors {
+		if monitor.spec.Selector.DockerCompose == nil || monitor.lastDockerComposeContainerID == nil {
+			continue
+		}
+		lastSeen[name] = *monitor.lastDockerComposeContainerID
+		manifests[name] = model.ManifestName(monitor.manifestName)
+	}
+	r.mu.Unlock()
+
+	if len(lastSeen) == 0 {
+		return nil
+	}
+
+	state := st.RLockState()
+	defer st.RUnlockState()
+	for name, lastCID := range lastSeen {
+		var cID container.ID
+		ms, ok := state.ManifestState(manifests[name])
+		if ok {
+			cID = ms.DCRuntimeState().ContainerID
+		}
+		if cID != lastCID {
+			r.requeuer.Add(types.NamespacedName{Name: name})
+		}
+	}
+	return nil
+}
+
 // Go through all the file changes, and delete files that aren't relevant
 // to the current build.
 //
@@ -427,6 +511,8 @@ func (r *Reconciler) reconcileKubernetesResource(ctx context.Context, monitor *m
 // 2) If there's no ImageMap, we prefer the KubernetesApply.LastApplyStartTime.
 // 3) If there's no KubernetesApply, we prefer the oldest pod
 //    in the filtered pod list.
+//
+// Docker Compose services only have the ImageMap to go on.
 func (r *Reconciler) garbageCollectFileChanges(res *k8sconv.KubernetesResource, monitor *monitor) {
 	for _, source := range monitor.spec.Sources {
 		fwn := source.FileWatch
@@ -439,9 +525,9 @@ func (r *Reconciler) garbageCollectFileChanges(res *k8sconv.KubernetesResource,
 		var gcTime time.Time
 		if lastImageStatus != nil && lastImageStatus.BuildStartTime != nil {
 			gcTime = lastImageStatus.BuildStartTime.Time
-		} else if res.ApplyStatus != nil {
+		} else if res != nil && res.ApplyStatus != nil {
 			gcTime = res.ApplyStatus.LastApplyStartTime.Time
-		} else {
+		} else if res != nil {
 			for _, pod := range res.FilteredPods {
 				if gcTime.IsZero() || (!pod.CreatedAt.IsZero() && pod.CreatedAt.Time.Before(gcTime)) {
 					gcTime = pod.CreatedAt.Time
@@ -480,6 +566,18 @@ func (r *Reconciler) garbageCollectFileChanges(res *k8sconv.KubernetesResource,
 // Go through all the container monitors, and delete any that are no longer
 // being selected. We don't care why they're not being selected.
 func (r *Reconciler) garbageCollectMonitorContainers(res *k8sconv.KubernetesResource, monitor *monitor) {
+	if res == nil {
+		// A Docker Compose service has exactly one container, so
+		// any container we're not currently connected to is gone.
+		for key := range monitor.containers {
+			if monitor.lastDockerComposeContainerID == nil ||
+				key.containerID != monitor.lastDockerComposeContainerID.String() {
+				r.forgetMonitorContainer(monitor, key)
+			}
+		}
+		return
+	}
+
 	podsByKey := map[monitorContainerKey]bool{}
 	for _, pod := range res.FilteredPods {
 		podsByKey[monitorContainerKey{podName: pod.Name, namespace: pod.Namespace}] = true
@@ -488,16 +586,40 @@ func (r *Reconciler) garbageCollectMonitorContainers(res *k8sconv.KubernetesReso
 	for key := range monitor.containers {
 		podKey := monitorContainerKey{podName: key.podName, namespace: key.namespace}
 		if !podsByKey[podKey] {
-			delete(monitor.containers, key)
+			r.forgetMonitorContainer(monitor, key)
 		}
 	}
 }
 
+// Stop tracking a container, and tell the updaters to forget what they
+// learned about it, so that a replacement container starts fresh.
+func (r *Reconciler) forgetMonitorContainer(monitor *monitor, key monitorContainerKey) {
+	delete(monitor.containers, key)
+	id := container.ID(key.containerID)
+	r.DockerUpdater.ForgetContainer(id)
+	r.ExecUpdater.ForgetContainer(id)
+}
+
 // Visit all selected containers.
+//
+// Docker Compose services don't have pods, so we represent
+// the service container as the only container in an unnamed pod.
 func (r *Reconciler) visitSelectedContainers(
-	kSelector *v1alpha1.LiveUpdateKubernetesSelector,
+	monitor *monitor,
 	kResource *k8sconv.KubernetesResource,
 	visit func(pod v1alpha1.Pod, c v1alpha1.Container) bool) {
+	dcSelector := monitor.spec.Selector.DockerCompose
+	if dcSelector != nil {
+		c := v1alpha1.Container{Name: dcSelector.Service}
+		if monitor.lastDockerComposeContainerID != nil && *monitor.lastDockerComposeContainerID != "" {
+			c.ID = monitor.lastDockerComposeContainerID.String()
+			c.State.Running = &v1alpha1.ContainerStateRunning{}
+		}
+		visit(v1alpha1.Pod{}, c)
+		return
+	}
+
+	kSelector := monitor.spec.Selector.Kubernetes
 	for _, pod := range kResource.FilteredPods {
 		for _, c := range pod.Containers {
 			// Only visit well-formed containers matching our image
@@ -540,10 +662,7 @@ func (r *Reconciler) dispatchCompleteBuildAction(lu *v1alpha1.LiveUpdate, newSta
 	if newStatus.Failed != nil {
 		err = fmt.Errorf("%s", newStatus.Failed.Message)
 	}
-	imageTargetID := model.TargetID{
-		Type: model.TargetTypeImage,
-		Name: model.TargetName(apis.SanitizeName(lu.Spec.Selector.Kubernetes.Image)),
-	}
+	imageTargetID := liveUpdateImageTargetID(lu)
 	containerIDs := []container.ID{}
 	for _, status := range newStatus.Containers {
 		if status.Waiting == nil {
@@ -555,20 +674,45 @@ func (r *Reconciler) dispatchCompleteBuildAction(lu *v1alpha1.LiveUpdate, newSta
 	r.store.Dispatch(buildcontrols.NewBuildCompleteAction(manifestName, spanID, resultSet, err))
 }
 
+// Determine the image target that this live update is updating,
+// so that we can report the result to the engine.
+func liveUpdateImageTargetID(lu *v1alpha1.LiveUpdate) model.TargetID {
+	if lu.Spec.Selector.Kubernetes != nil {
+		return model.TargetID{
+			Type: model.TargetTypeImage,
+			Name: model.TargetName(apis.SanitizeName(lu.Spec.Selector.Kubernetes.Image)),
+		}
+	}
+
+	name := ""
+	if lu.Spec.Selector.DockerCompose != nil {
+		name = apis.SanitizeName(lu.Spec.Selector.DockerCompose.Image)
+	}
+	return model.TargetID{
+		Type: model.TargetTypeImage,
+		Name: model.TargetName(name),
+	}
+}
+
 // Convert the currently tracked state into a set of inputs
 // to the updater, then apply them.
 func (r *Reconciler) maybeSync(ctx context.Context, lu *v1alpha1.LiveUpdate, monitor *monitor) v1alpha1.LiveUpdateStatus {
 	var status v1alpha1.LiveUpdateStatus
 	kSelector := lu.Spec.Selector.Kubernetes
-	if kSelector == nil {
+	dcSelector := lu.Spec.Selector.DockerCompose
+	if kSelector == nil && dcSelector == nil {
 		status.Failed = createFailedState(lu, "Invalid", "no valid selector")
 		return status
 	}
 
-	kResource, err := k8sconv.NewKubernetesResource(monitor.lastKubernetesDiscovery, monitor.lastKubernetesApplyStatus)
-	if err != nil {
-		status.Failed = createFailedState(lu, "KubernetesError", fmt.Sprintf("creating kube resource: %v", err))
-		return status
+	var kResource *k8sconv.KubernetesResource
+	if kSelector != nil {
+		var err error
+		kResource, err = k8sconv.NewKubernetesResource(monitor.lastKubernetesDiscovery, monitor.lastKubernetesApplyStatus)
+		if err != nil {
+			status.Failed = createFailedState(lu, "KubernetesError", fmt.Sprintf("creating kube resource: %v", err))
+			return status
+		}
 	}
 
 	manifestName := lu.Annotations[v1alpha1.AnnotationManifest]
@@ -587,7 +731,7 @@ func (r *Reconciler) maybeSync(ctx context.Context, lu *v1alpha1.LiveUpdate, mon
 
 	// Go through all the container monitors, and check if any of them are unrecoverable.
 	// If they are, it's not important to figure out why.
-	r.visitSelectedContainers(kSelector, kResource, func(pod v1alpha1.Pod, c v1alpha1.Container) bool {
+	r.visitSelectedContainers(monitor, kResource, func(pod v1alpha1.Pod, c v1alpha1.Container) bool {
 		cKey := monitorContainerKey{
 			containerID: c.ID,
 			podName:     pod.Name,
@@ -611,7 +755,7 @@ func (r *Reconciler) maybeSync(ctx context.Context, lu *v1alpha1.LiveUpdate, mon
 	// Visit all containers, apply changes, and return their statuses.
 	terminatedContainerPodName := ""
 	hasAnyFilesToSync := false
-	r.visitSelectedContainers(kSelector, kResource, func(pod v1alpha1.Pod, cInfo v1alpha1.Container) bool {
+	r.visitSelectedContainers(monitor, kResource, func(pod v1alpha1.Pod, cInfo v1alpha1.Container) bool {
 		c := liveupdates.Container{
 			ContainerID:   container.ID(cInfo.ID),
 			ContainerName: container.Name(cInfo.Name),
@@ -727,7 +871,7 @@ func (r *Reconciler) maybeSync(ctx context.Context, lu *v1alpha1.LiveUpdate, mon
 
 			// Apply the change to the container.
 			oneUpdateStatus = r.applyInternal(ctx, lu.Spec, Input{
-				IsDC:               false, // update this once we support DockerCompose in the API.
+				IsDC:               dcSelector != nil,
 				ChangedFiles:       plan.SyncPaths,
 				Containers:         []liveupdates.Container{c},
 				LastFileTimeSynced: newHighWaterMark,
@@ -934,6 +1078,7 @@ func (r *Reconciler) applyInternal(
 			PodName:            cInfo.PodID.String(),
 			Namespace:          cInfo.Namespace.String(),
 			LastFileTimeSynced: lastFileTimeSynced,
+			FileTransport:      cu.FileTransport(cInfo),
 		}
 
 		if err != nil {
@@ -1002,7 +1147,8 @@ func (r *Reconciler) CreateBuilder(mgr ctrl.Manager) (*builder.Builder, error) {
 		Watches(&source.Kind{Type: &v1alpha1.ImageMap{}},
 			handler.EnqueueRequestsFromMapFunc(r.indexer.Enqueue)).
 		Watches(&source.Kind{Type: &v1alpha1.ConfigMap{}},
-			handler.EnqueueRequestsFromMapFunc(r.enqueueTriggerQueue))
+			handler.EnqueueRequestsFromMapFunc(r.enqueueTriggerQueue)).
+		Watches(r.requeuer, handler.Funcs{})
 
 	return b, nil
 }
@@ -11,13 +11,16 @@ import (
 	"github.com/stretchr/testify/require"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/client-go/util/workqueue"
 	"sigs.k8s.io/controller-runtime/pkg/reconcile"
 
+	"github.com/tilt-dev/tilt/internal/build"
 	"github.com/tilt-dev/tilt/internal/container"
 	"github.com/tilt-dev/tilt/internal/containerupdate"
 	"github.com/tilt-dev/tilt/internal/controllers/apis/configmap"
 	"github.com/tilt-dev/tilt/internal/controllers/apis/liveupdate"
 	"github.com/tilt-dev/tilt/internal/controllers/fake"
+	"github.com/tilt-dev/tilt/internal/dockercompose"
 	"github.com/tilt-dev/tilt/internal/store"
 	"github.com/tilt-dev/tilt/internal/store/buildcontrols"
 	"github.com/tilt-dev/tilt/pkg/apis"
@@ -566,6 +569,171 @@ func TestStopPathConsumedByKubernetesApply(t *testing.T) {
 	assert.Equal(t, 1, len(f.cu.Calls))
 }
 
+func TestDockerComposeConsumeFileEvents(t *testing.T) {
+	f := newFixture(t)
+
+	p, _ := os.Getwd()
+	nowMicro := apis.NowMicro()
+	txtPath := filepath.Join(p, "a.txt")
+	txtChangeTime := metav1.MicroTime{Time: nowMicro.Add(time.Second)}
+
+	f.setDockerComposeContainerID("backend-id")
+	f.setupDockerComposeBackend()
+
+	f.addFileEvent("backend-fw", txtPath, txtChangeTime)
+	f.MustReconcile(types.NamespacedName{Name: "backend-liveupdate"})
+
+	var lu v1alpha1.LiveUpdate
+	f.MustGet(types.NamespacedName{Name: "backend-liveupdate"}, &lu)
+	assert.Nil(t, lu.Status.Failed)
+	if assert.Equal(t, 1, len(lu.Status.Containers)) {
+		assert.Equal(t, "backend", lu.Status.Containers[0].ContainerName)
+		assert.Equal(t, "backend-id", lu.Status.Containers[0].ContainerID)
+		assert.Equal(t, txtChangeTime, lu.Status.Containers[0].LastFileTimeSynced)
+	}
+
+	if assert.Equal(t, 1, len(f.cu.Calls)) {
+		assert.Equal(t, container.ID("backend-id"), f.cu.Calls[0].ContainerInfo.ContainerID)
+	}
+
+	if assert.NotNil(t, f.st.lastCompletedAction) {
+		result := f.st.lastCompletedAction.Result[model.ImageID(container.MustParseSelector("backend-image"))]
+		if assert.NotNil(t, result) {
+			assert.Equal(t,
+				[]container.ID{"backend-id"},
+				result.(store.LiveUpdateBuildResult).LiveUpdatedContainerIDs)
+		}
+	}
+
+	f.assertSteadyState(&lu)
+}
+
+func TestDockerComposeWaitingForContainer(t *testing.T) {
+	f := newFixture(t)
+
+	p, _ := os.Getwd()
+	nowMicro := apis.NowMicro()
+	txtPath := filepath.Join(p, "a.txt")
+	txtChangeTime := metav1.MicroTime{Time: nowMicro.Add(time.Second)}
+
+	f.setupDockerComposeBackend()
+
+	f.addFileEvent("backend-fw", txtPath, txtChangeTime)
+	f.MustReconcile(types.NamespacedName{Name: "backend-liveupdate"})
+
+	var lu v1alpha1.LiveUpdate
+	f.MustGet(types.NamespacedName{Name: "backend-liveupdate"}, &lu)
+	assert.Nil(t, lu.Status.Failed)
+	if assert.Equal(t, 1, len(lu.Status.Containers)) {
+		assert.Equal(t, "ContainerWaiting", lu.Status.Containers[0].Waiting.Reason)
+	}
+	assert.Equal(t, 0, len(f.cu.Calls))
+
+	// Nothing has changed yet, so nothing should be re-queued.
+	assert.Empty(t, f.requeued())
+
+	// Once the container starts, we should re-queue the LiveUpdate
+	// and sync the files we missed.
+	f.setDockerComposeContainerID("backend-id")
+	requeued := f.requeued()
+	assert.Equal(t, []types.NamespacedName{{Name: "backend-liveupdate"}}, requeued)
+	for _, nn := range requeued {
+		f.MustReconcile(nn)
+	}
+
+	f.MustGet(types.NamespacedName{Name: "backend-liveupdate"}, &lu)
+	assert.Nil(t, lu.Status.Failed)
+	if assert.Equal(t, 1, len(lu.Status.Containers)) {
+		assert.Nil(t, lu.Status.Containers[0].Waiting)
+		assert.Equal(t, "backend-id", lu.Status.Containers[0].ContainerID)
+		assert.Equal(t, txtChangeTime, lu.Status.Containers[0].LastFileTimeSynced)
+	}
+	assert.Equal(t, 1, len(f.cu.Calls))
+}
+
+func TestDockerComposeRestartedContainer(t *testing.T) {
+	f := newFixture(t)
+
+	p, _ := os.Getwd()
+	nowMicro := apis.NowMicro()
+	txtPath := filepath.Join(p, "a.txt")
+	txtChangeTime := metav1.MicroTime{Time: nowMicro.Add(time.Second)}
+
+	f.setDockerComposeContainerID("backend-id")
+	f.setupDockerComposeBackend()
+
+	f.addFileEvent("backend-fw", txtPath, txtChangeTime)
+	f.MustReconcile(types.NamespacedName{Name: "backend-liveupdate"})
+	assert.Equal(t, 1, len(f.cu.Calls))
+	assert.Empty(t, f.requeued())
+
+	// When Compose replaces the container, we should re-queue the
+	// LiveUpdate and stop syncing to the old one.
+	f.setDockerComposeContainerID("backend-id-2")
+	requeued := f.requeued()
+	assert.Equal(t, []types.NamespacedName{{Name: "backend-liveupdate"}}, requeued)
+	for _, nn := range requeued {
+		f.MustReconcile(nn)
+	}
+
+	var lu v1alpha1.LiveUpdate
+	f.MustGet(types.NamespacedName{Name: "backend-liveupdate"}, &lu)
+	assert.Nil(t, lu.Status.Failed)
+	if assert.Equal(t, 1, len(lu.Status.Containers)) {
+		assert.Equal(t, "backend-id-2", lu.Status.Containers[0].ContainerID)
+	}
+}
+
+func TestDockerComposeExecError(t *testing.T) {
+	f := newFixture(t)
+
+	p, _ := os.Getwd()
+	nowMicro := apis.NowMicro()
+	txtPath := filepath.Join(p, "a.txt")
+	txtChangeTime := metav1.MicroTime{Time: nowMicro.Add(time.Second)}
+
+	f.setDockerComposeContainerID("backend-id")
+	f.setupDockerComposeBackend()
+	f.cu.SetUpdateErr(build.RunStepFailure{
+		Cmd:      model.ToUnixCmd("make"),
+		ExitCode: 1,
+	})
+
+	f.addFileEvent("backend-fw", txtPath, txtChangeTime)
+	f.MustReconcile(types.NamespacedName{Name: "backend-liveupdate"})
+
+	var lu v1alpha1.LiveUpdate
+	f.MustGet(types.NamespacedName{Name: "backend-liveupdate"}, &lu)
+	assert.Nil(t, lu.Status.Failed)
+	if assert.Equal(t, 1, len(lu.Status.Containers)) {
+		assert.Contains(t, lu.Status.Containers[0].LastExecError, "make")
+	}
+}
+
+func TestDockerComposeInvalidSelector(t *testing.T) {
+	f := newFixture(t)
+
+	f.Create(&v1alpha1.LiveUpdate{
+		ObjectMeta: metav1.ObjectMeta{Name: "backend-liveupdate"},
+		Spec: v1alpha1.LiveUpdateSpec{
+			BasePath: "/tmp",
+			Selector: v1alpha1.LiveUpdateSelector{
+				DockerCompose: &v1alpha1.LiveUpdateDockerComposeSelector{},
+			},
+			Syncs: []v1alpha1.LiveUpdateSync{
+				{LocalPath: ".", ContainerPath: "/app"},
+			},
+		},
+	})
+
+	var lu v1alpha1.LiveUpdate
+	f.MustGet(types.NamespacedName{Name: "backend-liveupdate"}, &lu)
+	if assert.NotNil(t, lu.Status.Failed) {
+		assert.Equal(t, "Invalid", lu.Status.Failed.Reason)
+		assert.Equal(t, "DockerCompose selector requires Service", lu.Status.Failed.Message)
+	}
+}
+
 type TestingStore struct {
 	*store.TestingStore
 	ctx                 context.Context
@@ -591,9 +759,10 @@ func (s *TestingStore) Dispatch(action store.Action) {
 
 type fixture struct {
 	*fake.ControllerFixture
-	r  *Reconciler
-	cu *containerupdate.FakeContainerUpdater
-	st *TestingStore
+	r     *Reconciler
+	cu    *containerupdate.FakeContainerUpdater
+	st    *TestingStore
+	queue workqueue.RateLimitingInterface
 }
 
 func newFixture(t testing.TB) *fixture {
@@ -603,11 +772,18 @@ func newFixture(t testing.TB) *fixture {
 	r := NewFakeReconciler(st, cu, cfb.Client)
 	cf := cfb.Build(r)
 	st.ctx = cf.Context()
+
+	q := workqueue.NewRateLimitingQueue(workqueue.DefaultControllerRateLimiter())
+	t.Cleanup(q.ShutDown)
+	err := r.requeuer.Start(cf.Context(), nil, q)
+	require.NoError(t, err)
+
 	return &fixture{
 		ControllerFixture: cf,
 		r:                 r,
 		cu:                cu,
 		st:                st,
+		queue:             q,
 	}
 }
 
@@ -708,6 +884,86 @@ func (f *fixture) setupFrontend() {
 	})
 }
 
+// Create a backend LiveUpdate for a Docker Compose service.
+func (f *fixture) setupDockerComposeBackend() {
+	p, _ := os.Getwd()
+	nowMicro := apis.NowMicro()
+
+	f.Create(&v1alpha1.FileWatch{
+		ObjectMeta: metav1.ObjectMeta{Name: "backend-fw"},
+		Spec: v1alpha1.FileWatchSpec{
+			WatchedPaths: []string{p},
+		},
+		Status: v1alpha1.FileWatchStatus{
+			MonitorStartTime: nowMicro,
+		},
+	})
+	f.Create(&v1alpha1.ImageMap{
+		ObjectMeta: metav1.ObjectMeta{Name: "backend-image"},
+		Status: v1alpha1.ImageMapStatus{
+			Image:          "backend-image:my-tag",
+			BuildStartTime: &nowMicro,
+		},
+	})
+	f.Create(&v1alpha1.LiveUpdate{
+		ObjectMeta: metav1.ObjectMeta{
+			Name: "backend-liveupdate",
+			Annotations: map[string]string{
+				v1alpha1.AnnotationManifest:     "backend",
+				liveupdate.AnnotationUpdateMode: "auto",
+			},
+		},
+		Spec: v1alpha1.LiveUpdateSpec{
+			BasePath: p,
+			Sources: []v1alpha1.LiveUpdateSource{{
+				FileWatch: "backend-fw",
+				ImageMap:  "backend-image",
+			}},
+			Selector: v1alpha1.LiveUpdateSelector{
+				DockerCompose: &v1alpha1.LiveUpdateDockerComposeSelector{
+					Service: "backend",
+					Image:   "backend-image",
+					Project: v1alpha1.DockerComposeProject{
+						ConfigPaths: []string{filepath.Join(p, "docker-compose.yml")},
+						ProjectPath: p,
+					},
+				},
+			},
+			Syncs: []v1alpha1.LiveUpdateSync{
+				{LocalPath: ".", ContainerPath: "/app"},
+			},
+		},
+	})
+}
+
+// Record the container of the backend service, like the engine does
+// when it hears about it from Docker Compose.
+func (f *fixture) setDockerComposeContainerID(id container.ID) {
+	f.st.WithState(func(state *store.EngineState) {
+		mt, ok := state.ManifestTargets["backend"]
+		if !ok {
+			mt = store.NewManifestTarget(model.Manifest{Name: "backend"})
+			state.UpsertManifestTarget(mt)
+		}
+		mt.State.RuntimeState = dockercompose.State{}.WithContainerID(id)
+	})
+}
+
+// Run the store subscriber, and return the LiveUpdates it re-queued.
+func (f *fixture) requeued() []types.NamespacedName {
+	err := f.r.OnChange(f.Context(), f.st, store.ChangeSummary{})
+	require.NoError(f.T(), err)
+
+	var result []types.NamespacedName
+	q := f.queue
+	for q.Len() > 0 {
+		item, _ := q.Get()
+		result = append(result, item.(reconcile.Request).NamespacedName)
+		q.Done(item)
+	}
+	return result
+}
+
 func (f *fixture) assertSteadyState(lu *v1alpha1.LiveUpdate) {
 	startCalls := len(f.cu.Calls)
 
@@ -34,6 +34,9 @@ type Reconciler struct {
 
 	// map of PortForward object name --> running forward(s)
 	activeForwards map[types.NamespacedName]*portForwardEntry
+
+	// how often to check the number of open connections through each forward
+	connectionPollInterval time.Duration
 }
 
 var _ store.TearDowner = &Reconciler{}
@@ -45,6 +48,8 @@ func NewReconciler(ctrlClient ctrlclient.Client, store store.RStore, kClient k8s
 		kClient:        kClient,
 		ctrlClient:     ctrlClient,
 		activeForwards: make(map[types.NamespacedName]*portForwardEntry),
+
+		connectionPollInterval: time.Second,
 	}
 }
 
@@ -159,24 +164,38 @@ func (r *Reconciler) onePortForward(ctx context.Context, entry *portForwardEntry
 			entry.ObjectMeta.Annotations[v1alpha1.AnnotationManifest],
 			forward.LocalPort, forward.ContainerPort, err)
 	}
+	setError := func(status ForwardStatus, err error) {
+		logError(err)
+		status.Error = err.Error()
+		status.Reconnects = entry.reconnects(forward)
+		shouldUpdate := entry.setStatus(forward, status)
+		if shouldUpdate {
+			r.updateForwardStatus(ctx, entry)
+		}
+	}
+
+	target, err := r.resolveTarget(ctx, entry.Spec, forward, entry.currentPod(forward))
+	if err != nil {
+		setError(ForwardStatus{
+			LocalPort:     forward.LocalPort,
+			ContainerPort: forward.ContainerPort,
+		}, err)
+		return
+	}
 
 	pf, err := r.kClient.CreatePortForwarder(
 		ctx,
 		k8s.Namespace(entry.Spec.Namespace),
-		k8s.PodID(entry.Spec.PodName),
+		target.podID,
 		int(forward.LocalPort),
-		int(forward.ContainerPort),
+		target.remotePort,
 		forward.Host)
 	if err != nil {
-		logError(err)
-		shouldUpdate := entry.setStatus(forward, ForwardStatus{
+		setError(ForwardStatus{
 			LocalPort:     forward.LocalPort,
 			ContainerPort: forward.ContainerPort,
-			Error:         err.Error(),
-		})
-		if shouldUpdate {
-			r.updateForwardStatus(ctx, entry)
-		}
+			PodName:       target.podID.String(),
+		}, err)
 		return
 	}
 
@@ -197,12 +216,38 @@ func (r *Reconciler) onePortForward(ctx context.Context, entry *portForwardEntry
 			// forward initialization errored at start before ready
 			return
 		case <-readyCh:
-			entry.setStatus(forward, ForwardStatus{
-				LocalPort:     int32(pf.LocalPort()),
-				ContainerPort: forward.ContainerPort,
-				Addresses:     pf.Addresses(),
-				StartedAt:     apis.NowMicro(),
-			})
+		}
+
+		entry.recordStart(forward)
+		status := ForwardStatus{
+			LocalPort:     int32(pf.LocalPort()),
+			ContainerPort: forward.ContainerPort,
+			Addresses:     pf.Addresses(),
+			StartedAt:     apis.NowMicro(),
+			PodName:       target.podID.String(),
+			Reconnects:    entry.reconnects(forward),
+		}
+		entry.setStatus(forward, status)
+		r.updateForwardStatus(ctx, entry)
+
+		// keep the connection count in the status up-to-date while the forward is running
+		ticker := time.NewTicker(r.connectionPollInterval)
+		defer ticker.Stop()
+		for {
+			select {
+			case <-ctx.Done():
+				return
+			case <-doneCh:
+				return
+			case <-ticker.C:
+			}
+
+			connections := int32(pf.ActiveConnections())
+			if connections == status.ActiveConnections {
+				continue
+			}
+			status.ActiveConnections = connections
+			entry.setStatus(forward, status)
 			r.updateForwardStatus(ctx, entry)
 		}
 	}()
@@ -210,16 +255,12 @@ func (r *Reconciler) onePortForward(ctx context.Context, entry *portForwardEntry
 	err = pf.ForwardPorts()
 	close(doneCh)
 	if err != nil {
-		logError(err)
-		shouldUpdate := entry.setStatus(forward, ForwardStatus{
+		setError(ForwardStatus{
 			LocalPort:     int32(pf.LocalPort()),
 			ContainerPort: forward.ContainerPort,
 			Addresses:     pf.Addresses(),
-			Error:         err.Error(),
-		})
-		if shouldUpdate {
-			r.updateForwardStatus(ctx, entry)
-		}
+			PodName:       target.podID.String(),
+		}, err)
 		return
 	}
 }
@@ -246,6 +287,7 @@ type portForwardEntry struct {
 
 	mu     sync.Mutex
 	status map[Forward]statusMeta
+	starts map[Forward]int32
 }
 
 func newEntry(ctx context.Context, pf *PortForward) *portForwardEntry {
@@ -255,6 +297,7 @@ func newEntry(ctx context.Context, pf *PortForward) *portForwardEntry {
 		ctx:         ctx,
 		cancel:      cancel,
 		status:      make(map[Forward]statusMeta),
+		starts:      make(map[Forward]int32),
 	}
 }
 
@@ -287,6 +330,30 @@ func (e *portForwardEntry) setStatus(spec Forward, status ForwardStatus) (should
 	return shouldUpdate
 }
 
+// The pod that the forward most recently connected to (if any).
+func (e *portForwardEntry) currentPod(spec Forward) k8s.PodID {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+	return k8s.PodID(e.status[spec].status.PodName)
+}
+
+// recordStart tracks that the forward was successfully established.
+func (e *portForwardEntry) recordStart(spec Forward) {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+	e.starts[spec]++
+}
+
+// The number of times the forward was re-established after it first started.
+func (e *portForwardEntry) reconnects(spec Forward) int32 {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+	if e.starts[spec] == 0 {
+		return 0
+	}
+	return e.starts[spec] - 1
+}
+
 func (e *portForwardEntry) statuses() []ForwardStatus {
 	e.mu.Lock()
 	defer e.mu.Unlock()
@@ -11,8 +11,10 @@ import (
 	"github.com/davecgh/go-spew/spew"
 
 	"github.com/stretchr/testify/require"
+	v1 "k8s.io/api/core/v1"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/apimachinery/pkg/util/intstr"
 	ctrl "sigs.k8s.io/controller-runtime"
 
 	"github.com/tilt-dev/tilt/internal/controllers/fake"
@@ -277,6 +279,95 @@ func TestPortForwardPartialSuccess(t *testing.T) {
 	f.requirePortForwardError(pfFooName, k8s.MagicTestExplodingPort, 8082, "fake error starting port forwarding")
 }
 
+func TestPortForwardToService(t *testing.T) {
+	f := newPFRFixture(t)
+
+	f.kCli.UpsertService(&v1.Service{
+		ObjectMeta: metav1.ObjectMeta{Name: "fe", Namespace: "default"},
+		Spec: v1.ServiceSpec{
+			Selector: map[string]string{"app": "fe"},
+			Ports:    []v1.ServicePort{{Port: 80, TargetPort: intstr.FromString("http")}},
+		},
+	})
+	f.kCli.UpsertPod(f.makeReadyPod("fe-1", map[string]string{"app": "fe"}, false))
+	f.kCli.UpsertPod(f.makeReadyPod("fe-2", map[string]string{"app": "fe"}, true))
+
+	pf := f.makeTargetPF(pfFooName, PortForwardSpec{
+		ServiceName: "fe",
+		Forwards:    []Forward{f.makeForward(8000, 80, "")},
+	})
+	f.Create(pf)
+
+	f.requirePortForwardStarted(pfFooName, 8000, 80)
+	assert.Equal(t, "fe-2", f.kCli.LastForwardPortPodID().String())
+	assert.Equal(t, 8080, f.kCli.LastForwardPortRemotePort())
+	f.requirePortForwardStatus(pfFooName, 8000, 80, func(status ForwardStatus) (bool, string) {
+		return status.PodName == "fe-2", fmt.Sprintf("podName=%q", status.PodName)
+	})
+}
+
+func TestPortForwardSwitchesPodsWhenPodGoesAway(t *testing.T) {
+	f := newPFRFixture(t)
+
+	pod1 := f.makeReadyPod("fe-1", map[string]string{"app": "fe"}, true)
+	f.kCli.UpsertPod(pod1)
+
+	pf := f.makeTargetPF(pfFooName, PortForwardSpec{
+		PodSelector: map[string]string{"app": "fe"},
+		Forwards:    []Forward{f.makeForward(8000, 8080, "")},
+	})
+	f.Create(pf)
+	f.requirePortForwardStarted(pfFooName, 8000, 8080)
+	assert.Equal(t, "fe-1", f.kCli.LastForwardPortPodID().String())
+
+	// Simulate a rollout: the new pod becomes ready, then the old pod goes
+	// away and drops the connection.
+	f.kCli.UpsertPod(f.makeReadyPod("fe-2", map[string]string{"app": "fe"}, true))
+	f.kCli.EmitPodDelete(pod1)
+	f.kCli.LastForwarder().TriggerFailure(nil)
+
+	f.requirePortForwardStatus(pfFooName, 8000, 8080, func(status ForwardStatus) (bool, string) {
+		ok := status.PodName == "fe-2" && status.Reconnects == 1 && !status.StartedAt.IsZero()
+		return ok, fmt.Sprintf("podName=%q reconnects=%d startedAt=%s",
+			status.PodName, status.Reconnects, status.StartedAt.String())
+	})
+	assert.Equal(t, "fe-2", f.kCli.LastForwardPortPodID().String())
+}
+
+func TestPortForwardNoReadyPods(t *testing.T) {
+	f := newPFRFixture(t)
+
+	f.kCli.UpsertPod(f.makeReadyPod("fe-1", map[string]string{"app": "fe"}, false))
+
+	pf := f.makeTargetPF(pfFooName, PortForwardSpec{
+		PodSelector: map[string]string{"app": "fe"},
+		Forwards:    []Forward{f.makeForward(8000, 8080, "")},
+	})
+	f.Create(pf)
+
+	f.requirePortForwardError(pfFooName, 8000, 8080, "no ready pods")
+	assert.Equal(t, 0, f.kCli.CreatePortForwardCallCount())
+}
+
+func TestPortForwardActiveConnections(t *testing.T) {
+	f := newPFRFixture(t)
+	f.r.connectionPollInterval = 10 * time.Millisecond
+
+	pf := f.makeSimplePF(pfFooName, 8000, 8080)
+	f.Create(pf)
+	f.requirePortForwardStarted(pfFooName, 8000, 8080)
+
+	f.kCli.LastForwarder().SetActiveConnections(3)
+	f.requirePortForwardStatus(pfFooName, 8000, 8080, func(status ForwardStatus) (bool, string) {
+		return status.ActiveConnections == 3, fmt.Sprintf("activeConnections=%d", status.ActiveConnections)
+	})
+
+	f.kCli.LastForwarder().SetActiveConnections(0)
+	f.requirePortForwardStatus(pfFooName, 8000, 8080, func(status ForwardStatus) (bool, string) {
+		return status.ActiveConnections == 0, fmt.Sprintf("activeConnections=%d", status.ActiveConnections)
+	})
+}
+
 type pfrFixture struct {
 	*fake.ControllerFixture
 	t    *testing.T
@@ -404,6 +495,42 @@ func (f *pfrFixture) makePF(name string, mName model.ManifestName, podName k8s.P
 	}
 }
 
+func (f *pfrFixture) makeTargetPF(name string, spec PortForwardSpec) *PortForward {
+	return &PortForward{
+		ObjectMeta: metav1.ObjectMeta{
+			Name: name,
+			Annotations: map[string]string{
+				v1alpha1.AnnotationManifest: fmt.Sprintf("manifest-%s", name),
+			},
+		},
+		Spec: spec,
+	}
+}
+
+func (f *pfrFixture) makeReadyPod(name string, labels map[string]string, ready bool) *v1.Pod {
+	readyStatus := v1.ConditionFalse
+	if ready {
+		readyStatus = v1.ConditionTrue
+	}
+	return &v1.Pod{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      name,
+			Namespace: "default",
+			Labels:    labels,
+		},
+		Spec: v1.PodSpec{
+			Containers: []v1.Container{{
+				Name:  "main",
+				Ports: []v1.ContainerPort{{Name: "http", ContainerPort: 8080}},
+			}},
+		},
+		Status: v1.PodStatus{
+			Phase:      v1.PodRunning,
+			Conditions: []v1.PodCondition{{Type: v1.PodReady, Status: readyStatus}},
+		},
+	}
+}
+
 func (f *pfrFixture) makeSimplePF(name string, localPort, containerPort int32) *PortForward {
 	fwd := Forward{
 		LocalPort:     localPort,
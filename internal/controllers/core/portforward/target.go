@@ -0,0 +1,133 @@
+package portforward
+
+import (
+	"context"
+	"fmt"
+	"sort"
+
+	v1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/labels"
+	"k8s.io/apimachinery/pkg/util/intstr"
+
+	"github.com/tilt-dev/tilt/internal/k8s"
+)
+
+// The pod and port that a forward connects to.
+type forwardTarget struct {
+	podID      k8s.PodID
+	remotePort int
+}
+
+// Picks the pod and port to connect the forward to.
+//
+// For forwards to a Service or pod selector, we stick with the current pod
+// as long as it's ready, so that we only switch pods when we have to.
+func (r *Reconciler) resolveTarget(ctx context.Context, spec PortForwardSpec, forward Forward, current k8s.PodID) (forwardTarget, error) {
+	if spec.PodName != "" {
+		return forwardTarget{podID: k8s.PodID(spec.PodName), remotePort: int(forward.ContainerPort)}, nil
+	}
+
+	ns := k8s.Namespace(spec.Namespace)
+	if spec.ServiceName == "" {
+		pod, err := r.pickReadyPod(ctx, ns, labels.SelectorFromSet(spec.PodSelector), current)
+		if err != nil {
+			return forwardTarget{}, fmt.Errorf("pods matching %s: %v", labels.FormatLabels(spec.PodSelector), err)
+		}
+		return forwardTarget{podID: k8s.PodIDFromPod(pod), remotePort: int(forward.ContainerPort)}, nil
+	}
+
+	svc, err := r.kClient.GetService(ctx, ns, spec.ServiceName)
+	if err != nil {
+		return forwardTarget{}, fmt.Errorf("service %s: %v", spec.ServiceName, err)
+	}
+	if len(svc.Spec.Selector) == 0 {
+		return forwardTarget{}, fmt.Errorf("service %s has no pod selector", spec.ServiceName)
+	}
+
+	var svcPort *v1.ServicePort
+	for i, p := range svc.Spec.Ports {
+		if p.Port == forward.ContainerPort {
+			svcPort = &svc.Spec.Ports[i]
+			break
+		}
+	}
+	if svcPort == nil {
+		return forwardTarget{}, fmt.Errorf("service %s has no port %d", spec.ServiceName, forward.ContainerPort)
+	}
+
+	pod, err := r.pickReadyPod(ctx, ns, labels.SelectorFromSet(svc.Spec.Selector), current)
+	if err != nil {
+		return forwardTarget{}, fmt.Errorf("service %s: %v", spec.ServiceName, err)
+	}
+
+	remotePort, err := targetPortForPod(*svcPort, pod)
+	if err != nil {
+		return forwardTarget{}, fmt.Errorf("service %s: %v", spec.ServiceName, err)
+	}
+	return forwardTarget{podID: k8s.PodIDFromPod(pod), remotePort: remotePort}, nil
+}
+
+// Returns the current pod if it's still ready. Otherwise, returns the newest ready pod.
+func (r *Reconciler) pickReadyPod(ctx context.Context, ns k8s.Namespace, selector labels.Selector, current k8s.PodID) (*v1.Pod, error) {
+	pods, err := r.kClient.ListPods(ctx, ns, selector)
+	if err != nil {
+		return nil, err
+	}
+
+	var ready []*v1.Pod
+	for i := range pods {
+		pod := &pods[i]
+		if !isPodReady(pod) {
+			continue
+		}
+		if current != "" && k8s.PodIDFromPod(pod) == current {
+			return pod, nil
+		}
+		ready = append(ready, pod)
+	}
+	if len(ready) == 0 {
+		return nil, fmt.Errorf("no ready pods")
+	}
+
+	sort.Slice(ready, func(i, j int) bool {
+		ti, tj := ready[i].CreationTimestamp, ready[j].CreationTimestamp
+		if !ti.Equal(&tj) {
+			return tj.Before(&ti)
+		}
+		return ready[i].Name < ready[j].Name
+	})
+	return ready[0], nil
+}
+
+func isPodReady(pod *v1.Pod) bool {
+	if pod.DeletionTimestamp != nil || pod.Status.Phase != v1.PodRunning {
+		return false
+	}
+	for _, c := range pod.Status.Conditions {
+		if c.Type == v1.PodReady {
+			return c.Status == v1.ConditionTrue
+		}
+	}
+	return false
+}
+
+// Maps a Service port to the port on the pod, resolving named target ports
+// against the pod's container ports.
+func targetPortForPod(svcPort v1.ServicePort, pod *v1.Pod) (int, error) {
+	tp := svcPort.TargetPort
+	if tp.Type == intstr.Int {
+		if tp.IntVal == 0 {
+			return int(svcPort.Port), nil
+		}
+		return int(tp.IntVal), nil
+	}
+
+	for _, c := range pod.Spec.Containers {
+		for _, p := range c.Ports {
+			if p.Name == tp.StrVal {
+				return int(p.ContainerPort), nil
+			}
+		}
+	}
+	return 0, fmt.Errorf("pod %s has no port named %q", pod.Name, tp.StrVal)
+}
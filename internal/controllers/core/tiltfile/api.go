@@ -156,6 +156,7 @@ var typesWithTiltfileBuiltins = []apiset.Object{
 	&v1alpha1.UIButton{},
 	&v1alpha1.ConfigMap{},
 	&v1alpha1.KubernetesDiscovery{},
+	&v1alpha1.DockerImage{},
 }
 
 var typesToReconcile = append([]apiset.Object{
@@ -292,7 +293,7 @@ func toToggleButtons(tlr *tiltfile.TiltfileLoadResult, disableSources disableSou
 				ObjectMeta: metav1.ObjectMeta{
 					Name: fmt.Sprintf("%s-disable", name),
 					Annotations: map[string]string{
-						v1alpha1.AnnotationButtonType: "DisableToggle",
+						v1alpha1.AnnotationButtonType: v1alpha1.UIButtonTypeDisableToggle,
 					},
 				},
 				Spec: v1alpha1.ToggleButtonSpec{
@@ -388,7 +388,7 @@ func (r *Reconciler) enqueueTriggerQueue(obj client.Object) []reconcile.Request
 }
 
 func requiresDocker(tlr tiltfile.TiltfileLoadResult) bool {
-	if tlr.Orchestrator() == model.OrchestratorDC {
+	if tlr.HasOrchestrator(model.OrchestratorDC) {
 		return true
 	}
 
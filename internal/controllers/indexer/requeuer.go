@@ -0,0 +1,55 @@
+package indexer
+
+import (
+	"context"
+	"sync"
+
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/client-go/util/workqueue"
+	"sigs.k8s.io/controller-runtime/pkg/handler"
+	"sigs.k8s.io/controller-runtime/pkg/predicate"
+	"sigs.k8s.io/controller-runtime/pkg/reconcile"
+	"sigs.k8s.io/controller-runtime/pkg/source"
+)
+
+// A controller-runtime Source that lets a reconciler re-queue its own objects
+// when something it depends on changes outside the API server
+// (e.g., state that the engine still tracks itself).
+//
+// Requests added before the controller starts are queued once it does.
+type Requeuer struct {
+	mu      sync.Mutex
+	queue   workqueue.RateLimitingInterface
+	pending map[types.NamespacedName]bool
+}
+
+var _ source.Source = &Requeuer{}
+
+func NewRequeuer() *Requeuer {
+	return &Requeuer{
+		pending: make(map[types.NamespacedName]bool),
+	}
+}
+
+// Start sends requests straight to the queue, so the event handler and
+// predicates are ignored.
+func (r *Requeuer) Start(ctx context.Context, h handler.EventHandler, q workqueue.RateLimitingInterface, p ...predicate.Predicate) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.queue = q
+	for nn := range r.pending {
+		q.Add(reconcile.Request{NamespacedName: nn})
+	}
+	r.pending = make(map[types.NamespacedName]bool)
+	return nil
+}
+
+func (r *Requeuer) Add(nn types.NamespacedName) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	if r.queue == nil {
+		r.pending[nn] = true
+		return
+	}
+	r.queue.Add(reconcile.Request{NamespacedName: nn})
+}
@@ -76,6 +76,9 @@ type Client interface {
 
 	ServerVersion() types.Version
 
+	// Info about the daemon, including which registries it treats as insecure.
+	Info(ctx context.Context) (types.Info, error)
+
 	// Set the orchestrator we're talking to. This is only relevant to switchClient,
 	// which can talk to either the Local or in-cluster docker daemon.
 	SetOrchestrator(orc model.Orchestrator)
@@ -90,6 +93,10 @@ type Client interface {
 	// Returns an ExitError if the command exits with a non-zero exit code.
 	ExecInContainer(ctx context.Context, cID container.ID, cmd model.Cmd, in io.Reader, out io.Writer) error
 
+	// Unpack a tar archive into the root of the container filesystem with the
+	// copy API. Works even if the container doesn't have a `tar` binary.
+	CopyToContainerRoot(ctx context.Context, cID container.ID, archive io.Reader) error
+
 	ImagePull(ctx context.Context, ref reference.Named) (reference.Canonical, error)
 	ImagePush(ctx context.Context, image reference.NamedTagged) (io.ReadCloser, error)
 	ImageBuild(ctx context.Context, buildContext io.Reader, options BuildOptions) (types.ImageBuildResponse, error)
@@ -650,6 +657,10 @@ func (c *Cli) ExecInContainer(ctx context.Context, cID container.ID, cmd model.C
 	}
 }
 
+func (c *Cli) CopyToContainerRoot(ctx context.Context, cID container.ID, archive io.Reader) error {
+	return c.CopyToContainer(ctx, cID.String(), "/", archive, types.CopyToContainerOptions{})
+}
+
 func (c *Cli) Run(ctx context.Context, opts RunConfig) (RunResult, error) {
 	if opts.Pull {
 		namedRef, ok := opts.Image.(reference.Named)
@@ -670,7 +681,9 @@ func (c *Cli) Run(ctx context.Context, opts RunConfig) (RunResult, error) {
 	}
 
 	hc := &mobycontainer.HostConfig{
-		Mounts: opts.Mounts,
+		Mounts:  opts.Mounts,
+		PidMode: opts.PidMode,
+		CapAdd:  opts.CapAdd,
 	}
 
 	createResp, err := c.Client.ContainerCreate(ctx,
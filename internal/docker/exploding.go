@@ -38,6 +38,9 @@ func (c explodingClient) BuilderVersion() types.BuilderVersion {
 func (c explodingClient) ServerVersion() types.Version {
 	return types.Version{}
 }
+func (c explodingClient) Info(ctx context.Context) (types.Info, error) {
+	return types.Info{}, c.err
+}
 func (c explodingClient) ContainerInspect(ctx context.Context, containerID string) (types.ContainerJSON, error) {
 	return types.ContainerJSON{}, c.err
 }
@@ -53,6 +56,9 @@ func (c explodingClient) Run(ctx context.Context, opts RunConfig) (RunResult, er
 func (c explodingClient) ExecInContainer(ctx context.Context, cID container.ID, cmd model.Cmd, in io.Reader, out io.Writer) error {
 	return c.err
 }
+func (c explodingClient) CopyToContainerRoot(ctx context.Context, cID container.ID, archive io.Reader) error {
+	return c.err
+}
 func (c explodingClient) ImagePull(_ context.Context, _ reference.Named) (reference.Canonical, error) {
 	return nil, c.err
 }
@@ -15,6 +15,7 @@ import (
 
 	"github.com/docker/distribution/reference"
 	"github.com/docker/docker/api/types"
+	mobycontainer "github.com/docker/docker/api/types/container"
 	"github.com/docker/docker/api/types/filters"
 
 	"github.com/tilt-dev/tilt/internal/container"
@@ -109,6 +110,19 @@ type FakeClient struct {
 	CopyContainer string
 	CopyContent   io.Reader
 
+	// Calls to the copy API (rather than exec'ing tar).
+	CopyAPICount int
+
+	// Execs of these binaries fail as if they're not in the container image.
+	MissingExecutables map[string]bool
+
+	// Returned by Info.
+	InfoOutput types.Info
+
+	// Containers started with Run, and the exit code they all finish with.
+	RunConfigs  []RunConfig
+	RunExitCode int64
+
 	ExecCalls         []ExecCall
 	ExecErrorsToThrow []error // next call to exec will throw ExecError[0] (which we then pop)
 
@@ -158,6 +172,10 @@ func (c *FakeClient) Env() Env {
 func (c *FakeClient) BuilderVersion() types.BuilderVersion {
 	return types.BuilderV1
 }
+func (c *FakeClient) Info(ctx context.Context) (types.Info, error) {
+	return c.InfoOutput, nil
+}
+
 func (c *FakeClient) ServerVersion() types.Version {
 	return types.Version{}
 }
@@ -207,10 +225,26 @@ func (c *FakeClient) ContainerRestartNoWait(ctx context.Context, containerID str
 }
 
 func (c *FakeClient) Run(ctx context.Context, opts RunConfig) (RunResult, error) {
-	return RunResult{}, nil
+	c.RunConfigs = append(c.RunConfigs, opts)
+
+	statusRespCh := make(chan mobycontainer.ContainerWaitOKBody, 1)
+	statusRespCh <- mobycontainer.ContainerWaitOKBody{StatusCode: c.RunExitCode}
+	logsErrCh := make(chan error, 1)
+	logsErrCh <- nil
+	return RunResult{
+		ContainerID:  fmt.Sprintf("run-%d", len(c.RunConfigs)),
+		statusRespCh: statusRespCh,
+		logsErrCh:    logsErrCh,
+	}, nil
 }
 
 func (c *FakeClient) ExecInContainer(ctx context.Context, cID container.ID, cmd model.Cmd, in io.Reader, out io.Writer) error {
+	if c.MissingExecutables[cmd.Argv[0]] {
+		_, _ = fmt.Fprintf(out, "OCI runtime exec failed: exec failed: container_linux.go:380: "+
+			"starting container process caused: exec: %q: executable file not found in $PATH: unknown\n", cmd.Argv[0])
+		return ExitError{ExitCode: 126}
+	}
+
 	if cmd.Argv[0] == "tar" {
 		c.CopyCount++
 		c.CopyContainer = string(cID)
@@ -235,6 +269,13 @@ func (c *FakeClient) ExecInContainer(ctx context.Context, cID container.ID, cmd
 	return err
 }
 
+func (c *FakeClient) CopyToContainerRoot(ctx context.Context, cID container.ID, archive io.Reader) error {
+	c.CopyAPICount++
+	c.CopyContainer = string(cID)
+	c.CopyContent = archive
+	return nil
+}
+
 func (c *FakeClient) ImagePull(_ context.Context, ref reference.Named) (reference.Canonical, error) {
 	// fake digest is the reference itself hashed
 	// i.e. docker.io/library/_/nginx -> sha256sum(docker.io/library/_/nginx) -> 2ca21a92e8ee99f672764b7619a413019de5ffc7f06dbc7422d41eca17705802
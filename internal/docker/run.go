@@ -35,6 +35,11 @@ type RunConfig struct {
 	Cmd []string
 	// Mounts to attach to the container.
 	Mounts []mount.Mount
+	// PidMode sets the PID namespace of the container,
+	// e.g., "container:<id>" to share the namespace of another container.
+	PidMode mobycontainer.PidMode
+	// CapAdd adds Linux capabilities to the container.
+	CapAdd []string
 }
 
 // RunResult contains information about a container execution.
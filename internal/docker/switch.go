@@ -59,6 +59,9 @@ func (c *switchCli) BuilderVersion() types.BuilderVersion {
 func (c *switchCli) ServerVersion() types.Version {
 	return c.client().ServerVersion()
 }
+func (c *switchCli) Info(ctx context.Context) (types.Info, error) {
+	return c.client().Info(ctx)
+}
 func (c *switchCli) ContainerInspect(ctx context.Context, containerID string) (types.ContainerJSON, error) {
 	return c.client().ContainerInspect(ctx, containerID)
 }
@@ -74,6 +77,9 @@ func (c *switchCli) Run(ctx context.Context, opts RunConfig) (RunResult, error)
 func (c *switchCli) ExecInContainer(ctx context.Context, cID container.ID, cmd model.Cmd, in io.Reader, out io.Writer) error {
 	return c.client().ExecInContainer(ctx, cID, cmd, in, out)
 }
+func (c *switchCli) CopyToContainerRoot(ctx context.Context, cID container.ID, archive io.Reader) error {
+	return c.client().CopyToContainerRoot(ctx, cID, archive)
+}
 func (c *switchCli) ImagePull(ctx context.Context, ref reference.Named) (reference.Canonical, error) {
 	return c.client().ImagePull(ctx, ref)
 }
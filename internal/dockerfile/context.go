@@ -0,0 +1,297 @@
+package dockerfile
+
+import (
+	"path"
+	"path/filepath"
+	"sort"
+	"strings"
+
+	"github.com/docker/cli/opts"
+	"github.com/moby/buildkit/frontend/dockerfile/command"
+	"github.com/moby/buildkit/frontend/dockerfile/instructions"
+	"github.com/moby/buildkit/frontend/dockerfile/parser"
+	"github.com/moby/buildkit/frontend/dockerfile/shell"
+	"github.com/pkg/errors"
+)
+
+// A context path that means the build may read anything in the build context.
+const WholeContext = "."
+
+// Find the paths in the build context that this Dockerfile reads,
+// from COPY, ADD, and RUN --mount=type=bind instructions.
+//
+// Paths are relative to the build context, and use forward slashes. The last
+// path component may contain a glob (e.g., package*.json). If the Dockerfile
+// may read anything in the context (e.g., COPY . .), the only path is WholeContext.
+//
+// Skips instructions that read from other stages or images (COPY --from), and
+// stages after targetStage, because they're never built.
+func (d Dockerfile) ContextPaths(buildArgs []string, targetStage string) ([]string, error) {
+	ast, err := ParseAST(d)
+	if err != nil {
+		return nil, err
+	}
+	return ast.ContextPaths(buildArgs, targetStage)
+}
+
+func (a AST) ContextPaths(buildArgs []string, targetStage string) ([]string, error) {
+	c := newContextPathCollector(a.result.EscapeToken, buildArgs)
+	targetStage = strings.ToLower(targetStage)
+	inTarget := false
+
+	for _, node := range a.result.AST.Children {
+		if node.Value == command.From && inTarget {
+			break
+		}
+
+		err := c.visit(node)
+		if err != nil {
+			return nil, errors.Wrapf(err, "line %d", node.StartLine)
+		}
+
+		if node.Value == command.From && targetStage != "" && c.stageName == targetStage {
+			inTarget = true
+		}
+	}
+
+	return c.result(), nil
+}
+
+type contextPathCollector struct {
+	shlex     *shell.Lex
+	buildArgs map[string]*string
+
+	// ARGs declared before the first FROM.
+	metaArgs map[string]string
+
+	// The ENV of each named stage, so that stages built on top of them inherit it.
+	stageEnvs map[string]map[string]string
+
+	// The current stage, or empty if we haven't seen a FROM yet.
+	inStage   bool
+	stageName string
+	env       map[string]string
+	vars      map[string]string
+
+	paths map[string]bool
+}
+
+func newContextPathCollector(escapeToken rune, buildArgs []string) *contextPathCollector {
+	return &contextPathCollector{
+		shlex:     shell.NewLex(escapeToken),
+		buildArgs: opts.ConvertKVStringsToMapWithNil(buildArgs),
+		metaArgs:  make(map[string]string),
+		stageEnvs: make(map[string]map[string]string),
+		paths:     make(map[string]bool),
+	}
+}
+
+func (c *contextPathCollector) visit(node *parser.Node) error {
+	switch node.Value {
+	case command.From:
+		inst, err := instructions.ParseInstruction(node)
+		if err != nil {
+			return err
+		}
+		stage, ok := inst.(*instructions.Stage)
+		if !ok {
+			return nil
+		}
+
+		baseName, _ := c.shlex.ProcessWordWithMap(stage.BaseName, c.metaArgs)
+
+		// ARGs don't carry over between stages, but ENVs do.
+		c.inStage = true
+		c.stageName = strings.ToLower(stage.Name)
+		c.env = make(map[string]string)
+		c.vars = make(map[string]string)
+		for k, v := range c.stageEnvs[strings.ToLower(baseName)] {
+			c.env[k] = v
+			c.vars[k] = v
+		}
+		if c.stageName != "" {
+			c.stageEnvs[c.stageName] = c.env
+		}
+
+	case command.Arg:
+		inst, err := instructions.ParseInstruction(node)
+		if err != nil {
+			return err
+		}
+		argCmd, ok := inst.(*instructions.ArgCommand)
+		if !ok {
+			return nil
+		}
+		for _, arg := range argCmd.Args {
+			c.visitArg(arg)
+		}
+
+	case command.Env:
+		if !c.inStage {
+			return nil
+		}
+		inst, err := instructions.ParseInstruction(node)
+		if err != nil {
+			return err
+		}
+		envCmd, ok := inst.(*instructions.EnvCommand)
+		if !ok {
+			return nil
+		}
+		for _, kv := range envCmd.Env {
+			val, _ := c.shlex.ProcessWordWithMap(kv.Value, c.vars)
+			c.env[kv.Key] = val
+			c.vars[kv.Key] = val
+		}
+
+	case command.Copy, command.Add:
+		inst, err := instructions.ParseInstruction(node)
+		if err != nil {
+			// The daemon may understand flags that our parser doesn't (e.g., COPY --link),
+			// so we assume the instruction could read anything.
+			c.paths[WholeContext] = true
+			return nil
+		}
+		switch cmd := inst.(type) {
+		case *instructions.CopyCommand:
+			if cmd.From != "" {
+				// Reads from another stage or image, not the context.
+				return nil
+			}
+			c.addSources(cmd.Sources())
+		case *instructions.AddCommand:
+			c.addSources(cmd.Sources())
+		}
+
+	case command.Run:
+		// We parse RUN flags ourselves, because the buildkit parser
+		// only understands --mount with the dfrunmount build tag.
+		for _, flag := range node.Flags {
+			if strings.HasPrefix(flag, "--mount=") {
+				c.visitMount(strings.TrimPrefix(flag, "--mount="))
+			}
+		}
+	}
+	return nil
+}
+
+// Build args override ARG defaults. ARGs inside a stage without a
+// default inherit the value of the ARG before the first FROM.
+func (c *contextPathCollector) visitArg(arg instructions.KeyValuePairOptional) {
+	scope := c.vars
+	if !c.inStage {
+		scope = c.metaArgs
+	}
+
+	if val, ok := c.buildArgs[arg.Key]; ok {
+		if val == nil {
+			scope[arg.Key] = ""
+		} else {
+			scope[arg.Key] = *val
+		}
+		return
+	}
+
+	if arg.Value != nil {
+		val, _ := c.shlex.ProcessWordWithMap(*arg.Value, scope)
+		scope[arg.Key] = val
+		return
+	}
+
+	if val, ok := c.metaArgs[arg.Key]; ok && c.inStage {
+		scope[arg.Key] = val
+	}
+}
+
+// Bind mounts read from the context unless they specify another stage or image.
+//
+// https://docs.docker.com/engine/reference/builder/#run---mounttypebind
+func (c *contextPathCollector) visitMount(value string) {
+	mountType := "bind"
+	source := ""
+	from := ""
+	for _, field := range strings.Split(value, ",") {
+		kv := strings.SplitN(field, "=", 2)
+		if len(kv) != 2 {
+			continue
+		}
+		switch strings.ToLower(kv[0]) {
+		case "type":
+			mountType = strings.ToLower(kv[1])
+		case "source", "src":
+			source = kv[1]
+		case "from":
+			from = kv[1]
+		}
+	}
+
+	if mountType != "bind" || from != "" {
+		return
+	}
+	if source == "" {
+		source = WholeContext
+	}
+	c.addSources([]string{source})
+}
+
+func (c *contextPathCollector) addSources(sources []string) {
+	for _, src := range sources {
+		if isRemoteSource(src) {
+			continue
+		}
+
+		expanded, err := c.shlex.ProcessWordWithMap(src, c.vars)
+		if err != nil {
+			// If we don't understand the path, assume it could be anything.
+			expanded = WholeContext
+		}
+		c.paths[contextPath(expanded)] = true
+	}
+}
+
+func (c *contextPathCollector) result() []string {
+	if c.paths[WholeContext] {
+		return []string{WholeContext}
+	}
+
+	result := make([]string, 0, len(c.paths))
+	for p := range c.paths {
+		result = append(result, p)
+	}
+	sort.Strings(result)
+	return result
+}
+
+// ADD can download URLs and clone git repos.
+func isRemoteSource(src string) bool {
+	for _, prefix := range []string{"http://", "https://", "git://", "git@"} {
+		if strings.HasPrefix(src, prefix) {
+			return true
+		}
+	}
+	return false
+}
+
+// Normalizes a source path so that it's relative to the context.
+//
+// Dockerignore-style matchers can't tell which directories a glob in the middle
+// of a path might match, so we trim the path to the parent of the first glob,
+// unless the glob is in the last path component.
+func contextPath(src string) string {
+	p := path.Clean("/" + filepath.ToSlash(src))
+	p = strings.TrimPrefix(p, "/")
+	if p == "" {
+		return WholeContext
+	}
+
+	parts := strings.Split(p, "/")
+	for i, part := range parts[:len(parts)-1] {
+		if strings.ContainsAny(part, "*?[") {
+			if i == 0 {
+				return WholeContext
+			}
+			return strings.Join(parts[:i], "/")
+		}
+	}
+	return p
+}
@@ -0,0 +1,151 @@
+package dockerfile
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestContextPathsCopyAndAdd(t *testing.T) {
+	assertContextPaths(t, `
+FROM golang:1.17
+COPY go.mod go.sum /app/
+COPY ./cmd/server /app/cmd/server
+ADD --chown=app:app static/ /app/static
+ADD https://example.com/file.tar.gz /tmp/
+`, nil, "", "cmd/server", "go.mod", "go.sum", "static")
+}
+
+func TestContextPathsCopyDot(t *testing.T) {
+	assertContextPaths(t, `
+FROM golang:1.17
+COPY go.mod /app/
+COPY . /app/
+`, nil, "", WholeContext)
+}
+
+func TestContextPathsNoCopy(t *testing.T) {
+	assertContextPaths(t, `
+FROM busybox
+RUN echo hi
+`, nil, "")
+}
+
+func TestContextPathsAbsoluteSource(t *testing.T) {
+	assertContextPaths(t, `
+FROM busybox
+COPY /src/main.go /app/
+COPY ../outside.txt /app/
+`, nil, "", "outside.txt", "src/main.go")
+}
+
+func TestContextPathsGlobs(t *testing.T) {
+	assertContextPaths(t, `
+FROM node:16
+COPY package*.json /app/
+COPY src/*/index.js /app/
+`, nil, "", "package*.json", "src")
+}
+
+func TestContextPathsGlobInFirstDir(t *testing.T) {
+	assertContextPaths(t, `
+FROM node:16
+COPY */package.json /app/
+`, nil, "", WholeContext)
+}
+
+func TestContextPathsCopyFromStage(t *testing.T) {
+	assertContextPaths(t, `
+FROM golang:1.17 AS builder
+COPY main.go .
+RUN go build -o /server main.go
+
+FROM busybox
+COPY --from=builder /server /server
+COPY --from=nginx:latest /etc/nginx/nginx.conf /etc/nginx/
+COPY config.yaml /config.yaml
+`, nil, "", "config.yaml", "main.go")
+}
+
+func TestContextPathsTargetStage(t *testing.T) {
+	df := `
+FROM golang:1.17 AS builder
+COPY main.go .
+
+FROM builder AS test
+COPY main_test.go .
+
+FROM busybox AS release
+COPY config.yaml /config.yaml
+`
+	assertContextPaths(t, df, nil, "builder", "main.go")
+	assertContextPaths(t, df, nil, "TEST", "main.go", "main_test.go")
+	assertContextPaths(t, df, nil, "", "config.yaml", "main.go", "main_test.go")
+}
+
+func TestContextPathsBuildArgs(t *testing.T) {
+	df := `
+ARG SERVICE=frontend
+FROM golang:1.17
+ARG SERVICE
+ARG VERSION=v1
+COPY services/${SERVICE}/${VERSION} /app/
+`
+	assertContextPaths(t, df, nil, "", "services/frontend/v1")
+	assertContextPaths(t, df, []string{"SERVICE=backend", "VERSION=v2"}, "", "services/backend/v2")
+}
+
+func TestContextPathsArgsDontCrossStages(t *testing.T) {
+	assertContextPaths(t, `
+FROM golang:1.17
+ARG DIR=src
+COPY $DIR /app/
+
+FROM busybox
+COPY $DIR /app/
+`, nil, "", WholeContext)
+}
+
+func TestContextPathsEnvInheritedFromStage(t *testing.T) {
+	assertContextPaths(t, `
+FROM golang:1.17 AS base
+ENV SRC=pkg
+
+FROM base
+ENV CMD=${SRC}/cmd
+COPY $SRC/lib ${CMD} /app/
+`, nil, "", "pkg/cmd", "pkg/lib")
+}
+
+func TestContextPathsRunMount(t *testing.T) {
+	assertContextPaths(t, `
+FROM golang:1.17
+RUN --mount=type=cache,target=/root/.cache go env
+RUN --mount=type=bind,source=go.mod,target=/app/go.mod go mod download
+RUN --mount=type=bind,from=builder,source=/out,target=/out ls /out
+RUN --mount=type=secret,id=token cat /run/secrets/token
+`, nil, "", "go.mod")
+
+	assertContextPaths(t, `
+FROM golang:1.17
+RUN --mount=target=/src go build ./...
+`, nil, "", WholeContext)
+}
+
+func TestContextPathsUnknownCopyFlag(t *testing.T) {
+	assertContextPaths(t, `
+FROM golang:1.17
+COPY --some-new-flag main.go /app/
+`, nil, "", WholeContext)
+}
+
+func assertContextPaths(t *testing.T, df string, buildArgs []string, target string, expected ...string) {
+	t.Helper()
+	actual, err := Dockerfile(df).ContextPaths(buildArgs, target)
+	require.NoError(t, err)
+	if len(expected) == 0 {
+		expected = []string{}
+	}
+	assert.Equal(t, expected, actual)
+}
@@ -11,6 +11,7 @@ import (
 	"github.com/tilt-dev/tilt/internal/store"
 	"github.com/tilt-dev/tilt/internal/token"
 	"github.com/tilt-dev/tilt/pkg/model"
+	"github.com/tilt-dev/tilt/pkg/model/logstore"
 )
 
 func NewErrorAction(err error) store.ErrorAction {
@@ -30,6 +31,9 @@ type InitAction struct {
 	CloudAddress string
 	Token        token.Token
 	TerminalMode store.TerminalMode
+
+	// If set, truncated logs are archived here.
+	LogHistory *logstore.History
 }
 
 func (InitAction) Action() {}
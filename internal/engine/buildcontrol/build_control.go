@@ -51,14 +51,10 @@ func NextTargetToBuild(state store.EngineState) (*store.ManifestTarget, HoldSet)
 		HoldUnparallelizableLocalTargets(targets, holds)
 	}
 
-	// Uncategorized YAML might contain namespaces or volumes that
-	// we don't want to parallelize.
-	//
-	// TODO(nick): Long-term, we should try to infer dependencies between Kubernetes
-	// resources. A general library might make sense.
-	if IsBuildingUncategorizedYAML(state) {
-		HoldK8sTargets(targets, holds)
-	}
+	// Kubernetes objects might need objects deployed by other resources
+	// (like namespaces, CRDs, or volumes), so hold them until those resources
+	// have deployed.
+	HoldK8sTargetsWaitingOnObjectDependencies(state, targets, holds)
 
 	HoldTargetsWithBuildingComponents(targets, holds)
 	HoldTargetsWaitingOnDependencies(state, targets, holds)
@@ -303,15 +299,67 @@ func HoldUnparallelizableLocalTargets(targets []*store.ManifestTarget, holds map
 	}
 }
 
-func HoldK8sTargets(targets []*store.ManifestTarget, holds HoldSet) {
-	for _, target := range targets {
-		if target.Manifest.IsK8s() {
-			holds.AddHold(target, store.Hold{
-				Reason: store.HoldReasonWaitingForUncategorized,
-				HoldOn: []model.TargetID{model.UnresourcedYAMLManifestName.TargetID()},
-			})
+func HoldK8sTargetsWaitingOnObjectDependencies(state store.EngineState, mts []*store.ManifestTarget, holds HoldSet) {
+	uncategorizedID := model.UnresourcedYAMLManifestName.TargetID()
+	for _, mt := range mts {
+		waitingOn := waitingOnObjectDependencies(state, mt)
+		if len(waitingOn) == 0 {
+			continue
+		}
+
+		reason := store.HoldReasonWaitingForDep
+		if len(waitingOn) == 1 && waitingOn[0] == uncategorizedID {
+			reason = store.HoldReasonWaitingForUncategorized
+		}
+		holds.AddHold(mt, store.Hold{
+			Reason: reason,
+			HoldOn: waitingOn,
+		})
+	}
+}
+
+func waitingOnObjectDependencies(state store.EngineState, mt *store.ManifestTarget) []model.TargetID {
+	m := mt.Manifest
+	if !m.IsK8s() {
+		return nil
+	}
+
+	deps := m.ObjectDependencies
+	if m.K8sTarget().YAML == "" && m.Name != model.UnresourcedYAMLManifestName {
+		// If we don't know what objects this resource deploys (e.g., it uses
+		// a custom deploy command), assume it might need the uncategorized YAML.
+		deps = []model.ManifestName{model.UnresourcedYAMLManifestName}
+	}
+
+	var waitingOn []model.TargetID
+	for _, mn := range deps {
+		dep, ok := state.ManifestTargets[mn]
+		if !ok {
+			continue
+		}
+
+		// After the first deploy, the objects already exist, so we only need to
+		// wait for in-progress deploys.
+		if dep.State.IsBuilding() ||
+			(!mt.State.StartedFirstBuild() && isWaitingForFirstDeploy(state, dep)) {
+			waitingOn = append(waitingOn, mn.TargetID())
+		}
+	}
+	return waitingOn
+}
+
+// Returns true if the target will deploy automatically but hasn't finished
+// deploying yet.
+func isWaitingForFirstDeploy(state store.EngineState, mt *store.ManifestTarget) bool {
+	if !mt.State.LastBuild().Empty() || !mt.Manifest.TriggerMode.AutoInitial() {
+		return false
+	}
+	if uir, ok := state.UIResources[mt.Manifest.Name.String()]; ok {
+		if uir.Status.DisableStatus.DisabledCount > 0 {
+			return false
 		}
 	}
+	return true
 }
 
 func IsBuildingAnything(state store.EngineState) bool {
@@ -335,16 +383,6 @@ func IsBuildingUnparallelizableLocalTarget(state store.EngineState) (model.Manif
 	return "", "", false
 }
 
-func IsBuildingUncategorizedYAML(state store.EngineState) bool {
-	mts := state.Targets()
-	for _, mt := range mts {
-		if mt.State.IsBuilding() && mt.Manifest.Name == model.UnresourcedYAMLManifestName {
-			return true
-		}
-	}
-	return false
-}
-
 // Go through all the manifests, and check:
 // 1) all pending file changes
 // 2) all pending dependency changes (where an image has been rebuilt by another manifest), and
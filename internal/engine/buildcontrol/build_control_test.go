@@ -58,20 +58,68 @@ func TestCurrentlyBuildingK8sResourceDisablesLocalScheduling(t *testing.T) {
 	f.assertNoTargetNextToBuild()
 }
 
-func TestCurrentlyBuildingUncategorizedDisablesOtherK8sTargets(t *testing.T) {
+func TestCurrentlyBuildingUncategorizedDisablesDependentK8sTargets(t *testing.T) {
 	f := newTestFixture(t)
 	defer f.TearDown()
 
-	_ = f.upsertK8sManifest("k8s1")
+	_ = f.upsertK8sManifest("k8s1", withObjectDeps(model.UnresourcedYAMLManifestName.String()))
 	k8sUnresourced := f.upsertK8sManifest(model.UnresourcedYAMLManifestName)
 	_ = f.upsertK8sManifest("k8s2")
 
 	f.assertNextTargetToBuild(model.UnresourcedYAMLManifestName)
 	k8sUnresourced.State.CurrentBuild = model.BuildRecord{StartTime: time.Now()}
+	f.assertNextTargetToBuild("k8s2")
+	f.assertHold("k8s1", store.HoldReasonWaitingForUncategorized, model.ManifestName("uncategorized").TargetID())
+	f.assertHold("k8s2", store.HoldReasonNone)
+}
+
+func TestObjectDepsOnlyBlockWhileBuilding(t *testing.T) {
+	f := newTestFixture(t)
+	defer f.TearDown()
+
+	crd := f.upsertK8sManifest("crd")
+	cr := f.upsertK8sManifest("cr", withObjectDeps("crd"))
+
+	crd.State.CurrentBuild = model.BuildRecord{StartTime: time.Now()}
 	f.assertNoTargetNextToBuild()
-	for _, mn := range []model.ManifestName{"k8s1", "k8s2"} {
-		f.assertHold(mn, store.HoldReasonWaitingForUncategorized, model.ManifestName("uncategorized").TargetID())
-	}
+	f.assertHold("cr", store.HoldReasonWaitingForDep, model.ManifestName("crd").TargetID())
+
+	// Deploy order doesn't depend on whether the first deploy succeeded.
+	crd.State.CurrentBuild = model.BuildRecord{}
+	crd.State.AddCompletedBuild(model.BuildRecord{
+		StartTime:  time.Now(),
+		FinishTime: time.Now(),
+		Error:      fmt.Errorf("crd failed"),
+	})
+	f.assertNextTargetToBuild("cr")
+
+	cr.State.AddCompletedBuild(model.BuildRecord{
+		StartTime:  time.Now(),
+		FinishTime: time.Now(),
+	})
+	cr.State.AddPendingFileChange(cr.Manifest.K8sTarget().ID(), f.JoinPath("a.yaml"), time.Now())
+	crd.State.CurrentBuild = model.BuildRecord{StartTime: time.Now()}
+	f.assertNoTargetNextToBuild()
+
+	crd.State.CurrentBuild = model.BuildRecord{}
+	f.assertNextTargetToBuild("cr")
+}
+
+func TestObjectDepsWaitForFirstDeploy(t *testing.T) {
+	f := newTestFixture(t)
+	defer f.TearDown()
+
+	_ = f.upsertK8sManifest("workload", withObjectDeps("namespace"))
+	ns := f.upsertK8sManifest("namespace")
+
+	f.assertNextTargetToBuild("namespace")
+	f.assertHold("workload", store.HoldReasonWaitingForDep, model.ManifestName("namespace").TargetID())
+
+	ns.State.AddCompletedBuild(model.BuildRecord{
+		StartTime:  time.Now(),
+		FinishTime: time.Now(),
+	})
+	f.assertNextTargetToBuild("workload")
 }
 
 func TestK8sDependsOnLocal(t *testing.T) {
@@ -547,6 +595,11 @@ func withResourceDeps(deps ...string) manifestOption {
 		return m.WithResourceDeps(deps...)
 	})
 }
+func withObjectDeps(deps ...string) manifestOption {
+	return manifestOption(func(m manifestbuilder.ManifestBuilder) manifestbuilder.ManifestBuilder {
+		return m.WithObjectDeps(deps...)
+	})
+}
 func withK8sPodReadiness(pr model.PodReadinessMode) manifestOption {
 	return manifestOption(func(m manifestbuilder.ManifestBuilder) manifestbuilder.ManifestBuilder {
 		return m.WithK8sPodReadiness(pr)
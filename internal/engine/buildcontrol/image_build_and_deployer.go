@@ -76,7 +76,7 @@ type ImageBuildAndDeployer struct {
 
 func NewImageBuildAndDeployer(
 	db build.DockerBuilder,
-	customBuilder build.CustomBuilder,
+	ib *ImageBuilder,
 	k8sClient k8s.Client,
 	env k8s.Env,
 	kubeContext k8s.KubeContext,
@@ -88,7 +88,7 @@ func NewImageBuildAndDeployer(
 ) *ImageBuildAndDeployer {
 	return &ImageBuildAndDeployer{
 		db:          db,
-		ib:          NewImageBuilder(db, customBuilder),
+		ib:          ib,
 		k8sClient:   k8sClient,
 		env:         env,
 		kubeContext: kubeContext,
@@ -189,7 +189,7 @@ func (ibd *ImageBuildAndDeployer) BuildAndDeploy(ctx context.Context, st store.R
 		// while an image build is going on in parallel.
 		startTime := apis.NowMicro()
 
-		refs, err := ibd.ib.Build(ctx, iTarget, ps)
+		refs, err := ibd.ib.BuildOrReuse(ctx, iTarget, stateSet[iTarget.ID()], ps)
 		if err != nil {
 			return store.ImageBuildResult{}, err
 		}
@@ -266,6 +266,72 @@ func TestImageIsClean(t *testing.T) {
 	assert.Equal(t, 0, f.docker.PushCount)
 }
 
+func TestReuseImageBuiltInPreviousSession(t *testing.T) {
+	f := newIBDFixture(t, k8s.EnvGKE)
+	defer f.TearDown()
+
+	// Keep the build context separate from the cache dir.
+	f.WriteFile("src/main.go", "package main")
+	manifest := manifestbuilder.New(f, "sancho").
+		WithK8sYAML(SanchoYAML).
+		WithImageTarget(model.MustNewImageTarget(SanchoRef).
+			WithDockerImage(v1alpha1.DockerImageSpec{
+				DockerfileContents: SanchoDockerfile,
+				Context:            f.JoinPath("src"),
+			})).
+		Build()
+
+	result1, err := f.BuildAndDeploy(BuildTargets(manifest), store.BuildStateSet{})
+	require.NoError(t, err)
+	assert.Equal(t, 1, f.docker.BuildCount)
+
+	// A new session with the same inputs reuses the image.
+	result2, err := f.BuildAndDeploy(BuildTargets(manifest), store.BuildStateSet{})
+	require.NoError(t, err)
+	assert.Equal(t, 1, f.docker.BuildCount)
+
+	id := manifest.ImageTargetAt(0).ID()
+	assert.Equal(t,
+		store.LocalImageRefFromBuildResult(result1[id]).String(),
+		store.LocalImageRefFromBuildResult(result2[id]).String())
+	assert.Contains(t, f.out.String(), "Build inputs unchanged")
+
+	// A force update always rebuilds.
+	stateSet := store.BuildStateSet{id: store.BuildState{FullBuildTriggered: true}}
+	_, err = f.BuildAndDeploy(BuildTargets(manifest), stateSet)
+	require.NoError(t, err)
+	assert.Equal(t, 2, f.docker.BuildCount)
+
+	// So does a change to the inputs.
+	f.WriteFile("src/main.go", "package main\n\nfunc main() {}")
+	_, err = f.BuildAndDeploy(BuildTargets(manifest), store.BuildStateSet{})
+	require.NoError(t, err)
+	assert.Equal(t, 3, f.docker.BuildCount)
+}
+
+func TestDontReuseImageThatNoLongerExists(t *testing.T) {
+	f := newIBDFixture(t, k8s.EnvGKE)
+	defer f.TearDown()
+
+	f.WriteFile("src/main.go", "package main")
+	manifest := manifestbuilder.New(f, "sancho").
+		WithK8sYAML(SanchoYAML).
+		WithImageTarget(model.MustNewImageTarget(SanchoRef).
+			WithDockerImage(v1alpha1.DockerImageSpec{
+				DockerfileContents: SanchoDockerfile,
+				Context:            f.JoinPath("src"),
+			})).
+		Build()
+
+	_, err := f.BuildAndDeploy(BuildTargets(manifest), store.BuildStateSet{})
+	require.NoError(t, err)
+
+	f.docker.ImageAlwaysExists = false
+	_, err = f.BuildAndDeploy(BuildTargets(manifest), store.BuildStateSet{})
+	require.NoError(t, err)
+	assert.Equal(t, 2, f.docker.BuildCount)
+}
+
 func TestImageIsDirtyAfterContainerBuild(t *testing.T) {
 	f := newIBDFixture(t, k8s.EnvGKE)
 	defer f.TearDown()
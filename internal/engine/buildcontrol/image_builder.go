@@ -9,18 +9,24 @@ import (
 	"github.com/tilt-dev/tilt/internal/build"
 	"github.com/tilt-dev/tilt/internal/container"
 	"github.com/tilt-dev/tilt/internal/ignore"
+	"github.com/tilt-dev/tilt/internal/store"
+	"github.com/tilt-dev/tilt/pkg/logger"
 	"github.com/tilt-dev/tilt/pkg/model"
 )
 
 type ImageBuilder struct {
 	db    build.DockerBuilder
 	custb build.CustomBuilder
+	cache *build.ImageDigestCache
+	clock build.Clock
 }
 
-func NewImageBuilder(db build.DockerBuilder, custb build.CustomBuilder) *ImageBuilder {
+func NewImageBuilder(db build.DockerBuilder, custb build.CustomBuilder, cache *build.ImageDigestCache, c build.Clock) *ImageBuilder {
 	return &ImageBuilder{
 		db:    db,
 		custb: custb,
+		cache: cache,
+		clock: c,
 	}
 }
 
@@ -68,3 +74,65 @@ func (icb *ImageBuilder) Build(ctx context.Context, iTarget model.ImageTarget,
 
 	return refs, nil
 }
+
+// BuildOrReuse builds the image, unless an image built from the same inputs
+// in a previous session still exists in the image store.
+//
+// We only look for a previous image on the first build of a target in this
+// session. Later builds happen because something changed (or because the user
+// asked for a rebuild), so we always run the build.
+func (icb *ImageBuilder) BuildOrReuse(ctx context.Context, iTarget model.ImageTarget,
+	state store.BuildState, ps *build.PipelineState) (container.TaggedRefs, error) {
+	if !icb.canUseDigestCache(iTarget) {
+		return icb.Build(ctx, iTarget, ps)
+	}
+
+	digest, err := build.ImageTargetDigest(iTarget, ignore.CreateBuildContextFilter(iTarget))
+	if err != nil {
+		// The digest is an optimization, so don't fail the build over it.
+		logger.Get(ctx).Debugf("Computing digest of %s: %v",
+			container.FamiliarString(iTarget.Refs.ConfigurationRef), err)
+		return icb.Build(ctx, iTarget, ps)
+	}
+
+	if state.LastResult == nil && !state.FullBuildTriggered {
+		refs, ok := icb.cache.Get(digest)
+		if ok {
+			exists, err := icb.db.ImageExists(ctx, refs.LocalRef)
+			if err == nil && exists {
+				ps.StartPipelineStep(ctx, "Reusing image: [%s]", container.FamiliarString(iTarget.Refs.ConfigurationRef))
+				ps.Printf(ctx, "Build inputs unchanged since %s was built", container.FamiliarString(refs.LocalRef))
+				ps.EndPipelineStep(ctx)
+				return refs, nil
+			}
+		}
+	}
+
+	refs, err := icb.Build(ctx, iTarget, ps)
+	if err != nil {
+		return container.TaggedRefs{}, err
+	}
+
+	err = icb.cache.Put(digest, refs, icb.clock.Now())
+	if err != nil {
+		logger.Get(ctx).Debugf("Saving digest of %s: %v", container.FamiliarString(refs.LocalRef), err)
+	}
+	return refs, nil
+}
+
+// Whether we can check that an image built from the target's inputs still exists.
+func (icb *ImageBuilder) canUseDigestCache(iTarget model.ImageTarget) bool {
+	if icb.cache == nil {
+		return false
+	}
+	switch bd := iTarget.BuildDetails.(type) {
+	case model.DockerBuild:
+		// If the base image might have changed, the inputs don't determine the image.
+		return !bd.Pull
+	case model.CustomBuild:
+		// If the image never lands in the local Docker image store,
+		// we have no way to check that it still exists.
+		return !bd.SkipsLocalDocker
+	}
+	return false
+}
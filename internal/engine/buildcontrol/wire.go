@@ -22,6 +22,7 @@ import (
 	"github.com/tilt-dev/tilt/internal/store"
 	"github.com/tilt-dev/tilt/internal/store/liveupdates"
 	"github.com/tilt-dev/tilt/internal/tracer"
+	"github.com/tilt-dev/tilt/internal/xdg"
 	"github.com/tilt-dev/tilt/pkg/apis/core/v1alpha1"
 )
 
@@ -45,6 +46,7 @@ var BaseWireSet = wire.NewSet(
 	containerupdate.NewDockerUpdater,
 	containerupdate.NewExecUpdater,
 	NewImageBuilder,
+	build.NewImageDigestCache,
 
 	tracer.InitOpenTelemetry,
 
@@ -69,6 +71,7 @@ func ProvideImageBuildAndDeployer(
 		BaseWireSet,
 		kubernetesapply.NewReconciler,
 		provideFakeK8sNamespace,
+		provideFakeXDGBase,
 	)
 
 	return nil, nil
@@ -78,6 +81,10 @@ func provideFakeK8sNamespace() k8s.Namespace {
 	return "default"
 }
 
+func provideFakeXDGBase(dir *dirs.TiltDevDir) xdg.Base {
+	return xdg.FakeBase{Dir: dir.Root()}
+}
+
 func ProvideDockerComposeBuildAndDeployer(
 	ctx context.Context,
 	dcCli dockercompose.DockerComposeClient,
@@ -86,6 +93,7 @@ func ProvideDockerComposeBuildAndDeployer(
 	wire.Build(
 		BaseWireSet,
 		build.ProvideClock,
+		provideFakeXDGBase,
 	)
 
 	return nil, nil
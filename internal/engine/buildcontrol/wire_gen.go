@@ -24,6 +24,7 @@ import (
 	"github.com/tilt-dev/tilt/internal/store"
 	"github.com/tilt-dev/tilt/internal/store/liveupdates"
 	"github.com/tilt-dev/tilt/internal/tracer"
+	"github.com/tilt-dev/tilt/internal/xdg"
 	"github.com/tilt-dev/tilt/pkg/apis/core/v1alpha1"
 )
 
@@ -34,10 +35,13 @@ func ProvideImageBuildAndDeployer(ctx context.Context, docker2 docker.Client, kC
 	dockerImageBuilder := build.NewDockerImageBuilder(docker2, labels)
 	dockerBuilder := build.DefaultDockerBuilder(dockerImageBuilder)
 	execCustomBuilder := build.NewExecCustomBuilder(docker2, clock)
+	base := provideFakeXDGBase(dir)
+	imageDigestCache := build.NewImageDigestCache(base)
+	imageBuilder := NewImageBuilder(dockerBuilder, execCustomBuilder, imageDigestCache, clock)
 	scheme := v1alpha1.NewScheme()
 	namespace := provideFakeK8sNamespace()
 	reconciler := kubernetesapply.NewReconciler(ctrlclient, kClient, scheme, dockerBuilder, kubeContext, st, namespace, execer)
-	imageBuildAndDeployer := NewImageBuildAndDeployer(dockerBuilder, execCustomBuilder, kClient, env, kubeContext, analytics2, clock, kp, ctrlclient, reconciler)
+	imageBuildAndDeployer := NewImageBuildAndDeployer(dockerBuilder, imageBuilder, kClient, env, kubeContext, analytics2, clock, kp, ctrlclient, reconciler)
 	return imageBuildAndDeployer, nil
 }
 
@@ -51,7 +55,9 @@ func ProvideDockerComposeBuildAndDeployer(ctx context.Context, dcCli dockercompo
 	dockerBuilder := build.DefaultDockerBuilder(dockerImageBuilder)
 	clock := build.ProvideClock()
 	execCustomBuilder := build.NewExecCustomBuilder(dCli, clock)
-	imageBuilder := NewImageBuilder(dockerBuilder, execCustomBuilder)
+	base := provideFakeXDGBase(dir)
+	imageDigestCache := build.NewImageDigestCache(base)
+	imageBuilder := NewImageBuilder(dockerBuilder, execCustomBuilder, imageDigestCache, clock)
 	dockerComposeBuildAndDeployer := NewDockerComposeBuildAndDeployer(dcCli, dCli, imageBuilder, clock)
 	return dockerComposeBuildAndDeployer, nil
 }
@@ -61,9 +67,13 @@ func ProvideDockerComposeBuildAndDeployer(ctx context.Context, dcCli dockercompo
 var BaseWireSet = wire.NewSet(wire.Value(dockerfile.Labels{}), v1alpha1.NewScheme, k8s.ProvideMinikubeClient, build.DefaultDockerBuilder, build.NewDockerImageBuilder, build.NewExecCustomBuilder, wire.Bind(new(build.CustomBuilder), new(*build.ExecCustomBuilder)), wire.Bind(new(build.DockerKubeConnection), new(build.DockerBuilder)), NewDockerComposeBuildAndDeployer,
 	NewImageBuildAndDeployer,
 	NewLiveUpdateBuildAndDeployer,
-	NewLocalTargetBuildAndDeployer, containerupdate.NewDockerUpdater, containerupdate.NewExecUpdater, NewImageBuilder, tracer.InitOpenTelemetry, liveupdates.ProvideUpdateMode,
+	NewLocalTargetBuildAndDeployer, containerupdate.NewDockerUpdater, containerupdate.NewExecUpdater, NewImageBuilder, build.NewImageDigestCache, tracer.InitOpenTelemetry, liveupdates.ProvideUpdateMode,
 )
 
 func provideFakeK8sNamespace() k8s.Namespace {
 	return "default"
 }
+
+func provideFakeXDGBase(dir *dirs.TiltDevDir) xdg.Base {
+	return xdg.FakeBase{Dir: dir.Root()}
+}
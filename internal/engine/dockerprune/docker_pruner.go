@@ -7,6 +7,7 @@ import (
 	"strings"
 	"time"
 
+	"github.com/docker/distribution/reference"
 	"github.com/docker/go-units"
 
 	"github.com/docker/docker/api/types"
@@ -26,19 +27,24 @@ import (
 
 type DockerPruner struct {
 	dCli docker.Client
+	rCli RegistryClient
 
 	disabledForTesting bool
 	disabledOnSetup    bool
 
 	lastPruneBuildCount int
 	lastPruneTime       time.Time
+
+	// The registry images that this Tilt has pushed. Other images in the
+	// repository may belong to teammates, so we never prune them.
+	pushedImages map[string]bool
 }
 
 var _ store.Subscriber = &DockerPruner{}
 var _ store.SetUpper = &DockerPruner{}
 
-func NewDockerPruner(dCli docker.Client) *DockerPruner {
-	return &DockerPruner{dCli: dCli}
+func NewDockerPruner(dCli docker.Client, rCli RegistryClient) *DockerPruner {
+	return &DockerPruner{dCli: dCli, rCli: rCli, pushedImages: make(map[string]bool)}
 }
 
 func (dp *DockerPruner) DisabledForTesting(disabled bool) {
@@ -76,6 +82,10 @@ func (dp *DockerPruner) OnChange(ctx context.Context, st store.RStore, summary s
 
 	state := st.RLockState()
 	settings := state.DockerPruneSettings
+	if settings.Enabled && settings.Registry {
+		dp.recordPushedImages(state)
+	}
+
 	// Exit early if possible if any of the following is true:
 	// 	* Pruning is disabled entirely
 	// 	* Engine is currently building something
@@ -110,8 +120,17 @@ func (dp *DockerPruner) OnChange(ctx context.Context, st store.RStore, summary s
 		// 	of store events and this is a comparatively expensive operation (lots of regex), but 99% of the time this
 		// 	is called, no pruning is going to happen, so avoid burning CPU cycles unnecessarily
 		imgSelectors := model.LocalRefSelectorsForManifests(state.Manifests())
+		var registryRefSets []container.RefSet
+		var inUse []reference.NamedTagged
+		if settings.Registry {
+			registryRefSets = RegistryRefSetsForManifests(state.Manifests())
+			inUse = inUseImageRefs(state)
+		}
 		st.RUnlockState()
 		dp.PruneAndRecordState(ctx, settings.MaxAge, settings.KeepRecent, imgSelectors, curBuildCount)
+		if len(registryRefSets) > 0 {
+			dp.PruneRegistry(ctx, settings.MaxAge, settings.KeepRecent, registryRefSets, inUse)
+		}
 		return nil
 	}
 
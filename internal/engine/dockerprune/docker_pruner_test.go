@@ -377,6 +377,7 @@ type dockerPruneFixture struct {
 	st   *store.TestingStore
 
 	dCli *docker.FakeClient
+	rCli *FakeRegistryClient
 	dp   *DockerPruner
 }
 
@@ -386,7 +387,8 @@ func newFixture(t *testing.T) *dockerPruneFixture {
 	st := store.NewTestingStore()
 
 	dCli := docker.NewFakeClient()
-	dp := NewDockerPruner(dCli)
+	rCli := NewFakeRegistryClient()
+	dp := NewDockerPruner(dCli, rCli)
 
 	return &dockerPruneFixture{
 		t:    t,
@@ -394,6 +396,7 @@ func newFixture(t *testing.T) *dockerPruneFixture {
 		logs: logs,
 		st:   st,
 		dCli: dCli,
+		rCli: rCli,
 		dp:   dp,
 	}
 }
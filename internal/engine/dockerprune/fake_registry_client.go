@@ -0,0 +1,71 @@
+package dockerprune
+
+import (
+	"context"
+	"fmt"
+	"sync"
+
+	"github.com/docker/distribution/reference"
+	"github.com/opencontainers/go-digest"
+)
+
+type FakeRegistryClient struct {
+	mu sync.Mutex
+
+	// Tags of each repository, keyed by repository name.
+	TagsByRepo map[string][]string
+
+	// Images, keyed by tagged ref.
+	Images map[string]RegistryImage
+
+	TagsError   error
+	DeleteError error
+	Deleted     []digest.Digest
+}
+
+var _ RegistryClient = &FakeRegistryClient{}
+
+func NewFakeRegistryClient() *FakeRegistryClient {
+	return &FakeRegistryClient{
+		TagsByRepo: make(map[string][]string),
+		Images:     make(map[string]RegistryImage),
+	}
+}
+
+// Adds a tagged image to the fake registry.
+func (c *FakeRegistryClient) AddImage(ref reference.NamedTagged, image RegistryImage) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	repo := ref.Name()
+	c.TagsByRepo[repo] = append(c.TagsByRepo[repo], ref.Tag())
+	c.Images[ref.String()] = image
+}
+
+func (c *FakeRegistryClient) Tags(ctx context.Context, repo reference.Named) ([]string, error) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if c.TagsError != nil {
+		return nil, c.TagsError
+	}
+	return append([]string{}, c.TagsByRepo[repo.Name()]...), nil
+}
+
+func (c *FakeRegistryClient) Inspect(ctx context.Context, ref reference.NamedTagged) (RegistryImage, error) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	image, ok := c.Images[ref.String()]
+	if !ok {
+		return RegistryImage{}, fmt.Errorf("image not found: %s", ref)
+	}
+	return image, nil
+}
+
+func (c *FakeRegistryClient) Delete(ctx context.Context, repo reference.Named, dgst digest.Digest) error {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if c.DeleteError != nil {
+		return c.DeleteError
+	}
+	c.Deleted = append(c.Deleted, dgst)
+	return nil
+}
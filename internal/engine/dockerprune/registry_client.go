@@ -0,0 +1,336 @@
+package dockerprune
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"net"
+	"net/http"
+	"net/url"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/docker/cli/cli/config"
+	"github.com/docker/distribution"
+	"github.com/docker/distribution/manifest/schema2"
+	"github.com/docker/distribution/reference"
+	"github.com/docker/distribution/registry/client"
+	"github.com/docker/distribution/registry/client/auth"
+	"github.com/docker/distribution/registry/client/auth/challenge"
+	"github.com/docker/distribution/registry/client/transport"
+	registrytypes "github.com/docker/docker/api/types/registry"
+	"github.com/opencontainers/go-digest"
+
+	"github.com/tilt-dev/tilt/internal/docker"
+)
+
+// An image in a registry.
+type RegistryImage struct {
+	Digest  digest.Digest
+	Created time.Time
+}
+
+// Talks to an image registry with the registry v2 API.
+type RegistryClient interface {
+	// Lists the tags in a repository.
+	Tags(ctx context.Context, repo reference.Named) ([]string, error)
+
+	// Fetches the digest and creation time of a tagged image.
+	Inspect(ctx context.Context, ref reference.NamedTagged) (RegistryImage, error)
+
+	// Deletes the image with the given digest, and all the tags that point to it.
+	Delete(ctx context.Context, repo reference.Named, dgst digest.Digest) error
+}
+
+type registryClient struct {
+	// The Docker daemon that pushes the images. We allow plain HTTP
+	// for the same registries that it does.
+	dCli docker.Client
+
+	mu sync.Mutex
+
+	// Authenticated transports, keyed by repository.
+	transports map[string]registryTransport
+}
+
+type registryTransport struct {
+	baseURL   string
+	transport http.RoundTripper
+}
+
+var _ RegistryClient = &registryClient{}
+
+func NewRegistryClient(dCli docker.Client) RegistryClient {
+	return &registryClient{
+		dCli:       dCli,
+		transports: make(map[string]registryTransport),
+	}
+}
+
+func (c *registryClient) Tags(ctx context.Context, repo reference.Named) ([]string, error) {
+	r, err := c.repository(ctx, repo)
+	if err != nil {
+		return nil, err
+	}
+	tags, err := r.Tags(ctx).All(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("listing tags of %s: %v", repo, err)
+	}
+	return tags, nil
+}
+
+func (c *registryClient) Inspect(ctx context.Context, ref reference.NamedTagged) (RegistryImage, error) {
+	r, err := c.repository(ctx, ref)
+	if err != nil {
+		return RegistryImage{}, err
+	}
+
+	desc, err := r.Tags(ctx).Get(ctx, ref.Tag())
+	if err != nil {
+		return RegistryImage{}, fmt.Errorf("inspecting %s: %v", ref, err)
+	}
+
+	ms, err := r.Manifests(ctx)
+	if err != nil {
+		return RegistryImage{}, fmt.Errorf("inspecting %s: %v", ref, err)
+	}
+	m, err := ms.Get(ctx, desc.Digest)
+	if err != nil {
+		return RegistryImage{}, fmt.Errorf("inspecting %s: %v", ref, err)
+	}
+
+	// Tilt pushes single-platform images, so we don't try to make
+	// sense of manifest lists.
+	image, ok := m.(*schema2.DeserializedManifest)
+	if !ok {
+		return RegistryImage{}, fmt.Errorf("inspecting %s: unsupported manifest type", ref)
+	}
+
+	configJSON, err := r.Blobs(ctx).Get(ctx, image.Config.Digest)
+	if err != nil {
+		return RegistryImage{}, fmt.Errorf("inspecting %s: %v", ref, err)
+	}
+
+	var imageConfig struct {
+		Created time.Time `json:"created"`
+	}
+	err = json.Unmarshal(configJSON, &imageConfig)
+	if err != nil {
+		return RegistryImage{}, fmt.Errorf("inspecting %s: %v", ref, err)
+	}
+
+	return RegistryImage{
+		Digest:  desc.Digest,
+		Created: imageConfig.Created,
+	}, nil
+}
+
+func (c *registryClient) Delete(ctx context.Context, repo reference.Named, dgst digest.Digest) error {
+	r, err := c.repository(ctx, repo)
+	if err != nil {
+		return err
+	}
+	ms, err := r.Manifests(ctx)
+	if err != nil {
+		return err
+	}
+	return ms.Delete(ctx, dgst)
+}
+
+func (c *registryClient) repository(ctx context.Context, ref reference.Named) (distribution.Repository, error) {
+	host := reference.Domain(ref)
+	path := reference.Path(ref)
+
+	t, err := c.transport(ctx, host, path)
+	if err != nil {
+		return nil, err
+	}
+
+	name, err := reference.WithName(path)
+	if err != nil {
+		return nil, err
+	}
+	return client.NewRepository(name, t.baseURL, t.transport)
+}
+
+// Pings the registry to find out how it wants us to authenticate,
+// then creates a transport that authenticates with the user's Docker credentials.
+func (c *registryClient) transport(ctx context.Context, host, path string) (registryTransport, error) {
+	key := host + "/" + path
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	t, ok := c.transports[key]
+	if ok {
+		return t, nil
+	}
+
+	baseURL, resp, err := ping(ctx, host, c.isInsecure(ctx, host))
+	if err != nil {
+		return registryTransport{}, err
+	}
+
+	manager := challenge.NewSimpleManager()
+	err = manager.AddResponse(resp)
+	if err != nil {
+		return registryTransport{}, err
+	}
+
+	creds := newCredentialStore(host)
+	tokenHandler := auth.NewTokenHandlerWithOptions(auth.TokenHandlerOptions{
+		Transport:   http.DefaultTransport,
+		Credentials: creds,
+		Scopes: []auth.Scope{
+			auth.RepositoryScope{Repository: path, Actions: []string{"pull", "push", "delete"}},
+		},
+	})
+	basicHandler := auth.NewBasicHandler(creds)
+
+	t = registryTransport{
+		baseURL:   baseURL,
+		transport: transport.NewTransport(http.DefaultTransport, auth.NewAuthorizer(manager, tokenHandler, basicHandler)),
+	}
+	c.transports[key] = t
+	return t, nil
+}
+
+// Checks whether the Docker daemon treats the registry as insecure, e.g.,
+// a registry at a minikube IP that the daemon was started with
+// --insecure-registry for.
+func (c *registryClient) isInsecure(ctx context.Context, host string) bool {
+	info, err := c.dCli.Info(ctx)
+	if err != nil {
+		return false
+	}
+	return isInsecureRegistry(info.RegistryConfig, host)
+}
+
+// Matches the registry against the daemon's insecure registries,
+// the same way Docker does.
+func isInsecureRegistry(config *registrytypes.ServiceConfig, host string) bool {
+	if config == nil {
+		return false
+	}
+
+	index, ok := config.IndexConfigs[host]
+	if ok {
+		return !index.Secure
+	}
+
+	hostname := host
+	if h, _, err := net.SplitHostPort(host); err == nil {
+		hostname = h
+	}
+	ips := []net.IP{net.ParseIP(hostname)}
+	if ips[0] == nil {
+		var err error
+		ips, err = net.LookupIP(hostname)
+		if err != nil {
+			return false
+		}
+	}
+
+	for _, ip := range ips {
+		for _, cidr := range config.InsecureRegistryCIDRs {
+			if (*net.IPNet)(cidr).Contains(ip) {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+// Pings the v2 API of the registry.
+//
+// Like Docker, we allow plain HTTP for registries on localhost,
+// which is where most local dev registries live, and for registries
+// that the Docker daemon treats as insecure.
+func ping(ctx context.Context, host string, insecure bool) (string, *http.Response, error) {
+	schemes := []string{"https"}
+	if insecure || isLocalhost(host) {
+		schemes = append(schemes, "http")
+	}
+
+	httpClient := &http.Client{Timeout: 10 * time.Second}
+	var lastErr error
+	for _, scheme := range schemes {
+		baseURL := fmt.Sprintf("%s://%s", scheme, host)
+		req, err := http.NewRequestWithContext(ctx, http.MethodGet, baseURL+"/v2/", nil)
+		if err != nil {
+			return "", nil, err
+		}
+
+		resp, err := httpClient.Do(req)
+		if err != nil {
+			lastErr = err
+			continue
+		}
+		_ = resp.Body.Close()
+		return baseURL, resp, nil
+	}
+	return "", nil, fmt.Errorf("connecting to registry %s: %v", host, lastErr)
+}
+
+func isLocalhost(host string) bool {
+	hostname := host
+	if h, _, err := net.SplitHostPort(host); err == nil {
+		hostname = h
+	}
+	if hostname == "localhost" || strings.HasSuffix(hostname, ".localhost") {
+		return true
+	}
+	ip := net.ParseIP(hostname)
+	return ip != nil && ip.IsLoopback()
+}
+
+// Reads credentials for the registry from the Docker config,
+// the same way `docker push` does.
+type credentialStore struct {
+	username string
+	password string
+
+	mu            sync.Mutex
+	refreshTokens map[string]string
+}
+
+func newCredentialStore(host string) *credentialStore {
+	configFile := config.LoadDefaultConfigFile(ioutil.Discard)
+
+	// If we fail to get credentials for some reason, that's OK.
+	// Many local registries don't need them.
+	authConfig, _ := configFile.GetAuthConfig(host)
+
+	refreshTokens := make(map[string]string)
+	if authConfig.IdentityToken != "" {
+		refreshTokens[""] = authConfig.IdentityToken
+	}
+
+	return &credentialStore{
+		username:      authConfig.Username,
+		password:      authConfig.Password,
+		refreshTokens: refreshTokens,
+	}
+}
+
+func (s *credentialStore) Basic(*url.URL) (string, string) {
+	return s.username, s.password
+}
+
+func (s *credentialStore) RefreshToken(_ *url.URL, service string) string {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	token, ok := s.refreshTokens[service]
+	if !ok {
+		return s.refreshTokens[""]
+	}
+	return token
+}
+
+func (s *credentialStore) SetRefreshToken(_ *url.URL, service, token string) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.refreshTokens[service] = token
+}
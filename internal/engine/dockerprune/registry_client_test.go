@@ -0,0 +1,29 @@
+package dockerprune
+
+import (
+	"net"
+	"testing"
+
+	registrytypes "github.com/docker/docker/api/types/registry"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestIsInsecureRegistry(t *testing.T) {
+	_, cidr, err := net.ParseCIDR("192.168.49.0/24")
+	if err != nil {
+		t.Fatal(err)
+	}
+	config := &registrytypes.ServiceConfig{
+		InsecureRegistryCIDRs: []*registrytypes.NetIPNet{(*registrytypes.NetIPNet)(cidr)},
+		IndexConfigs: map[string]*registrytypes.IndexInfo{
+			"kind-registry:5000": {Name: "kind-registry:5000", Secure: false},
+			"docker.io":          {Name: "docker.io", Secure: true},
+		},
+	}
+
+	assert.True(t, isInsecureRegistry(config, "192.168.49.2:5000"))
+	assert.True(t, isInsecureRegistry(config, "kind-registry:5000"))
+	assert.False(t, isInsecureRegistry(config, "docker.io"))
+	assert.False(t, isInsecureRegistry(config, "10.0.0.2:5000"))
+	assert.False(t, isInsecureRegistry(nil, "192.168.49.2:5000"))
+}
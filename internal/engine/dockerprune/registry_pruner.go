@@ -0,0 +1,218 @@
+package dockerprune
+
+import (
+	"context"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/docker/distribution/reference"
+	"github.com/opencontainers/go-digest"
+
+	"github.com/tilt-dev/tilt/internal/build"
+	"github.com/tilt-dev/tilt/internal/container"
+	"github.com/tilt-dev/tilt/internal/store"
+	"github.com/tilt-dev/tilt/pkg/logger"
+	"github.com/tilt-dev/tilt/pkg/model"
+)
+
+type RegistryPruneReport struct {
+	// The refs of the tags we deleted.
+	TagsDeleted []string
+}
+
+// The images that Tilt pushes to a registry.
+func RegistryRefSetsForManifests(manifests []model.Manifest) []container.RefSet {
+	var result []container.RefSet
+	for _, m := range manifests {
+		for _, iTarget := range m.ImageTargets {
+			if iTarget.Refs.Registry().Empty() {
+				continue
+			}
+			result = append(result, iTarget.Refs)
+		}
+	}
+	return result
+}
+
+// The images that Tilt has most recently built (and deployed) for each manifest.
+func inUseImageRefs(state store.EngineState) []reference.NamedTagged {
+	var result []reference.NamedTagged
+	for _, mt := range state.Targets() {
+		for _, iTarget := range mt.Manifest.ImageTargets {
+			ref := store.LocalImageRefFromBuildResult(mt.State.BuildStatus(iTarget.ID()).LastResult)
+			if ref != nil {
+				result = append(result, ref)
+			}
+		}
+	}
+	return result
+}
+
+// Remembers the images that we've pushed to a registry, from the latest build results.
+func (dp *DockerPruner) recordPushedImages(state store.EngineState) {
+	for _, mt := range state.Targets() {
+		for _, iTarget := range mt.Manifest.ImageTargets {
+			if iTarget.Refs.Registry().Empty() {
+				continue
+			}
+			ref := store.LocalImageRefFromBuildResult(mt.State.BuildStatus(iTarget.ID()).LastResult)
+			if ref != nil {
+				dp.pushedImages[ref.String()] = true
+			}
+		}
+	}
+}
+
+// Deletes Tilt-built images from the registries that Tilt pushes to.
+//
+// Only deletes images that this Tilt pushed, because teammates may share
+// the registry and still have their images deployed.
+//
+// Keeps the keepRecent most recent images of each repository,
+// any images that are in use, and any images newer than maxAge.
+func (dp *DockerPruner) PruneRegistry(ctx context.Context, maxAge time.Duration, keepRecent int, refSets []container.RefSet, inUse []reference.NamedTagged) {
+	report := dp.pruneRegistry(ctx, maxAge, keepRecent, refSets, inUse)
+	prettyPrintRegistryPruneReport(report, logger.Get(ctx))
+}
+
+func (dp *DockerPruner) pruneRegistry(ctx context.Context, maxAge time.Duration, keepRecent int, refSets []container.RefSet, inUse []reference.NamedTagged) RegistryPruneReport {
+	inUseRefs := make(map[string]bool, len(inUse))
+	for _, ref := range inUse {
+		inUseRefs[ref.String()] = true
+	}
+
+	report := RegistryPruneReport{}
+	seen := make(map[string]bool)
+	for _, refs := range refSets {
+		repo := refs.LocalRef()
+		if seen[repo.String()] {
+			continue
+		}
+		seen[repo.String()] = true
+
+		deleted := dp.pruneRepository(ctx, maxAge, keepRecent, refs, inUseRefs)
+		report.TagsDeleted = append(report.TagsDeleted, deleted...)
+	}
+	return report
+}
+
+type registryTag struct {
+	ref   reference.NamedTagged
+	image RegistryImage
+}
+
+// Returns the refs of the tags we deleted.
+func (dp *DockerPruner) pruneRepository(ctx context.Context, maxAge time.Duration, keepRecent int, refs container.RefSet, inUseRefs map[string]bool) []string {
+	l := logger.Get(ctx)
+	repo := refs.LocalRef()
+
+	// Tilt tags images with a content hash, so we only look at tags with Tilt's prefix.
+	// (If the registry has a single image name, the prefix includes the image name.)
+	prefixRefs, err := refs.AddTagSuffix(build.ImageTagPrefix)
+	if err != nil {
+		l.Debugf("[Docker Prune] error pruning registry %s: %v", repo, err)
+		return nil
+	}
+	prefix := prefixRefs.LocalRef.Tag()
+
+	tagNames, err := dp.rCli.Tags(ctx, repo)
+	if err != nil {
+		l.Warnf("[Docker Prune] error pruning registry %s: %v", repo, err)
+		return nil
+	}
+
+	var tags []registryTag
+	var otherTags []reference.NamedTagged
+	for _, tagName := range tagNames {
+		ref, err := reference.WithTag(repo, tagName)
+		if err != nil {
+			continue
+		}
+
+		if !strings.HasPrefix(tagName, prefix) || !dp.pushedImages[ref.String()] {
+			otherTags = append(otherTags, ref)
+			continue
+		}
+
+		// If we can't inspect a tag, we don't know how old it is, so we keep it
+		// (and everything else, unless we can inspect it later).
+		image, err := dp.rCli.Inspect(ctx, ref)
+		if err != nil {
+			l.Debugf("[Docker Prune] error inspecting %s: %v", ref, err)
+			otherTags = append(otherTags, ref)
+			continue
+		}
+		tags = append(tags, registryTag{ref: ref, image: image})
+	}
+
+	// Sort the tags from most recent to least recent.
+	sort.SliceStable(tags, func(i, j int) bool {
+		return tags[i].image.Created.After(tags[j].image.Created)
+	})
+
+	// Deleting an image deletes every tag that points to it,
+	// so we keep an image if we want to keep any of its tags.
+	digestsToKeep := make(map[digest.Digest]bool)
+	for i, tag := range tags {
+		if i < keepRecent || inUseRefs[tag.ref.String()] || time.Since(tag.image.Created) < maxAge {
+			digestsToKeep[tag.image.Digest] = true
+		}
+	}
+
+	var toDelete []digest.Digest
+	tagsByDigest := make(map[digest.Digest][]string)
+	for _, tag := range tags {
+		dgst := tag.image.Digest
+		if digestsToKeep[dgst] {
+			continue
+		}
+		if len(tagsByDigest[dgst]) == 0 {
+			toDelete = append(toDelete, dgst)
+		}
+		tagsByDigest[dgst] = append(tagsByDigest[dgst], tag.ref.String())
+	}
+
+	if len(toDelete) == 0 {
+		return nil
+	}
+
+	// Deleting a digest also deletes the tags we don't manage, like a `latest`
+	// that points at a Tilt build. Keep any digest that has one of those tags.
+	// If we can't tell where a tag points, we can't tell what's safe to delete.
+	for _, ref := range otherTags {
+		image, err := dp.rCli.Inspect(ctx, ref)
+		if err != nil {
+			l.Debugf("[Docker Prune] not pruning registry %s: error inspecting %s: %v", repo, ref, err)
+			return nil
+		}
+		digestsToKeep[image.Digest] = true
+	}
+
+	var deleted []string
+	for _, dgst := range toDelete {
+		if digestsToKeep[dgst] {
+			continue
+		}
+
+		err := dp.rCli.Delete(ctx, repo, dgst)
+		if err != nil {
+			// Many registries don't allow deletes by default, so tell the user why nothing is happening.
+			l.Infof("[Docker Prune] error deleting %s from registry: %v", strings.Join(tagsByDigest[dgst], ", "), err)
+			return deleted
+		}
+		deleted = append(deleted, tagsByDigest[dgst]...)
+	}
+	return deleted
+}
+
+func prettyPrintRegistryPruneReport(report RegistryPruneReport, l logger.Logger) {
+	if len(report.TagsDeleted) == 0 && !l.Level().ShouldDisplay(logger.DebugLvl) {
+		return
+	}
+
+	l.Infof("[Docker Prune] removed %d images from registry", len(report.TagsDeleted))
+	for _, tag := range report.TagsDeleted {
+		l.Debugf("\t- deleted: %s", tag)
+	}
+}
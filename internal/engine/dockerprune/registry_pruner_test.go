@@ -0,0 +1,231 @@
+package dockerprune
+
+import (
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/docker/distribution/reference"
+	"github.com/opencontainers/go-digest"
+	"github.com/stretchr/testify/assert"
+
+	"github.com/tilt-dev/tilt/internal/container"
+	"github.com/tilt-dev/tilt/internal/store"
+	"github.com/tilt-dev/tilt/pkg/model"
+)
+
+var registryRefSet = mustRegistryRefSet("localhost:5000")
+
+func mustRegistryRefSet(host string) container.RefSet {
+	refs, err := container.NewRefSet(refSel, container.MustNewRegistry(host))
+	if err != nil {
+		panic(err)
+	}
+	return refs
+}
+
+func TestPruneRegistryKeepRecent(t *testing.T) {
+	f := newFixture(t)
+	f.withRegistryImage("tilt-1", "sha256:1", 4*time.Hour)
+	f.withRegistryImage("tilt-2", "sha256:2", 3*time.Hour)
+	f.withRegistryImage("tilt-3", "sha256:3", 2*time.Hour)
+
+	f.dp.PruneRegistry(f.ctx, time.Hour, 2, []container.RefSet{registryRefSet}, nil)
+
+	assert.Equal(t, []digest.Digest{"sha256:1"}, f.rCli.Deleted)
+	assert.Contains(t, f.logs.String(), "[Docker Prune] removed 1 images from registry")
+	assert.Contains(t, f.logs.String(), "- deleted: localhost:5000/some-ref:tilt-1")
+}
+
+func TestPruneRegistryKeepInUse(t *testing.T) {
+	f := newFixture(t)
+	inUse := f.withRegistryImage("tilt-1", "sha256:1", 4*time.Hour)
+	f.withRegistryImage("tilt-2", "sha256:2", 3*time.Hour)
+	f.withRegistryImage("tilt-3", "sha256:3", 2*time.Hour)
+
+	f.dp.PruneRegistry(f.ctx, time.Hour, 1, []container.RefSet{registryRefSet}, []reference.NamedTagged{inUse})
+
+	assert.Equal(t, []digest.Digest{"sha256:2"}, f.rCli.Deleted)
+}
+
+func TestPruneRegistryKeepNewerThanMaxAge(t *testing.T) {
+	f := newFixture(t)
+	f.withRegistryImage("tilt-1", "sha256:1", 4*time.Hour)
+	f.withRegistryImage("tilt-2", "sha256:2", 30*time.Minute)
+	f.withRegistryImage("tilt-3", "sha256:3", 10*time.Minute)
+
+	f.dp.PruneRegistry(f.ctx, time.Hour, 0, []container.RefSet{registryRefSet}, nil)
+
+	assert.Equal(t, []digest.Digest{"sha256:1"}, f.rCli.Deleted)
+}
+
+func TestPruneRegistryOnlyTiltTags(t *testing.T) {
+	f := newFixture(t)
+	f.withRegistryImage("tilt-1", "sha256:1", 4*time.Hour)
+	f.withRegistryImage("latest", "sha256:2", 4*time.Hour)
+	f.withRegistryImage("v1.0.0", "sha256:3", 4*time.Hour)
+
+	f.dp.PruneRegistry(f.ctx, time.Hour, 0, []container.RefSet{registryRefSet}, nil)
+
+	assert.Equal(t, []digest.Digest{"sha256:1"}, f.rCli.Deleted)
+}
+
+func TestPruneRegistryKeepDigestsWithOtherTags(t *testing.T) {
+	f := newFixture(t)
+	f.withRegistryImage("tilt-1", "sha256:1", 4*time.Hour)
+	f.withRegistryImage("tilt-2", "sha256:2", 4*time.Hour)
+	f.withRegistryImage("latest", "sha256:2", 4*time.Hour)
+
+	f.dp.PruneRegistry(f.ctx, time.Hour, 0, []container.RefSet{registryRefSet}, nil)
+
+	assert.Equal(t, []digest.Digest{"sha256:1"}, f.rCli.Deleted)
+}
+
+func TestPruneRegistryKeepTeammatesImages(t *testing.T) {
+	f := newFixture(t)
+	f.withRegistryImage("tilt-1", "sha256:1", 4*time.Hour)
+	f.withTeammateRegistryImage("tilt-2", "sha256:2", 4*time.Hour)
+	f.withTeammateRegistryImage("tilt-3", "sha256:3", 4*time.Hour)
+
+	f.dp.PruneRegistry(f.ctx, time.Hour, 0, []container.RefSet{registryRefSet}, nil)
+
+	assert.Equal(t, []digest.Digest{"sha256:1"}, f.rCli.Deleted)
+}
+
+func TestPruneRegistryUninspectableTag(t *testing.T) {
+	f := newFixture(t)
+	f.withRegistryImage("tilt-1", "sha256:1", 4*time.Hour)
+	repo := registryRefSet.LocalRef().Name()
+	f.rCli.TagsByRepo[repo] = append(f.rCli.TagsByRepo[repo], "latest")
+
+	// We can't tell if latest points to sha256:1, so we can't delete it.
+	f.dp.PruneRegistry(f.ctx, time.Hour, 0, []container.RefSet{registryRefSet}, nil)
+
+	assert.Empty(t, f.rCli.Deleted)
+}
+
+func TestPruneRegistrySharedDigest(t *testing.T) {
+	f := newFixture(t)
+	f.withRegistryImage("tilt-1", "sha256:1", 4*time.Hour)
+	f.withRegistryImage("tilt-2", "sha256:1", 4*time.Hour)
+	f.withRegistryImage("tilt-3", "sha256:2", 3*time.Hour)
+	f.withRegistryImage("tilt-4", "sha256:2", 2*time.Hour)
+
+	// The most recent tag points to sha256:2, so we can only delete sha256:1.
+	f.dp.PruneRegistry(f.ctx, time.Hour, 1, []container.RefSet{registryRefSet}, nil)
+
+	assert.Equal(t, []digest.Digest{"sha256:1"}, f.rCli.Deleted)
+	assert.Contains(t, f.logs.String(), "[Docker Prune] removed 2 images from registry")
+}
+
+func TestPruneRegistryDeleteError(t *testing.T) {
+	f := newFixture(t)
+	f.withRegistryImage("tilt-1", "sha256:1", 4*time.Hour)
+	f.rCli.DeleteError = fmt.Errorf("UNSUPPORTED")
+
+	f.dp.PruneRegistry(f.ctx, time.Hour, 0, []container.RefSet{registryRefSet}, nil)
+
+	assert.Empty(t, f.rCli.Deleted)
+	assert.Contains(t, f.logs.String(),
+		"[Docker Prune] error deleting localhost:5000/some-ref:tilt-1 from registry: UNSUPPORTED")
+}
+
+func TestPruneRegistryTagsErrorWarns(t *testing.T) {
+	f := newFixture(t)
+	f.rCli.TagsError = fmt.Errorf("connecting to registry 192.168.49.2:5000: http: server gave HTTP response to HTTPS client")
+
+	f.dp.PruneRegistry(f.ctx, time.Hour, 0, []container.RefSet{registryRefSet}, nil)
+
+	assert.Contains(t, f.logs.String(), "[Docker Prune] error pruning registry localhost:5000/some-ref")
+}
+
+func TestDockerPrunerRegistry(t *testing.T) {
+	f := newFixture(t)
+	inUse := f.withRegistryImage("tilt-1", "sha256:1", 4*time.Hour)
+	f.withRegistryImage("tilt-2", "sha256:2", 3*time.Hour)
+	f.withRegistryImage("tilt-3", "sha256:3", 2*time.Hour)
+	f.withRegistryManifest(inUse)
+	f.withBuildCount(5)
+	f.withRegistryPruneSettings(time.Hour, 1)
+
+	_ = f.dp.OnChange(f.ctx, f.st, store.LegacyChangeSummary())
+
+	f.assertPrune()
+	assert.Equal(t, []digest.Digest{"sha256:2"}, f.rCli.Deleted)
+}
+
+func TestDockerPrunerRegistryRecordsPushedImages(t *testing.T) {
+	f := newFixture(t)
+	pushed := f.withTeammateRegistryImage("tilt-1", "sha256:1", 4*time.Hour)
+	f.withTeammateRegistryImage("tilt-2", "sha256:2", 3*time.Hour)
+	f.withRegistryManifest(pushed)
+	f.withBuildCount(5)
+	f.withRegistryPruneSettings(time.Hour, 0)
+
+	_ = f.dp.OnChange(f.ctx, f.st, store.LegacyChangeSummary())
+	assert.True(t, f.dp.pushedImages[pushed.String()])
+
+	// The image we pushed is still in use, and we never pushed the other one.
+	f.assertPrune()
+	assert.Empty(t, f.rCli.Deleted)
+}
+
+func TestDockerPrunerRegistryDisabled(t *testing.T) {
+	f := newFixture(t)
+	f.withRegistryImage("tilt-1", "sha256:1", 4*time.Hour)
+	f.withRegistryManifest(nil)
+	f.withBuildCount(5)
+	f.withDockerPruneSettings(true, time.Hour, 0, 0)
+
+	_ = f.dp.OnChange(f.ctx, f.st, store.LegacyChangeSummary())
+
+	f.assertPrune()
+	assert.Empty(t, f.rCli.Deleted)
+}
+
+// Adds an image to the registry that this Tilt pushed.
+func (dpf *dockerPruneFixture) withRegistryImage(tag string, dgst digest.Digest, age time.Duration) reference.NamedTagged {
+	ref := dpf.withTeammateRegistryImage(tag, dgst, age)
+	dpf.dp.pushedImages[ref.String()] = true
+	return ref
+}
+
+// Adds an image to the registry that someone else pushed.
+func (dpf *dockerPruneFixture) withTeammateRegistryImage(tag string, dgst digest.Digest, age time.Duration) reference.NamedTagged {
+	ref, err := reference.WithTag(registryRefSet.LocalRef(), tag)
+	if err != nil {
+		dpf.t.Fatal(err)
+	}
+	dpf.rCli.AddImage(ref, RegistryImage{
+		Digest:  dgst,
+		Created: time.Now().Add(-age),
+	})
+	return ref
+}
+
+// Adds a manifest that pushes to the registry. If lastBuilt is non-nil,
+// it's the image that's currently deployed.
+func (dpf *dockerPruneFixture) withRegistryManifest(lastBuilt reference.NamedTagged) {
+	iTarget := model.ImageTarget{
+		Refs:         registryRefSet,
+		BuildDetails: model.DockerBuild{},
+	}
+	m := model.Manifest{Name: "some-registry-manifest"}.WithImageTarget(iTarget)
+	mt := store.NewManifestTarget(m)
+	if lastBuilt != nil {
+		mt.State.MutableBuildStatus(iTarget.ID()).LastResult =
+			store.NewImageBuildResultSingleRef(iTarget.ID(), lastBuilt)
+	}
+	dpf.withManifestTarget(mt, true)
+}
+
+func (dpf *dockerPruneFixture) withRegistryPruneSettings(maxAge time.Duration, keepRecent int) {
+	store := dpf.st.LockMutableStateForTesting()
+	store.DockerPruneSettings = model.DockerPruneSettings{
+		Enabled:    true,
+		MaxAge:     maxAge,
+		KeepRecent: keepRecent,
+		Registry:   true,
+	}
+	dpf.st.UnlockMutableState()
+}
@@ -132,12 +132,15 @@ func (c *ServerController) determineServers(ctx context.Context, st store.RStore
 				},
 			},
 			Spec: CmdServerSpec{
-				Args:           lt.ServeCmd.Argv,
-				Dir:            lt.ServeCmd.Dir,
-				Env:            lt.ServeCmd.Env,
-				TriggerTime:    mt.State.LastSuccessfulDeployTime,
-				ReadinessProbe: lt.ReadinessProbe,
-				DisableSource:  lt.ServeCmdDisableSource,
+				Args:                   lt.ServeCmd.Argv,
+				Dir:                    lt.ServeCmd.Dir,
+				Env:                    lt.ServeCmd.Env,
+				TriggerTime:            mt.State.LastSuccessfulDeployTime,
+				ReadinessProbe:         lt.ReadinessProbe,
+				RestartPolicy:          lt.ServeRestartPolicy,
+				StopSignal:             lt.ServeStopSignal,
+				TerminationGracePeriod: lt.ServeTerminationGracePeriod,
+				DisableSource:          lt.ServeCmdDisableSource,
 			},
 		}
 
@@ -261,10 +264,13 @@ func (c *ServerController) reconcile(ctx context.Context, server CmdServer, owne
 	}
 
 	cmdSpec := CmdSpec{
-		Args:           server.Spec.Args,
-		Dir:            server.Spec.Dir,
-		Env:            server.Spec.Env,
-		ReadinessProbe: server.Spec.ReadinessProbe,
+		Args:                   server.Spec.Args,
+		Dir:                    server.Spec.Dir,
+		Env:                    server.Spec.Env,
+		ReadinessProbe:         server.Spec.ReadinessProbe,
+		RestartPolicy:          server.Spec.RestartPolicy,
+		StopSignal:             server.Spec.StopSignal,
+		TerminationGracePeriod: metav1.Duration{Duration: server.Spec.TerminationGracePeriod},
 	}
 
 	triggerTime := c.createdTriggerTime[name]
@@ -332,10 +338,13 @@ type CmdServer struct {
 }
 
 type CmdServerSpec struct {
-	Args           []string
-	Dir            string
-	Env            []string
-	ReadinessProbe *v1alpha1.Probe
+	Args                   []string
+	Dir                    string
+	Env                    []string
+	ReadinessProbe         *v1alpha1.Probe
+	RestartPolicy          v1alpha1.CmdRestartPolicy
+	StopSignal             string
+	TerminationGracePeriod time.Duration
 
 	// Kubernetes tends to represent this as a "generation" field
 	// to force an update.
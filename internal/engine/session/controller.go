@@ -6,6 +6,7 @@ import (
 	"os"
 	"sort"
 	"strings"
+	"sync"
 	"time"
 
 	"github.com/tilt-dev/tilt/pkg/apis"
@@ -37,6 +38,9 @@ type Controller struct {
 	startTime  time.Time
 	client     ctrlclient.Client
 	engineMode store.EngineMode
+	ciSpec     *session.SessionCISpec
+
+	mu sync.Mutex
 
 	// The last status object sent to the server.
 	lastStatus *session.SessionStatus
@@ -45,16 +49,26 @@ type Controller struct {
 	// Note that the server may annotate and transform this
 	// on top of what we sent.
 	session *session.Session
+
+	// The number of target failures tolerated so far, and the last failure of
+	// each target that we retried, so that each failure is only counted once.
+	failures int32
+	retried  map[string]string
+
+	timeoutTimer *time.Timer
 }
 
 var _ store.Subscriber = &Controller{}
+var _ store.TearDowner = &Controller{}
 
-func NewController(cli ctrlclient.Client, engineMode store.EngineMode) *Controller {
+func NewController(cli ctrlclient.Client, engineMode store.EngineMode, ciSpec *session.SessionCISpec) *Controller {
 	return &Controller{
 		pid:        int64(os.Getpid()),
 		startTime:  time.Now(),
 		client:     cli,
 		engineMode: engineMode,
+		ciSpec:     ciSpec,
+		retried:    make(map[string]string),
 	}
 }
 
@@ -63,25 +77,55 @@ func (c *Controller) OnChange(ctx context.Context, st store.RStore, summary stor
 		return nil
 	}
 
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.update(ctx, st)
+	return nil
+}
+
+func (c *Controller) TearDown(ctx context.Context) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if c.timeoutTimer != nil {
+		c.timeoutTimer.Stop()
+	}
+}
+
+// The most recent Session, or nil if the Session hasn't been created yet.
+func (c *Controller) Session() *session.Session {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if c.session == nil {
+		return nil
+	}
+	return c.session.DeepCopy()
+}
+
+// Must hold the mutex.
+func (c *Controller) update(ctx context.Context, st store.RStore) {
 	if c.session == nil {
 		if initialized, err := c.initialize(ctx, st); err != nil {
 			st.Dispatch(store.NewErrorAction(fmt.Errorf("failed to initialize Session controller: %v", err)))
-			return nil
+			return
 		} else if !initialized {
 			// engine is still starting up, no-op until ready for initialization
-			return nil
+			return
 		}
 	}
 
-	newStatus := c.makeLatestStatus(st)
+	newStatus, retries := c.makeLatestStatus(st)
+	for _, mn := range retries {
+		logger.Get(ctx).Infof("Retrying %s after failure (%d of %d tolerated failures)",
+			mn, c.failures, c.session.Spec.CI.MaxFailures)
+		st.Dispatch(store.AppendToTriggerQueueAction{Name: mn, Reason: model.BuildReasonFlagTriggerUnknown})
+	}
+
 	if err := c.handleLatestStatus(ctx, st, newStatus); err != nil {
 		if strings.Contains(err.Error(), context.Canceled.Error()) {
-			return nil
+			return
 		}
 		logger.Get(ctx).Debugf("failed to update Session status: %v", err)
 	}
-
-	return nil
 }
 
 func (c *Controller) initialize(ctx context.Context, st store.RStore) (bool, error) {
@@ -98,6 +142,17 @@ func (c *Controller) initialize(ctx context.Context, st store.RStore) (bool, err
 
 	c.session = s
 
+	if s.Spec.CI != nil && s.Spec.CI.Timeout.Duration > 0 {
+		// Re-evaluate the exit condition when the timeout elapses,
+		// even if nothing else has changed.
+		remaining := time.Until(c.startTime.Add(s.Spec.CI.Timeout.Duration))
+		c.timeoutTimer = time.AfterFunc(remaining, func() {
+			c.mu.Lock()
+			defer c.mu.Unlock()
+			c.update(ctx, st)
+		})
+	}
+
 	return true, nil
 }
 
@@ -131,12 +186,15 @@ func (c *Controller) makeSession(st store.RStore) *session.Session {
 		s.Spec.ExitCondition = session.ExitConditionManual
 	case store.EngineModeCI:
 		s.Spec.ExitCondition = session.ExitConditionCI
+		if c.ciSpec != nil {
+			s.Spec.CI = c.ciSpec.DeepCopy()
+		}
 	}
 
 	return s
 }
 
-func (c *Controller) makeLatestStatus(st store.RStore) *session.SessionStatus {
+func (c *Controller) makeLatestStatus(st store.RStore) (*session.SessionStatus, []model.ManifestName) {
 	state := st.RLockState()
 	defer st.RUnlockState()
 
@@ -157,16 +215,58 @@ func (c *Controller) makeLatestStatus(st store.RStore) *session.SessionStatus {
 	// N.B. we don't actually care about what's "next" to build, but the info comes alongside that
 	_, holds := buildcontrol.NextTargetToBuild(state)
 
+	required := make(map[string]bool)
 	for _, mt := range state.ManifestTargets {
 		status.Targets = append(status.Targets, targetsForResource(mt, holds)...)
+		if isRequiredResource(c.session.Spec.CI, mt.Manifest) {
+			required[mt.Manifest.Name.String()] = true
+		}
 	}
 	// ensure consistent ordering to avoid unnecessary updates
 	sort.SliceStable(status.Targets, func(i, j int) bool {
 		return status.Targets[i].Name < status.Targets[j].Name
 	})
 
-	processExitCondition(c.session.Spec.ExitCondition, status)
-	return status
+	retries := c.processExitCondition(status, required)
+	return status, retries
+}
+
+// Whether the exit condition should wait for this resource.
+func isRequiredResource(ci *session.SessionCISpec, m model.Manifest) bool {
+	if ci == nil {
+		return true
+	}
+
+	if len(ci.Resources) != 0 {
+		found := false
+		for _, r := range ci.Resources {
+			if r == m.Name.String() {
+				found = true
+				break
+			}
+		}
+		if !found {
+			return false
+		}
+	}
+
+	if len(ci.Labels) != 0 {
+		found := false
+		for _, l := range ci.Labels {
+			if _, ok := m.Labels[l]; ok {
+				found = true
+				break
+			}
+		}
+		if !found {
+			return false
+		}
+	}
+
+	if ci.TestsOnly && !(m.IsLocal() && m.LocalTarget().IsTest) {
+		return false
+	}
+	return true
 }
 
 func (c *Controller) handleLatestStatus(ctx context.Context, st store.RStore, newStatus *session.SessionStatus) error {
@@ -191,24 +291,69 @@ func (c *Controller) handleLatestStatus(ctx context.Context, st store.RStore, ne
 	return nil
 }
 
-func processExitCondition(exitCondition session.ExitCondition, status *session.SessionStatus) {
+// Evaluates the exit condition against the latest targets, and marks the status as Done if the session should exit.
+//
+// Returns any resources that failed and should be retried.
+func (c *Controller) processExitCondition(status *session.SessionStatus, required map[string]bool) []model.ManifestName {
+	exitCondition := c.session.Spec.ExitCondition
 	if exitCondition == session.ExitConditionManual {
-		return
+		return nil
 	} else if exitCondition != session.ExitConditionCI {
 		status.Done = true
 		status.Error = fmt.Sprintf("unsupported exit condition: %s", exitCondition)
+		return nil
+	}
+
+	ci := c.session.Spec.CI
+	if ci == nil {
+		ci = &session.SessionCISpec{}
+	}
+
+	if ci.Timeout.Duration > 0 && time.Since(c.startTime) >= ci.Timeout.Duration {
+		status.Done = true
+		status.Error = fmt.Sprintf("Timeout after %s", ci.Timeout.Duration)
+		return nil
 	}
 
+	var retries []model.ManifestName
 	allResourcesOK := true
+	tiltfileOK := false
+	requiredCount := 0
 	for _, res := range status.Targets {
+		isTiltfile := isTiltfileTarget(res)
+		if isTiltfile {
+			tiltfileOK = res.State.Terminated != nil && res.State.Terminated.Error == ""
+		} else if !isTargetRequired(res, required) {
+			continue
+		} else {
+			requiredCount++
+		}
+
 		if res.State.Waiting == nil && res.State.Active == nil && res.State.Terminated == nil {
 			// if all states are nil, the target has not been requested to run, e.g. auto_init=False
 			continue
 		}
 		if res.State.Terminated != nil && res.State.Terminated.Error != "" {
+			failure := targetFailureKey(res)
+			if !isTiltfile && c.retried[res.Name] == failure {
+				// We've already retried this failure, and are waiting for the retry to start.
+				allResourcesOK = false
+				continue
+			}
+
+			if !isTiltfile && c.failures < ci.MaxFailures {
+				c.failures++
+				c.retried[res.Name] = failure
+				for _, r := range res.Resources {
+					retries = append(retries, model.ManifestName(r))
+				}
+				allResourcesOK = false
+				continue
+			}
+
 			status.Done = true
 			status.Error = res.State.Terminated.Error
-			return
+			return nil
 		}
 		if res.State.Waiting != nil {
 			allResourcesOK = false
@@ -220,9 +365,33 @@ func processExitCondition(exitCondition session.ExitCondition, status *session.S
 
 	// Tiltfile is _always_ a target, so ensure that there's at least one other real target, or it's possible to
 	// exit before the targets have actually been initialized
-	if allResourcesOK && len(status.Targets) > 1 {
+	if allResourcesOK && requiredCount > 0 {
+		status.Done = true
+	} else if tiltfileOK && requiredCount == 0 && len(status.Targets) > 1 {
+		// The Tiltfile loaded resources, but none of them match the filters.
 		status.Done = true
+		status.Error = "No resources matched the CI session filters"
 	}
+	return retries
+}
+
+func isTiltfileTarget(t session.Target) bool {
+	return len(t.Resources) == 1 && t.Resources[0] == model.MainTiltfileManifestName.String()
+}
+
+func isTargetRequired(t session.Target, required map[string]bool) bool {
+	for _, r := range t.Resources {
+		if required[r] {
+			return true
+		}
+	}
+	return false
+}
+
+// Identifies a particular failure of a target, so that we only count it once.
+func targetFailureKey(t session.Target) string {
+	term := t.State.Terminated
+	return fmt.Sprintf("%s|%s|%s", term.StartTime.String(), term.FinishTime.String(), term.Error)
 }
 
 // errToString returns a stringified version of an error or an empty string if the error is nil.
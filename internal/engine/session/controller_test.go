@@ -5,6 +5,7 @@ import (
 	"errors"
 	"fmt"
 	"os"
+	"reflect"
 	"sync"
 	"testing"
 	"time"
@@ -242,6 +243,171 @@ func TestExitControlCI_Success(t *testing.T) {
 	f.store.requireExitSignalWithNoError()
 }
 
+func TestExitControlCI_Timeout(t *testing.T) {
+	f := newFixture(t, store.EngineModeCI)
+	defer f.TearDown()
+
+	f.c.ciSpec = &v1alpha1.SessionCISpec{Timeout: metav1.Duration{Duration: time.Minute}}
+	f.c.startTime = time.Now().Add(-time.Hour)
+
+	f.store.WithState(func(state *store.EngineState) {
+		m := manifestbuilder.New(f, "fe").WithK8sYAML(testyaml.SanchoYAML).Build()
+		state.UpsertManifestTarget(store.NewManifestTarget(m))
+	})
+
+	_ = f.c.OnChange(f.ctx, f.store, store.LegacyChangeSummary())
+	f.store.requireExitSignalWithError("Timeout after 1m0s")
+}
+
+func TestExitControlCI_WaitForResourceSubset(t *testing.T) {
+	f := newFixture(t, store.EngineModeCI)
+	defer f.TearDown()
+
+	f.c.ciSpec = &v1alpha1.SessionCISpec{Resources: []string{"fe"}}
+
+	f.store.WithState(func(state *store.EngineState) {
+		m := manifestbuilder.New(f, "fe").WithK8sYAML(testyaml.SanchoYAML).Build()
+		state.UpsertManifestTarget(store.NewManifestTarget(m))
+
+		m2 := manifestbuilder.New(f, "fe2").WithK8sYAML(testyaml.SanchoYAML).Build()
+		state.UpsertManifestTarget(store.NewManifestTarget(m2))
+
+		state.ManifestTargets["fe"].State.AddCompletedBuild(model.BuildRecord{
+			StartTime:  time.Now(),
+			FinishTime: time.Now(),
+		})
+		state.ManifestTargets["fe"].State.RuntimeState = store.NewK8sRuntimeStateWithPods(m, pod("pod-a", true))
+	})
+
+	// fe2 never builds, but we're not waiting for it.
+	_ = f.c.OnChange(f.ctx, f.store, store.LegacyChangeSummary())
+	f.store.requireExitSignalWithNoError()
+}
+
+func TestExitControlCI_WaitForLabel(t *testing.T) {
+	f := newFixture(t, store.EngineModeCI)
+	defer f.TearDown()
+
+	f.c.ciSpec = &v1alpha1.SessionCISpec{Labels: []string{"backend"}}
+
+	f.store.WithState(func(state *store.EngineState) {
+		m := manifestbuilder.New(f, "fe").WithK8sYAML(testyaml.SanchoYAML).Build()
+		state.UpsertManifestTarget(store.NewManifestTarget(m))
+
+		m2 := manifestbuilder.New(f, "be").WithK8sYAML(testyaml.SanchoYAML).Build().
+			WithLabels(map[string]string{"backend": "backend"})
+		state.UpsertManifestTarget(store.NewManifestTarget(m2))
+
+		state.ManifestTargets["fe"].State.AddCompletedBuild(model.BuildRecord{
+			StartTime:  time.Now(),
+			FinishTime: time.Now(),
+		})
+		state.ManifestTargets["fe"].State.RuntimeState = store.NewK8sRuntimeStateWithPods(m, pod("pod-a", true))
+	})
+
+	// fe is healthy, but be has the label we're waiting for.
+	_ = f.c.OnChange(f.ctx, f.store, store.LegacyChangeSummary())
+	f.store.requireNoExitSignal()
+
+	f.store.WithState(func(state *store.EngineState) {
+		mt := state.ManifestTargets["be"]
+		mt.State.AddCompletedBuild(model.BuildRecord{
+			StartTime:  time.Now(),
+			FinishTime: time.Now(),
+		})
+		mt.State.RuntimeState = store.NewK8sRuntimeStateWithPods(mt.Manifest, pod("pod-b", true))
+	})
+
+	_ = f.c.OnChange(f.ctx, f.store, store.LegacyChangeSummary())
+	f.store.requireExitSignalWithNoError()
+}
+
+func TestExitControlCI_TestsOnly(t *testing.T) {
+	f := newFixture(t, store.EngineModeCI)
+	defer f.TearDown()
+
+	f.c.ciSpec = &v1alpha1.SessionCISpec{TestsOnly: true}
+
+	f.store.WithState(func(state *store.EngineState) {
+		m := manifestbuilder.New(f, "fe").WithK8sYAML(testyaml.SanchoYAML).Build()
+		state.UpsertManifestTarget(store.NewManifestTarget(m))
+
+		m2 := manifestbuilder.New(f, "unit-tests").WithLocalResource("go test ./...", nil).Build()
+		m2 = m2.WithDeployTarget(m2.LocalTarget().WithIsTest(true))
+		state.UpsertManifestTarget(store.NewManifestTarget(m2))
+	})
+
+	_ = f.c.OnChange(f.ctx, f.store, store.LegacyChangeSummary())
+	f.store.requireNoExitSignal()
+
+	f.store.WithState(func(state *store.EngineState) {
+		state.ManifestTargets["unit-tests"].State.AddCompletedBuild(model.BuildRecord{
+			StartTime:  time.Now(),
+			FinishTime: time.Now(),
+		})
+	})
+
+	// fe never deploys, but we only care about the tests.
+	_ = f.c.OnChange(f.ctx, f.store, store.LegacyChangeSummary())
+	f.store.requireExitSignalWithNoError()
+}
+
+func TestExitControlCI_NoMatchingResources(t *testing.T) {
+	f := newFixture(t, store.EngineModeCI)
+	defer f.TearDown()
+
+	f.c.ciSpec = &v1alpha1.SessionCISpec{TestsOnly: true}
+
+	f.store.WithState(func(state *store.EngineState) {
+		m := manifestbuilder.New(f, "fe").WithK8sYAML(testyaml.SanchoYAML).Build()
+		state.UpsertManifestTarget(store.NewManifestTarget(m))
+	})
+
+	_ = f.c.OnChange(f.ctx, f.store, store.LegacyChangeSummary())
+	f.store.requireExitSignalWithError("No resources matched the CI session filters")
+}
+
+func TestExitControlCI_MaxFailures(t *testing.T) {
+	f := newFixture(t, store.EngineModeCI)
+	defer f.TearDown()
+
+	f.c.ciSpec = &v1alpha1.SessionCISpec{MaxFailures: 1}
+
+	f.store.WithState(func(state *store.EngineState) {
+		m := manifestbuilder.New(f, "fe").WithLocalResource("make", nil).Build()
+		state.UpsertManifestTarget(store.NewManifestTarget(m))
+		state.ManifestTargets["fe"].State.AddCompletedBuild(model.BuildRecord{
+			StartTime:  time.Now(),
+			FinishTime: time.Now(),
+			Error:      fmt.Errorf("flaky failure"),
+		})
+	})
+
+	// The first failure is tolerated, and retried.
+	_ = f.c.OnChange(f.ctx, f.store, store.LegacyChangeSummary())
+	f.store.requireNoExitSignal()
+	a := f.store.WaitForAction(t, reflect.TypeOf(store.AppendToTriggerQueueAction{}))
+	assert.Equal(t, store.AppendToTriggerQueueAction{
+		Name:   "fe",
+		Reason: model.BuildReasonFlagTriggerUnknown,
+	}, a)
+
+	// Re-evaluating the same failure doesn't count it twice.
+	_ = f.c.OnChange(f.ctx, f.store, store.LegacyChangeSummary())
+	f.store.requireNoExitSignal()
+
+	f.store.WithState(func(state *store.EngineState) {
+		state.ManifestTargets["fe"].State.AddCompletedBuild(model.BuildRecord{
+			StartTime:  time.Now().Add(time.Second),
+			FinishTime: time.Now().Add(time.Second),
+			Error:      fmt.Errorf("not so flaky failure"),
+		})
+	})
+
+	_ = f.c.OnChange(f.ctx, f.store, store.LegacyChangeSummary())
+	f.store.requireExitSignalWithError("not so flaky failure")
+}
+
 func TestExitControlCI_PodReadinessMode_Wait(t *testing.T) {
 	f := newFixture(t, store.EngineModeCI)
 	defer f.TearDown()
@@ -530,7 +696,7 @@ func newFixture(t *testing.T, engineMode store.EngineMode) *fixture {
 	})
 
 	cli := fake.NewFakeTiltClient()
-	c := NewController(cli, engineMode)
+	c := NewController(cli, engineMode, nil)
 	ctx := context.Background()
 	l := logger.NewLogger(logger.VerboseLvl, os.Stdout)
 	ctx = logger.WithLogger(ctx, l)
@@ -0,0 +1,114 @@
+package session
+
+import (
+	"encoding/json"
+	"encoding/xml"
+	"fmt"
+	"io"
+	"strings"
+
+	session "github.com/tilt-dev/tilt/pkg/apis/core/v1alpha1"
+)
+
+// WriteJSONReport writes a machine-readable summary of the session status,
+// including the state of each target.
+func WriteJSONReport(w io.Writer, s *session.Session) error {
+	encoder := json.NewEncoder(w)
+	encoder.SetIndent("", "  ")
+	return encoder.Encode(s.Status)
+}
+
+type junitTestSuites struct {
+	XMLName xml.Name         `xml:"testsuites"`
+	Suites  []junitTestSuite `xml:"testsuite"`
+}
+
+type junitTestSuite struct {
+	Name      string          `xml:"name,attr"`
+	Tests     int             `xml:"tests,attr"`
+	Failures  int             `xml:"failures,attr"`
+	Skipped   int             `xml:"skipped,attr"`
+	Time      string          `xml:"time,attr"`
+	Timestamp string          `xml:"timestamp,attr,omitempty"`
+	Cases     []junitTestCase `xml:"testcase"`
+}
+
+type junitTestCase struct {
+	Name      string        `xml:"name,attr"`
+	ClassName string        `xml:"classname,attr"`
+	Time      string        `xml:"time,attr,omitempty"`
+	Failure   *junitMessage `xml:"failure,omitempty"`
+	Skipped   *junitMessage `xml:"skipped,omitempty"`
+}
+
+type junitMessage struct {
+	Message string `xml:"message,attr"`
+	Text    string `xml:",chardata"`
+}
+
+// WriteJUnitReport writes the state of each target as a JUnit test case,
+// so that CI systems can display them.
+//
+// Targets that failed are reported as failures. Targets that never finished
+// (or never ran) are reported as skipped.
+func WriteJUnitReport(w io.Writer, s *session.Session) error {
+	suite := junitTestSuite{
+		Name: "tilt",
+	}
+	if !s.Status.StartTime.IsZero() {
+		suite.Timestamp = s.Status.StartTime.UTC().Format("2006-01-02T15:04:05")
+	}
+
+	total := 0.0
+	for _, t := range s.Status.Targets {
+		tc := junitTestCase{
+			Name:      t.Name,
+			ClassName: strings.Join(t.Resources, ","),
+		}
+
+		state := t.State
+		switch {
+		case state.Terminated != nil:
+			if !state.Terminated.StartTime.IsZero() && !state.Terminated.FinishTime.IsZero() {
+				seconds := state.Terminated.FinishTime.Sub(state.Terminated.StartTime.Time).Seconds()
+				tc.Time = fmt.Sprintf("%.3f", seconds)
+				total += seconds
+			}
+			if state.Terminated.Error != "" {
+				tc.Failure = &junitMessage{Message: state.Terminated.Error, Text: state.Terminated.Error}
+			}
+		case state.Active != nil:
+			if !state.Active.Ready || t.Type == session.TargetTypeJob {
+				tc.Skipped = &junitMessage{Message: "still running"}
+			}
+		case state.Waiting != nil:
+			tc.Skipped = &junitMessage{Message: fmt.Sprintf("waiting: %s", state.Waiting.WaitReason)}
+		default:
+			tc.Skipped = &junitMessage{Message: "disabled"}
+		}
+
+		suite.Tests++
+		if tc.Failure != nil {
+			suite.Failures++
+		}
+		if tc.Skipped != nil {
+			suite.Skipped++
+		}
+		suite.Cases = append(suite.Cases, tc)
+	}
+	suite.Time = fmt.Sprintf("%.3f", total)
+
+	_, err := io.WriteString(w, xml.Header)
+	if err != nil {
+		return err
+	}
+
+	encoder := xml.NewEncoder(w)
+	encoder.Indent("", "  ")
+	err = encoder.Encode(junitTestSuites{Suites: []junitTestSuite{suite}})
+	if err != nil {
+		return err
+	}
+	_, err = io.WriteString(w, "\n")
+	return err
+}
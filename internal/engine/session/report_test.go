@@ -0,0 +1,97 @@
+package session
+
+import (
+	"bytes"
+	"encoding/json"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	"github.com/tilt-dev/tilt/pkg/apis"
+	"github.com/tilt-dev/tilt/pkg/apis/core/v1alpha1"
+)
+
+func TestWriteJUnitReport(t *testing.T) {
+	start := time.Date(2021, time.June, 1, 12, 0, 0, 0, time.UTC)
+	s := reportTestSession(start)
+
+	var buf bytes.Buffer
+	err := WriteJUnitReport(&buf, s)
+	require.NoError(t, err)
+
+	assert.Equal(t, `<?xml version="1.0" encoding="UTF-8"?>
+<testsuites>
+  <testsuite name="tilt" tests="3" failures="1" skipped="1" time="3.500" timestamp="2021-06-01T12:00:00">
+    <testcase name="be:update" classname="be" time="1.500">
+      <failure message="does not compile">does not compile</failure>
+    </testcase>
+    <testcase name="fe:runtime" classname="fe">
+      <skipped message="waiting: waiting-for-pod"></skipped>
+    </testcase>
+    <testcase name="fe:update" classname="fe" time="2.000"></testcase>
+  </testsuite>
+</testsuites>
+`, buf.String())
+}
+
+func TestWriteJSONReport(t *testing.T) {
+	s := reportTestSession(time.Date(2021, time.June, 1, 12, 0, 0, 0, time.UTC))
+
+	var buf bytes.Buffer
+	err := WriteJSONReport(&buf, s)
+	require.NoError(t, err)
+
+	var status v1alpha1.SessionStatus
+	err = json.Unmarshal(buf.Bytes(), &status)
+	require.NoError(t, err)
+	assert.Equal(t, "does not compile", status.Error)
+	require.Len(t, status.Targets, 3)
+	assert.Equal(t, "be:update", status.Targets[0].Name)
+}
+
+func reportTestSession(start time.Time) *v1alpha1.Session {
+	return &v1alpha1.Session{
+		ObjectMeta: metav1.ObjectMeta{Name: "Tiltfile"},
+		Status: v1alpha1.SessionStatus{
+			StartTime: apis.NewMicroTime(start),
+			Done:      true,
+			Error:     "does not compile",
+			Targets: []v1alpha1.Target{
+				{
+					Name:      "be:update",
+					Resources: []string{"be"},
+					Type:      v1alpha1.TargetTypeJob,
+					State: v1alpha1.TargetState{
+						Terminated: &v1alpha1.TargetStateTerminated{
+							StartTime:  apis.NewMicroTime(start),
+							FinishTime: apis.NewMicroTime(start.Add(1500 * time.Millisecond)),
+							Error:      "does not compile",
+						},
+					},
+				},
+				{
+					Name:      "fe:runtime",
+					Resources: []string{"fe"},
+					Type:      v1alpha1.TargetTypeServer,
+					State: v1alpha1.TargetState{
+						Waiting: &v1alpha1.TargetStateWaiting{WaitReason: "waiting-for-pod"},
+					},
+				},
+				{
+					Name:      "fe:update",
+					Resources: []string{"fe"},
+					Type:      v1alpha1.TargetTypeJob,
+					State: v1alpha1.TargetState{
+						Terminated: &v1alpha1.TargetStateTerminated{
+							StartTime:  apis.NewMicroTime(start),
+							FinishTime: apis.NewMicroTime(start.Add(2 * time.Second)),
+						},
+					},
+				},
+			},
+		},
+	}
+}
@@ -3,6 +3,7 @@ package engine
 import (
 	"github.com/tilt-dev/tilt/internal/cloud"
 	"github.com/tilt-dev/tilt/internal/controllers"
+	"github.com/tilt-dev/tilt/internal/controllers/core/liveupdate"
 	"github.com/tilt-dev/tilt/internal/engine/analytics"
 	"github.com/tilt-dev/tilt/internal/engine/configs"
 	"github.com/tilt-dev/tilt/internal/engine/dcwatch"
@@ -66,6 +67,7 @@ func ProvideSubscribers(
 	sc *session.Controller,
 	uss *uisession.Subscriber,
 	urs *uiresource.Subscriber,
+	lur *liveupdate.Reconciler,
 ) []store.Subscriber {
 	apiSubscribers := ProvideSubscribersAPIOnly(hudsc, tscm, cb, ts)
 
@@ -89,6 +91,7 @@ func ProvideSubscribers(
 		sc,
 		uss,
 		urs,
+		lur,
 	}
 	return append(apiSubscribers, legacySubscribers...)
 }
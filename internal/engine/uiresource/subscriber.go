@@ -10,6 +10,7 @@ import (
 	"github.com/tilt-dev/tilt/internal/controllers/apicmp"
 	"github.com/tilt-dev/tilt/internal/hud/webview"
 	"github.com/tilt-dev/tilt/internal/store"
+	"github.com/tilt-dev/tilt/pkg/apis"
 	"github.com/tilt-dev/tilt/pkg/apis/core/v1alpha1"
 )
 
@@ -70,6 +71,8 @@ func (s *Subscriber) OnChange(ctx context.Context, st store.RStore, summary stor
 			continue
 		}
 
+		r.Status.Conditions = updateConditionTransitionTimes(stored.Status.Conditions, r.Status.Conditions)
+
 		if !apicmp.DeepEqual(r.Status, stored.Status) {
 			// If the current version is different than what's stored, update it.
 			update := stored.DeepCopy()
@@ -85,4 +88,22 @@ func (s *Subscriber) OnChange(ctx context.Context, st store.RStore, summary stor
 	return nil
 }
 
+// Carries over the LastTransitionTime of conditions whose status hasn't changed,
+// and stamps the ones that have changed with the current time.
+func updateConditionTransitionTimes(old, current []v1alpha1.UIResourceCondition) []v1alpha1.UIResourceCondition {
+	now := apis.NowMicro()
+	result := make([]v1alpha1.UIResourceCondition, 0, len(current))
+	for _, c := range current {
+		c.LastTransitionTime = now
+		for _, o := range old {
+			if o.Type == c.Type && o.Status == c.Status {
+				c.LastTransitionTime = o.LastTransitionTime
+				break
+			}
+		}
+		result = append(result, c)
+	}
+	return result
+}
+
 var _ store.Subscriber = &Subscriber{}
@@ -51,6 +51,35 @@ func TestUpdateTiltfile(t *testing.T) {
 	assert.Equal(t, "3", r.ObjectMeta.ResourceVersion)
 }
 
+func TestConditionTransitionTimes(t *testing.T) {
+	f := newFixture(t)
+	defer f.TearDown()
+
+	r := &v1alpha1.UIResource{ObjectMeta: metav1.ObjectMeta{Name: "(Tiltfile)"}}
+	err := f.tc.Create(f.ctx, r)
+	require.NoError(t, err)
+
+	_ = f.sub.OnChange(f.ctx, f.store, store.LegacyChangeSummary())
+	r = f.resource("(Tiltfile)")
+	require.Len(t, r.Status.Conditions, 2)
+	ready := r.Status.Conditions[1]
+	assert.Equal(t, v1alpha1.UIResourceReady, ready.Type)
+	assert.Equal(t, metav1.ConditionFalse, ready.Status)
+	assert.Equal(t, "UpdatePending", ready.Reason)
+	assert.False(t, ready.LastTransitionTime.IsZero())
+
+	// Changes that don't affect the condition status don't move the transition time.
+	f.store.WithState(func(es *store.EngineState) {
+		es.TiltfileStates[model.MainTiltfileManifestName].CurrentBuild.StartTime = time.Now()
+	})
+	_ = f.sub.OnChange(f.ctx, f.store, store.LegacyChangeSummary())
+	r = f.resource("(Tiltfile)")
+	require.Len(t, r.Status.Conditions, 2)
+	assert.Equal(t, metav1.ConditionFalse, r.Status.Conditions[1].Status)
+	assert.Equal(t, "UpdateInProgress", r.Status.Conditions[1].Reason)
+	assert.True(t, ready.LastTransitionTime.Equal(&r.Status.Conditions[1].LastTransitionTime))
+}
+
 type fixture struct {
 	*tempdir.TempDirFixture
 	ctx   context.Context
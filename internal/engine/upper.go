@@ -36,18 +36,20 @@ import (
 	"github.com/tilt-dev/tilt/internal/token"
 	"github.com/tilt-dev/tilt/pkg/logger"
 	"github.com/tilt-dev/tilt/pkg/model"
+	"github.com/tilt-dev/tilt/pkg/model/logstore"
 )
 
 // TODO(nick): maybe this should be called 'BuildEngine' or something?
 // Upper seems like a poor and undescriptive name.
 type Upper struct {
-	store *store.Store
+	store      *store.Store
+	logHistory *logstore.History
 }
 
 type ServiceWatcherMaker func(context.Context, *store.Store) error
 type PodWatcherMaker func(context.Context, *store.Store) error
 
-func NewUpper(ctx context.Context, st *store.Store, subs []store.Subscriber) (Upper, error) {
+func NewUpper(ctx context.Context, st *store.Store, subs []store.Subscriber, logHistory *logstore.History) (Upper, error) {
 	// There's not really a good reason to add all the subscribers
 	// in NewUpper(), but it's as good a place as any.
 	for _, sub := range subs {
@@ -58,7 +60,8 @@ func NewUpper(ctx context.Context, st *store.Store, subs []store.Subscriber) (Up
 	}
 
 	return Upper{
-		store: st,
+		store:      st,
+		logHistory: logHistory,
 	}, nil
 }
 
@@ -96,6 +99,7 @@ func (u Upper) Start(
 		Token:            token,
 		CloudAddress:     cloudAddress,
 		TerminalMode:     initTerminalMode,
+		LogHistory:       u.logHistory,
 	})
 }
 
@@ -148,7 +152,7 @@ func upperReducerFn(ctx context.Context, state *store.EngineState, action store.
 		ctrltiltfile.HandleConfigsReloaded(ctx, state, action)
 	case dcwatch.EventAction:
 		handleDockerComposeEvent(ctx, state, action)
-	case server.AppendToTriggerQueueAction:
+	case store.AppendToTriggerQueueAction:
 		state.AppendToTriggerQueue(action.Name, action.Reason)
 	case hud.DumpEngineStateAction:
 		handleDumpEngineStateAction(ctx, state)
@@ -190,6 +194,8 @@ func upperReducerFn(ctx context.Context, state *store.EngineState, action store.
 		kubernetesapplys.HandleKubernetesApplyUpsertAction(state, action)
 	case kubernetesapplys.KubernetesApplyDeleteAction:
 		kubernetesapplys.HandleKubernetesApplyDeleteAction(state, action)
+	case kubernetesapplys.KubernetesApplyReapplyAction:
+		kubernetesapplys.HandleKubernetesApplyReapplyAction(state, action)
 	case kubernetesdiscoverys.KubernetesDiscoveryUpsertAction:
 		kubernetesdiscoverys.HandleKubernetesDiscoveryUpsertAction(state, action)
 	case kubernetesdiscoverys.KubernetesDiscoveryDeleteAction:
@@ -251,7 +257,10 @@ func handleDumpEngineStateAction(ctx context.Context, engineState *store.EngineS
 	}
 
 	logger.Get(ctx).Infof("dumped tilt engine state to %q", f.Name())
-	spew.Fdump(f, engineState)
+	_, err = f.Write(engineState.Secrets.Scrub([]byte(spew.Sdump(engineState))))
+	if err != nil {
+		logger.Get(ctx).Infof("error writing engine state: %v", err)
+	}
 
 	err = f.Close()
 	if err != nil {
@@ -270,6 +279,9 @@ func handleInitAction(ctx context.Context, engineState *store.EngineState, actio
 	engineState.CloudAddress = action.CloudAddress
 	engineState.Token = action.Token
 	engineState.TerminalMode = action.TerminalMode
+	if action.LogHistory != nil {
+		engineState.LogStore.SetHistory(action.LogHistory)
+	}
 }
 
 func handleHudExitAction(state *store.EngineState, action hud.ExitAction) {
@@ -33,6 +33,7 @@ import (
 	ctrlclient "sigs.k8s.io/controller-runtime/pkg/client"
 
 	tiltanalytics "github.com/tilt-dev/tilt/internal/analytics"
+	"github.com/tilt-dev/tilt/internal/build"
 	"github.com/tilt-dev/tilt/internal/cloud"
 	"github.com/tilt-dev/tilt/internal/container"
 	"github.com/tilt-dev/tilt/internal/containerupdate"
@@ -40,6 +41,7 @@ import (
 	apitiltfile "github.com/tilt-dev/tilt/internal/controllers/apis/tiltfile"
 	"github.com/tilt-dev/tilt/internal/controllers/core/cmd"
 	"github.com/tilt-dev/tilt/internal/controllers/core/configmap"
+	"github.com/tilt-dev/tilt/internal/controllers/core/dockerimage"
 	"github.com/tilt-dev/tilt/internal/controllers/core/extension"
 	"github.com/tilt-dev/tilt/internal/controllers/core/extensionrepo"
 	"github.com/tilt-dev/tilt/internal/controllers/core/filewatch"
@@ -56,6 +58,7 @@ import (
 	ctrluisession "github.com/tilt-dev/tilt/internal/controllers/core/uisession"
 	"github.com/tilt-dev/tilt/internal/docker"
 	"github.com/tilt-dev/tilt/internal/dockercompose"
+	"github.com/tilt-dev/tilt/internal/dockerfile"
 	engineanalytics "github.com/tilt-dev/tilt/internal/engine/analytics"
 	"github.com/tilt-dev/tilt/internal/engine/buildcontrol"
 	"github.com/tilt-dev/tilt/internal/engine/configs"
@@ -3731,7 +3734,7 @@ func TestHandleTiltfileTriggerQueue(t *testing.T) {
 		assert.Equal(t, model.BuildReasonNone, st.MainTiltfileState().TriggerReason,
 			"initial state should not have Tiltfile trigger reason")
 	})
-	action := server.AppendToTriggerQueueAction{Name: model.MainTiltfileManifestName, Reason: 123}
+	action := store.AppendToTriggerQueueAction{Name: model.MainTiltfileManifestName, Reason: 123}
 	f.store.Dispatch(action)
 
 	f.WaitUntil("Tiltfile trigger processed", func(st store.EngineState) bool {
@@ -3848,7 +3851,7 @@ func TestDisablingResourcePreventsBuild(t *testing.T) {
 		return false
 	})
 
-	action := server.AppendToTriggerQueueAction{Name: "foo", Reason: 123}
+	action := store.AppendToTriggerQueueAction{Name: "foo", Reason: 123}
 	f.store.Dispatch(action)
 
 	f.WaitUntil("is waiting+disabled", func(state store.EngineState) bool {
@@ -4012,7 +4015,7 @@ func newTestFixture(t *testing.T, options ...fixtureOptions) *testFixture {
 	versionExt := version.NewPlugin(model.TiltBuild{Version: "0.5.0"})
 	configExt := config.NewPlugin("up")
 	execer := localexec.NewFakeExecer(t)
-	realTFL := tiltfile.ProvideTiltfileLoader(ta, b.kClient, k8sContextExt, versionExt, configExt, fakeDcc, "localhost", execer, feature.MainDefaults, env)
+	realTFL := tiltfile.ProvideTiltfileLoader(ta, b.kClient, k8sContextExt, versionExt, configExt, fakeDcc, "localhost", execer, feature.MainDefaults, env, base)
 	tfl := tiltfile.NewFakeTiltfileLoader()
 	buildSource := ctrltiltfile.NewBuildSource()
 	cc := configs.NewConfigsController(cdc)
@@ -4038,7 +4041,7 @@ func newTestFixture(t *testing.T, options ...fixtureOptions) *testFixture {
 	fwc := filewatch.NewController(cdc, st, watcher.NewSub, timerMaker.Maker(), v1alpha1.NewScheme())
 	cmds := cmd.NewController(ctx, fe, fpm, cdc, st, clock, v1alpha1.NewScheme())
 	lsc := local.NewServerController(cdc)
-	sessionController := session.NewController(cdc, engineMode)
+	sessionController := session.NewController(cdc, engineMode, nil)
 	ts := hud.NewTerminalStream(hud.NewIncrementalPrinter(log), st)
 	tp := prompt.NewTerminalPrompt(ta, prompt.TTYOpen, openurl.BrowserOpen,
 		log, "localhost", model.WebURL{})
@@ -4068,6 +4071,8 @@ func newTestFixture(t *testing.T, options ...fixtureOptions) *testFixture {
 	extrr, err := extensionrepo.NewReconciler(cdc, base)
 	require.NoError(t, err)
 	cmr := configmap.NewReconciler(cdc, st)
+	dir := dockerimage.NewReconciler(cdc, st, sch, build.NewDockerImageBuilder(dockerClient, dockerfile.Labels{}), build.ProvideClock(),
+		b.kClient, env, k8s.KubeContext("kind-kind"), &fakeKINDLoader{})
 
 	cu := &containerupdate.FakeContainerUpdater{}
 	lur := liveupdate.NewFakeReconciler(st, cu, cdc)
@@ -4087,9 +4092,10 @@ func newTestFixture(t *testing.T, options ...fixtureOptions) *testFixture {
 		extrr,
 		lur,
 		cmr,
+		dir,
 	))
 
-	dp := dockerprune.NewDockerPruner(dockerClient)
+	dp := dockerprune.NewDockerPruner(dockerClient, dockerprune.NewFakeRegistryClient())
 	dp.DisabledForTesting(true)
 
 	ret := &testFixture{
@@ -4131,8 +4137,8 @@ func newTestFixture(t *testing.T, options ...fixtureOptions) *testFixture {
 	uss := uisession.NewSubscriber(cdc)
 	urs := uiresource.NewSubscriber(cdc)
 
-	subs := ProvideSubscribers(hudsc, tscm, cb, h, ts, tp, sw, bc, cc, tqs, dcw, dclm, ar, au, ewm, tcum, dp, tc, lsc, podm, sessionController, uss, urs)
-	ret.upper, err = NewUpper(ctx, st, subs)
+	subs := ProvideSubscribers(hudsc, tscm, cb, h, ts, tp, sw, bc, cc, tqs, dcw, dclm, ar, au, ewm, tcum, dp, tc, lsc, podm, sessionController, uss, urs, lur)
+	ret.upper, err = NewUpper(ctx, st, subs, nil)
 	require.NoError(t, err)
 
 	go func() {
@@ -1,4 +1,6 @@
+//go:build wireinject
 // +build wireinject
+
 // The build tag makes sure the stub is not built in the final build.
 
 package engine
@@ -15,6 +17,7 @@ import (
 	"github.com/tilt-dev/tilt/internal/analytics"
 	"github.com/tilt-dev/tilt/internal/build"
 	"github.com/tilt-dev/tilt/internal/container"
+	"github.com/tilt-dev/tilt/internal/containerupdate"
 	"github.com/tilt-dev/tilt/internal/controllers/core/cmd"
 	"github.com/tilt-dev/tilt/internal/controllers/core/kubernetesapply"
 	"github.com/tilt-dev/tilt/internal/controllers/core/liveupdate"
@@ -25,6 +28,7 @@ import (
 	"github.com/tilt-dev/tilt/internal/localexec"
 	"github.com/tilt-dev/tilt/internal/store"
 	"github.com/tilt-dev/tilt/internal/store/liveupdates"
+	"github.com/tilt-dev/tilt/internal/xdg"
 )
 
 var DeployerBaseWireSet = wire.NewSet(
@@ -72,6 +76,8 @@ func provideFakeBuildAndDeployer(
 		cmd.WireSet,
 		clockwork.NewRealClock,
 		provideFakeEnv,
+		provideFakeXDGBase,
+		wire.Value(containerupdate.DefaultHelperImage),
 	)
 
 	return nil, nil
@@ -81,6 +87,10 @@ func provideFakeEnv() *localexec.Env {
 	return localexec.EmptyEnv()
 }
 
+func provideFakeXDGBase(dir *dirs.TiltDevDir) xdg.Base {
+	return xdg.FakeBase{Dir: dir.Root()}
+}
+
 func provideFakeK8sNamespace() k8s.Namespace {
 	return "default"
 }
@@ -30,14 +30,16 @@ import (
 	"github.com/tilt-dev/tilt/internal/store"
 	"github.com/tilt-dev/tilt/internal/store/liveupdates"
 	"github.com/tilt-dev/tilt/internal/tracer"
+	"github.com/tilt-dev/tilt/internal/xdg"
 	"github.com/tilt-dev/tilt/pkg/apis/core/v1alpha1"
 )
 
 // Injectors from wire.go:
 
 func provideFakeBuildAndDeployer(ctx context.Context, docker2 docker.Client, kClient k8s.Client, dir *dirs.TiltDevDir, env k8s.Env, updateMode liveupdates.UpdateModeFlag, dcc dockercompose.DockerComposeClient, clock build.Clock, kp buildcontrol.KINDLoader, analytics2 *analytics.TiltAnalytics, ctrlClient client.Client, st store.RStore, execer localexec.Execer) (buildcontrol.BuildAndDeployer, error) {
-	dockerUpdater := containerupdate.NewDockerUpdater(docker2)
-	execUpdater := containerupdate.NewExecUpdater(kClient)
+	helperImage := _wireHelperImageValue
+	dockerUpdater := containerupdate.NewDockerUpdater(docker2, helperImage)
+	execUpdater := containerupdate.NewExecUpdater(kClient, helperImage)
 	kubeContext := provideFakeKubeContext(env)
 	runtime := k8s.ProvideContainerRuntime(ctx, kClient)
 	clusterEnv := provideFakeDockerClusterEnv(docker2, env, kubeContext, runtime)
@@ -52,10 +54,12 @@ func provideFakeBuildAndDeployer(ctx context.Context, docker2 docker.Client, kCl
 	dockerImageBuilder := build.NewDockerImageBuilder(docker2, labels)
 	dockerBuilder := build.DefaultDockerBuilder(dockerImageBuilder)
 	execCustomBuilder := build.NewExecCustomBuilder(docker2, clock)
+	base := provideFakeXDGBase(dir)
+	imageDigestCache := build.NewImageDigestCache(base)
+	imageBuilder := buildcontrol.NewImageBuilder(dockerBuilder, execCustomBuilder, imageDigestCache, clock)
 	namespace := provideFakeK8sNamespace()
 	kubernetesapplyReconciler := kubernetesapply.NewReconciler(ctrlClient, kClient, scheme, dockerBuilder, kubeContext, st, namespace, execer)
-	imageBuildAndDeployer := buildcontrol.NewImageBuildAndDeployer(dockerBuilder, execCustomBuilder, kClient, env, kubeContext, analytics2, clock, kp, ctrlClient, kubernetesapplyReconciler)
-	imageBuilder := buildcontrol.NewImageBuilder(dockerBuilder, execCustomBuilder)
+	imageBuildAndDeployer := buildcontrol.NewImageBuildAndDeployer(dockerBuilder, imageBuilder, kClient, env, kubeContext, analytics2, clock, kp, ctrlClient, kubernetesapplyReconciler)
 	dockerComposeBuildAndDeployer := buildcontrol.NewDockerComposeBuildAndDeployer(dcc, docker2, imageBuilder, clock)
 	localexecEnv := provideFakeEnv()
 	cmdExecer := cmd.ProvideExecer(localexecEnv)
@@ -71,6 +75,7 @@ func provideFakeBuildAndDeployer(ctx context.Context, docker2 docker.Client, kCl
 }
 
 var (
+	_wireHelperImageValue  = containerupdate.DefaultHelperImage
 	_wireLabelsValue       = dockerfile.Labels{}
 	_wireSpanExporterValue = (trace.SpanExporter)(nil)
 )
@@ -91,6 +96,10 @@ func provideFakeEnv() *localexec.Env {
 	return localexec.EmptyEnv()
 }
 
+func provideFakeXDGBase(dir *dirs.TiltDevDir) xdg.Base {
+	return xdg.FakeBase{Dir: dir.Root()}
+}
+
 func provideFakeK8sNamespace() k8s.Namespace {
 	return "default"
 }
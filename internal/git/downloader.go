@@ -0,0 +1,171 @@
+package git
+
+import (
+	"bytes"
+	"fmt"
+	"io"
+	"os"
+	"os/exec"
+	"path"
+	"path/filepath"
+	"strings"
+
+	giturls "github.com/whilp/git-urls"
+)
+
+// Downloader clones git remotes under a root directory.
+//
+// Unlike go-get, it doesn't need to discover the repo from an import path,
+// so it works with self-hosted git servers. All network access goes
+// through the git CLI, so git's own configuration (SSH keys, credential helpers,
+// insteadOf rewrites) applies.
+//
+// Not thread-safe.
+type Downloader struct {
+	// Where to copy the output of failed git commands, in addition to
+	// including it in the returned error.
+	Stderr io.Writer
+
+	rootDir string
+}
+
+func NewDownloader(rootDir string) *Downloader {
+	return &Downloader{
+		Stderr:  os.Stderr,
+		rootDir: rootDir,
+	}
+}
+
+// Determines where the remote will be cloned, e.g.,
+// git@example.com:org/repo.git is cloned to ROOT/example.com/org/repo
+func (d *Downloader) DestinationPath(url string) string {
+	result, _ := d.destinationPath(url)
+	return result
+}
+
+func (d *Downloader) destinationPath(url string) (string, error) {
+	u, err := giturls.Parse(url)
+	if err != nil {
+		return "", fmt.Errorf("invalid git URL %s: %v", url, err)
+	}
+
+	p := path.Clean("/" + strings.TrimSuffix(strings.TrimSuffix(u.Path, "/"), ".git"))
+	if p == "/" {
+		return "", fmt.Errorf("invalid git URL %s: missing repo path", url)
+	}
+	return filepath.Join(d.rootDir, u.Hostname(), filepath.FromSlash(p)), nil
+}
+
+// Clones the remote, or fetches the latest version if it's already cloned,
+// and checks out the remote's default branch.
+//
+// Returns the directory of the checkout.
+func (d *Downloader) Download(url string) (string, error) {
+	dest, err := d.destinationPath(url)
+	if err != nil {
+		return "", err
+	}
+
+	_, err = os.Stat(filepath.Join(dest, ".git"))
+	if os.IsNotExist(err) {
+		err = os.MkdirAll(filepath.Dir(dest), 0777)
+		if err != nil {
+			return "", err
+		}
+
+		err = d.run(filepath.Dir(dest), "clone", "--", url, dest)
+		if err != nil {
+			return "", err
+		}
+		return dest, nil
+	} else if err != nil {
+		return "", err
+	}
+
+	err = d.run(dest, "fetch", "--tags", "--force", "origin")
+	if err != nil {
+		return "", err
+	}
+
+	err = d.run(dest, "checkout", "--detach", "origin/HEAD")
+	if err != nil {
+		return "", err
+	}
+	return dest, nil
+}
+
+// Checks out the given ref: a commit, a tag, or a branch of the remote.
+// Assumes the remote has already been downloaded.
+func (d *Downloader) RefSync(url string, ref string) error {
+	dest, err := d.destinationPath(url)
+	if err != nil {
+		return err
+	}
+
+	// Prefer the remote branch, so that we don't check out a stale local branch.
+	target := ref
+	_, err = d.output(dest, "rev-parse", "--verify", "--quiet", fmt.Sprintf("origin/%s^{commit}", ref))
+	if err == nil {
+		target = fmt.Sprintf("origin/%s", ref)
+	}
+
+	return d.run(dest, "checkout", "--detach", target)
+}
+
+// Determines the hash of the currently checked out head.
+func (d *Downloader) HeadRef(url string) (string, error) {
+	dest, err := d.destinationPath(url)
+	if err != nil {
+		return "", err
+	}
+
+	out, err := d.output(dest, "rev-parse", "HEAD")
+	if err != nil {
+		return "", err
+	}
+	return strings.TrimSpace(out), nil
+}
+
+func (d *Downloader) run(dir string, args ...string) error {
+	_, err := d.output(dir, args...)
+	if err != nil && d.Stderr != nil {
+		_, _ = fmt.Fprintf(d.Stderr, "%v\n", err)
+	}
+	return err
+}
+
+func (d *Downloader) output(dir string, args ...string) (string, error) {
+	cmd := exec.Command("git", args...)
+	cmd.Dir = dir
+	cmd.Env = gitEnv(os.Environ())
+
+	stdout := bytes.NewBuffer(nil)
+	stderr := bytes.NewBuffer(nil)
+	cmd.Stdout = stdout
+	cmd.Stderr = stderr
+	err := cmd.Run()
+	if err != nil {
+		msg := strings.TrimSpace(stderr.String())
+		if msg == "" {
+			return "", fmt.Errorf("git %s: %v", args[0], err)
+		}
+		return "", fmt.Errorf("git %s: %v\n%s", args[0], err, msg)
+	}
+	return stdout.String(), nil
+}
+
+// Git runs in the background, so make sure it fails
+// rather than waiting on a password prompt.
+//
+// Credential helpers and SSH agents still work.
+func gitEnv(env []string) []string {
+	env = append(env, "GIT_TERMINAL_PROMPT=0")
+	if os.Getenv("GIT_SSH") == "" {
+		sshCommand := os.Getenv("GIT_SSH_COMMAND")
+		if sshCommand == "" {
+			sshCommand = "ssh"
+		}
+		env = append(env, fmt.Sprintf("GIT_SSH_COMMAND=%s -o BatchMode=yes", sshCommand))
+	}
+	return env
+}
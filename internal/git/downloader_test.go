@@ -0,0 +1,135 @@
+package git
+
+import (
+	"fmt"
+	"io/ioutil"
+	"os/exec"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/tilt-dev/tilt/internal/testutils/tempdir"
+)
+
+func TestDestinationPath(t *testing.T) {
+	d := NewDownloader("/root")
+	assert.Equal(t, "/root/example.com/org/repo", d.DestinationPath("git@example.com:org/repo.git"))
+	assert.Equal(t, "/root/example.com/org/repo", d.DestinationPath("ssh://git@example.com:2222/org/repo.git"))
+	assert.Equal(t, "/root/example.com/org/repo", d.DestinationPath("https://example.com/org/repo.git"))
+	assert.Equal(t, "/root/example.com/repo", d.DestinationPath("git@example.com:../../repo.git"))
+}
+
+func TestDownload(t *testing.T) {
+	f := newDownloaderFixture(t)
+
+	f.commit("exts/my-ext/Tiltfile", "print('v1')")
+	v1 := f.head()
+	f.git("tag", "v1")
+	f.commit("exts/my-ext/Tiltfile", "print('v2')")
+	v2 := f.head()
+
+	dest, err := f.dlr.Download(f.url)
+	require.NoError(t, err)
+	assert.Equal(t, f.dlr.DestinationPath(f.url), dest)
+	f.assertFile(dest, "exts/my-ext/Tiltfile", "print('v2')")
+
+	ref, err := f.dlr.HeadRef(f.url)
+	require.NoError(t, err)
+	assert.Equal(t, v2, ref)
+
+	require.NoError(t, f.dlr.RefSync(f.url, "v1"))
+	ref, err = f.dlr.HeadRef(f.url)
+	require.NoError(t, err)
+	assert.Equal(t, v1, ref)
+	f.assertFile(dest, "exts/my-ext/Tiltfile", "print('v1')")
+
+	// Downloading again moves back to the latest commit.
+	f.commit("exts/my-ext/Tiltfile", "print('v3')")
+	_, err = f.dlr.Download(f.url)
+	require.NoError(t, err)
+	f.assertFile(dest, "exts/my-ext/Tiltfile", "print('v3')")
+}
+
+func TestRefSyncBranch(t *testing.T) {
+	f := newDownloaderFixture(t)
+
+	f.commit("Tiltfile", "print('main')")
+	f.git("checkout", "-q", "-b", "feature")
+	f.commit("Tiltfile", "print('feature')")
+	feature := f.head()
+	f.git("checkout", "-q", "-")
+
+	_, err := f.dlr.Download(f.url)
+	require.NoError(t, err)
+	require.NoError(t, f.dlr.RefSync(f.url, "feature"))
+
+	ref, err := f.dlr.HeadRef(f.url)
+	require.NoError(t, err)
+	assert.Equal(t, feature, ref)
+}
+
+func TestDownloadErrorIncludesStderr(t *testing.T) {
+	f := newDownloaderFixture(t)
+
+	url := fmt.Sprintf("file://%s", f.tmp.JoinPath("does-not-exist.git"))
+	_, err := f.dlr.Download(url)
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "git clone: exit status 128")
+	assert.Contains(t, err.Error(), "does-not-exist.git")
+}
+
+type downloaderFixture struct {
+	t   *testing.T
+	tmp *tempdir.TempDirFixture
+	dlr *Downloader
+	src string
+	url string
+}
+
+func newDownloaderFixture(t *testing.T) *downloaderFixture {
+	tmp := tempdir.NewTempDirFixture(t)
+	t.Cleanup(tmp.TearDown)
+
+	dlr := NewDownloader(tmp.JoinPath("root"))
+	dlr.Stderr = nil
+
+	src := tmp.JoinPath("src", "repo")
+	f := &downloaderFixture{
+		t:   t,
+		tmp: tmp,
+		dlr: dlr,
+		src: src,
+		url: fmt.Sprintf("file://%s", src),
+	}
+	tmp.MkdirAll(src)
+	f.git("init", "-q")
+	return f
+}
+
+func (f *downloaderFixture) git(args ...string) string {
+	args = append([]string{"-c", "user.name=tilt", "-c", "user.email=tilt@example.com"}, args...)
+	cmd := exec.Command("git", args...)
+	cmd.Dir = f.src
+	out, err := cmd.CombinedOutput()
+	require.NoError(f.t, err, string(out))
+	return strings.TrimSpace(string(out))
+}
+
+func (f *downloaderFixture) commit(path, contents string) {
+	f.tmp.WriteFile(f.tmp.JoinPath("src", "repo", path), contents)
+	f.git("add", ".")
+	f.git("commit", "-q", "-m", contents)
+}
+
+func (f *downloaderFixture) head() string {
+	return f.git("rev-parse", "HEAD")
+}
+
+func (f *downloaderFixture) assertFile(dir, path, expected string) {
+	out, err := ioutil.ReadFile(filepath.Join(dir, path))
+	require.NoError(f.t, err)
+	assert.Equal(f.t, expected, string(out))
+}
@@ -0,0 +1,337 @@
+package hud
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/pkg/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+
+	"github.com/tilt-dev/tilt/internal/hud/view"
+	"github.com/tilt-dev/tilt/internal/store"
+	"github.com/tilt-dev/tilt/pkg/apis/core/v1alpha1"
+	"github.com/tilt-dev/tilt/pkg/model"
+)
+
+// The resource actions that the web UI offers, for people who can't
+// easily open the web UI (e.g., because they're connected over SSH).
+
+const labelFilterPrefix = "label:"
+
+// Opens the command palette for the selected resource.
+//
+// The palette lists the same buttons that the web UI shows for the resource,
+// plus the actions that the HUD has keybindings for.
+func (h *Hud) openCommandPalette(ctx context.Context, dispatch func(action store.Action)) {
+	filter := promptChoice{label: "Filter resources", run: h.promptResourceFilter}
+	_, r := h.selectedResource()
+	if r.Name == "" {
+		h.prompt = newChoicePrompt("Commands", []promptChoice{filter})
+		return
+	}
+
+	name := r.Name
+	p := newChoicePrompt(fmt.Sprintf("Commands: %s", name), []promptChoice{
+		{label: "Trigger update", run: func() { h.triggerResource(dispatch, name) }},
+		filter,
+	})
+	h.recordInteraction("command_palette")
+	h.prompt = p
+
+	// Open the palette right away, and add the buttons when they load.
+	h.callAPIServer(ctx, func(ctx context.Context) func() {
+		buttons, err := h.resourceButtons(ctx, name)
+		return func() {
+			if err != nil {
+				h.currentViewState.AlertMessage = err.Error()
+				return
+			}
+
+			var choices []promptChoice
+			for _, b := range buttons {
+				choices = append(choices, h.buttonChoice(ctx, name, b))
+			}
+			p.insertChoices(1, choices)
+		}
+	})
+}
+
+// Calls the apiserver in the background, so that the HUD doesn't freeze
+// while holding the lock. The returned func applies the result
+// (while holding the lock), and then we re-render.
+func (h *Hud) callAPIServer(ctx context.Context, call func(ctx context.Context) func()) {
+	go func() {
+		apply := call(ctx)
+
+		h.mu.Lock()
+		defer h.mu.Unlock()
+		apply()
+		h.refresh(ctx)
+	}()
+}
+
+// Runs a command that the user chose in the background,
+// and shows its error (if any).
+func (h *Hud) runCommand(ctx context.Context, command func(ctx context.Context) error) {
+	h.callAPIServer(ctx, func(ctx context.Context) func() {
+		err := command(ctx)
+		return func() {
+			if err != nil {
+				h.currentViewState.AlertMessage = err.Error()
+			}
+		}
+	})
+}
+
+func (h *Hud) triggerResource(dispatch func(action store.Action), name model.ManifestName) {
+	h.recordInteraction("trigger")
+	dispatch(store.AppendToTriggerQueueAction{Name: name, Reason: model.BuildReasonFlagTriggerHUD})
+}
+
+// The buttons that the web UI shows on a resource, sorted by name.
+func (h *Hud) resourceButtons(ctx context.Context, name model.ManifestName) ([]v1alpha1.UIButton, error) {
+	var list v1alpha1.UIButtonList
+	err := h.client.List(ctx, &list)
+	if err != nil {
+		return nil, errors.Wrap(err, "fetching buttons")
+	}
+
+	var result []v1alpha1.UIButton
+	for _, b := range list.Items {
+		loc := b.Spec.Location
+		if loc.ComponentType == v1alpha1.ComponentTypeResource && loc.ComponentID == name.String() {
+			result = append(result, b)
+		}
+	}
+	sort.Slice(result, func(i, j int) bool {
+		return result[i].Name < result[j].Name
+	})
+	return result, nil
+}
+
+// A palette entry that runs a button the way the web UI does,
+// asking for confirmation and inputs first.
+func (h *Hud) buttonChoice(ctx context.Context, name model.ManifestName, b v1alpha1.UIButton) promptChoice {
+	label := b.Spec.Text
+	action := func() { h.promptButtonInputs(ctx, b, b.Spec.Inputs, nil) }
+	if isDisableToggle(b) {
+		// Rather than click the button, we write the ConfigMaps that it would toggle.
+		label = fmt.Sprintf("%s resource", b.Spec.Text)
+		action = func() {
+			h.runCommand(ctx, func(ctx context.Context) error {
+				return h.toggleDisable(ctx, name)
+			})
+		}
+	}
+
+	run := action
+	if b.Spec.RequiresConfirmation {
+		run = func() {
+			h.prompt = newChoicePrompt(fmt.Sprintf("%s %s?", b.Spec.Text, name), []promptChoice{
+				{label: "Confirm", run: action},
+				{label: "Cancel", run: func() {}},
+			})
+		}
+	}
+	return promptChoice{label: label, run: run}
+}
+
+func isDisableToggle(b v1alpha1.UIButton) bool {
+	return b.Annotations[v1alpha1.AnnotationButtonType] == v1alpha1.UIButtonTypeDisableToggle
+}
+
+// Enables or disables the selected resource.
+func (h *Hud) toggleSelectedDisabled(ctx context.Context) {
+	_, r := h.selectedResource()
+	if r.Name == "" {
+		return
+	}
+
+	name := r.Name
+	h.callAPIServer(ctx, func(ctx context.Context) func() {
+		buttons, err := h.resourceButtons(ctx, name)
+		return func() {
+			if err != nil {
+				h.currentViewState.AlertMessage = err.Error()
+				return
+			}
+			for _, b := range buttons {
+				if isDisableToggle(b) {
+					h.buttonChoice(ctx, name, b).run()
+					return
+				}
+			}
+
+			h.currentViewState.AlertMessage = fmt.Sprintf("resource %q can't be disabled. "+
+				"Disabling resources is experimental; to try it, add enable_feature('disable_resources') to your Tiltfile", name)
+		}
+	})
+}
+
+// Enables the resource if any part of it is disabled, and disables it otherwise,
+// by writing to the ConfigMaps of its DisableSources.
+func (h *Hud) toggleDisable(ctx context.Context, name model.ManifestName) error {
+	h.recordInteraction("toggle_disable")
+
+	var r v1alpha1.UIResource
+	err := h.client.Get(ctx, types.NamespacedName{Name: name.String()}, &r)
+	if err != nil {
+		return errors.Wrapf(err, "fetching resource %q", name)
+	}
+
+	disable := r.Status.DisableStatus.DisabledCount == 0
+	for _, source := range r.Status.DisableStatus.Sources {
+		if source.ConfigMap == nil {
+			continue
+		}
+		err := h.setConfigMapValue(ctx, source.ConfigMap.Name, source.ConfigMap.Key, strconv.FormatBool(disable))
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func (h *Hud) setConfigMapValue(ctx context.Context, name, key, value string) error {
+	var cm v1alpha1.ConfigMap
+	err := h.client.Get(ctx, types.NamespacedName{Name: name}, &cm)
+	if err != nil {
+		return errors.Wrapf(err, "fetching ConfigMap %q", name)
+	}
+
+	if cm.Data[key] == value {
+		return nil
+	}
+	if cm.Data == nil {
+		cm.Data = make(map[string]string)
+	}
+	cm.Data[key] = value
+	err = h.client.Update(ctx, &cm)
+	if err != nil {
+		return errors.Wrapf(err, "updating ConfigMap %q", name)
+	}
+	return nil
+}
+
+// Prompts for each of the button's inputs in turn, then clicks the button.
+func (h *Hud) promptButtonInputs(ctx context.Context, b v1alpha1.UIButton, specs []v1alpha1.UIInputSpec, inputs []v1alpha1.UIInputStatus) {
+	if len(specs) == 0 {
+		h.runCommand(ctx, func(ctx context.Context) error {
+			return h.clickButton(ctx, b.Name, inputs)
+		})
+		return
+	}
+
+	spec := specs[0]
+	next := func(status v1alpha1.UIInputStatus) {
+		h.promptButtonInputs(ctx, b, specs[1:], append(inputs, status))
+	}
+
+	label := spec.Label
+	if label == "" {
+		label = spec.Name
+	}
+	title := fmt.Sprintf("%s: %s", b.Spec.Text, label)
+
+	switch {
+	case spec.Text != nil:
+		h.prompt = newTextPrompt(title, spec.Text.DefaultValue, func(input string) {
+			next(v1alpha1.UIInputStatus{Name: spec.Name, Text: &v1alpha1.UITextInputStatus{Value: input}})
+		})
+	case spec.Bool != nil:
+		choice := func(value bool) promptChoice {
+			return promptChoice{
+				label: strconv.FormatBool(value),
+				run: func() {
+					next(v1alpha1.UIInputStatus{Name: spec.Name, Bool: &v1alpha1.UIBoolInputStatus{Value: value}})
+				},
+			}
+		}
+		// The default comes first, so that enter picks it.
+		h.prompt = newChoicePrompt(title, []promptChoice{
+			choice(spec.Bool.DefaultValue),
+			choice(!spec.Bool.DefaultValue),
+		})
+	case spec.Hidden != nil:
+		next(v1alpha1.UIInputStatus{Name: spec.Name, Hidden: &v1alpha1.UIHiddenInputStatus{Value: spec.Hidden.Value}})
+	default:
+		h.promptButtonInputs(ctx, b, specs[1:], inputs)
+	}
+}
+
+// Clicks the button the same way that the web UI does, by updating its status.
+func (h *Hud) clickButton(ctx context.Context, name string, inputs []v1alpha1.UIInputStatus) error {
+	h.recordInteraction("click_button")
+
+	var b v1alpha1.UIButton
+	err := h.client.Get(ctx, types.NamespacedName{Name: name}, &b)
+	if err != nil {
+		return errors.Wrapf(err, "fetching button %q", name)
+	}
+
+	b.Status.LastClickedAt = metav1.NowMicro()
+	b.Status.Inputs = inputs
+	err = h.client.Status().Update(ctx, &b)
+	if err != nil {
+		return errors.Wrapf(err, "clicking button %q", name)
+	}
+	return nil
+}
+
+func (h *Hud) promptResourceFilter() {
+	title := fmt.Sprintf("Filter resources by name or %s<label>", labelFilterPrefix)
+	h.prompt = newTextPrompt(title, h.currentViewState.ResourceFilter, h.setResourceFilter)
+}
+
+func (h *Hud) setResourceFilter(filter string) {
+	filter = strings.TrimSpace(filter)
+	if filter == h.currentViewState.ResourceFilter {
+		return
+	}
+	h.recordInteraction("filter_resources")
+
+	h.currentViewState.ResourceFilter = filter
+
+	// The collapse state is stored by index, so it doesn't survive the list changing.
+	h.currentViewState.Resources = nil
+	h.currentViewState.SelectedIndex = 0
+	h.currentView = filterView(h.unfilteredView, filter)
+	h.resetResourceSelection()
+}
+
+func filterView(v view.View, filter string) view.View {
+	if filter == "" {
+		return v
+	}
+
+	var resources []view.Resource
+	for _, r := range v.Resources {
+		if resourceMatchesFilter(r, filter) {
+			resources = append(resources, r)
+		}
+	}
+	v.Resources = resources
+	return v
+}
+
+// Every term in the filter must match. Terms like "label:backend" match resources
+// with that label, and other terms match resources with the term in their name.
+func resourceMatchesFilter(r view.Resource, filter string) bool {
+	for _, term := range strings.Fields(filter) {
+		if strings.HasPrefix(term, labelFilterPrefix) {
+			if _, ok := r.Labels[strings.TrimPrefix(term, labelFilterPrefix)]; !ok {
+				return false
+			}
+			continue
+		}
+
+		if !strings.Contains(strings.ToLower(r.Name.String()), strings.ToLower(term)) {
+			return false
+		}
+	}
+	return true
+}
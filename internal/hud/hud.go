@@ -11,6 +11,7 @@ import (
 
 	"github.com/gdamore/tcell"
 	"github.com/pkg/errors"
+	ctrlclient "sigs.k8s.io/controller-runtime/pkg/client"
 
 	"github.com/tilt-dev/tilt/internal/analytics"
 	"github.com/tilt-dev/tilt/internal/hud/view"
@@ -38,9 +39,14 @@ type Hud struct {
 	r       *Renderer
 	webURL  model.WebURL
 	openurl openurl.OpenURL
+	client  ctrlclient.Client
+
+	// The view with every resource, before we apply the resource filter.
+	unfilteredView view.View
 
 	currentView      view.View
 	currentViewState view.ViewState
+	prompt           *prompt
 	mu               sync.RWMutex
 	isStarted        bool
 	isRunning        bool
@@ -49,12 +55,13 @@ type Hud struct {
 
 var _ HeadsUpDisplay = (*Hud)(nil)
 
-func NewHud(renderer *Renderer, webURL model.WebURL, analytics *analytics.TiltAnalytics, openurl openurl.OpenURL) HeadsUpDisplay {
+func NewHud(renderer *Renderer, webURL model.WebURL, analytics *analytics.TiltAnalytics, openurl openurl.OpenURL, client ctrlclient.Client) HeadsUpDisplay {
 	return &Hud{
 		r:       renderer,
 		webURL:  webURL,
 		a:       analytics,
 		openurl: openurl,
+		client:  client,
 	}
 }
 
@@ -132,11 +139,22 @@ func (h *Hud) handleScreenEvent(ctx context.Context, dispatch func(action store.
 		am := h.activeModal()
 		if am != nil {
 			am.Close(&h.currentViewState)
+		} else if h.currentViewState.ResourceFilter != "" {
+			h.setResourceFilter("")
 		}
 	}
 
 	switch ev := ev.(type) {
 	case *tcell.EventKey:
+		if h.prompt != nil && ev.Key() != tcell.KeyCtrlC {
+			p := h.prompt
+			// Choosing an option may open another prompt, e.g., for a button's inputs.
+			if p.handleKey(ev) && h.prompt == p {
+				h.prompt = nil
+			}
+			break
+		}
+
 		switch ev.Key() {
 		case tcell.KeyEscape:
 			escape()
@@ -186,6 +204,17 @@ func (h *Hud) handleScreenEvent(ctx context.Context, dispatch func(action store.
 			case r == '3':
 				h.recordInteraction("tab_pod_log")
 				h.currentViewState.TabState = view.TabRuntimeLog
+			case r == 't': // [T]rigger
+				_, selected := h.selectedResource()
+				if selected.Name != "" {
+					h.triggerResource(dispatch, selected.Name)
+				}
+			case r == 'd': // [D]isable or enable
+				h.toggleSelectedDisabled(ctx)
+			case r == ':':
+				h.openCommandPalette(ctx, dispatch)
+			case r == '/':
+				h.promptResourceFilter()
 			}
 		case tcell.KeyUp:
 			h.activeScroller().Up()
@@ -288,6 +317,9 @@ func (h *Hud) OnChange(ctx context.Context, st store.RStore, _ store.ChangeSumma
 
 	fmt.Print(toPrint)
 
+	h.unfilteredView = view
+	view = filterView(view, h.currentViewState.ResourceFilter)
+
 	// if we're going from 1 resource (i.e., the Tiltfile) to more than 1, reset
 	// the resource selection, so that we're not scrolled to the bottom with the Tiltfile selected
 	if len(h.currentView.Resources) == 1 && len(view.Resources) > 1 {
@@ -319,6 +351,10 @@ func (h *Hud) refresh(ctx context.Context) {
 
 	vs := h.currentViewState
 	vs.Resources = append(vs.Resources, h.currentViewState.Resources...)
+	h.currentViewState.Prompt = nil
+	if h.prompt != nil {
+		h.currentViewState.Prompt = h.prompt.view()
+	}
 
 	h.r.Render(h.currentView, h.currentViewState)
 }
@@ -2,15 +2,26 @@ package hud
 
 import (
 	"bytes"
+	"context"
 	"net/url"
+	"strings"
 	"testing"
 	"time"
 
 	"github.com/gdamore/tcell"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+	ctrlclient "sigs.k8s.io/controller-runtime/pkg/client"
 
+	"github.com/tilt-dev/tilt/internal/controllers/fake"
+	"github.com/tilt-dev/tilt/internal/hud/view"
 	"github.com/tilt-dev/tilt/internal/openurl"
 	"github.com/tilt-dev/tilt/internal/rty"
+	"github.com/tilt-dev/tilt/internal/store"
 	"github.com/tilt-dev/tilt/internal/testutils"
+	"github.com/tilt-dev/tilt/pkg/apis/core/v1alpha1"
 	"github.com/tilt-dev/tilt/pkg/model"
 )
 
@@ -22,6 +33,349 @@ func TestRenderInit(t *testing.T) {
 	r := NewRenderer(clockForTest)
 	r.rty = rty.NewRTY(tcell.NewSimulationScreen(""), t)
 	webURL, _ := url.Parse("http://localhost:10350")
-	hud := NewHud(r, model.WebURL(*webURL), ta, openurl.BrowserOpen)
+	hud := NewHud(r, model.WebURL(*webURL), ta, openurl.BrowserOpen, fake.NewFakeTiltClient())
 	hud.(*Hud).refresh(ctx) // Ensure we render without error
 }
+
+func TestTriggerSelectedResource(t *testing.T) {
+	f := newHudFixture(t)
+	f.setResources("frontend", "backend")
+
+	f.pressKey(tcell.KeyDown)
+	f.typeRunes("t")
+
+	assert.Equal(t, []store.Action{
+		store.AppendToTriggerQueueAction{Name: "backend", Reason: model.BuildReasonFlagTriggerHUD},
+	}, f.actions)
+}
+
+func TestFilterResources(t *testing.T) {
+	f := newHudFixture(t)
+	f.setResources("frontend", "backend", "db")
+	f.hud.unfilteredView.Resources[1].Labels = map[string]string{"api": "api"}
+	f.hud.unfilteredView.Resources[2].Labels = map[string]string{"api": "api"}
+
+	f.typeRunes("/label:api")
+	assert.Equal(t, "label:api", f.prompt().Input)
+	f.pressKey(tcell.KeyEnter)
+	assert.Nil(t, f.prompt())
+	assert.Equal(t, "label:api", f.hud.currentViewState.ResourceFilter)
+	assert.Equal(t, []model.ManifestName{"backend", "db"}, f.resourceNames())
+
+	// Edit the filter, which starts with the current one.
+	f.typeRunes("/ db")
+	assert.Equal(t, "label:api db", f.prompt().Input)
+	f.pressKey(tcell.KeyEnter)
+	assert.Equal(t, []model.ManifestName{"db"}, f.resourceNames())
+
+	f.typeRunes("/")
+	f.pressKey(tcell.KeyCtrlU)
+	f.typeRunes("db")
+	f.pressKey(tcell.KeyEnter)
+
+	// The filter sticks when the engine state changes.
+	f.setResources("frontend", "db", "dbadmin")
+	assert.Equal(t, []model.ManifestName{"db", "dbadmin"}, f.resourceNames())
+
+	f.pressKey(tcell.KeyEscape)
+	assert.Equal(t, "", f.hud.currentViewState.ResourceFilter)
+	assert.Equal(t, []model.ManifestName{"frontend", "db", "dbadmin"}, f.resourceNames())
+}
+
+func TestResourceMatchesFilter(t *testing.T) {
+	r := view.Resource{Name: "Frontend-web", Labels: map[string]string{"web": "web"}}
+	for _, tc := range []struct {
+		filter   string
+		expected bool
+	}{
+		{"", true},
+		{"front", true},
+		{"WEB", true},
+		{"back", false},
+		{"label:web", true},
+		{"label:api", false},
+		{"front label:web", true},
+		{"back label:web", false},
+	} {
+		assert.Equal(t, tc.expected, resourceMatchesFilter(r, tc.filter), "filter %q", tc.filter)
+	}
+}
+
+func TestCommandPaletteFiltersChoices(t *testing.T) {
+	f := newHudFixture(t)
+	f.setResources("frontend")
+	f.createButton(resourceButton("frontend", "frontend-lint", "Lint"))
+
+	f.typeRunes(":")
+	p := f.prompt()
+	require.NotNil(t, p)
+	assert.Equal(t, "Commands: frontend", p.Title)
+	f.waitForChoices("Trigger update", "Lint", "Filter resources")
+
+	f.typeRunes("r")
+	assert.Equal(t, []string{"Trigger update", "Filter resources"}, f.prompt().Choices)
+
+	f.pressKey(tcell.KeyDown)
+	f.pressKey(tcell.KeyEnter)
+	assert.Equal(t, "Filter resources by name or label:<label>", f.prompt().Title)
+
+	f.pressKey(tcell.KeyEscape)
+	assert.Nil(t, f.prompt())
+	assert.Empty(t, f.actions)
+}
+
+func TestCommandPaletteTrigger(t *testing.T) {
+	f := newHudFixture(t)
+	f.setResources("frontend")
+
+	f.typeRunes(":trig")
+	f.waitForChoices("Trigger update")
+	f.pressKey(tcell.KeyEnter)
+
+	assert.Nil(t, f.prompt())
+	assert.Equal(t, []store.Action{
+		store.AppendToTriggerQueueAction{Name: "frontend", Reason: model.BuildReasonFlagTriggerHUD},
+	}, f.actions)
+}
+
+func TestClickButtonWithInputs(t *testing.T) {
+	f := newHudFixture(t)
+	f.setResources("frontend")
+
+	b := resourceButton("frontend", "frontend-deploy", "Deploy")
+	b.Spec.Inputs = []v1alpha1.UIInputSpec{
+		{Name: "env", Label: "Environment", Text: &v1alpha1.UITextInputSpec{DefaultValue: "staging"}},
+		{Name: "token", Hidden: &v1alpha1.UIHiddenInputSpec{Value: "secret"}},
+		{Name: "dry-run", Bool: &v1alpha1.UIBoolInputSpec{DefaultValue: true}},
+	}
+	f.createButton(b)
+
+	f.typeRunes(":deploy")
+	f.waitForChoices("Deploy")
+	f.pressKey(tcell.KeyEnter)
+
+	p := f.prompt()
+	require.NotNil(t, p)
+	assert.Equal(t, "Deploy: Environment", p.Title)
+	assert.Equal(t, "staging", p.Input)
+	f.pressKey(tcell.KeyCtrlU)
+	f.typeRunes("prod")
+	f.pressKey(tcell.KeyEnter)
+
+	p = f.prompt()
+	require.NotNil(t, p)
+	assert.Equal(t, "Deploy: dry-run", p.Title)
+	assert.Equal(t, []string{"true", "false"}, p.Choices)
+	f.pressKey(tcell.KeyDown)
+	f.pressKey(tcell.KeyEnter)
+	assert.Nil(t, f.prompt())
+
+	require.Eventually(t, func() bool {
+		return !f.button("frontend-deploy").Status.LastClickedAt.IsZero()
+	}, time.Second, time.Millisecond)
+	assert.Equal(t, "", f.alertMessage())
+
+	clicked := f.button("frontend-deploy")
+	assert.Equal(t, []v1alpha1.UIInputStatus{
+		{Name: "env", Text: &v1alpha1.UITextInputStatus{Value: "prod"}},
+		{Name: "token", Hidden: &v1alpha1.UIHiddenInputStatus{Value: "secret"}},
+		{Name: "dry-run", Bool: &v1alpha1.UIBoolInputStatus{Value: false}},
+	}, clicked.Status.Inputs)
+}
+
+func TestToggleDisable(t *testing.T) {
+	f := newHudFixture(t)
+	f.setResources("frontend")
+	f.createDisableToggle("frontend", false)
+
+	f.typeRunes("d")
+	f.waitForChoices("Confirm", "Cancel")
+	assert.Equal(t, "Disable frontend?", f.prompt().Title)
+	assert.Equal(t, "false", f.configMapValue("frontend-disable"))
+
+	f.pressKey(tcell.KeyEnter)
+	assert.Nil(t, f.prompt())
+	f.waitForConfigMapValue("frontend-disable", "true")
+	assert.Equal(t, "", f.alertMessage())
+}
+
+func TestToggleEnableFromCommandPalette(t *testing.T) {
+	f := newHudFixture(t)
+	f.setResources("frontend")
+	f.createDisableToggle("frontend", true)
+
+	f.typeRunes(":enable")
+	f.waitForChoices("Enable resource")
+	f.pressKey(tcell.KeyEnter)
+
+	assert.Nil(t, f.prompt())
+	f.waitForConfigMapValue("frontend-disable", "false")
+}
+
+func TestToggleDisableNotEnabled(t *testing.T) {
+	f := newHudFixture(t)
+	f.setResources("frontend")
+
+	f.typeRunes("d")
+	require.Eventually(t, func() bool {
+		return strings.Contains(f.alertMessage(),
+			`resource "frontend" can't be disabled. Disabling resources is experimental`)
+	}, time.Second, time.Millisecond)
+}
+
+type hudFixture struct {
+	t       *testing.T
+	ctx     context.Context
+	hud     *Hud
+	client  ctrlclient.Client
+	actions []store.Action
+}
+
+func newHudFixture(t *testing.T) *hudFixture {
+	ctx, _, ta := testutils.ForkedCtxAndAnalyticsForTest(new(bytes.Buffer))
+
+	r := NewRenderer(clockForTest)
+	r.rty = rty.NewRTY(tcell.NewSimulationScreen(""), t)
+	webURL, _ := url.Parse("http://localhost:10350")
+	client := fake.NewFakeTiltClient()
+	h := NewHud(r, model.WebURL(*webURL), ta, openurl.BrowserOpen, client).(*Hud)
+
+	return &hudFixture{
+		t:      t,
+		ctx:    ctx,
+		hud:    h,
+		client: client,
+	}
+}
+
+// Simulates an engine state change.
+func (f *hudFixture) setResources(names ...model.ManifestName) {
+	v := newView()
+	for _, name := range names {
+		v.Resources = append(v.Resources, view.Resource{Name: name, ResourceInfo: view.K8sResourceInfo{}})
+	}
+
+	f.hud.mu.Lock()
+	defer f.hud.mu.Unlock()
+	f.hud.unfilteredView = v
+	f.hud.currentView = filterView(v, f.hud.currentViewState.ResourceFilter)
+	f.hud.refresh(f.ctx)
+	f.hud.refreshSelectedIndex()
+}
+
+func (f *hudFixture) resourceNames() []model.ManifestName {
+	var result []model.ManifestName
+	for _, r := range f.hud.currentView.Resources {
+		result = append(result, r.Name)
+	}
+	return result
+}
+
+func (f *hudFixture) dispatch(action store.Action) {
+	f.actions = append(f.actions, action)
+}
+
+// Commands call the apiserver in the background, so we wait for their results.
+func (f *hudFixture) waitForChoices(expected ...string) {
+	var choices []string
+	ok := assert.Eventually(f.t, func() bool {
+		choices = nil
+		if p := f.prompt(); p != nil {
+			choices = p.Choices
+		}
+		return assert.ObjectsAreEqual(expected, choices)
+	}, time.Second, time.Millisecond)
+	if !ok {
+		f.t.Fatalf("Timed out waiting for choices %q. Actual: %q", expected, choices)
+	}
+}
+
+func (f *hudFixture) waitForConfigMapValue(name, expected string) {
+	require.Eventually(f.t, func() bool {
+		return f.configMapValue(name) == expected
+	}, time.Second, time.Millisecond, "ConfigMap %s never set to %q", name, expected)
+}
+
+func (f *hudFixture) prompt() *view.Prompt {
+	f.hud.mu.Lock()
+	defer f.hud.mu.Unlock()
+	return f.hud.currentViewState.Prompt
+}
+
+func (f *hudFixture) alertMessage() string {
+	f.hud.mu.Lock()
+	defer f.hud.mu.Unlock()
+	return f.hud.currentViewState.AlertMessage
+}
+
+func (f *hudFixture) pressKey(key tcell.Key) {
+	f.hud.handleScreenEvent(f.ctx, f.dispatch, tcell.NewEventKey(key, 0, tcell.ModNone))
+}
+
+func (f *hudFixture) typeRunes(s string) {
+	for _, r := range s {
+		f.hud.handleScreenEvent(f.ctx, f.dispatch, tcell.NewEventKey(tcell.KeyRune, r, tcell.ModNone))
+	}
+}
+
+func resourceButton(resource, name, text string) *v1alpha1.UIButton {
+	return &v1alpha1.UIButton{
+		ObjectMeta: metav1.ObjectMeta{Name: name},
+		Spec: v1alpha1.UIButtonSpec{
+			Location: v1alpha1.UIComponentLocation{
+				ComponentID:   resource,
+				ComponentType: v1alpha1.ComponentTypeResource,
+			},
+			Text: text,
+		},
+	}
+}
+
+func (f *hudFixture) createButton(b *v1alpha1.UIButton) {
+	require.NoError(f.t, f.client.Create(f.ctx, b))
+}
+
+func (f *hudFixture) button(name string) *v1alpha1.UIButton {
+	var b v1alpha1.UIButton
+	require.NoError(f.t, f.client.Get(f.ctx, types.NamespacedName{Name: name}, &b))
+	return &b
+}
+
+// Creates the objects that the Tiltfile and the ToggleButton reconciler
+// would create for a resource that can be disabled.
+func (f *hudFixture) createDisableToggle(resource string, disabled bool) {
+	cmName := resource + "-disable"
+	source := v1alpha1.DisableSource{
+		ConfigMap: &v1alpha1.ConfigMapDisableSource{Name: cmName, Key: "isDisabled"},
+	}
+
+	value := "false"
+	text := "Disable"
+	status := v1alpha1.DisableResourceStatus{EnabledCount: 1, Sources: []v1alpha1.DisableSource{source}}
+	if disabled {
+		value = "true"
+		text = "Enable"
+		status = v1alpha1.DisableResourceStatus{DisabledCount: 1, Sources: []v1alpha1.DisableSource{source}}
+	}
+
+	require.NoError(f.t, f.client.Create(f.ctx, &v1alpha1.ConfigMap{
+		ObjectMeta: metav1.ObjectMeta{Name: cmName},
+		Data:       map[string]string{"isDisabled": value},
+	}))
+
+	r := &v1alpha1.UIResource{ObjectMeta: metav1.ObjectMeta{Name: resource}}
+	require.NoError(f.t, f.client.Create(f.ctx, r))
+	r.Status.DisableStatus = status
+	require.NoError(f.t, f.client.Status().Update(f.ctx, r))
+
+	b := resourceButton(resource, "toggle-"+resource+"-disable", text)
+	b.Annotations = map[string]string{v1alpha1.AnnotationButtonType: v1alpha1.UIButtonTypeDisableToggle}
+	b.Spec.RequiresConfirmation = !disabled
+	f.createButton(b)
+}
+
+func (f *hudFixture) configMapValue(name string) string {
+	var cm v1alpha1.ConfigMap
+	require.NoError(f.t, f.client.Get(f.ctx, types.NamespacedName{Name: name}, &cm))
+	return cm.Data["isDisabled"]
+}
@@ -1,7 +1,9 @@
 package hud
 
 import (
+	"encoding/json"
 	"io"
+	"strings"
 	"time"
 
 	"github.com/tilt-dev/tilt/pkg/model/logstore"
@@ -13,13 +15,13 @@ var backoffMultiplier = time.Duration(2)
 type Stdout io.Writer
 
 type IncrementalPrinter struct {
-	progress map[progressKey]progressStatus
+	progress progressFilter
 	stdout   Stdout
 }
 
 func NewIncrementalPrinter(stdout Stdout) *IncrementalPrinter {
 	return &IncrementalPrinter{
-		progress: make(map[progressKey]progressStatus),
+		progress: make(progressFilter),
 		stdout:   stdout,
 	}
 }
@@ -30,39 +32,92 @@ func (p *IncrementalPrinter) PrintNewline() {
 
 func (p *IncrementalPrinter) Print(lines []logstore.LogLine) {
 	for _, line := range lines {
-		// Naive progress implementation: skip lines that have already been printed
-		// recently. This works with any output stream.
-		//
-		// TODO(nick): Use ANSI codes to overwrite previous lines. It requires
-		// a little extra bookkeeping about where to find the progress line,
-		// and only works on terminals.
-		progressID := line.ProgressID
-		key := progressKey{spanID: line.SpanID, progressID: progressID}
-		if progressID != "" {
-			status, hasBeenPrinted := p.progress[key]
-			shouldPrint := line.ProgressMustPrint ||
-				!hasBeenPrinted ||
-				line.Time.Sub(status.lastPrinted) > status.printWait
-			if !shouldPrint {
-				continue
-			}
+		if !p.progress.shouldPrint(line) {
+			continue
 		}
 		_, _ = io.WriteString(p.stdout, line.Text)
+		p.progress.printed(line)
+	}
+}
+
+// Prints log lines as JSON objects, one per line, for consumption by other tools.
+type JSONPrinter struct {
+	progress progressFilter
+	encoder  *json.Encoder
+}
 
-		if progressID != "" {
-			status := p.progress[key]
-			newWait := backoffInit
-			if status.printWait > 0 {
-				newWait = backoffMultiplier * status.printWait
-			}
-			p.progress[key] = progressStatus{
-				lastPrinted: line.Time,
-				printWait:   newWait,
-			}
+func NewJSONPrinter(stdout Stdout) *JSONPrinter {
+	return &JSONPrinter{
+		progress: make(progressFilter),
+		encoder:  json.NewEncoder(stdout),
+	}
+}
+
+type jsonLogLine struct {
+	Time     time.Time `json:"time"`
+	Manifest string    `json:"manifest,omitempty"`
+	Span     string    `json:"span,omitempty"`
+	Level    string    `json:"level"`
+	Source   string    `json:"source"`
+	Text     string    `json:"text"`
+}
+
+func (p *JSONPrinter) Print(lines []logstore.LogLine) {
+	for _, line := range lines {
+		text := strings.TrimSuffix(line.Text, "\n")
+		if text == "" || !p.progress.shouldPrint(line) {
+			continue
 		}
+		_ = p.encoder.Encode(jsonLogLine{
+			Time:     line.Time,
+			Manifest: line.ManifestName.String(),
+			Span:     string(line.SpanID),
+			Level:    line.Level.String(),
+			Source:   string(logstore.SourceForSpanID(line.SpanID)),
+			Text:     text,
+		})
+		p.progress.printed(line)
 	}
 }
 
+// Naive progress implementation: skip lines that have already been printed
+// recently. This works with any output stream.
+//
+// TODO(nick): Use ANSI codes to overwrite previous lines. It requires
+// a little extra bookkeeping about where to find the progress line,
+// and only works on terminals.
+type progressFilter map[progressKey]progressStatus
+
+func (f progressFilter) shouldPrint(line logstore.LogLine) bool {
+	if line.ProgressID == "" {
+		return true
+	}
+	status, hasBeenPrinted := f[progressKeyForLine(line)]
+	return line.ProgressMustPrint ||
+		!hasBeenPrinted ||
+		line.Time.Sub(status.lastPrinted) > status.printWait
+}
+
+func (f progressFilter) printed(line logstore.LogLine) {
+	if line.ProgressID == "" {
+		return
+	}
+	key := progressKeyForLine(line)
+	status := f[key]
+	newWait := backoffInit
+	if status.printWait > 0 {
+		newWait = backoffMultiplier * status.printWait
+	}
+	f[key] = progressStatus{
+		lastPrinted: line.Time,
+		printWait:   newWait,
+	}
+}
+
+func progressKeyForLine(line logstore.LogLine) progressKey {
+	return progressKey{spanID: line.SpanID, progressID: line.ProgressID}
+}
+
 type progressKey struct {
 	spanID     logstore.SpanID
 	progressID string
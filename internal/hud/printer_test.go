@@ -7,6 +7,7 @@ import (
 
 	"github.com/stretchr/testify/assert"
 
+	"github.com/tilt-dev/tilt/pkg/logger"
 	"github.com/tilt-dev/tilt/pkg/model/logstore"
 )
 
@@ -52,3 +53,20 @@ func TestPrinterMustPrint(t *testing.T) {
 	assert.Equal(t, "layer 1: Pending\nlayer 2: Pending\nlayer 1: Done\n", out.String())
 
 }
+
+func TestJSONPrinter(t *testing.T) {
+	out := &bytes.Buffer{}
+	now := time.Date(2021, time.May, 1, 12, 0, 0, 0, time.UTC)
+	printer := NewJSONPrinter(Stdout(out))
+
+	printer.Print([]logstore.LogLine{
+		logstore.LogLine{Text: "\n", SpanID: "build:1", ManifestName: "fe", Level: logger.InfoLvl, Time: now},
+		logstore.LogLine{Text: "Building Dockerfile\n", SpanID: "build:1", ManifestName: "fe", Level: logger.InfoLvl, Time: now},
+		logstore.LogLine{Text: "connection refused\n", SpanID: "pod:fe-123", ManifestName: "fe", Level: logger.ErrorLvl, Time: now},
+	})
+
+	assert.Equal(t,
+		`{"time":"2021-05-01T12:00:00Z","manifest":"fe","span":"build:1","level":"info","source":"build","text":"Building Dockerfile"}
+{"time":"2021-05-01T12:00:00Z","manifest":"fe","span":"pod:fe-123","level":"error","source":"runtime","text":"connection refused"}
+`, out.String())
+}
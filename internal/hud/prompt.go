@@ -0,0 +1,141 @@
+package hud
+
+import (
+	"strings"
+
+	"github.com/gdamore/tcell"
+
+	"github.com/tilt-dev/tilt/internal/hud/view"
+)
+
+// A prompt that reads a line of input from the user.
+//
+// If the prompt has choices, the input narrows them down,
+// and enter runs the selected one. Otherwise, enter submits the input.
+type prompt struct {
+	title string
+	input string
+
+	choices  []promptChoice
+	selected int
+
+	submit func(input string)
+}
+
+type promptChoice struct {
+	label string
+	run   func()
+}
+
+func newTextPrompt(title string, input string, submit func(input string)) *prompt {
+	return &prompt{title: title, input: input, submit: submit}
+}
+
+func newChoicePrompt(title string, choices []promptChoice) *prompt {
+	return &prompt{title: title, choices: choices}
+}
+
+func (p *prompt) hasChoices() bool {
+	return p.submit == nil
+}
+
+// The choices whose labels contain the input, ignoring case.
+func (p *prompt) matchingChoices() []promptChoice {
+	var result []promptChoice
+	for _, i := range p.matchingIndices() {
+		result = append(result, p.choices[i])
+	}
+	return result
+}
+
+func (p *prompt) matchingIndices() []int {
+	query := strings.ToLower(strings.TrimSpace(p.input))
+	var result []int
+	for i, c := range p.choices {
+		if strings.Contains(strings.ToLower(c.label), query) {
+			result = append(result, i)
+		}
+	}
+	return result
+}
+
+// Adds choices before the choice at index i, keeping the same choice selected.
+//
+// Used for choices that we load after the prompt opens, so that the user
+// can start typing right away.
+func (p *prompt) insertChoices(i int, choices []promptChoice) {
+	selected := -1
+	matches := p.matchingIndices()
+	if p.selected < len(matches) {
+		selected = matches[p.selected]
+		if selected >= i {
+			selected += len(choices)
+		}
+	}
+
+	newChoices := append([]promptChoice{}, p.choices[:i]...)
+	newChoices = append(newChoices, choices...)
+	p.choices = append(newChoices, p.choices[i:]...)
+
+	p.selected = 0
+	for j, index := range p.matchingIndices() {
+		if index == selected {
+			p.selected = j
+		}
+	}
+}
+
+// Handles a key press. Returns true if the prompt is done,
+// either because the user submitted it or dismissed it.
+func (p *prompt) handleKey(ev *tcell.EventKey) (done bool) {
+	switch ev.Key() {
+	case tcell.KeyEscape:
+		return true
+	case tcell.KeyEnter:
+		if !p.hasChoices() {
+			p.submit(p.input)
+			return true
+		}
+		matches := p.matchingChoices()
+		if p.selected < len(matches) {
+			matches[p.selected].run()
+			return true
+		}
+	case tcell.KeyUp, tcell.KeyCtrlP:
+		if p.selected > 0 {
+			p.selected--
+		}
+	case tcell.KeyDown, tcell.KeyCtrlN:
+		if p.selected < len(p.matchingChoices())-1 {
+			p.selected++
+		}
+	case tcell.KeyBackspace, tcell.KeyBackspace2:
+		if len(p.input) > 0 {
+			r := []rune(p.input)
+			p.setInput(string(r[:len(r)-1]))
+		}
+	case tcell.KeyCtrlU:
+		p.setInput("")
+	case tcell.KeyRune:
+		p.setInput(p.input + string(ev.Rune()))
+	}
+	return false
+}
+
+func (p *prompt) setInput(input string) {
+	p.input = input
+	p.selected = 0
+}
+
+func (p *prompt) view() *view.Prompt {
+	v := &view.Prompt{
+		Title:      p.title,
+		Input:      p.input,
+		HasChoices: p.hasChoices(),
+		Selected:   p.selected,
+	}
+	for _, c := range p.matchingChoices() {
+		v.Choices = append(v.Choices, c.label)
+	}
+	return v
+}
@@ -16,6 +16,8 @@ import (
 
 const defaultLogPaneHeight = 8
 
+const promptMinWidth = 50
+
 type Renderer struct {
 	rty    rty.RTY
 	screen tcell.Screen
@@ -62,6 +64,8 @@ func (r *Renderer) layout(v view.View, vs view.ViewState) rty.Component {
 
 	ret = r.maybeAddFullScreenLog(v, vs, ret)
 
+	ret = r.maybeAddPrompt(vs, ret)
+
 	ret = r.maybeAddAlertModal(v, vs, ret)
 
 	return ret
@@ -106,6 +110,39 @@ func (r *Renderer) maybeAddAlertModal(v view.View, vs view.ViewState, layout rty
 	return layout
 }
 
+func (r *Renderer) maybeAddPrompt(vs view.ViewState, layout rty.Component) rty.Component {
+	p := vs.Prompt
+	if p == nil {
+		return layout
+	}
+
+	l := rty.NewLines()
+	l.Add(rty.TextString(""))
+
+	input := rty.NewMinLengthLayout(promptMinWidth, rty.DirHor)
+	input.Add(rty.NewStringBuilder().Text(" > ").Text(p.Input).Bg(tcell.ColorWhiteSmoke).Text(" ").Build())
+	l.Add(input)
+
+	if p.HasChoices {
+		l.Add(rty.TextString(""))
+		if len(p.Choices) == 0 {
+			l.Add(rty.ColoredString("   No matches", cLightText))
+		}
+		for i, choice := range p.Choices {
+			if i == p.Selected {
+				l.Add(rty.NewStringBuilder().Text(" ▶ ").Fg(cText).Bg(tcell.ColorWhiteSmoke).Textf(" %s ", choice).Build())
+			} else {
+				l.Add(rty.TextString(fmt.Sprintf("    %s ", choice)))
+			}
+		}
+	}
+	l.Add(rty.TextString(""))
+
+	w := rty.NewWindow(l)
+	w.SetTitle(p.Title)
+	return r.renderModal(w, layout, false)
+}
+
 func (r *Renderer) renderLogPane(v view.View, vs view.ViewState) rty.Component {
 	tabView := NewTabView(v, vs)
 	var height int
@@ -189,10 +226,19 @@ func (r *Renderer) renderFooter(v view.View, keys string) rty.Component {
 }
 
 func keyLegend(v view.View, vs view.ViewState) string {
-	defaultKeys := "Browse (↓ ↑), Expand (→) ┊ (enter) log ┊ (ctrl-C) quit  "
+	defaultKeys := "(t)rigger ┊ (d)isable ┊ (:) commands ┊ (/) filter ┊ (ctrl-C) quit  "
 	if vs.AlertMessage != "" {
 		return "Tilt (l)og ┊ (esc) close alert "
 	}
+	if vs.Prompt != nil {
+		if vs.Prompt.HasChoices {
+			return "Browse (↓ ↑) ┊ (enter) select ┊ (esc) cancel "
+		}
+		return "(enter) submit ┊ (esc) cancel "
+	}
+	if vs.ResourceFilter != "" {
+		return fmt.Sprintf("Filter: %s ┊ (/) edit ┊ (esc) clear ┊ (:) commands ┊ (ctrl-C) quit  ", vs.ResourceFilter)
+	}
 	return defaultKeys
 }
 
@@ -298,6 +298,51 @@ func TestRenderNarrationMessage(t *testing.T) {
 	rtf.run("narration message", 60, 20, v, vs)
 }
 
+func TestRenderPrompt(t *testing.T) {
+	rtf := newRendererTestFixture(t)
+
+	v := newView(view.Resource{
+		Name:         "frontend",
+		ResourceInfo: view.K8sResourceInfo{},
+	})
+	vs := fakeViewState(1, view.CollapseNo)
+
+	vs.Prompt = &view.Prompt{
+		Title:      "Commands: frontend",
+		Input:      "e",
+		HasChoices: true,
+		Choices:    []string{"Trigger update", "Disable resource", "Filter resources"},
+		Selected:   1,
+	}
+	rtf.run("command palette", 70, 20, v, vs)
+
+	vs.Prompt = &view.Prompt{
+		Title:      "Commands: frontend",
+		Input:      "xyz",
+		HasChoices: true,
+	}
+	rtf.run("command palette no matches", 70, 20, v, vs)
+
+	vs.Prompt = &view.Prompt{
+		Title: "Deploy: Environment",
+		Input: "staging",
+	}
+	rtf.run("text prompt", 70, 20, v, vs)
+}
+
+func TestRenderResourceFilter(t *testing.T) {
+	rtf := newRendererTestFixture(t)
+
+	v := newView(view.Resource{
+		Name:         "backend",
+		ResourceInfo: view.K8sResourceInfo{},
+	})
+	vs := fakeViewState(1, view.CollapseNo)
+	vs.ResourceFilter = "label:api"
+
+	rtf.run("resource filter", 100, 20, v, vs)
+}
+
 func TestAutoCollapseModes(t *testing.T) {
 	rtf := newRendererTestFixture(t)
 
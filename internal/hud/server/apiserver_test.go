@@ -62,6 +62,10 @@ func TestAPIServerDynamicClient(t *testing.T) {
 		"ImageMap": map[string]interface{}{
 			"selector": "busybox",
 		},
+		"DockerImage": map[string]interface{}{
+			"ref":     "busybox",
+			"context": "/tmp",
+		},
 		"UIButton": map[string]interface{}{
 			"text": "I'm a button!",
 			"location": map[string]interface{}{
@@ -0,0 +1,158 @@
+package server
+
+import (
+	"fmt"
+	"net/url"
+	"regexp"
+	"strconv"
+	"time"
+
+	"github.com/tilt-dev/tilt/internal/store"
+	"github.com/tilt-dev/tilt/pkg/logger"
+	"github.com/tilt-dev/tilt/pkg/model"
+	"github.com/tilt-dev/tilt/pkg/model/logstore"
+	proto_webview "github.com/tilt-dev/tilt/pkg/webview"
+)
+
+// Query parameters accepted by /api/view for searching logs.
+//
+// If any of them are set, the LogList in the view contains the logs matching
+// the query instead of the in-memory logs. Logs archived to disk are only
+// included with history=true.
+const (
+	logQueryParamHistory  = "history"
+	logQueryParamManifest = "manifest"
+	logQueryParamLevel    = "level"
+	logQueryParamSince    = "since"
+	logQueryParamUntil    = "until"
+	logQueryParamSearch   = "search"
+	logQueryParamRegex    = "regex"
+	logQueryParamSource   = "source"
+)
+
+var logQueryParams = []string{
+	logQueryParamHistory,
+	logQueryParamManifest,
+	logQueryParamLevel,
+	logQueryParamSince,
+	logQueryParamUntil,
+	logQueryParamSearch,
+	logQueryParamRegex,
+	logQueryParamSource,
+}
+
+// Parses the log query from the URL parameters.
+//
+// Returns nil if there are no log query parameters.
+func parseLogQuery(values url.Values) (*logstore.Query, error) {
+	hasQuery := false
+	for _, p := range logQueryParams {
+		if _, ok := values[p]; ok {
+			hasQuery = true
+		}
+	}
+	if !hasQuery {
+		return nil, nil
+	}
+
+	q := &logstore.Query{}
+	if history := values.Get(logQueryParamHistory); history != "" {
+		var err error
+		q.History, err = strconv.ParseBool(history)
+		if err != nil {
+			return nil, fmt.Errorf("invalid %s: %v", logQueryParamHistory, err)
+		}
+	}
+
+	if mns := values[logQueryParamManifest]; len(mns) > 0 {
+		q.ManifestNames = make(model.ManifestNameSet, len(mns))
+		for _, mn := range mns {
+			q.ManifestNames[model.ManifestName(mn)] = true
+		}
+	}
+
+	if level := values.Get(logQueryParamLevel); level != "" {
+		l, err := logger.ParseLevel(level)
+		if err != nil {
+			return nil, err
+		}
+		q.Level = l
+	}
+
+	var err error
+	q.Since, err = parseLogQueryTime(values, logQueryParamSince)
+	if err != nil {
+		return nil, err
+	}
+	q.Until, err = parseLogQueryTime(values, logQueryParamUntil)
+	if err != nil {
+		return nil, err
+	}
+
+	q.Substring = values.Get(logQueryParamSearch)
+	if expr := values.Get(logQueryParamRegex); expr != "" {
+		q.Regexp, err = regexp.Compile(expr)
+		if err != nil {
+			return nil, fmt.Errorf("invalid %s: %v", logQueryParamRegex, err)
+		}
+	}
+	q.Source, err = logstore.ParseSource(values.Get(logQueryParamSource))
+	if err != nil {
+		return nil, err
+	}
+	return q, nil
+}
+
+func parseLogQueryTime(values url.Values, param string) (time.Time, error) {
+	v := values.Get(param)
+	if v == "" {
+		return time.Time{}, nil
+	}
+	t, err := time.Parse(time.RFC3339Nano, v)
+	if err != nil {
+		return time.Time{}, fmt.Errorf("invalid %s: %v", param, err)
+	}
+	return t, nil
+}
+
+// Encodes the log query as URL parameters, the inverse of parseLogQuery.
+func encodeLogQuery(q logstore.Query) url.Values {
+	values := url.Values{}
+	values.Set(logQueryParamHistory, strconv.FormatBool(q.History))
+	for mn := range q.ManifestNames {
+		values.Add(logQueryParamManifest, mn.String())
+	}
+	if q.Level != logger.NoneLvl {
+		values.Set(logQueryParamLevel, q.Level.String())
+	}
+	if !q.Since.IsZero() {
+		values.Set(logQueryParamSince, q.Since.Format(time.RFC3339Nano))
+	}
+	if !q.Until.IsZero() {
+		values.Set(logQueryParamUntil, q.Until.Format(time.RFC3339Nano))
+	}
+	if q.Substring != "" {
+		values.Set(logQueryParamSearch, q.Substring)
+	}
+	if q.Regexp != nil {
+		values.Set(logQueryParamRegex, q.Regexp.String())
+	}
+	if q.Source != logstore.SourceAll {
+		values.Set(logQueryParamSource, string(q.Source))
+	}
+	return values
+}
+
+// Runs the log query against the store, without holding the state lock
+// while reading archived logs.
+func queryLogList(st *store.Store, q logstore.Query) (*proto_webview.LogList, error) {
+	state := st.RLockState()
+	reader := logstore.NewReader(st.StateMutex(), state.LogStore)
+	st.RUnlockState()
+
+	lines, err := reader.Query(q)
+	if err != nil {
+		return nil, err
+	}
+	return logstore.LinesToLogList(lines)
+}
@@ -2,7 +2,11 @@ package server
 
 import (
 	"context"
+	"fmt"
 	"io"
+	"io/ioutil"
+	"net/http"
+	"strings"
 
 	"github.com/golang/protobuf/jsonpb"
 	"github.com/gorilla/websocket"
@@ -32,11 +36,6 @@ type WebsocketReader struct {
 	handler      ViewHandler
 }
 
-func newWebsocketReaderForLogs(conn WebsocketConn, persistent bool, resources []string, p *hud.IncrementalPrinter) *WebsocketReader {
-	ls := NewLogStreamer(resources, p)
-	return newWebsocketReader(conn, persistent, ls)
-}
-
 func newWebsocketReader(conn WebsocketConn, persistent bool, handler ViewHandler) *WebsocketReader {
 	return &WebsocketReader{
 		conn:         conn,
@@ -51,6 +50,34 @@ type ViewHandler interface {
 	Handle(v *proto_webview.View) error
 }
 
+// Prints log lines, e.g. to stdout.
+type LogPrinter interface {
+	Print(lines []logstore.LogLine)
+}
+
+var _ LogPrinter = &hud.IncrementalPrinter{}
+var _ LogPrinter = &hud.JSONPrinter{}
+
+// Options for which logs to stream, and how to print them.
+type LogStreamOptions struct {
+	// Only print the logs that match this query.
+	Query logstore.Query
+
+	// If non-negative, only print this many lines of the logs
+	// that exist when we connect. New logs are always printed.
+	Tail int
+
+	// Print lines without the resource name prefix (e.g., for JSON output,
+	// where the resource name is a separate field).
+	SuppressPrefix bool
+
+	// If set, only print lines from the log spans it accepts.
+	//
+	// Called as lines arrive, so the accepted spans can change
+	// over the life of the stream.
+	SpanFilter func(spanID logstore.SpanID) bool
+}
+
 type LogStreamer struct {
 	logstore *logstore.LogStore
 	// checkpoint tracks the client's latest printed logs.
@@ -62,17 +89,29 @@ type LogStreamer struct {
 	// This value should only be used to compare to other server values, NOT client checkpoints.
 	serverWatermark int32
 	resources       model.ManifestNameSet // if present, resource(s) to stream logs for
-	printer         *hud.IncrementalPrinter
+	options         LogStreamOptions
+	printer         LogPrinter
+
+	// whether we've printed the logs that existed when we connected
+	printedHistory bool
 }
 
-func NewLogStreamer(resources []string, p *hud.IncrementalPrinter) *LogStreamer {
+func NewLogStreamer(resources []string, p LogPrinter) *LogStreamer {
 	mnSet := make(map[model.ManifestName]bool, len(resources))
 	for _, r := range resources {
 		mnSet[model.ManifestName(r)] = true
 	}
 
+	return NewFilteredLogStreamer(LogStreamOptions{
+		Query: logstore.Query{ManifestNames: mnSet},
+		Tail:  -1,
+	}, p)
+}
+
+func NewFilteredLogStreamer(options LogStreamOptions, p LogPrinter) *LogStreamer {
 	return &LogStreamer{
-		resources: mnSet,
+		resources: options.Query.ManifestNames,
+		options:   options,
 		logstore:  logstore.NewLogStore(),
 		printer:   p,
 	}
@@ -85,7 +124,7 @@ func (ls *LogStreamer) Handle(v *proto_webview.View) error {
 	}
 
 	// if printing logs for only one resource, don't need resource name prefix
-	suppressPrefix := len(ls.resources) == 1
+	suppressPrefix := len(ls.resources) == 1 || ls.options.SuppressPrefix
 
 	segments := v.LogList.Segments
 	if v.LogList.FromCheckpoint < ls.serverWatermark {
@@ -99,17 +138,56 @@ func (ls *LogStreamer) Handle(v *proto_webview.View) error {
 		ls.logstore.Append(webview.LogSegmentToEvent(seg, v.LogList.Spans), model.SecretSet{})
 	}
 
-	ls.printer.Print(ls.logstore.ContinuingLinesWithOptions(ls.checkpoint, logstore.LineOptions{
+	lines := ls.logstore.ContinuingLinesWithOptions(ls.checkpoint, logstore.LineOptions{
 		ManifestNames:  ls.resources,
 		SuppressPrefix: suppressPrefix,
-	}))
+	})
+	ls.printer.Print(ls.filter(lines))
 
 	ls.checkpoint = ls.logstore.Checkpoint()
 	ls.serverWatermark = v.LogList.ToCheckpoint
 
 	return nil
 }
-func StreamLogs(ctx context.Context, follow bool, url model.WebURL, resources []string, printer *hud.IncrementalPrinter) error {
+
+// Applies the query and tail options to lines that are about to be printed.
+func (ls *LogStreamer) filter(lines []logstore.LogLine) []logstore.LogLine {
+	q := ls.options.Query
+	q.ManifestNames = ls.resources
+
+	result := make([]logstore.LogLine, 0, len(lines))
+	for _, line := range lines {
+		// Match against the text without the resource name prefix.
+		unprefixed := line
+		unprefixed.Text = strings.TrimPrefix(line.Text, logstore.SourcePrefix(line.ManifestName))
+		if ls.options.SpanFilter != nil && !ls.options.SpanFilter(line.SpanID) {
+			continue
+		}
+		if q.Matches(unprefixed) {
+			result = append(result, line)
+		}
+	}
+
+	if !ls.printedHistory {
+		ls.printedHistory = true
+		if ls.options.Tail >= 0 && len(result) > ls.options.Tail {
+			result = result[len(result)-ls.options.Tail:]
+		}
+	}
+	return result
+}
+
+func StreamLogs(ctx context.Context, follow bool, url model.WebURL, options LogStreamOptions, printer LogPrinter) error {
+	if !follow {
+		return printLogHistory(ctx, url, options, printer)
+	}
+
+	return StreamView(ctx, url, NewFilteredLogStreamer(options, printer))
+}
+
+// Streams the state of a running Tilt to the handler, until the context is canceled
+// or the server goes away.
+func StreamView(ctx context.Context, url model.WebURL, handler ViewHandler) error {
 	url.Scheme = "ws"
 	url.Path = "/ws/view"
 	logger.Get(ctx).Debugf("connecting to %s", url.String())
@@ -120,10 +198,43 @@ func StreamLogs(ctx context.Context, follow bool, url model.WebURL, resources []
 	}
 	defer conn.Close()
 
-	wsr := newWebsocketReaderForLogs(conn, follow, resources, printer)
+	wsr := newWebsocketReader(conn, true, handler)
 	return wsr.Listen(ctx)
 }
 
+// Prints the logs matching the query, including logs that the server
+// has truncated from memory and archived to disk.
+func printLogHistory(ctx context.Context, url model.WebURL, options LogStreamOptions, printer LogPrinter) error {
+	ls := NewFilteredLogStreamer(options, printer)
+
+	url.Path = "/api/view"
+	url.RawQuery = encodeLogQuery(options.Query).Encode()
+	logger.Get(ctx).Debugf("fetching %s", url.String())
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, url.String(), nil)
+	if err != nil {
+		return errors.Wrapf(err, "fetching logs from %s", url.String())
+	}
+	resp, err := http.DefaultClient.Do(req)
+	if err != nil {
+		return errors.Wrapf(err, "fetching logs from %s", url.String())
+	}
+	defer func() { _ = resp.Body.Close() }()
+
+	if resp.StatusCode != http.StatusOK {
+		body, _ := ioutil.ReadAll(resp.Body)
+		return fmt.Errorf("fetching logs from %s: %s", url.String(), strings.TrimSpace(string(body)))
+	}
+
+	v := &proto_webview.View{}
+	unmarshaller := jsonpb.Unmarshaler{AllowUnknownFields: true}
+	err = unmarshaller.Unmarshal(resp.Body, v)
+	if err != nil {
+		return errors.Wrap(err, "Unmarshalling logs")
+	}
+	return ls.Handle(v)
+}
+
 func (wsr *WebsocketReader) Listen(ctx context.Context) error {
 	done := make(chan struct{})
 	go func() {
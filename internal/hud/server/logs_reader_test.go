@@ -3,13 +3,16 @@ package server
 import (
 	"bytes"
 	"fmt"
+	"regexp"
 	"strings"
 	"testing"
 
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
 
+	"github.com/tilt-dev/tilt/pkg/logger"
 	"github.com/tilt-dev/tilt/pkg/model"
+	"github.com/tilt-dev/tilt/pkg/model/logstore"
 
 	"github.com/tilt-dev/tilt/internal/hud"
 	proto_webview "github.com/tilt-dev/tilt/pkg/webview"
@@ -131,6 +134,71 @@ func TestLogStreamerCheckpointHandlingWithFiltering(t *testing.T) {
 	f.assertExpectedLogLines(expected)
 }
 
+func TestLogStreamerTail(t *testing.T) {
+	f := newLogStreamerFixture(t).withOptions(LogStreamOptions{Tail: 2})
+	view := f.newViewWithLogsForManifest(alphabet[:4], "foo", 0)
+	f.handle(view)
+
+	// Tail only applies to the logs that existed when we connected.
+	view = f.newViewWithLogsForManifest(alphabet[4:6], "foo", view.LogList.ToCheckpoint)
+	f.handle(view)
+
+	expected := f.expectedLinesWithPrefix([]string{"charlie", "delta", "echo", "foxtrot"}, "foo")
+	f.assertExpectedLogLines(expected)
+}
+
+func TestLogStreamerFiltersOnQuery(t *testing.T) {
+	f := newLogStreamerFixture(t).withOptions(LogStreamOptions{
+		Query: logstore.Query{
+			Level:  logger.WarnLvl,
+			Source: logstore.SourceRuntime,
+			Regexp: regexp.MustCompile("(alpha|charlie|delta)$"),
+		},
+		Tail: -1,
+	})
+
+	view := f.newViewWithLogsForManifest(alphabet[:5], "foo", 0)
+	levels := []logger.Level{logger.WarnLvl, logger.WarnLvl, logger.InfoLvl, logger.ErrorLvl, logger.ErrorLvl}
+	for i, seg := range view.LogList.Segments {
+		seg.Level = proto_webview.LogLevel(levels[i].ToProtoID())
+	}
+	view.LogList.Segments[0].SpanId = "build:1"
+	view.LogList.Spans["build:1"] = &proto_webview.LogSpan{ManifestName: "foo"}
+	f.handle(view)
+
+	// alpha is a build log, bravo doesn't match the regexp,
+	// charlie is info, and echo doesn't match the regexp.
+	expected := f.expectedLinesWithPrefix([]string{"delta"}, "foo")
+	f.assertExpectedLogLines(expected)
+}
+
+func TestLogStreamerFiltersOnSpan(t *testing.T) {
+	spans := map[logstore.SpanID]bool{"build:2": true}
+	f := newLogStreamerFixture(t).withOptions(LogStreamOptions{
+		Tail:       -1,
+		SpanFilter: func(spanID logstore.SpanID) bool { return spans[spanID] },
+	})
+
+	view := f.newViewWithLogsForManifest(alphabet[:4], "foo", 0)
+	for i, seg := range view.LogList.Segments {
+		seg.SpanId = fmt.Sprintf("build:%d", i%2+1)
+		view.LogList.Spans[seg.SpanId] = &proto_webview.LogSpan{ManifestName: "foo"}
+	}
+	f.handle(view)
+
+	// The filter is consulted as lines arrive, so new spans are picked up.
+	spans["build:3"] = true
+	view = f.newViewWithLogsForManifest(alphabet[4:6], "foo", view.LogList.ToCheckpoint)
+	for _, seg := range view.LogList.Segments {
+		seg.SpanId = "build:3"
+		view.LogList.Spans[seg.SpanId] = &proto_webview.LogSpan{ManifestName: "foo"}
+	}
+	f.handle(view)
+
+	expected := f.expectedLinesWithPrefix([]string{"bravo", "delta", "echo", "foxtrot"}, "foo")
+	f.assertExpectedLogLines(expected)
+}
+
 type logStreamerFixture struct {
 	t          *testing.T
 	fakeStdout *bytes.Buffer
@@ -158,6 +226,11 @@ func (f *logStreamerFixture) withResourceNames(resourceNames ...string) *logStre
 	return f
 }
 
+func (f *logStreamerFixture) withOptions(options LogStreamOptions) *logStreamerFixture {
+	f.ls = NewFilteredLogStreamer(options, f.printer)
+	return f
+}
+
 func (f *logStreamerFixture) handle(view *proto_webview.View) {
 	err := f.ls.Handle(view)
 	require.NoError(f.t, err)
@@ -119,12 +119,26 @@ func (s *HeadsUpServer) Router() http.Handler {
 }
 
 func (s *HeadsUpServer) ViewJSON(w http.ResponseWriter, req *http.Request) {
+	logQuery, err := parseLogQuery(req.URL.Query())
+	if err != nil {
+		http.Error(w, fmt.Sprintf("Error parsing log query: %v", err), http.StatusBadRequest)
+		return
+	}
+
 	view, err := webview.CompleteView(req.Context(), s.ctrlClient, s.store)
 	if err != nil {
 		http.Error(w, fmt.Sprintf("Error converting view to proto: %v", err), http.StatusInternalServerError)
 		return
 	}
 
+	if logQuery != nil {
+		view.LogList, err = queryLogList(s.store, *logQuery)
+		if err != nil {
+			http.Error(w, fmt.Sprintf("Error querying logs: %v", err), http.StatusInternalServerError)
+			return
+		}
+	}
+
 	jsEncoder := &runtime.JSONPb{}
 
 	w.Header().Set("Content-Type", "application/json")
@@ -139,11 +153,25 @@ func (s *HeadsUpServer) DumpEngineJSON(w http.ResponseWriter, req *http.Request)
 	state := s.store.RLockState()
 	defer s.store.RUnlockState()
 
-	encoder := store.CreateEngineStateEncoder(w)
+	// The engine state includes the secrets themselves,
+	// so we scrub the whole output.
+	buf := &bytes.Buffer{}
+	encoder := store.CreateEngineStateEncoder(buf)
 	err := encoder.Encode(state)
 	if err != nil {
 		log.Printf("Error encoding: %v", err)
 	}
+
+	scrubbed, err := state.Secrets.ScrubJSON(buf.Bytes())
+	if err != nil {
+		http.Error(w, fmt.Sprintf("Error scrubbing secrets: %v", err), http.StatusInternalServerError)
+		return
+	}
+
+	_, err = w.Write(scrubbed)
+	if err != nil {
+		log.Printf("Error writing: %v", err)
+	}
 }
 
 func (s *HeadsUpServer) SnapshotJSON(w http.ResponseWriter, req *http.Request) {
@@ -247,16 +275,26 @@ func (s *HeadsUpServer) HandleTrigger(w http.ResponseWriter, req *http.Request)
 		return
 	}
 
-	if len(payload.ManifestNames) != 1 {
-		http.Error(w, fmt.Sprintf("/api/trigger currently supports exactly one manifest name, got %d", len(payload.ManifestNames)), http.StatusBadRequest)
+	if len(payload.ManifestNames) == 0 {
+		http.Error(w, "/api/trigger requires at least one manifest name", http.StatusBadRequest)
 		return
 	}
 
-	err = SendToTriggerQueue(s.store, payload.ManifestNames[0], payload.BuildReason)
+	// Check all the manifests up-front, so that we don't trigger
+	// some of them if the request is invalid.
+	err = checkManifestsExist(s.store, payload.ManifestNames)
 	if err != nil {
 		http.Error(w, err.Error(), http.StatusBadRequest)
 		return
 	}
+
+	for _, name := range payload.ManifestNames {
+		err = SendToTriggerQueue(s.store, name, payload.BuildReason)
+		if err != nil {
+			http.Error(w, err.Error(), http.StatusBadRequest)
+			return
+		}
+	}
 }
 
 func SendToTriggerQueue(st store.RStore, name string, buildReason model.BuildReason) error {
@@ -267,7 +305,7 @@ func SendToTriggerQueue(st store.RStore, name string, buildReason model.BuildRea
 		return err
 	}
 
-	st.Dispatch(AppendToTriggerQueueAction{Name: mName, Reason: buildReason})
+	st.Dispatch(store.AppendToTriggerQueueAction{Name: mName, Reason: buildReason})
 	return nil
 }
 
@@ -313,6 +351,8 @@ func (s *HeadsUpServer) HandleNewSnapshot(w http.ResponseWriter, req *http.Reque
 	st := s.store.RLockState()
 	token := st.Token
 	teamID := st.TeamID
+	secrets := model.SecretSet{}
+	secrets.AddAll(st.Secrets)
 	s.store.RUnlockState()
 
 	b, err := ioutil.ReadAll(req.Body)
@@ -323,6 +363,16 @@ func (s *HeadsUpServer) HandleNewSnapshot(w http.ResponseWriter, req *http.Reque
 		return
 	}
 
+	// The web UI may have received logs before we knew they had secrets
+	// (e.g., a Tiltfile registered a new secret), so scrub them again.
+	b, err = secrets.ScrubJSON(b)
+	if err != nil {
+		msg := fmt.Sprintf("Error scrubbing snapshot: %v", err)
+		log.Println(msg)
+		http.Error(w, msg, http.StatusInternalServerError)
+		return
+	}
+
 	jspb := &runtime.JSONPb{}
 	decoder := jspb.NewDecoder(bytes.NewBuffer(b))
 	var snapshot *proto_webview.Snapshot
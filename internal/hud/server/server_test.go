@@ -3,6 +3,7 @@ package server_test
 import (
 	"bytes"
 	"context"
+	"encoding/json"
 	"fmt"
 	"io"
 	"io/ioutil"
@@ -10,6 +11,7 @@ import (
 	"net/http/httptest"
 	"path/filepath"
 	"reflect"
+	"regexp"
 	"runtime"
 	"strings"
 	"testing"
@@ -33,6 +35,7 @@ import (
 	"github.com/tilt-dev/tilt/internal/store"
 	"github.com/tilt-dev/tilt/pkg/apis/core/v1alpha1"
 	"github.com/tilt-dev/tilt/pkg/assets"
+	"github.com/tilt-dev/tilt/pkg/logger"
 	"github.com/tilt-dev/tilt/pkg/model"
 	proto_webview "github.com/tilt-dev/tilt/pkg/webview"
 )
@@ -138,14 +141,52 @@ func TestHandleTriggerNoManifestWithName(t *testing.T) {
 	require.Contains(t, respBody, "no manifest found with name")
 }
 
-func TestHandleTriggerTooManyManifestNames(t *testing.T) {
+func TestHandleTriggerNoManifestNames(t *testing.T) {
 	f := newTestFixture(t)
 
+	payload := `{"manifest_names":[]}`
+	status, respBody := f.makeReq("/api/trigger", f.serv.HandleTrigger, http.MethodPost, payload)
+
+	require.Equal(t, http.StatusBadRequest, status, "handler returned wrong status code")
+	require.Contains(t, respBody, "requires at least one manifest name")
+}
+
+func TestHandleTriggerMultipleManifestNames(t *testing.T) {
+	f := newTestFixture(t)
+	state := f.st.LockMutableStateForTesting()
+	state.UpsertManifestTarget(&store.ManifestTarget{Manifest: model.Manifest{Name: "foo"}})
+	state.UpsertManifestTarget(&store.ManifestTarget{Manifest: model.Manifest{Name: "bar"}})
+	f.st.UnlockMutableState()
+
+	payload := `{"manifest_names":["foo", "bar"]}`
+	status, _ := f.makeReq("/api/trigger", f.serv.HandleTrigger, http.MethodPost, payload)
+	require.Equal(t, http.StatusOK, status, "handler returned wrong status code")
+
+	triggered := func() []model.ManifestName {
+		var result []model.ManifestName
+		for _, a := range f.getActions() {
+			if action, ok := a.(store.AppendToTriggerQueueAction); ok {
+				result = append(result, action.Name)
+			}
+		}
+		return result
+	}
+	require.Eventually(t, func() bool { return len(triggered()) == 2 }, time.Second, 10*time.Millisecond)
+	assert.Equal(t, []model.ManifestName{"foo", "bar"}, triggered())
+}
+
+func TestHandleTriggerMultipleManifestNamesOneMissing(t *testing.T) {
+	f := newTestFixture(t)
+	state := f.st.LockMutableStateForTesting()
+	state.UpsertManifestTarget(&store.ManifestTarget{Manifest: model.Manifest{Name: "foo"}})
+	f.st.UnlockMutableState()
+
 	payload := `{"manifest_names":["foo", "bar"]}`
 	status, respBody := f.makeReq("/api/trigger", f.serv.HandleTrigger, http.MethodPost, payload)
 
 	require.Equal(t, http.StatusBadRequest, status, "handler returned wrong status code")
-	require.Contains(t, respBody, "currently supports exactly one manifest name, got 2")
+	require.Contains(t, respBody, "no manifest found with name 'bar'")
+	assert.Empty(t, f.getActions())
 }
 
 func TestHandleTriggerNonPost(t *testing.T) {
@@ -197,8 +238,8 @@ func TestSendToTriggerQueue_manualManifest(t *testing.T) {
 		t.Fatal(err)
 	}
 
-	a := store.WaitForAction(t, reflect.TypeOf(server.AppendToTriggerQueueAction{}), f.getActions)
-	action, ok := a.(server.AppendToTriggerQueueAction)
+	a := store.WaitForAction(t, reflect.TypeOf(store.AppendToTriggerQueueAction{}), f.getActions)
+	action, ok := a.(store.AppendToTriggerQueueAction)
 	if !ok {
 		t.Fatalf("Action was not of type 'AppendToTriggerQueueAction': %+v", action)
 	}
@@ -224,8 +265,8 @@ func TestSendToTriggerQueue_automaticManifest(t *testing.T) {
 		t.Fatal(err)
 	}
 
-	a := store.WaitForAction(t, reflect.TypeOf(server.AppendToTriggerQueueAction{}), f.getActions)
-	action, ok := a.(server.AppendToTriggerQueueAction)
+	a := store.WaitForAction(t, reflect.TypeOf(store.AppendToTriggerQueueAction{}), f.getActions)
+	action, ok := a.(store.AppendToTriggerQueueAction)
 	if !ok {
 		t.Fatalf("Action was not of type 'AppendToTriggerQueueAction': %+v", action)
 	}
@@ -240,13 +281,13 @@ func TestSendToTriggerQueue_Tiltfile(t *testing.T) {
 		t.Fatal(err)
 	}
 
-	a := store.WaitForAction(t, reflect.TypeOf(server.AppendToTriggerQueueAction{}), f.getActions)
-	action, ok := a.(server.AppendToTriggerQueueAction)
+	a := store.WaitForAction(t, reflect.TypeOf(store.AppendToTriggerQueueAction{}), f.getActions)
+	action, ok := a.(store.AppendToTriggerQueueAction)
 	if !ok {
 		t.Fatalf("Action was not of type 'AppendToTriggreQueueAction': %+v", action)
 	}
 
-	expected := server.AppendToTriggerQueueAction{
+	expected := store.AppendToTriggerQueueAction{
 		Name:   model.MainTiltfileManifestName,
 		Reason: model.BuildReasonFlagTriggerWeb,
 	}
@@ -259,7 +300,7 @@ func TestSendToTriggerQueue_noManifestWithName(t *testing.T) {
 	err := server.SendToTriggerQueue(f.st, "foobar", model.BuildReasonFlagTriggerWeb)
 
 	assert.EqualError(t, err, "no manifest found with name 'foobar'")
-	store.AssertNoActionOfType(t, reflect.TypeOf(server.AppendToTriggerQueueAction{}), f.getActions)
+	store.AssertNoActionOfType(t, reflect.TypeOf(store.AppendToTriggerQueueAction{}), f.getActions)
 }
 
 func TestHandleOverrideTriggerModeReturnsErrorForBadManifest(t *testing.T) {
@@ -361,6 +402,64 @@ func TestHandleNewSnapshot(t *testing.T) {
 	}
 }
 
+func TestHandleNewSnapshotScrubsSecrets(t *testing.T) {
+	f := newTestFixture(t)
+
+	state := f.st.LockMutableStateForTesting()
+	state.Secrets.AddSecret("my-secret", "records", []byte("Update records"))
+	f.st.UnlockMutableState()
+
+	sp := filepath.Join("..", "webview", "testdata", "snapshot.json")
+	snap, err := ioutil.ReadFile(sp)
+	require.NoError(t, err)
+	require.Contains(t, string(snap), "Update records")
+
+	req, err := http.NewRequest(http.MethodPost, "/api/snapshot/new", bytes.NewBuffer(snap))
+	require.NoError(t, err)
+
+	rr := httptest.NewRecorder()
+	http.HandlerFunc(f.serv.HandleNewSnapshot).ServeHTTP(rr, req)
+	require.Equal(t, http.StatusOK, rr.Code)
+
+	lastReq := f.snapshotHTTP.lastReq
+	if assert.NotNil(t, lastReq) {
+		body, err := ioutil.ReadAll(lastReq.Body)
+		require.NoError(t, err)
+		assert.NotContains(t, string(body), "Update records")
+		assert.Contains(t, string(body), "[redacted secret my-secret:records]")
+	}
+}
+
+func TestDumpEngineJSONScrubsSecrets(t *testing.T) {
+	f := newTestFixture(t)
+
+	state := f.st.LockMutableStateForTesting()
+	state.Secrets.AddSecret("my-secret", "client-id", []byte("hunter22"))
+	f.st.UnlockMutableState()
+
+	status, body := f.makeReq("/api/dump/engine", f.serv.DumpEngineJSON, http.MethodGet, "")
+	require.Equal(t, http.StatusOK, status)
+	assert.NotContains(t, body, "hunter22")
+	assert.Contains(t, body, "[redacted secret my-secret:client-id]")
+}
+
+func TestDumpEngineJSONScrubsPatternSecrets(t *testing.T) {
+	f := newTestFixture(t)
+
+	state := f.st.LockMutableStateForTesting()
+	state.Secrets.AddPattern("secret_settings", regexp.MustCompile(`ghp_\w{10}`))
+	state.DesiredTiltfilePath = "/ghp_abcdefghij/Tiltfile"
+	f.st.UnlockMutableState()
+
+	status, body := f.makeReq("/api/dump/engine", f.serv.DumpEngineJSON, http.MethodGet, "")
+	require.Equal(t, http.StatusOK, status)
+	assert.NotContains(t, body, "ghp_abcdefghij")
+	assert.Contains(t, body, "[redacted secret secret_settings]")
+
+	var decoded map[string]interface{}
+	assert.NoError(t, json.Unmarshal([]byte(body), &decoded))
+}
+
 func TestSetTiltfileArgs(t *testing.T) {
 	f := newTestFixture(t)
 
@@ -384,6 +483,31 @@ func TestSetTiltfileArgs(t *testing.T) {
 	assert.Equal(t, []string{"--foo", "bar", "as df"}, action.Args)
 }
 
+func TestViewJSONLogQuery(t *testing.T) {
+	f := newTestFixture(t)
+
+	state := f.st.LockMutableStateForTesting()
+	state.LogStore.Append(store.NewLogAction("fe", "fe", logger.InfoLvl, nil, []byte("fe: starting\n")), nil)
+	state.LogStore.Append(store.NewLogAction("be", "be", logger.WarnLvl, nil, []byte("be: disk almost full\n")), nil)
+	state.LogStore.Append(store.NewLogAction("be", "be", logger.InfoLvl, nil, []byte("be: request ok\n")), nil)
+	f.st.UnlockMutableState()
+
+	code, body := f.makeReq("/api/view?manifest=be&level=warn", f.serv.ViewJSON, http.MethodGet, "")
+	require.Equal(t, http.StatusOK, code, body)
+
+	var view proto_webview.View
+	jspb := &grpcRuntime.JSONPb{}
+	err := jspb.NewDecoder(strings.NewReader(body)).Decode(&view)
+	require.NoError(t, err)
+	require.Len(t, view.LogList.Segments, 1)
+	assert.Equal(t, "WARNING: be: disk almost full\n", view.LogList.Segments[0].Text)
+	assert.Equal(t, "be", view.LogList.Spans[view.LogList.Segments[0].SpanId].ManifestName)
+
+	code, body = f.makeReq("/api/view?level=loud", f.serv.ViewJSON, http.MethodGet, "")
+	assert.Equal(t, http.StatusBadRequest, code)
+	assert.Contains(t, body, "unknown log level")
+}
+
 type serverFixture struct {
 	t            *testing.T
 	serv         *server.HeadsUpServer
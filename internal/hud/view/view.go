@@ -122,6 +122,7 @@ type Resource struct {
 	PendingBuildSince  time.Time
 
 	Endpoints []string
+	Labels    map[string]string
 
 	ResourceInfo ResourceInfoView
 
@@ -241,6 +242,24 @@ type ViewState struct {
 	TabState         TabState
 	SelectedIndex    int
 	TiltLogState     TiltLogState
+
+	// Only resources that match the filter are in the View.
+	ResourceFilter string
+
+	// The prompt that's waiting for input, if any.
+	Prompt *Prompt
+}
+
+// A prompt for input from the user, e.g., the command palette.
+type Prompt struct {
+	Title string
+	Input string
+
+	// If the prompt has choices, the input narrows them down,
+	// and the user picks the selected one.
+	HasChoices bool
+	Choices    []string
+	Selected   int
 }
 
 type TabState int
@@ -0,0 +1,91 @@
+package webview
+
+import (
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	"github.com/tilt-dev/tilt/pkg/apis/core/v1alpha1"
+)
+
+// Summarizes the UIResource status as conditions, so that tools
+// like `tilt wait --for=condition=Ready` can watch them.
+//
+// The LastTransitionTime is left empty. The caller is responsible for
+// filling it in from the previous conditions.
+func uiResourceConditions(s v1alpha1.UIResourceStatus) []v1alpha1.UIResourceCondition {
+	return []v1alpha1.UIResourceCondition{
+		uiResourceUpToDateCondition(s),
+		uiResourceReadyCondition(s),
+	}
+}
+
+func uiResourceUpToDateCondition(s v1alpha1.UIResourceStatus) v1alpha1.UIResourceCondition {
+	c := v1alpha1.UIResourceCondition{
+		Type:   v1alpha1.UIResourceUpToDate,
+		Status: metav1.ConditionUnknown,
+	}
+
+	if isDisabled(s) {
+		c.Status = metav1.ConditionFalse
+		c.Reason = "Disabled"
+		return c
+	}
+
+	switch s.UpdateStatus {
+	case v1alpha1.UpdateStatusOK, v1alpha1.UpdateStatusNotApplicable:
+		c.Status = metav1.ConditionTrue
+	case v1alpha1.UpdateStatusError:
+		c.Status = metav1.ConditionFalse
+		c.Reason = "UpdateError"
+		if len(s.BuildHistory) > 0 {
+			c.Message = s.BuildHistory[0].Error
+		}
+	case v1alpha1.UpdateStatusInProgress:
+		c.Status = metav1.ConditionFalse
+		c.Reason = "UpdateInProgress"
+	case v1alpha1.UpdateStatusPending:
+		c.Status = metav1.ConditionFalse
+		c.Reason = "UpdatePending"
+	case v1alpha1.UpdateStatusNone:
+		c.Status = metav1.ConditionFalse
+		c.Reason = "NeverUpdated"
+	}
+	return c
+}
+
+func uiResourceReadyCondition(s v1alpha1.UIResourceStatus) v1alpha1.UIResourceCondition {
+	c := v1alpha1.UIResourceCondition{
+		Type:   v1alpha1.UIResourceReady,
+		Status: metav1.ConditionUnknown,
+	}
+
+	if isDisabled(s) {
+		c.Status = metav1.ConditionFalse
+		c.Reason = "Disabled"
+		return c
+	}
+
+	switch s.RuntimeStatus {
+	case v1alpha1.RuntimeStatusOK:
+		c.Status = metav1.ConditionTrue
+	case v1alpha1.RuntimeStatusError:
+		c.Status = metav1.ConditionFalse
+		c.Reason = "RuntimeError"
+		if s.K8sResourceInfo != nil {
+			c.Message = s.K8sResourceInfo.PodStatusMessage
+		}
+	case v1alpha1.RuntimeStatusPending:
+		c.Status = metav1.ConditionFalse
+		c.Reason = "RuntimePending"
+	case v1alpha1.RuntimeStatusNotApplicable:
+		// Resources without a runtime are ready when they're up-to-date.
+		upToDate := uiResourceUpToDateCondition(s)
+		c.Status = upToDate.Status
+		c.Reason = upToDate.Reason
+		c.Message = upToDate.Message
+	}
+	return c
+}
+
+func isDisabled(s v1alpha1.UIResourceStatus) bool {
+	return s.DisableStatus.DisabledCount > 0 && s.DisableStatus.EnabledCount == 0
+}
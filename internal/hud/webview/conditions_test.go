@@ -0,0 +1,91 @@
+package webview
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	"github.com/tilt-dev/tilt/pkg/apis/core/v1alpha1"
+)
+
+func TestUIResourceConditions(t *testing.T) {
+	for _, tc := range []struct {
+		name           string
+		status         v1alpha1.UIResourceStatus
+		upToDate       metav1.ConditionStatus
+		ready          metav1.ConditionStatus
+		readyReason    string
+		upToDateReason string
+	}{
+		{
+			name: "server running",
+			status: v1alpha1.UIResourceStatus{
+				UpdateStatus:  v1alpha1.UpdateStatusOK,
+				RuntimeStatus: v1alpha1.RuntimeStatusOK,
+			},
+			upToDate: metav1.ConditionTrue,
+			ready:    metav1.ConditionTrue,
+		},
+		{
+			name: "server pending",
+			status: v1alpha1.UIResourceStatus{
+				UpdateStatus:  v1alpha1.UpdateStatusOK,
+				RuntimeStatus: v1alpha1.RuntimeStatusPending,
+			},
+			upToDate:    metav1.ConditionTrue,
+			ready:       metav1.ConditionFalse,
+			readyReason: "RuntimePending",
+		},
+		{
+			name: "server crashing",
+			status: v1alpha1.UIResourceStatus{
+				UpdateStatus:  v1alpha1.UpdateStatusOK,
+				RuntimeStatus: v1alpha1.RuntimeStatusError,
+			},
+			upToDate:    metav1.ConditionTrue,
+			ready:       metav1.ConditionFalse,
+			readyReason: "RuntimeError",
+		},
+		{
+			name: "job succeeded",
+			status: v1alpha1.UIResourceStatus{
+				UpdateStatus:  v1alpha1.UpdateStatusOK,
+				RuntimeStatus: v1alpha1.RuntimeStatusNotApplicable,
+			},
+			upToDate: metav1.ConditionTrue,
+			ready:    metav1.ConditionTrue,
+		},
+		{
+			name: "job failed",
+			status: v1alpha1.UIResourceStatus{
+				UpdateStatus:  v1alpha1.UpdateStatusError,
+				RuntimeStatus: v1alpha1.RuntimeStatusNotApplicable,
+			},
+			upToDate:       metav1.ConditionFalse,
+			upToDateReason: "UpdateError",
+			ready:          metav1.ConditionFalse,
+			readyReason:    "UpdateError",
+		},
+		{
+			name: "disabled",
+			status: v1alpha1.UIResourceStatus{
+				UpdateStatus:  v1alpha1.UpdateStatusOK,
+				RuntimeStatus: v1alpha1.RuntimeStatusOK,
+				DisableStatus: v1alpha1.DisableResourceStatus{DisabledCount: 1},
+			},
+			upToDate:       metav1.ConditionFalse,
+			upToDateReason: "Disabled",
+			ready:          metav1.ConditionFalse,
+			readyReason:    "Disabled",
+		},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			conditions := uiResourceConditions(tc.status)
+			assert.Equal(t, []v1alpha1.UIResourceCondition{
+				{Type: v1alpha1.UIResourceUpToDate, Status: tc.upToDate, Reason: tc.upToDateReason},
+				{Type: v1alpha1.UIResourceReady, Status: tc.ready, Reason: tc.readyReason},
+			}, conditions)
+		})
+	}
+}
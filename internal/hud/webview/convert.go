@@ -295,6 +295,7 @@ func toUIResource(mt *store.ManifestTarget, s store.EngineState, disableSources
 	if err != nil {
 		return nil, err
 	}
+	r.Status.Conditions = uiResourceConditions(r.Status)
 	return r, nil
 }
 
@@ -327,6 +328,7 @@ func TiltfileResourceProtoView(name model.ManifestName, ms *store.ManifestState,
 	} else {
 		tr.Status.LastDeployTime = finish
 	}
+	tr.Status.Conditions = uiResourceConditions(tr.Status)
 	return tr
 }
 
@@ -381,9 +383,11 @@ func LogSegmentToEvent(seg *proto_webview.LogSegment, spans map[string]*proto_we
 		return store.LogAction{}
 	}
 
-	// TODO(maia): actually get level (just spoofing for now)
-	spoofedLevel := logger.InfoLvl
-	return store.NewLogAction(model.ManifestName(span.ManifestName), logstore.SpanID(seg.SpanId), spoofedLevel, seg.Fields, []byte(seg.Text))
+	level := logger.InfoLvl
+	if seg.Level != proto_webview.LogLevel_NONE {
+		level = logger.LevelFromProtoID(int32(seg.Level))
+	}
+	return store.NewLogAction(model.ManifestName(span.ManifestName), logstore.SpanID(seg.SpanId), level, seg.Fields, []byte(seg.Text))
 }
 
 func holdToWaiting(hold store.Hold) *v1alpha1.UIResourceStateWaiting {
@@ -17,6 +17,7 @@ import (
 	"k8s.io/apimachinery/pkg/api/meta"
 	"k8s.io/apimachinery/pkg/api/validation"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/labels"
 	"k8s.io/apimachinery/pkg/runtime/schema"
 	utilerrors "k8s.io/apimachinery/pkg/util/errors"
 	"k8s.io/apimachinery/pkg/version"
@@ -38,6 +39,7 @@ import (
 	_ "k8s.io/client-go/plugin/pkg/client/auth"
 
 	"github.com/tilt-dev/tilt/internal/container"
+	"github.com/tilt-dev/tilt/pkg/apis/core/v1alpha1"
 	"github.com/tilt-dev/tilt/pkg/logger"
 )
 
@@ -80,13 +82,38 @@ type Client interface {
 	// than they were passed in) and with UUIDs from the Kube API
 	Upsert(ctx context.Context, entities []K8sEntity, timeout time.Duration) ([]K8sEntity, error)
 
+	// Like Upsert, but updates the entities with server-side apply,
+	// as the "tilt" field manager.
+	//
+	// Tilt forces conflicts, so any fields that Tilt applies
+	// are taken over from other field managers.
+	ServerSideApply(ctx context.Context, entities []K8sEntity, timeout time.Duration) ([]K8sEntity, error)
+
 	// Deletes all given entities.
 	//
 	// Currently ignores any "not found" errors, because that seems like the correct
 	// behavior for our use cases.
 	Delete(ctx context.Context, entities []K8sEntity) error
 
+	// Installs or upgrades a Helm release with the Helm SDK.
+	//
+	// postRender (if non-nil) modifies the rendered objects before they're applied.
+	//
+	// Returns the objects in the release, with UUIDs from the Kube API.
+	UpsertHelmRelease(ctx context.Context, release v1alpha1.KubernetesHelmRelease, timeout time.Duration, postRender PostRenderFunc) ([]K8sEntity, error)
+
+	// Uninstalls a Helm release.
+	//
+	// Ignores releases that don't exist.
+	UninstallHelmRelease(ctx context.Context, release v1alpha1.KubernetesHelmRelease, timeout time.Duration) error
+
 	GetMetaByReference(ctx context.Context, ref v1.ObjectReference) (metav1.Object, error)
+
+	// Fetches the full object from the cluster.
+	//
+	// Returns a NotFound error if the object doesn't exist, or if its UID
+	// doesn't match the reference's UID.
+	GetByReference(ctx context.Context, ref v1.ObjectReference) (K8sEntity, error)
 	ListMeta(ctx context.Context, gvk schema.GroupVersionKind, ns Namespace) ([]metav1.Object, error)
 
 	// Streams the container logs
@@ -106,6 +133,15 @@ type Client interface {
 	NodeIP(ctx context.Context) NodeIP
 
 	Exec(ctx context.Context, podID PodID, cName container.Name, n Namespace, cmd []string, stdin io.Reader, stdout io.Writer, stderr io.Writer) error
+
+	// Adds an ephemeral container to the pod (unless it already has an ephemeral
+	// container with the same name), and waits for it to start running.
+	EnsureEphemeralContainer(ctx context.Context, podID PodID, n Namespace, ec v1.EphemeralContainer) error
+
+	GetService(ctx context.Context, n Namespace, name string) (*v1.Service, error)
+
+	// Lists the pods in the namespace that match the label selector.
+	ListPods(ctx context.Context, n Namespace, selector labels.Selector) ([]v1.Pod, error)
 }
 
 type RESTMapper interface {
@@ -411,7 +447,14 @@ func (k *K8sClient) helmResultToEntities(result *kube.Result) ([]K8sEntity, erro
 	}
 
 	// Helm parses the results as unstructured info, but Tilt needs them parsed with the current
-	// API scheme. The easiest way to do this is to serialize them to yaml and re-parse again.
+	// API scheme.
+	return reparseEntities(entities)
+}
+
+// Re-parses unstructured entities with the current API scheme.
+//
+// The easiest way to do this is to serialize them to yaml and re-parse again.
+func reparseEntities(entities []K8sEntity) ([]K8sEntity, error) {
 	buf, err := SerializeSpecYAMLToBuffer(entities)
 	if err != nil {
 		return nil, errors.Wrap(err, "reading kubernetes result")
@@ -425,6 +468,20 @@ func (k *K8sClient) helmResultToEntities(result *kube.Result) ([]K8sEntity, erro
 }
 
 func (k *K8sClient) deleteAndCreate(list kube.ResourceList) (*kube.Result, error) {
+	err := k.deleteAndWait(list)
+	if err != nil {
+		return nil, err
+	}
+
+	result, err := k.resourceClient.Create(list)
+	if err != nil {
+		return nil, errors.Wrap(err, "kubernetes create")
+	}
+	return result, nil
+}
+
+// Deletes the resources, and waits for them to disappear.
+func (k *K8sClient) deleteAndWait(list kube.ResourceList) error {
 	// Delete is destructive, so clone first.
 	toDelete := kube.ResourceList{}
 	for _, r := range list {
@@ -438,7 +495,7 @@ func (k *K8sClient) deleteAndCreate(list kube.ResourceList) (*kube.Result, error
 		if isNotFoundError(err) {
 			continue
 		}
-		return nil, errors.Wrap(err, "kubernetes delete")
+		return errors.Wrap(err, "kubernetes delete")
 	}
 
 	var wg sync.WaitGroup
@@ -460,12 +517,7 @@ func (k *K8sClient) deleteAndCreate(list kube.ResourceList) (*kube.Result, error
 	}
 
 	wg.Wait()
-
-	result, err := k.resourceClient.Create(list)
-	if err != nil {
-		return nil, errors.Wrap(err, "kubernetes create")
-	}
-	return result, nil
+	return nil
 }
 
 // Update a resource in-place, starting with the least intrusive
@@ -561,6 +613,15 @@ func (k *K8sClient) Delete(ctx context.Context, entities []K8sEntity) error {
 }
 
 func (k *K8sClient) forceDiscovery(ctx context.Context, gvk schema.GroupVersionKind) (schema.GroupVersionResource, error) {
+	rm, err := k.forceDiscoveryMapping(ctx, gvk)
+	if err != nil {
+		return schema.GroupVersionResource{}, err
+	}
+	return rm.Resource, nil
+}
+
+// Like forceDiscovery, but returns the full mapping, including whether the type is namespaced.
+func (k *K8sClient) forceDiscoveryMapping(ctx context.Context, gvk schema.GroupVersionKind) (*meta.RESTMapping, error) {
 	rm, err := k.drm.RESTMapping(gvk.GroupKind(), gvk.Version)
 	if err != nil {
 		// The REST mapper doesn't have any sort of internal invalidation
@@ -576,10 +637,10 @@ func (k *K8sClient) forceDiscovery(ctx context.Context, gvk schema.GroupVersionK
 
 		rm, err = k.drm.RESTMapping(gvk.GroupKind(), gvk.Version)
 		if err != nil {
-			return schema.GroupVersionResource{}, errors.Wrapf(err, "error mapping %s/%s", gvk.Group, gvk.Kind)
+			return nil, errors.Wrapf(err, "error mapping %s/%s", gvk.Group, gvk.Kind)
 		}
 	}
-	return rm.Resource, nil
+	return rm, nil
 }
 
 func (k *K8sClient) ListMeta(ctx context.Context, gvk schema.GroupVersionKind, ns Namespace) ([]metav1.Object, error) {
@@ -627,6 +688,31 @@ func (k *K8sClient) GetMetaByReference(ctx context.Context, ref v1.ObjectReferen
 	return &meta, nil
 }
 
+func (k *K8sClient) GetByReference(ctx context.Context, ref v1.ObjectReference) (K8sEntity, error) {
+	gvk := ReferenceGVK(ref)
+	gvr, err := k.forceDiscovery(ctx, gvk)
+	if err != nil {
+		return K8sEntity{}, err
+	}
+
+	obj, err := k.dynamic.Resource(gvr).Namespace(ref.Namespace).Get(ctx, ref.Name, metav1.GetOptions{})
+	if err != nil {
+		return K8sEntity{}, err
+	}
+	if ref.UID != "" && obj.GetUID() != ref.UID {
+		return K8sEntity{}, apierrors.NewNotFound(v1.Resource(gvr.Resource), ref.Name)
+	}
+
+	parsed, err := reparseEntities([]K8sEntity{NewK8sEntity(obj)})
+	if err != nil {
+		return K8sEntity{}, err
+	}
+	if len(parsed) != 1 {
+		return K8sEntity{}, fmt.Errorf("internal error: expected 1 object, got %d", len(parsed))
+	}
+	return parsed[0], nil
+}
+
 // Tests whether a string is a valid version for a k8s resource type.
 // from https://kubernetes.io/docs/tasks/access-kubernetes-api/custom-resources/custom-resource-definition-versioning/#version-priority
 // Versions start with a v followed by a number, an optional beta or alpha designation, and optional additional numeric
@@ -0,0 +1,218 @@
+package k8s
+
+import (
+	"fmt"
+
+	v1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime"
+)
+
+// A named group of entities that are deployed together (e.g., the entities of
+// one Tilt resource).
+type EntityGroup struct {
+	Name     string
+	Entities []K8sEntity
+}
+
+// Identifies an object that other objects may need to exist before they're
+// created, e.g., "Namespace/frontend" or "ConfigMap/frontend/settings".
+type dependencyKey string
+
+func namespaceKey(ns string) dependencyKey {
+	return dependencyKey(fmt.Sprintf("Namespace/%s", ns))
+}
+
+func crdKey(group, kind string) dependencyKey {
+	return dependencyKey(fmt.Sprintf("CustomResourceDefinition/%s/%s", group, kind))
+}
+
+func namespacedKey(kind, ns, name string) dependencyKey {
+	return dependencyKey(fmt.Sprintf("%s/%s/%s", kind, ns, name))
+}
+
+// InferGroupDependencies analyzes the entities in each group, and returns a map
+// from the name of each group to the names of the groups it depends on.
+//
+// A group depends on another group if it contains an object that can't be
+// created (or can't start) until an object in the other group exists:
+//
+// - Namespaced objects depend on their Namespace.
+// - Custom resources depend on their CustomResourceDefinition.
+// - Pod templates depend on the ConfigMaps, Secrets, PersistentVolumeClaims,
+//   and ServiceAccounts they reference.
+//
+// Groups should be listed in the order they'd normally deploy. If the objects
+// depend on each other in a cycle, the dependencies of earlier groups on later
+// groups are dropped, so that the result can always be scheduled.
+func InferGroupDependencies(groups []EntityGroup) map[string][]string {
+	providers := make(map[dependencyKey][]int)
+	for i, g := range groups {
+		for _, e := range g.Entities {
+			for _, key := range providedDependencyKeys(e) {
+				providers[key] = append(providers[key], i)
+			}
+		}
+	}
+
+	edges := make([]map[int]bool, len(groups))
+	for i := range edges {
+		edges[i] = make(map[int]bool)
+	}
+
+	// Returns true if there's a path from start to end.
+	var reaches func(start, end int, visited map[int]bool) bool
+	reaches = func(start, end int, visited map[int]bool) bool {
+		if start == end {
+			return true
+		}
+		if visited[start] {
+			return false
+		}
+		visited[start] = true
+		for next := range edges[start] {
+			if reaches(next, end, visited) {
+				return true
+			}
+		}
+		return false
+	}
+
+	result := make(map[string][]string)
+	for i := len(groups) - 1; i >= 0; i-- {
+		g := groups[i]
+		for _, e := range g.Entities {
+			for _, key := range requiredDependencyKeys(e) {
+				for _, j := range providers[key] {
+					if edges[i][j] || reaches(j, i, make(map[int]bool)) {
+						continue
+					}
+					edges[i][j] = true
+					result[g.Name] = append(result[g.Name], groups[j].Name)
+				}
+			}
+		}
+	}
+	return result
+}
+
+// The objects that this entity creates that other objects might depend on.
+func providedDependencyKeys(e K8sEntity) []dependencyKey {
+	kind := e.GVK().Kind
+	switch kind {
+	case "Namespace":
+		return []dependencyKey{namespaceKey(e.Name())}
+	case "CustomResourceDefinition":
+		content, err := unstructuredContent(e)
+		if err != nil {
+			return nil
+		}
+		group, _, _ := unstructured.NestedString(content, "spec", "group")
+		crdKind, _, _ := unstructured.NestedString(content, "spec", "names", "kind")
+		if group == "" || crdKind == "" {
+			return nil
+		}
+		return []dependencyKey{crdKey(group, crdKind)}
+	case "ConfigMap", "Secret", "PersistentVolumeClaim", "ServiceAccount":
+		return []dependencyKey{namespacedKey(kind, e.Namespace().String(), e.Name())}
+	}
+	return nil
+}
+
+// The objects that must exist before this entity can be created.
+func requiredDependencyKeys(e K8sEntity) []dependencyKey {
+	var result []dependencyKey
+	ns := e.Namespace().String()
+	if ns != "" && e.GVK().Kind != "Namespace" {
+		result = append(result, namespaceKey(ns))
+	}
+
+	gvk := e.GVK()
+	if gvk.Group != "" {
+		result = append(result, crdKey(gvk.Group, gvk.Kind))
+	}
+
+	podSpecs, err := ExtractPods(&e)
+	if err != nil {
+		return result
+	}
+	for _, spec := range podSpecs {
+		result = append(result, podSpecDependencyKeys(ns, spec)...)
+	}
+	return result
+}
+
+func podSpecDependencyKeys(ns string, spec *v1.PodSpec) []dependencyKey {
+	var result []dependencyKey
+	configMap := func(name string) {
+		if name != "" {
+			result = append(result, namespacedKey("ConfigMap", ns, name))
+		}
+	}
+	secret := func(name string) {
+		if name != "" {
+			result = append(result, namespacedKey("Secret", ns, name))
+		}
+	}
+
+	if spec.ServiceAccountName != "" {
+		result = append(result, namespacedKey("ServiceAccount", ns, spec.ServiceAccountName))
+	}
+
+	for _, s := range spec.ImagePullSecrets {
+		secret(s.Name)
+	}
+
+	for _, v := range spec.Volumes {
+		if v.ConfigMap != nil {
+			configMap(v.ConfigMap.Name)
+		}
+		if v.Secret != nil {
+			secret(v.Secret.SecretName)
+		}
+		if v.PersistentVolumeClaim != nil && v.PersistentVolumeClaim.ClaimName != "" {
+			result = append(result, namespacedKey("PersistentVolumeClaim", ns, v.PersistentVolumeClaim.ClaimName))
+		}
+		if v.Projected != nil {
+			for _, source := range v.Projected.Sources {
+				if source.ConfigMap != nil {
+					configMap(source.ConfigMap.Name)
+				}
+				if source.Secret != nil {
+					secret(source.Secret.Name)
+				}
+			}
+		}
+	}
+
+	containers := append(append([]v1.Container{}, spec.InitContainers...), spec.Containers...)
+	for _, c := range containers {
+		for _, envFrom := range c.EnvFrom {
+			if envFrom.ConfigMapRef != nil {
+				configMap(envFrom.ConfigMapRef.Name)
+			}
+			if envFrom.SecretRef != nil {
+				secret(envFrom.SecretRef.Name)
+			}
+		}
+		for _, env := range c.Env {
+			if env.ValueFrom == nil {
+				continue
+			}
+			if env.ValueFrom.ConfigMapKeyRef != nil {
+				configMap(env.ValueFrom.ConfigMapKeyRef.Name)
+			}
+			if env.ValueFrom.SecretKeyRef != nil {
+				secret(env.ValueFrom.SecretKeyRef.Name)
+			}
+		}
+	}
+	return result
+}
+
+func unstructuredContent(e K8sEntity) (map[string]interface{}, error) {
+	if u, ok := e.Obj.(runtime.Unstructured); ok {
+		return u.UnstructuredContent(), nil
+	}
+	return runtime.DefaultUnstructuredConverter.ToUnstructured(e.Obj)
+}
@@ -0,0 +1,122 @@
+package k8s
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/tilt-dev/tilt/internal/k8s/testyaml"
+)
+
+const depsWorkloadYAML = `apiVersion: apps/v1
+kind: Deployment
+metadata:
+  name: fe
+  namespace: mynamespace
+spec:
+  selector:
+    matchLabels:
+      app: fe
+  template:
+    metadata:
+      labels:
+        app: fe
+    spec:
+      serviceAccountName: fe-sa
+      containers:
+      - name: fe
+        image: fe
+        envFrom:
+        - configMapRef:
+            name: fe-config
+        env:
+        - name: PASSWORD
+          valueFrom:
+            secretKeyRef:
+              name: fe-secret
+              key: password
+      volumes:
+      - name: data
+        persistentVolumeClaim:
+          claimName: fe-data
+`
+
+const depsConfigYAML = `apiVersion: v1
+kind: ConfigMap
+metadata:
+  name: fe-config
+  namespace: mynamespace
+---
+apiVersion: v1
+kind: ServiceAccount
+metadata:
+  name: fe-sa
+  namespace: mynamespace
+`
+
+const depsSecretYAML = `apiVersion: v1
+kind: Secret
+metadata:
+  name: fe-secret
+  namespace: mynamespace
+---
+apiVersion: v1
+kind: PersistentVolumeClaim
+metadata:
+  name: fe-data
+  namespace: mynamespace
+`
+
+const depsUnrelatedYAML = `apiVersion: v1
+kind: ConfigMap
+metadata:
+  name: fe-config
+  namespace: othernamespace
+`
+
+func TestInferGroupDependenciesWorkload(t *testing.T) {
+	groups := []EntityGroup{
+		testEntityGroup(t, "uncategorized", testyaml.MyNamespaceYAML),
+		testEntityGroup(t, "config", depsConfigYAML),
+		testEntityGroup(t, "secret", depsSecretYAML),
+		testEntityGroup(t, "unrelated", depsUnrelatedYAML),
+		testEntityGroup(t, "fe", depsWorkloadYAML),
+	}
+
+	deps := InferGroupDependencies(groups)
+	assert.ElementsMatch(t, []string{"uncategorized", "config", "secret"}, deps["fe"])
+	assert.Equal(t, []string{"uncategorized"}, deps["config"])
+	assert.Equal(t, []string{"uncategorized"}, deps["secret"])
+	assert.Empty(t, deps["unrelated"])
+	assert.Empty(t, deps["uncategorized"])
+}
+
+func TestInferGroupDependenciesCRD(t *testing.T) {
+	entities, err := ParseYAMLFromString(testyaml.CRDYAML)
+	require.NoError(t, err)
+	require.Len(t, entities, 2)
+
+	groups := []EntityGroup{
+		{Name: "crd", Entities: entities[:1]},
+		{Name: "cr", Entities: entities[1:]},
+	}
+	deps := InferGroupDependencies(groups)
+	assert.Equal(t, map[string][]string{"cr": {"crd"}}, deps)
+}
+
+func TestInferGroupDependenciesBreaksCycles(t *testing.T) {
+	groups := []EntityGroup{
+		testEntityGroup(t, "a", testyaml.MyNamespaceYAML+"---\n"+depsWorkloadYAML),
+		testEntityGroup(t, "b", depsConfigYAML),
+	}
+
+	deps := InferGroupDependencies(groups)
+	assert.Equal(t, map[string][]string{"b": {"a"}}, deps)
+}
+
+func testEntityGroup(t *testing.T, name string, yaml string) EntityGroup {
+	entities, err := ParseYAMLFromString(yaml)
+	require.NoError(t, err)
+	return EntityGroup{Name: name, Entities: entities}
+}
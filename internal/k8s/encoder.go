@@ -6,7 +6,6 @@ import (
 	jsoniter "github.com/json-iterator/go"
 	"github.com/modern-go/reflect2"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
-	"k8s.io/client-go/kubernetes/scheme"
 )
 
 var defaultJSONIterator = createDefaultJSONIterator()
@@ -39,7 +38,7 @@ func createSpecJSONIterator() jsoniter.API {
 
 func allStatusTypes() []reflect2.Type {
 	result := []reflect2.Type{}
-	for _, typ := range scheme.Scheme.AllKnownTypes() {
+	for _, typ := range decodeScheme.AllKnownTypes() {
 		typ2 := reflect2.Type2(typ)
 
 		sTyp2, ok := typ2.(reflect2.StructType)
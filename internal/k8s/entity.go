@@ -9,7 +9,6 @@ import (
 	"k8s.io/apimachinery/pkg/api/meta"
 	"k8s.io/apimachinery/pkg/runtime/schema"
 	"k8s.io/apimachinery/pkg/types"
-	"k8s.io/client-go/kubernetes/scheme"
 
 	"github.com/tilt-dev/tilt/internal/kustomize"
 
@@ -87,7 +86,7 @@ func (e K8sEntity) GVK() schema.GroupVersionKind {
 		// On typed go objects, the GVK is usually empty by convention, so we grab it from the Scheme
 		// See https://github.com/kubernetes/kubernetes/pull/59264#issuecomment-362575608
 		// for discussion on why the API behaves this way.
-		gvks, _, _ := scheme.Scheme.ObjectKinds(e.Obj)
+		gvks, _, _ := decodeScheme.ObjectKinds(e.Obj)
 		if len(gvks) > 0 {
 			return gvks[0]
 		}
@@ -2,9 +2,14 @@ package k8s
 
 import (
 	"context"
+	"fmt"
 	"io"
+	"time"
 
+	"github.com/pkg/errors"
 	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/util/wait"
 	"k8s.io/client-go/tools/remotecommand"
 	"k8s.io/kubectl/pkg/scheme"
 
@@ -37,3 +42,50 @@ func (k *K8sClient) Exec(ctx context.Context, podID PodID, cName container.Name,
 		Stderr: stderr,
 	})
 }
+
+// How long to wait for an ephemeral container to start (including pulling its image).
+const ephemeralContainerStartTimeout = time.Minute
+
+func (k *K8sClient) EnsureEphemeralContainer(ctx context.Context, podID PodID, n Namespace, ec corev1.EphemeralContainer) error {
+	pods := k.core.Pods(n.String())
+	pod, err := pods.Get(ctx, podID.String(), metav1.GetOptions{})
+	if err != nil {
+		return errors.Wrap(err, "getting pod")
+	}
+
+	exists := false
+	for _, existing := range pod.Spec.EphemeralContainers {
+		if existing.Name == ec.Name {
+			exists = true
+			break
+		}
+	}
+
+	if !exists {
+		pod.Spec.EphemeralContainers = append(pod.Spec.EphemeralContainers, ec)
+		_, err = pods.UpdateEphemeralContainers(ctx, podID.String(), pod, metav1.UpdateOptions{})
+		if err != nil {
+			return errors.Wrap(err, "adding ephemeral container")
+		}
+	}
+
+	return wait.PollImmediate(250*time.Millisecond, ephemeralContainerStartTimeout, func() (bool, error) {
+		pod, err := pods.Get(ctx, podID.String(), metav1.GetOptions{})
+		if err != nil {
+			return false, errors.Wrap(err, "getting pod")
+		}
+
+		for _, status := range pod.Status.EphemeralContainerStatuses {
+			if status.Name != ec.Name {
+				continue
+			}
+			if status.State.Running != nil {
+				return true, nil
+			}
+			if t := status.State.Terminated; t != nil {
+				return false, fmt.Errorf("ephemeral container %s terminated: %s", ec.Name, t.Reason)
+			}
+		}
+		return false, nil
+	})
+}
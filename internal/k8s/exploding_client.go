@@ -9,10 +9,12 @@ import (
 	"github.com/pkg/errors"
 	v1 "k8s.io/api/core/v1"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/labels"
 	"k8s.io/apimachinery/pkg/runtime/schema"
 	"k8s.io/apimachinery/pkg/types"
 
 	"github.com/tilt-dev/tilt/internal/container"
+	"github.com/tilt-dev/tilt/pkg/apis/core/v1alpha1"
 	"github.com/tilt-dev/tilt/pkg/model"
 )
 
@@ -26,14 +28,30 @@ func (ec *explodingClient) Upsert(ctx context.Context, entities []K8sEntity, tim
 	return nil, errors.Wrap(ec.err, "could not set up k8s client")
 }
 
+func (ec *explodingClient) ServerSideApply(ctx context.Context, entities []K8sEntity, timeout time.Duration) ([]K8sEntity, error) {
+	return nil, errors.Wrap(ec.err, "could not set up k8s client")
+}
+
 func (ec *explodingClient) Delete(ctx context.Context, entities []K8sEntity) error {
 	return errors.Wrap(ec.err, "could not set up k8s client")
 }
 
+func (ec *explodingClient) UpsertHelmRelease(ctx context.Context, release v1alpha1.KubernetesHelmRelease, timeout time.Duration, postRender PostRenderFunc) ([]K8sEntity, error) {
+	return nil, errors.Wrap(ec.err, "could not set up k8s client")
+}
+
+func (ec *explodingClient) UninstallHelmRelease(ctx context.Context, release v1alpha1.KubernetesHelmRelease, timeout time.Duration) error {
+	return errors.Wrap(ec.err, "could not set up k8s client")
+}
+
 func (ec *explodingClient) GetMetaByReference(ctx context.Context, ref v1.ObjectReference) (metav1.Object, error) {
 	return nil, errors.Wrap(ec.err, "could not set up k8s client")
 }
 
+func (ec *explodingClient) GetByReference(ctx context.Context, ref v1.ObjectReference) (K8sEntity, error) {
+	return K8sEntity{}, errors.Wrap(ec.err, "could not set up k8s client")
+}
+
 func (ec *explodingClient) ListMeta(ctx context.Context, gvk schema.GroupVersionKind, ns Namespace) ([]metav1.Object, error) {
 	return nil, errors.Wrap(ec.err, "could not set up k8s client")
 }
@@ -89,3 +107,15 @@ func (ec *explodingClient) NodeIP(ctx context.Context) NodeIP {
 func (ec *explodingClient) Exec(ctx context.Context, podID PodID, cName container.Name, n Namespace, cmd []string, stdin io.Reader, stdout io.Writer, stderr io.Writer) error {
 	return errors.Wrap(ec.err, "could not set up k8s client")
 }
+
+func (ec *explodingClient) EnsureEphemeralContainer(ctx context.Context, podID PodID, n Namespace, c v1.EphemeralContainer) error {
+	return errors.Wrap(ec.err, "could not set up k8s client")
+}
+
+func (ec *explodingClient) GetService(ctx context.Context, n Namespace, name string) (*v1.Service, error) {
+	return nil, errors.Wrap(ec.err, "could not set up k8s client")
+}
+
+func (ec *explodingClient) ListPods(ctx context.Context, n Namespace, selector labels.Selector) ([]v1.Pod, error) {
+	return nil, errors.Wrap(ec.err, "could not set up k8s client")
+}
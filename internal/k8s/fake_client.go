@@ -4,8 +4,10 @@ import (
 	"context"
 	"io"
 	"io/ioutil"
+	"sort"
 	"strings"
 	"sync"
+	"sync/atomic"
 	"testing"
 	"time"
 
@@ -15,10 +17,12 @@ import (
 	v1 "k8s.io/api/core/v1"
 	apierrors "k8s.io/apimachinery/pkg/api/errors"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/labels"
 	"k8s.io/apimachinery/pkg/runtime/schema"
 	"k8s.io/apimachinery/pkg/types"
 
 	"github.com/tilt-dev/tilt/internal/container"
+	"github.com/tilt-dev/tilt/pkg/apis/core/v1alpha1"
 	"github.com/tilt-dev/tilt/pkg/logger"
 )
 
@@ -68,9 +72,15 @@ type FakeK8sClient struct {
 
 	EventsWatchErr error
 
-	UpsertError      error
-	LastUpsertResult []K8sEntity
-	UpsertTimeout    time.Duration
+	UpsertError          error
+	LastUpsertResult     []K8sEntity
+	LastUpsertServerSide bool
+	UpsertTimeout        time.Duration
+
+	// The rendered objects of every Helm release that's upserted.
+	HelmReleaseYAML         string
+	LastHelmRelease         *v1alpha1.KubernetesHelmRelease
+	UninstalledHelmReleases []v1alpha1.KubernetesHelmRelease
 
 	Runtime    container.Runtime
 	Registry   container.Registry
@@ -92,6 +102,15 @@ type FakeK8sClient struct {
 	ExecCalls   []ExecCall
 	ExecOutputs []io.Reader
 	ExecErrors  []error
+
+	EphemeralContainers    []EphemeralContainerCall
+	EphemeralContainerErrs []error
+}
+
+type EphemeralContainerCall struct {
+	PID       PodID
+	Ns        Namespace
+	Container v1.EphemeralContainer
 }
 
 type ExecCall struct {
@@ -348,6 +367,19 @@ func (c *FakeK8sClient) Upsert(ctx context.Context, entities []K8sEntity, timeou
 	c.mu.Lock()
 	defer c.mu.Unlock()
 
+	c.LastUpsertServerSide = false
+	return c.upsert(entities, timeout)
+}
+
+func (c *FakeK8sClient) ServerSideApply(ctx context.Context, entities []K8sEntity, timeout time.Duration) ([]K8sEntity, error) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	c.LastUpsertServerSide = true
+	return c.upsert(entities, timeout)
+}
+
+func (c *FakeK8sClient) upsert(entities []K8sEntity, timeout time.Duration) ([]K8sEntity, error) {
 	if c.UpsertError != nil {
 		return nil, c.UpsertError
 	}
@@ -370,6 +402,35 @@ func (c *FakeK8sClient) Upsert(ctx context.Context, entities []K8sEntity, timeou
 	return result, nil
 }
 
+// Simulates a Helm release whose chart renders HelmReleaseYAML.
+func (c *FakeK8sClient) UpsertHelmRelease(ctx context.Context, release v1alpha1.KubernetesHelmRelease, timeout time.Duration, postRender PostRenderFunc) ([]K8sEntity, error) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	entities, err := ParseYAMLFromString(c.HelmReleaseYAML)
+	if err != nil {
+		return nil, errors.Wrap(err, "helm release")
+	}
+	if postRender != nil {
+		entities, err = postRender(entities)
+		if err != nil {
+			return nil, errors.Wrap(err, "helm release")
+		}
+	}
+
+	c.LastHelmRelease = release.DeepCopy()
+	c.LastUpsertServerSide = false
+	return c.upsert(entities, timeout)
+}
+
+func (c *FakeK8sClient) UninstallHelmRelease(ctx context.Context, release v1alpha1.KubernetesHelmRelease, timeout time.Duration) error {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	c.UninstalledHelmReleases = append(c.UninstalledHelmReleases, *release.DeepCopy())
+	return nil
+}
+
 func (c *FakeK8sClient) Delete(ctx context.Context, entities []K8sEntity) error {
 	c.mu.Lock()
 	defer c.mu.Unlock()
@@ -418,6 +479,19 @@ func (c *FakeK8sClient) GetMetaByReference(ctx context.Context, ref v1.ObjectRef
 	return resp.Meta(), nil
 }
 
+// Returns an injected entity, like GetMetaByReference.
+func (c *FakeK8sClient) GetByReference(ctx context.Context, ref v1.ObjectReference) (K8sEntity, error) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	c.getByReferenceCallCount++
+	resp, ok := c.entities[ref.UID]
+	if !ok {
+		return K8sEntity{}, apierrors.NewNotFound(v1.Resource(ref.Kind), ref.Name)
+	}
+	return resp.DeepCopy(), nil
+}
+
 func (c *FakeK8sClient) ListMeta(_ context.Context, gvk schema.GroupVersionKind, ns Namespace) ([]metav1.Object, error) {
 	c.mu.Lock()
 	defer c.mu.Unlock()
@@ -582,6 +656,52 @@ func (c *FakeK8sClient) Exec(ctx context.Context, podID PodID, cName container.N
 	return nil
 }
 
+func (c *FakeK8sClient) EnsureEphemeralContainer(ctx context.Context, podID PodID, n Namespace, ec v1.EphemeralContainer) error {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	c.EphemeralContainers = append(c.EphemeralContainers, EphemeralContainerCall{
+		PID:       podID,
+		Ns:        n,
+		Container: ec,
+	})
+
+	if len(c.EphemeralContainerErrs) > 0 {
+		err := c.EphemeralContainerErrs[0]
+		c.EphemeralContainerErrs = c.EphemeralContainerErrs[1:]
+		return err
+	}
+	return nil
+}
+
+func (c *FakeK8sClient) GetService(ctx context.Context, n Namespace, name string) (*v1.Service, error) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	s, ok := c.services[types.NamespacedName{Name: name, Namespace: n.String()}]
+	if !ok {
+		return nil, apierrors.NewNotFound(v1.Resource("services"), name)
+	}
+	return s.DeepCopy(), nil
+}
+
+func (c *FakeK8sClient) ListPods(ctx context.Context, n Namespace, selector labels.Selector) ([]v1.Pod, error) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	var result []v1.Pod
+	for key, pod := range c.pods {
+		if key.Namespace != n.String() || !selector.Matches(labels.Set(pod.Labels)) {
+			continue
+		}
+		result = append(result, *pod.DeepCopy())
+	}
+	sort.Slice(result, func(i, j int) bool {
+		return result[i].Name < result[j].Name
+	})
+	return result, nil
+}
+
 type ReaderCloser struct {
 	io.Reader
 }
@@ -593,22 +713,24 @@ func (b ReaderCloser) Close() error {
 var _ io.ReadCloser = ReaderCloser{}
 
 type FakePortForwarder struct {
-	localPort int
-	namespace Namespace
-	ctx       context.Context
-	ready     chan struct{}
-	done      chan error
+	localPort   int
+	namespace   Namespace
+	ctx         context.Context
+	ready       chan struct{}
+	done        chan error
+	connections *int32
 }
 
 var _ PortForwarder = FakePortForwarder{}
 
 func NewFakePortForwarder(ctx context.Context, localPort int, namespace Namespace) FakePortForwarder {
 	return FakePortForwarder{
-		localPort: localPort,
-		namespace: namespace,
-		ctx:       ctx,
-		ready:     make(chan struct{}, 1),
-		done:      make(chan error),
+		localPort:   localPort,
+		namespace:   namespace,
+		ctx:         ctx,
+		ready:       make(chan struct{}, 1),
+		done:        make(chan error),
+		connections: new(int32),
 	}
 }
 
@@ -645,6 +767,15 @@ func (pf FakePortForwarder) ReadyCh() <-chan struct{} {
 	return pf.ready
 }
 
+func (pf FakePortForwarder) ActiveConnections() int {
+	return int(atomic.LoadInt32(pf.connections))
+}
+
+// SetActiveConnections allows tests to simulate local connections through the forward.
+func (pf FakePortForwarder) SetActiveConnections(n int) {
+	atomic.StoreInt32(pf.connections, int32(n))
+}
+
 // TriggerFailure allows tests to inject errors during forwarding that will be returned by ForwardPorts.
 func (pf FakePortForwarder) TriggerFailure(err error) {
 	pf.done <- err
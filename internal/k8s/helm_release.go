@@ -0,0 +1,225 @@
+package k8s
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/pkg/errors"
+	"helm.sh/helm/v3/pkg/action"
+	"helm.sh/helm/v3/pkg/chart"
+	"helm.sh/helm/v3/pkg/chart/loader"
+	"helm.sh/helm/v3/pkg/cli"
+	"helm.sh/helm/v3/pkg/cli/values"
+	"helm.sh/helm/v3/pkg/getter"
+	"helm.sh/helm/v3/pkg/kube"
+	"helm.sh/helm/v3/pkg/release"
+	"helm.sh/helm/v3/pkg/storage"
+	"helm.sh/helm/v3/pkg/storage/driver"
+	cmdutil "k8s.io/kubectl/pkg/cmd/util"
+
+	"github.com/tilt-dev/tilt/pkg/apis/core/v1alpha1"
+	"github.com/tilt-dev/tilt/pkg/logger"
+)
+
+// How many revisions of a release to keep, like `helm upgrade --history-max`.
+//
+// Tilt upgrades releases a lot, so we don't want to keep all of them.
+const helmReleaseMaxHistory = 10
+
+// Modifies the rendered objects of a Helm release before they're sent to the cluster.
+type PostRenderFunc func(entities []K8sEntity) ([]K8sEntity, error)
+
+func (f PostRenderFunc) Run(rendered *bytes.Buffer) (*bytes.Buffer, error) {
+	entities, err := ParseYAML(rendered)
+	if err != nil {
+		return nil, err
+	}
+
+	entities, err = f(entities)
+	if err != nil {
+		return nil, err
+	}
+
+	return SerializeSpecYAMLToBuffer(entities)
+}
+
+// Installs a Helm release, or upgrades it if it already exists.
+//
+// If an upgrade fails, rolls back to the previous revision.
+// If an install fails, uninstalls the release.
+func (k *K8sClient) UpsertHelmRelease(ctx context.Context, spec v1alpha1.KubernetesHelmRelease, timeout time.Duration, postRender PostRenderFunc) ([]K8sEntity, error) {
+	cfg, kc, namespace := k.helmActionConfig(ctx, spec.Namespace)
+
+	ch, vals, err := LoadHelmChart(spec)
+	if err != nil {
+		return nil, err
+	}
+
+	history := action.NewHistory(cfg)
+	history.Max = 1
+	_, err = history.Run(spec.ReleaseName)
+	isInstall := errors.Is(err, driver.ErrReleaseNotFound)
+	if err != nil && !isInstall {
+		return nil, errors.Wrapf(err, "helm release %s", spec.ReleaseName)
+	}
+
+	var rel *release.Release
+	if isInstall {
+		install := action.NewInstall(cfg)
+		install.ReleaseName = spec.ReleaseName
+		install.Namespace = namespace
+		install.CreateNamespace = true
+		install.Timeout = timeout
+		if postRender != nil {
+			install.PostRenderer = postRender
+		}
+
+		rel, err = install.Run(ch, vals)
+		if err != nil {
+			cfg.Log("Install of %s failed, uninstalling", spec.ReleaseName)
+			uninstall := action.NewUninstall(cfg)
+			uninstall.Timeout = timeout
+			_, uninstallErr := uninstall.Run(spec.ReleaseName)
+			if uninstallErr != nil && !errors.Is(uninstallErr, driver.ErrReleaseNotFound) {
+				cfg.Log("Uninstall of %s failed: %v", spec.ReleaseName, uninstallErr)
+			}
+			return nil, errors.Wrapf(err, "helm install %s", spec.ReleaseName)
+		}
+	} else {
+		upgrade := action.NewUpgrade(cfg)
+		upgrade.Namespace = namespace
+		upgrade.Timeout = timeout
+		upgrade.MaxHistory = helmReleaseMaxHistory
+		if postRender != nil {
+			upgrade.PostRenderer = postRender
+		}
+
+		rel, err = upgrade.Run(spec.ReleaseName, ch, vals)
+		if err != nil {
+			cfg.Log("Upgrade of %s failed, rolling back", spec.ReleaseName)
+			rollback := action.NewRollback(cfg)
+			rollback.Timeout = timeout
+			rollback.MaxHistory = helmReleaseMaxHistory
+			rollbackErr := rollback.Run(spec.ReleaseName)
+			if rollbackErr != nil {
+				return nil, fmt.Errorf("helm upgrade %s: %v\nRollback failed: %v", spec.ReleaseName, err, rollbackErr)
+			}
+			return nil, errors.Wrapf(err, "helm upgrade %s (rolled back to the previous revision)", spec.ReleaseName)
+		}
+	}
+
+	// Fetch the objects we deployed, so that we have their UIDs.
+	resources, err := kc.Build(bytes.NewBufferString(rel.Manifest), false)
+	if err != nil {
+		return nil, errors.Wrapf(err, "helm release %s", spec.ReleaseName)
+	}
+
+	result := make([]K8sEntity, 0, len(resources))
+	for _, info := range resources {
+		err := info.Get()
+		if err != nil {
+			return nil, errors.Wrapf(err, "helm release %s", spec.ReleaseName)
+		}
+		result = append(result, NewK8sEntity(info.Object))
+	}
+	return reparseEntities(result)
+}
+
+// Uninstalls a Helm release, running its delete hooks.
+//
+// Ignores releases that don't exist.
+func (k *K8sClient) UninstallHelmRelease(ctx context.Context, spec v1alpha1.KubernetesHelmRelease, timeout time.Duration) error {
+	cfg, _, _ := k.helmActionConfig(ctx, spec.Namespace)
+
+	uninstall := action.NewUninstall(cfg)
+	uninstall.Timeout = timeout
+	_, err := uninstall.Run(spec.ReleaseName)
+	if err != nil && !errors.Is(err, driver.ErrReleaseNotFound) {
+		return errors.Wrapf(err, "helm uninstall %s", spec.ReleaseName)
+	}
+	return nil
+}
+
+// Sets up the Helm SDK to talk to the cluster, storing releases
+// in Secrets (like the Helm CLI does).
+//
+// Helm logs its progress (including hooks) to the logger in ctx.
+func (k *K8sClient) helmActionConfig(ctx context.Context, namespace string) (*action.Configuration, *kube.Client, string) {
+	if namespace == "" {
+		namespace = k.configNamespace.String()
+	}
+
+	l := logger.Get(ctx)
+	log := func(format string, v ...interface{}) {
+		l.Infof(format, v...)
+	}
+
+	// Don't use action.Configuration.Init() here, because it calls kube.New(),
+	// which modifies globals in a way that breaks tests.
+	kc := &kube.Client{
+		Factory:   cmdutil.NewFactory(k),
+		Log:       log,
+		Namespace: namespace,
+	}
+	secrets := k.core.Secrets(namespace)
+
+	return &action.Configuration{
+		RESTClientGetter: k,
+		KubeClient:       kc,
+		Releases:         storage.Init(driver.NewSecrets(secrets)),
+		Log:              log,
+	}, kc, namespace
+}
+
+// Renders the objects in a Helm release client-side, like `helm template`.
+//
+// Hooks aren't included.
+func TemplateHelmRelease(spec v1alpha1.KubernetesHelmRelease) (string, error) {
+	ch, vals, err := LoadHelmChart(spec)
+	if err != nil {
+		return "", err
+	}
+
+	install := action.NewInstall(&action.Configuration{
+		Log: func(string, ...interface{}) {},
+	})
+	install.ReleaseName = spec.ReleaseName
+	install.Namespace = Namespace(spec.Namespace).String()
+	install.DryRun = true
+	install.ClientOnly = true
+	install.Replace = true
+	install.IncludeCRDs = true
+
+	rel, err := install.Run(ch, vals)
+	if err != nil {
+		return "", errors.Wrapf(err, "rendering helm chart %s", spec.Chart)
+	}
+	return rel.Manifest, nil
+}
+
+// Loads a local chart and the values to render it with.
+func LoadHelmChart(spec v1alpha1.KubernetesHelmRelease) (*chart.Chart, map[string]interface{}, error) {
+	ch, err := loader.Load(spec.Chart)
+	if err != nil {
+		return nil, nil, errors.Wrapf(err, "loading helm chart %s", spec.Chart)
+	}
+
+	if ch.Metadata.Dependencies != nil {
+		err := action.CheckDependencies(ch, ch.Metadata.Dependencies)
+		if err != nil {
+			return nil, nil, errors.Wrapf(err, "helm chart %s (try `helm dependency update`)", spec.Chart)
+		}
+	}
+
+	opts := values.Options{
+		ValueFiles: spec.ValueFiles,
+		Values:     spec.Set,
+	}
+	vals, err := opts.MergeValues(getter.All(cli.New()))
+	if err != nil {
+		return nil, nil, errors.Wrapf(err, "helm chart %s", spec.Chart)
+	}
+	return ch, vals, nil
+}
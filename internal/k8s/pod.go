@@ -7,6 +7,7 @@ import (
 
 	v1 "k8s.io/api/core/v1"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/labels"
 
 	"github.com/tilt-dev/tilt/internal/container"
 )
@@ -23,6 +24,18 @@ func (k *K8sClient) ContainerLogs(ctx context.Context, pID PodID, cName containe
 	return req.Stream(ctx)
 }
 
+func (k *K8sClient) ListPods(ctx context.Context, n Namespace, selector labels.Selector) ([]v1.Pod, error) {
+	list, err := k.core.Pods(n.String()).List(ctx, metav1.ListOptions{LabelSelector: selector.String()})
+	if err != nil {
+		return nil, err
+	}
+	return list.Items, nil
+}
+
+func (k *K8sClient) GetService(ctx context.Context, n Namespace, name string) (*v1.Service, error) {
+	return k.core.Services(n.String()).Get(ctx, name, metav1.GetOptions{})
+}
+
 func PodIDFromPod(pod *v1.Pod) PodID {
 	return PodID(pod.ObjectMeta.Name)
 }
@@ -41,6 +41,9 @@ type PortForwarder interface {
 	// when the context passed at creation is canceled.
 	ForwardPorts() error
 
+	// The number of local connections that are currently open.
+	ActiveConnections() int
+
 	// TODO(nick): If the port forwarder has any problems connecting to the pod,
 	// it just logs those as debug logs. I'm not sure that logs are the right API
 	// for this -- there are lots of cases (e.g., where you're deliberately
@@ -28,6 +28,7 @@ import (
 	"strconv"
 	"strings"
 	"sync"
+	"sync/atomic"
 
 	v1 "k8s.io/api/core/v1"
 	"k8s.io/apimachinery/pkg/util/httpstream"
@@ -53,6 +54,8 @@ type PortForwarder struct {
 	Ready         chan struct{}
 	requestIDLock sync.Mutex
 	requestID     int
+
+	activeConnections int32
 }
 
 // ForwardedPort contains a Local:Remote port pairing.
@@ -324,6 +327,9 @@ func (pf *PortForwarder) nextRequestID() int {
 func (pf *PortForwarder) handleConnection(conn net.Conn, port ForwardedPort) {
 	defer conn.Close()
 
+	atomic.AddInt32(&pf.activeConnections, 1)
+	defer atomic.AddInt32(&pf.activeConnections, -1)
+
 	requestID := pf.nextRequestID()
 
 	// create error stream
@@ -403,6 +409,11 @@ func (pf *PortForwarder) handleConnection(conn net.Conn, port ForwardedPort) {
 	}
 }
 
+// ActiveConnections returns the number of local connections currently being forwarded.
+func (pf *PortForwarder) ActiveConnections() int {
+	return int(atomic.LoadInt32(&pf.activeConnections))
+}
+
 // Close stops all listeners of PortForwarder.
 func (pf *PortForwarder) Close() {
 	// stop all listeners
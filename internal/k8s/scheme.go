@@ -0,0 +1,25 @@
+package k8s
+
+import (
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/apimachinery/pkg/runtime/serializer"
+	utilruntime "k8s.io/apimachinery/pkg/util/runtime"
+	"k8s.io/client-go/kubernetes/scheme"
+)
+
+// The types we decode YAML into. Any other type is decoded as unstructured.
+//
+// This has the same types as client-go's scheme.Scheme. We don't use that scheme directly,
+// because libraries like the Helm SDK add CRD types to it when they're imported,
+// and we want to keep CRDs unstructured.
+var decodeScheme = newDecodeScheme()
+var decodeCodecs = serializer.NewCodecFactory(decodeScheme)
+
+func newDecodeScheme() *runtime.Scheme {
+	s := runtime.NewScheme()
+	metav1.AddToGroupVersion(s, schema.GroupVersion{Version: "v1"})
+	utilruntime.Must(scheme.AddToScheme(s))
+	return s
+}
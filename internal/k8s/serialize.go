@@ -10,7 +10,6 @@ import (
 	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
 	"k8s.io/apimachinery/pkg/runtime"
 	yamlDecoder "k8s.io/apimachinery/pkg/util/yaml"
-	"k8s.io/client-go/kubernetes/scheme"
 	yamlEncoder "sigs.k8s.io/yaml"
 )
 
@@ -44,7 +43,7 @@ func decodeToRuntimeObj(ext runtime.RawExtension) (runtime.Object, error) {
 	}
 
 	obj, _, err :=
-		scheme.Codecs.UniversalDeserializer().Decode(ext.Raw, nil, nil)
+		decodeCodecs.UniversalDeserializer().Decode(ext.Raw, nil, nil)
 	if err == nil {
 		return obj, nil
 	}
@@ -0,0 +1,120 @@
+package k8s
+
+import (
+	"context"
+	"time"
+
+	"github.com/pkg/errors"
+	"k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+
+	"github.com/tilt-dev/tilt/pkg/logger"
+)
+
+// The field manager that Tilt uses for server-side apply.
+//
+// https://kubernetes.io/docs/reference/using-api/server-side-apply/#managers
+const FieldManager = "tilt"
+
+func (k *K8sClient) ServerSideApply(ctx context.Context, entities []K8sEntity, timeout time.Duration) ([]K8sEntity, error) {
+	result := make([]K8sEntity, 0, len(entities))
+
+	mutable, immutable := MutableAndImmutableEntities(entities)
+
+	for _, e := range mutable {
+		innerCtx, cancel := context.WithTimeout(ctx, timeout)
+		defer cancel()
+
+		newEntity, err := k.escalatingServerSideApply(innerCtx, e)
+		if err != nil {
+			if ctx.Err() == context.DeadlineExceeded {
+				return nil, timeoutError(timeout)
+			}
+			return nil, err
+		}
+		result = append(result, newEntity)
+	}
+
+	for _, e := range immutable {
+		innerCtx, cancel := context.WithTimeout(ctx, timeout)
+		defer cancel()
+
+		newEntity, err := k.deleteAndServerSideApplyEntity(innerCtx, e)
+		if err != nil {
+			if ctx.Err() == context.DeadlineExceeded {
+				return nil, timeoutError(timeout)
+			}
+			return nil, err
+		}
+		result = append(result, newEntity)
+	}
+
+	return reparseEntities(result)
+}
+
+// Applies the entity, falling back to deleting and re-creating it
+// if it changes an immutable field.
+func (k *K8sClient) escalatingServerSideApply(ctx context.Context, entity K8sEntity) (K8sEntity, error) {
+	result, err := k.serverSideApplyEntity(ctx, entity)
+	if err != nil && maybeImmutableFieldStderr(err.Error()) {
+		logger.Get(ctx).Infof("Updating %q failed: immutable field error", entity.Name())
+		logger.Get(ctx).Infof("Attempting to delete and re-create")
+		result, err = k.deleteAndServerSideApplyEntity(ctx, entity)
+		if err == nil {
+			logger.Get(ctx).Infof("Updating %q succeeded!", entity.Name())
+		}
+	}
+	return result, err
+}
+
+// Sends the entity to the server as an apply patch.
+//
+// Unlike client-side apply, the server tracks which fields Tilt owns,
+// so we don't need the last-applied-configuration annotation.
+func (k *K8sClient) serverSideApplyEntity(ctx context.Context, entity K8sEntity) (K8sEntity, error) {
+	mapping, err := k.forceDiscoveryMapping(ctx, entity.GVK())
+	if err != nil {
+		return K8sEntity{}, errors.Wrap(err, "kubernetes server-side apply")
+	}
+
+	data, err := SerializeSpecYAML([]K8sEntity{entity})
+	if err != nil {
+		return K8sEntity{}, errors.Wrap(err, "kubernetes server-side apply")
+	}
+
+	ri := k.dynamic.Resource(mapping.Resource)
+	force := true
+	opts := metav1.PatchOptions{FieldManager: FieldManager, Force: &force}
+	if mapping.Scope.Name() == meta.RESTScopeNameNamespace {
+		ns := entity.Meta().GetNamespace()
+		if ns == "" {
+			ns = k.configNamespace.String()
+		}
+		obj, err := ri.Namespace(ns).Patch(ctx, entity.Name(), types.ApplyPatchType, []byte(data), opts)
+		if err != nil {
+			return K8sEntity{}, errors.Wrap(err, "kubernetes server-side apply")
+		}
+		return NewK8sEntity(obj), nil
+	}
+
+	obj, err := ri.Patch(ctx, entity.Name(), types.ApplyPatchType, []byte(data), opts)
+	if err != nil {
+		return K8sEntity{}, errors.Wrap(err, "kubernetes server-side apply")
+	}
+	return NewK8sEntity(obj), nil
+}
+
+func (k *K8sClient) deleteAndServerSideApplyEntity(ctx context.Context, entity K8sEntity) (K8sEntity, error) {
+	resources, err := k.prepareUpdateList(ctx, entity)
+	if err != nil {
+		return K8sEntity{}, errors.Wrap(err, "kubernetes delete and re-create")
+	}
+
+	err = k.deleteAndWait(resources)
+	if err != nil {
+		return K8sEntity{}, err
+	}
+
+	return k.serverSideApplyEntity(ctx, entity)
+}
@@ -0,0 +1,155 @@
+package kustomize
+
+import (
+	"os"
+	"path/filepath"
+	"sort"
+	"sync"
+
+	"sigs.k8s.io/kustomize/api/konfig"
+	"sigs.k8s.io/kustomize/api/krusty"
+	"sigs.k8s.io/kustomize/api/types"
+	"sigs.k8s.io/kustomize/kyaml/filesys"
+)
+
+// Kustomize keeps some global state (like the OpenAPI schema) while it builds,
+// so we only run one build at a time.
+var buildMu sync.Mutex
+
+// Renders the kustomization in dir, in-process.
+//
+// We pin the build options, rather than relying on whatever version of
+// `kustomize` or `kubectl` is installed, so that everyone on a team gets the
+// same YAML. The options match the defaults of `kustomize build`.
+//
+// Returns the YAML and the absolute paths of all the files the build read or
+// tried to read (even if the build failed, so that the caller can watch them
+// for a fix). Remote bases are read from the cache, so their files are included.
+func Build(dir string, cache *RemoteCache) (string, []string, error) {
+	buildMu.Lock()
+	defer buildMu.Unlock()
+
+	opts := krusty.MakeDefaultOptions()
+	opts.DoLegacyResourceSort = true
+	opts.LoadRestrictions = types.LoadRestrictionsRootOnly
+	opts.PluginConfig = types.DisabledPluginConfig()
+
+	fSys := newRecordingFS(filesys.MakeFsOnDisk(), cache)
+	resMap, err := krusty.MakeKustomizer(opts).Run(fSys, dir)
+	if err != nil {
+		return "", fSys.deps(), err
+	}
+
+	yaml, err := resMap.AsYaml()
+	if err != nil {
+		return "", fSys.deps(), err
+	}
+
+	return string(yaml), fSys.deps(), nil
+}
+
+// A filesystem that records every file that kustomize reads,
+// and points remote bases at the cache.
+type recordingFS struct {
+	filesys.FileSystem
+
+	cache *RemoteCache
+
+	mu sync.Mutex
+
+	// Each path kustomize tried to read, and whether it exists.
+	paths map[string]bool
+}
+
+func newRecordingFS(delegate filesys.FileSystem, cache *RemoteCache) *recordingFS {
+	return &recordingFS{
+		FileSystem: delegate,
+		cache:      cache,
+		paths:      make(map[string]bool),
+	}
+}
+
+func (fs *recordingFS) Open(path string) (filesys.File, error) {
+	f, err := fs.FileSystem.Open(path)
+	fs.maybeRecord(path, err)
+	return f, err
+}
+
+func (fs *recordingFS) ReadFile(path string) ([]byte, error) {
+	b, err := fs.FileSystem.ReadFile(path)
+	fs.maybeRecord(path, err)
+	if err != nil || !isKustomizationFile(path) {
+		return b, err
+	}
+	return fs.cache.rewriteKustomization(path, b)
+}
+
+// Kustomize resolves a path before reading it, so this is where
+// we find out about files that don't exist.
+func (fs *recordingFS) CleanedAbs(path string) (filesys.ConfirmedDir, string, error) {
+	d, f, err := fs.FileSystem.CleanedAbs(path)
+	if err != nil && !fs.FileSystem.Exists(path) {
+		fs.record(path, false)
+	}
+	return d, f, err
+}
+
+func isKustomizationFile(path string) bool {
+	name := filepath.Base(path)
+	for _, n := range konfig.RecognizedKustomizationFileNames() {
+		if name == n {
+			return true
+		}
+	}
+	return false
+}
+
+// Records files that we read, and files that don't exist yet,
+// so that we notice when the user creates them.
+//
+// Other errors (like reading a directory as a file) aren't recorded.
+func (fs *recordingFS) maybeRecord(path string, err error) {
+	if err == nil {
+		fs.record(path, true)
+	} else if os.IsNotExist(err) {
+		fs.record(path, false)
+	}
+}
+
+func (fs *recordingFS) record(path string, exists bool) {
+	abs, err := filepath.Abs(path)
+	if err != nil {
+		return
+	}
+
+	fs.mu.Lock()
+	defer fs.mu.Unlock()
+	fs.paths[abs] = fs.paths[abs] || exists
+}
+
+func (fs *recordingFS) deps() []string {
+	fs.mu.Lock()
+	defer fs.mu.Unlock()
+
+	result := make([]string, 0, len(fs.paths))
+	for p, exists := range fs.paths {
+		// Kustomize looks for every kustomization file name in a directory.
+		// Once it's found one, the other names don't matter.
+		if !exists && isKustomizationFile(p) && fs.foundKustomizationIn(filepath.Dir(p)) {
+			continue
+		}
+		result = append(result, p)
+	}
+	sort.Strings(result)
+	return result
+}
+
+// Must hold the lock.
+func (fs *recordingFS) foundKustomizationIn(dir string) bool {
+	for _, name := range konfig.RecognizedKustomizationFileNames() {
+		if fs.paths[filepath.Join(dir, name)] {
+			return true
+		}
+	}
+	return false
+}
@@ -5,15 +5,57 @@ import (
 	"strings"
 	"testing"
 
+	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
 
 	"github.com/tilt-dev/tilt/internal/testutils/tempdir"
+	"github.com/tilt-dev/tilt/internal/xdg"
 )
 
+const testConfigMap = `apiVersion: v1
+kind: ConfigMap
+metadata:
+  name: the-map
+data:
+  altGreeting: "Good Morning!"
+`
+
+const testDeployment = `apiVersion: apps/v1
+kind: Deployment
+metadata:
+  name: the-deployment
+spec:
+  replicas: 3
+  template:
+    spec:
+      containers:
+      - name: the-container
+        image: monopole/hello:1
+`
+
+const testService = `kind: Service
+apiVersion: v1
+metadata:
+  name: the-service
+spec:
+  ports:
+  - port: 8666
+`
+
+const testPod = `apiVersion: v1
+kind: Pod
+metadata:
+  name: myapp-pod
+spec:
+  containers:
+  - name: nginx
+    image: nginx:1.7.9
+`
+
 func TestNoFile(t *testing.T) {
 	f := newKustomizeFixture(t)
 
-	f.assertErrorContains("unable to find one of [kustomization.yaml kustomization.yml Kustomization] in directory ")
+	f.assertErrorContains("unable to find one of 'kustomization.yaml', 'kustomization.yml' or 'Kustomization'")
 }
 
 func TestTooManyFiles(t *testing.T) {
@@ -21,13 +63,12 @@ func TestTooManyFiles(t *testing.T) {
 	f.tempdir.WriteFile("kustomization.yml", "")
 	f.tempdir.WriteFile("kustomization.yaml", "")
 
-	f.assertErrorContains("Found multiple kustomization files under")
+	f.assertErrorContains("Found multiple kustomization files")
 }
 
 func TestEmpty(t *testing.T) {
 	f := newKustomizeFixture(t)
-	kustomizeFile := ""
-	f.writeRootKustomize(kustomizeFile)
+	f.writeRootKustomize("namePrefix: dev-\n")
 
 	expected := []string{"kustomization.yaml"}
 
@@ -46,9 +87,22 @@ resources:
   - service.yaml
   - configMap.yaml`
 	f.writeRootKustomize(kustomizeFile)
+	f.tempdir.WriteFile("deployment.yaml", testDeployment)
+	f.tempdir.WriteFile("service.yaml", testService)
+	f.tempdir.WriteFile("configMap.yaml", testConfigMap)
+
+	// An unused file isn't a dependency.
+	f.tempdir.WriteFile("unused.yaml", testPod)
 
 	expected := []string{"kustomization.yaml", "deployment.yaml", "service.yaml", "configMap.yaml"}
 	f.assertDeps(expected)
+
+	yaml := f.build()
+	assert.Contains(t, yaml, "app: my-hello")
+
+	// Kustomize's legacy order puts the ConfigMap and Service before the Deployment.
+	assert.Less(t, strings.Index(yaml, "kind: ConfigMap"), strings.Index(yaml, "kind: Service"))
+	assert.Less(t, strings.Index(yaml, "kind: Service"), strings.Index(yaml, "kind: Deployment"))
 }
 
 func TestComplex(t *testing.T) {
@@ -57,6 +111,7 @@ func TestComplex(t *testing.T) {
 # declare ConfigMap as a resource
 resources:
 - configmap.yaml
+- deployment.yaml
 
 # declare ConfigMap from a ConfigMapGenerator
 configMapGenerator:
@@ -64,18 +119,37 @@ configMapGenerator:
   files:
     - configs/configfile
     - configs/another_configfile
+  envs:
+    - configs/config.env
 
 patchesJson6902:
   - target:
-      group: extensions
-      version: v1beta1
-      kind: Ingress
-      name: my-ingress
-    path: ingress_patch.yaml`
+      group: apps
+      version: v1
+      kind: Deployment
+      name: the-deployment
+    path: deployment_patch.yaml`
 	f.writeRootKustomize(kustomizeFile)
+	f.tempdir.WriteFile("configmap.yaml", testConfigMap)
+	f.tempdir.WriteFile("deployment.yaml", testDeployment)
+	f.tempdir.WriteFile("configs/configfile", "hello")
+	f.tempdir.WriteFile("configs/another_configfile", "goodbye")
+	f.tempdir.WriteFile("configs/config.env", "FOO=bar")
+	f.tempdir.WriteFile("deployment_patch.yaml", `- op: replace
+  path: /spec/replicas
+  value: 1`)
 
-	expected := []string{"kustomization.yaml", "configmap.yaml", "ingress_patch.yaml", "configs/configfile", "configs/another_configfile"}
+	expected := []string{
+		"kustomization.yaml",
+		"configmap.yaml",
+		"deployment.yaml",
+		"deployment_patch.yaml",
+		"configs/configfile",
+		"configs/another_configfile",
+		"configs/config.env",
+	}
 	f.assertDeps(expected)
+	assert.Contains(t, f.build(), "replicas: 1")
 }
 
 func TestRecursive(t *testing.T) {
@@ -97,17 +171,7 @@ namePrefix: cluster-a-`
 	base := `resources:
 - pod.yaml`
 	f.writeBaseKustomize("base", base)
-	basePod := `apiVersion: v1
-  kind: Pod
-  metadata:
-    name: myapp-pod
-    labels:
-      app: myapp
-  spec:
-    containers:
-    - name: nginx
-      image: nginx:1.7.9`
-	f.writeBaseFile("base", "pod.yaml", basePod)
+	f.writeBaseFile("base", "pod.yaml", testPod)
 
 	dev := `bases:
 - ./../base
@@ -133,6 +197,38 @@ namePrefix: prod-`
 		"kustomization.yaml",
 	}
 	f.assertDeps(expected)
+
+	yaml := f.build()
+	assert.Contains(t, yaml, "name: cluster-a-dev-myapp-pod")
+	assert.Contains(t, yaml, "name: cluster-a-prod-myapp-pod")
+}
+
+func TestComponents(t *testing.T) {
+	f := newKustomizeFixture(t)
+	f.writeRootKustomize(`resources:
+- deployment.yaml
+components:
+- ./replicas`)
+	f.tempdir.WriteFile("deployment.yaml", testDeployment)
+	f.writeBaseFile("replicas", "kustomization.yaml", `apiVersion: kustomize.config.k8s.io/v1alpha1
+kind: Component
+patchesStrategicMerge:
+- patch.yaml`)
+	f.writeBaseFile("replicas", "patch.yaml", `apiVersion: apps/v1
+kind: Deployment
+metadata:
+  name: the-deployment
+spec:
+  replicas: 5`)
+
+	expected := []string{
+		"kustomization.yaml",
+		"deployment.yaml",
+		"replicas/kustomization.yaml",
+		"replicas/patch.yaml",
+	}
+	f.assertDeps(expected)
+	assert.Contains(t, f.build(), "replicas: 5")
 }
 
 // patches was deprecated and then re-added with a different meaning
@@ -153,24 +249,45 @@ patches:
   - path: patch.yaml
     target:
       kind: Deployment
-      name: foo
+      name: the-deployment
 `
 	f.writeRootKustomize(kustomizeFile)
+	f.tempdir.WriteFile("deployment.yaml", testDeployment)
+	f.tempdir.WriteFile("service.yaml", testService)
+	f.tempdir.WriteFile("configMap.yaml", testConfigMap)
+	f.tempdir.WriteFile("patch.yaml", `- op: replace
+  path: /spec/replicas
+  value: 2`)
 
 	expected := []string{"kustomization.yaml", "deployment.yaml", "service.yaml", "configMap.yaml", "patch.yaml"}
 	f.assertDeps(expected)
 }
 
+func TestDepsOnError(t *testing.T) {
+	f := newKustomizeFixture(t)
+	f.writeRootKustomize(`resources:
+- deployment.yaml
+- missing.yaml`)
+	f.tempdir.WriteFile("deployment.yaml", testDeployment)
+
+	_, deps, err := Build(f.tempdir.Path(), f.cache)
+	require.Error(t, err)
+	assert.Contains(t, deps, f.tempdir.JoinPath("kustomization.yaml"))
+	assert.Contains(t, deps, f.tempdir.JoinPath("missing.yaml"))
+}
+
 type kustomizeFixture struct {
 	t       *testing.T
 	tempdir *tempdir.TempDirFixture
+	cache   *RemoteCache
 }
 
 func newKustomizeFixture(t *testing.T) *kustomizeFixture {
-
+	tempdir := tempdir.NewTempDirFixture(t)
 	return &kustomizeFixture{
 		t:       t,
-		tempdir: tempdir.NewTempDirFixture(t),
+		tempdir: tempdir,
+		cache:   NewRemoteCache(xdg.FakeBase{Dir: tempdir.JoinPath(".cache")}),
 	}
 }
 
@@ -186,8 +303,16 @@ func (f *kustomizeFixture) writeBaseFile(pathToContainingDirectory, name, conten
 	f.tempdir.WriteFile(filepath.Join(pathToContainingDirectory, name), contents)
 }
 
+func (f *kustomizeFixture) build() string {
+	yaml, _, err := Build(f.tempdir.Path(), f.cache)
+	if err != nil {
+		f.t.Fatal(err)
+	}
+	return yaml
+}
+
 func (f *kustomizeFixture) getDeps() []string {
-	deps, err := Deps(f.tempdir.Path())
+	_, deps, err := Build(f.tempdir.Path(), f.cache)
 	if err != nil {
 		f.t.Fatal(err)
 	}
@@ -204,7 +329,7 @@ func (f *kustomizeFixture) assertDeps(expected []string) {
 }
 
 func (f *kustomizeFixture) assertErrorContains(expected string) {
-	_, err := Deps(f.tempdir.Path())
+	_, _, err := Build(f.tempdir.Path(), f.cache)
 	if err == nil {
 		f.t.Fatal("Expected an error, got nil")
 	}
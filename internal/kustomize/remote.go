@@ -0,0 +1,245 @@
+package kustomize
+
+import (
+	"fmt"
+	"net/url"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+
+	"sigs.k8s.io/kustomize/kyaml/yaml"
+
+	"github.com/tilt-dev/tilt/internal/git"
+	"github.com/tilt-dev/tilt/internal/ospath"
+	"github.com/tilt-dev/tilt/internal/xdg"
+)
+
+// Where remote bases are cloned, relative to the Tilt cache dir.
+const remoteCacheRelDir = "kustomize/remotes"
+
+// Fields of a kustomization that can refer to a remote base.
+var remoteBaseFields = []string{"resources", "bases", "components"}
+
+// A cache of the remote bases that kustomizations refer to,
+// like github.com/org/repo//deploy?ref=v1.0.
+//
+// Kustomize clones remote bases into a temp directory, and deletes it after
+// each build. Instead, we clone them into the Tilt cache dir, so that we don't
+// re-clone them on every Tiltfile load, and so that we can watch their files.
+//
+// Each repo is fetched once per Tilt session. After that, we use the clone
+// we already have, so that a Tiltfile reload doesn't need the network.
+type RemoteCache struct {
+	base xdg.Base
+
+	mu      sync.Mutex
+	fetched map[string]bool
+}
+
+func NewRemoteCache(base xdg.Base) *RemoteCache {
+	return &RemoteCache{
+		base:    base,
+		fetched: make(map[string]bool),
+	}
+}
+
+// A remote base, split up the way kustomize does it.
+type remoteBase struct {
+	repoURL string
+	path    string
+	ref     string
+}
+
+// Parses kustomize's remote base syntax. Returns false if the entry is
+// not a git repo, e.g., if it's a local path or a YAML file served over HTTP.
+func parseRemoteBase(entry string) (remoteBase, bool) {
+	if entry == "" || filepath.IsAbs(entry) || strings.HasPrefix(entry, ".") {
+		return remoteBase{}, false
+	}
+
+	rest, query := entry, ""
+	if i := strings.Index(entry, "?"); i != -1 {
+		rest, query = entry[:i], entry[i+1:]
+	}
+	values, _ := url.ParseQuery(query)
+	ref := values.Get("ref")
+	if ref == "" {
+		ref = values.Get("version")
+	}
+
+	// Split off the scheme and the host.
+	prefix := ""
+	switch {
+	case strings.HasPrefix(rest, "git@"):
+		i := strings.Index(rest, ":")
+		if i == -1 {
+			return remoteBase{}, false
+		}
+		prefix, rest = rest[:i+1], rest[i+1:]
+	case strings.Contains(rest, "://"):
+		start := strings.Index(rest, "://") + len("://")
+		i := strings.Index(rest[start:], "/")
+		if i == -1 {
+			return remoteBase{}, false
+		}
+		prefix, rest = rest[:start+i+1], rest[start+i+1:]
+	default:
+		// A bare host, like github.com/org/repo, which kustomize clones over https.
+		i := strings.Index(rest, "/")
+		if i == -1 || !strings.Contains(rest[:i], ".") {
+			return remoteBase{}, false
+		}
+		prefix, rest = "https://"+rest[:i+1], rest[i+1:]
+	}
+
+	// The repo ends at "//", at ".git", after "_git/name" (Azure), or
+	// after the org and repo name.
+	repo, path := "", ""
+	switch {
+	case strings.Contains(rest, "//"):
+		i := strings.Index(rest, "//")
+		repo, path = rest[:i], rest[i+2:]
+	case strings.Contains(rest, ".git"):
+		i := strings.Index(rest, ".git") + len(".git")
+		repo, path = rest[:i], rest[i:]
+	case strings.Contains(rest, "_git/"):
+		start := strings.Index(rest, "_git/") + len("_git/")
+		i := strings.Index(rest[start:], "/")
+		if i == -1 {
+			repo = rest
+		} else {
+			repo, path = rest[:start+i], rest[start+i:]
+		}
+	default:
+		parts := strings.SplitN(rest, "/", 3)
+		if len(parts) < 2 || parts[0] == "" || parts[1] == "" {
+			return remoteBase{}, false
+		}
+		repo = parts[0] + "/" + parts[1]
+		if len(parts) == 3 {
+			path = parts[2]
+		}
+	}
+
+	path = strings.Trim(path, "/")
+	if repo == "" {
+		return remoteBase{}, false
+	}
+
+	// kustomize fetches YAML files over HTTP, rather than cloning them.
+	switch filepath.Ext(path) {
+	case ".yaml", ".yml", ".json":
+		return remoteBase{}, false
+	}
+
+	return remoteBase{repoURL: prefix + repo, path: path, ref: ref}, true
+}
+
+// Clones the repo of the remote base (if we haven't already),
+// checks out its ref, and returns the directory of the base.
+func (c *RemoteCache) checkout(b remoteBase) (string, error) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	root, err := c.base.CacheFile(remoteCacheRelDir)
+	if err != nil {
+		return "", err
+	}
+
+	// Check out each ref in its own clone, so that two kustomizations can
+	// use different versions of the same repo.
+	refDir := "HEAD"
+	if b.ref != "" {
+		refDir = url.PathEscape(b.ref)
+	}
+	dlr := git.NewDownloader(filepath.Join(root, refDir))
+	dlr.Stderr = nil
+
+	repoDir := dlr.DestinationPath(b.repoURL)
+	if repoDir == "" {
+		return "", fmt.Errorf("invalid git URL %s", b.repoURL)
+	}
+
+	if !c.fetched[repoDir] {
+		_, err := dlr.Download(b.repoURL)
+		if err != nil {
+			// If we're offline, fall back to the clone we already have.
+			if _, statErr := os.Stat(filepath.Join(repoDir, ".git")); statErr != nil {
+				return "", err
+			}
+		}
+
+		if b.ref != "" {
+			err := dlr.RefSync(b.repoURL, b.ref)
+			if err != nil {
+				return "", err
+			}
+		}
+		c.fetched[repoDir] = true
+	}
+
+	result := filepath.Join(repoDir, filepath.FromSlash(b.path))
+	if !ospath.IsChild(repoDir, result) {
+		return "", fmt.Errorf("invalid path %s in repo %s", b.path, b.repoURL)
+	}
+	return result, nil
+}
+
+// Rewrites the remote bases in a kustomization to point at their clones in
+// the cache, relative to the kustomization's directory (because kustomize
+// doesn't allow absolute paths to bases).
+func (c *RemoteCache) rewriteKustomization(path string, contents []byte) ([]byte, error) {
+	node, err := yaml.Parse(string(contents))
+	if err != nil {
+		// Let kustomize report the error.
+		return contents, nil
+	}
+
+	dir := filepath.Dir(path)
+	changed := false
+	for _, field := range remoteBaseFields {
+		list, err := node.Pipe(yaml.Lookup(field))
+		if err != nil || list == nil {
+			continue
+		}
+
+		for _, item := range list.Content() {
+			if item.Kind != yaml.ScalarNode {
+				continue
+			}
+
+			// A local path always wins, like it does in kustomize.
+			if _, err := os.Stat(filepath.Join(dir, item.Value)); err == nil {
+				continue
+			}
+
+			b, ok := parseRemoteBase(item.Value)
+			if !ok {
+				continue
+			}
+
+			baseDir, err := c.checkout(b)
+			if err != nil {
+				return nil, fmt.Errorf("fetching remote base %s: %v", item.Value, err)
+			}
+
+			rel, err := filepath.Rel(dir, baseDir)
+			if err != nil {
+				return nil, fmt.Errorf("fetching remote base %s: %v", item.Value, err)
+			}
+			item.Value = filepath.ToSlash(rel)
+			changed = true
+		}
+	}
+
+	if !changed {
+		return contents, nil
+	}
+
+	result, err := node.String()
+	if err != nil {
+		return nil, err
+	}
+	return []byte(result), nil
+}
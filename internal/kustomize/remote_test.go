@@ -0,0 +1,93 @@
+package kustomize
+
+import (
+	"fmt"
+	"os/exec"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestParseRemoteBase(t *testing.T) {
+	for _, tc := range []struct {
+		entry    string
+		expected remoteBase
+	}{
+		{"github.com/org/repo", remoteBase{repoURL: "https://github.com/org/repo"}},
+		{"github.com/org/repo/deploy/base?ref=v1.0",
+			remoteBase{repoURL: "https://github.com/org/repo", path: "deploy/base", ref: "v1.0"}},
+		{"https://github.com/org/repo//deploy?version=v2",
+			remoteBase{repoURL: "https://github.com/org/repo", path: "deploy", ref: "v2"}},
+		{"git@github.com:org/repo.git/deploy",
+			remoteBase{repoURL: "git@github.com:org/repo.git", path: "deploy"}},
+		{"ssh://git@example.com/org/repo.git//deploy?ref=main",
+			remoteBase{repoURL: "ssh://git@example.com/org/repo.git", path: "deploy", ref: "main"}},
+		{"https://dev.azure.com/org/project/_git/repo/deploy",
+			remoteBase{repoURL: "https://dev.azure.com/org/project/_git/repo", path: "deploy"}},
+	} {
+		t.Run(tc.entry, func(t *testing.T) {
+			actual, ok := parseRemoteBase(tc.entry)
+			require.True(t, ok)
+			assert.Equal(t, tc.expected, actual)
+		})
+	}
+
+	for _, entry := range []string{
+		"deployment.yaml",
+		"../base",
+		"base/overlay",
+		"/abs/base",
+		"https://raw.githubusercontent.com/org/repo/main/deploy.yaml",
+	} {
+		_, ok := parseRemoteBase(entry)
+		assert.False(t, ok, entry)
+	}
+}
+
+func TestRemoteBase(t *testing.T) {
+	f := newKustomizeFixture(t)
+	src := f.tempdir.JoinPath("src", "repo")
+	f.tempdir.MkdirAll(src)
+	gitInRepo := func(args ...string) {
+		args = append([]string{"-c", "user.name=tilt", "-c", "user.email=tilt@example.com"}, args...)
+		cmd := exec.Command("git", args...)
+		cmd.Dir = src
+		out, err := cmd.CombinedOutput()
+		require.NoError(t, err, string(out))
+	}
+	gitInRepo("init", "-q")
+	f.writeBaseKustomize(filepath.Join("src", "repo", "base"), "resources:\n- deployment.yaml")
+	f.writeBaseFile(filepath.Join("src", "repo", "base"), "deployment.yaml", testDeployment)
+	gitInRepo("add", ".")
+	gitInRepo("commit", "-q", "-m", "v1")
+	gitInRepo("tag", "v1")
+
+	f.writeRootKustomize(fmt.Sprintf("resources:\n- file://%s//base?ref=v1", src))
+
+	yaml, deps, err := Build(f.tempdir.Path(), f.cache)
+	require.NoError(t, err)
+	assert.Contains(t, yaml, "image: monopole/hello:1")
+
+	// The base is read from the cache, so its files can be watched.
+	cacheDir := f.tempdir.JoinPath(".cache")
+	var cachedDeps []string
+	for _, dep := range deps {
+		if strings.HasPrefix(dep, cacheDir) {
+			cachedDeps = append(cachedDeps, filepath.Base(dep))
+		}
+	}
+	assert.ElementsMatch(t, []string{"kustomization.yaml", "deployment.yaml"}, cachedDeps)
+
+	// Once a repo has been fetched, later builds don't fetch it again.
+	f.writeBaseFile(filepath.Join("src", "repo", "base"), "deployment.yaml",
+		strings.Replace(testDeployment, "monopole/hello:1", "monopole/hello:2", 1))
+	gitInRepo("commit", "-q", "-a", "-m", "v2")
+	gitInRepo("tag", "-f", "v1")
+
+	yaml, _, err = Build(f.tempdir.Path(), f.cache)
+	require.NoError(t, err)
+	assert.Contains(t, yaml, "image: monopole/hello:1")
+}
@@ -926,6 +926,7 @@ func StateToView(s EngineState, mu *sync.RWMutex) view.View {
 			PendingBuildReason: mt.NextBuildReason(),
 			CurrentBuild:       currentBuild,
 			Endpoints:          model.LinksToURLStrings(endpoints), // hud can't handle link names, just send URLs
+			Labels:             mt.Manifest.Labels,
 			ResourceInfo:       resourceInfoView(mt),
 		}
 
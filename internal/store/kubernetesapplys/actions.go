@@ -1,6 +1,9 @@
 package kubernetesapplys
 
-import "github.com/tilt-dev/tilt/pkg/apis/core/v1alpha1"
+import (
+	"github.com/tilt-dev/tilt/pkg/apis/core/v1alpha1"
+	"github.com/tilt-dev/tilt/pkg/model"
+)
 
 type KubernetesApplyUpsertAction struct {
 	KubernetesApply *v1alpha1.KubernetesApply
@@ -21,3 +24,15 @@ func NewKubernetesApplyDeleteAction(n string) KubernetesApplyDeleteAction {
 }
 
 func (KubernetesApplyDeleteAction) Action() {}
+
+// Asks the engine to re-apply a manifest whose objects have drifted
+// from the last applied result.
+type KubernetesApplyReapplyAction struct {
+	ManifestName model.ManifestName
+}
+
+func NewKubernetesApplyReapplyAction(mn model.ManifestName) KubernetesApplyReapplyAction {
+	return KubernetesApplyReapplyAction{ManifestName: mn}
+}
+
+func (KubernetesApplyReapplyAction) Action() {}
@@ -3,6 +3,7 @@ package kubernetesapplys
 import (
 	"github.com/tilt-dev/tilt/internal/store"
 	"github.com/tilt-dev/tilt/internal/store/kubernetesdiscoverys"
+	"github.com/tilt-dev/tilt/pkg/model"
 )
 
 func HandleKubernetesApplyUpsertAction(state *store.EngineState, action KubernetesApplyUpsertAction) {
@@ -15,3 +16,7 @@ func HandleKubernetesApplyDeleteAction(state *store.EngineState, action Kubernet
 	delete(state.KubernetesApplys, action.Name)
 	kubernetesdiscoverys.RefreshKubernetesResource(state, action.Name)
 }
+
+func HandleKubernetesApplyReapplyAction(state *store.EngineState, action KubernetesApplyReapplyAction) {
+	state.AppendToTriggerQueue(action.ManifestName, model.BuildReasonFlagTriggerUnknown)
+}
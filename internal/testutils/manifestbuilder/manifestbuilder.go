@@ -38,6 +38,7 @@ type ManifestBuilder struct {
 	localDeps          []string
 	localAllowParallel bool
 	resourceDeps       []string
+	objectDeps         []string
 	triggerMode        model.TriggerMode
 
 	// When set to true, we'll always use the old build-and-deploy-based liveupdate,
@@ -150,6 +151,11 @@ func (b ManifestBuilder) WithResourceDeps(deps ...string) ManifestBuilder {
 	return b
 }
 
+func (b ManifestBuilder) WithObjectDeps(deps ...string) ManifestBuilder {
+	b.objectDeps = deps
+	return b
+}
+
 func (b ManifestBuilder) Build() model.Manifest {
 	var m model.Manifest
 
@@ -161,7 +167,7 @@ func (b ManifestBuilder) Build() model.Manifest {
 		} else if b.useLiveUpdateBAD {
 			iTarget.LiveUpdateReconciler = false
 		} else if len(b.dcConfigPaths) > 0 {
-			// Docker Compose must use the buildAndDeployer
+			// Docker Compose tests exercise the buildAndDeployer
 			iTarget.LiveUpdateReconciler = false
 		} else {
 			iTarget.LiveUpdateReconciler = true
@@ -183,7 +189,11 @@ func (b ManifestBuilder) Build() model.Manifest {
 		k8sTarget.PodReadinessMode = b.k8sPodReadiness
 
 		m = assembleK8s(
-			model.Manifest{Name: b.name, ResourceDependencies: rds},
+			model.Manifest{
+				Name:                 b.name,
+				ResourceDependencies: rds,
+				ObjectDependencies:   model.ManifestNames(b.objectDeps),
+			},
 			k8sTarget,
 			b.iTargets...)
 	} else if len(b.dcConfigPaths) > 0 {
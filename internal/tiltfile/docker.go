@@ -54,6 +54,7 @@ type dockerImage struct {
 	dbDockerfile     dockerfile.Dockerfile
 	dbBuildPath      string
 	dbBuildArgs      []string
+	dbContextFilter  []string // optional: dockerignore patterns inferred from the dockerfile with infer_context=True
 	customCommand    model.Cmd
 	customDeps       []string
 	customTag        string
@@ -111,7 +112,7 @@ func (s *tiltfileState) dockerBuild(thread *starlark.Thread, fn *starlark.Builti
 	var buildArgs value.StringStringMap
 	var network, platform value.Stringable
 	var ssh, secret, extraTags, cacheFrom value.StringOrStringList
-	var matchInEnvVars, pullParent bool
+	var matchInEnvVars, pullParent, inferContext bool
 	var overrideArgsVal starlark.Sequence
 	if err := s.unpackArgs(fn.Name(), args, kwargs,
 		"ref", &dockerRef,
@@ -134,6 +135,7 @@ func (s *tiltfileState) dockerBuild(thread *starlark.Thread, fn *starlark.Builti
 		"cache_from?", &cacheFrom,
 		"pull?", &pullParent,
 		"platform?", &platform,
+		"infer_context?", &inferContext,
 	); err != nil {
 		return nil, err
 	}
@@ -240,6 +242,25 @@ func (s *tiltfileState) dockerBuild(thread *starlark.Thread, fn *starlark.Builti
 	}
 	sort.Strings(buildArgsList)
 
+	var contextFilter []string
+	if inferContext {
+		if onlyVal != nil {
+			return nil, fmt.Errorf("Cannot specify both only and infer_context keyword arguments")
+		}
+
+		contextPaths, err := dockerfile.Dockerfile(dockerfileContents).ContextPaths(buildArgsList, targetStage)
+		if err != nil {
+			return nil, errors.Wrap(err, "infer_context: parsing dockerfile")
+		}
+
+		if len(contextPaths) == 1 && contextPaths[0] == dockerfile.WholeContext {
+			s.logger.Warnf("docker_build(%q, infer_context=True): the Dockerfile may read the entire context "+
+				"(e.g., COPY . .), so Tilt will watch and send the entire context.", dockerRef)
+		} else {
+			contextFilter = contextPathsToDockerignorePatterns(contextPaths)
+		}
+	}
+
 	r := &dockerImage{
 		workDir:          starkit.CurrentExecPath(thread),
 		dbDockerfilePath: dockerfilePath,
@@ -247,6 +268,7 @@ func (s *tiltfileState) dockerBuild(thread *starlark.Thread, fn *starlark.Builti
 		dbBuildPath:      context,
 		configurationRef: container.NewRefSelector(ref),
 		dbBuildArgs:      buildArgsList,
+		dbContextFilter:  contextFilter,
 		liveUpdate:       liveUpdate,
 		matchInEnvVars:   matchInEnvVars,
 		sshSpecs:         ssh.Values,
@@ -580,6 +602,17 @@ func onlysToDockerignorePatterns(onlys []string) []string {
 	return result
 }
 
+// Unlike onlysToDockerignorePatterns, we allow globs in the last path component,
+// because they're common in COPY instructions (e.g., COPY package*.json ./).
+// If the Dockerfile reads nothing from the context, we exclude everything.
+func contextPathsToDockerignorePatterns(paths []string) []string {
+	result := []string{"**"}
+	for _, p := range paths {
+		result = append(result, fmt.Sprintf("!%s", filepath.FromSlash(p)))
+	}
+	return result
+}
+
 func (s *tiltfileState) dockerignoresForImage(image *dockerImage) ([]model.Dockerignore, error) {
 	var paths []string
 	var source string
@@ -592,9 +625,21 @@ func (s *tiltfileState) dockerignoresForImage(image *dockerImage) ([]model.Docke
 		paths = append(paths, image.customDeps...)
 		source = fmt.Sprintf("custom_build(%q)", ref)
 	}
-	return s.dockerignoresFromPathsAndContextFilters(
+	result, err := s.dockerignoresFromPathsAndContextFilters(
 		source,
 		paths, image.ignores, image.onlys, image.dbDockerfilePath)
+	if err != nil {
+		return nil, err
+	}
+
+	if len(image.dbContextFilter) != 0 {
+		result = append(result, model.Dockerignore{
+			LocalPath: image.dbBuildPath,
+			Source:    fmt.Sprintf("docker_build(%q, infer_context=True)", ref),
+			Patterns:  image.dbContextFilter,
+		})
+	}
+	return result, nil
 }
 
 // Filter out all images that are suppressed.
@@ -33,7 +33,7 @@ func (e Plugin) OnStart(env *starkit.Environment) error {
 }
 
 func (e Plugin) dockerPruneSettings(thread *starlark.Thread, fn *starlark.Builtin, args starlark.Tuple, kwargs []starlark.Tuple) (starlark.Value, error) {
-	var disable bool
+	var disable, registry bool
 	var keepRecent starlark.Value
 	var intervalHrs, numBuilds, maxAgeMins int
 	if err := starkit.UnpackArgs(thread, fn.Name(), args, kwargs,
@@ -41,7 +41,8 @@ func (e Plugin) dockerPruneSettings(thread *starlark.Thread, fn *starlark.Builti
 		"max_age_mins?", &maxAgeMins,
 		"num_builds?", &numBuilds,
 		"interval_hrs?", &intervalHrs,
-		"keep_recent?", &keepRecent); err != nil {
+		"keep_recent?", &keepRecent,
+		"registry?", &registry); err != nil {
 		return nil, err
 	}
 
@@ -66,6 +67,7 @@ func (e Plugin) dockerPruneSettings(thread *starlark.Thread, fn *starlark.Builti
 			}
 			settings.KeepRecent = recent
 		}
+		settings.Registry = registry
 		return settings, nil
 	})
 
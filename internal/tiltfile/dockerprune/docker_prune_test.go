@@ -45,6 +45,22 @@ docker_prune_settings(keep_recent=5)
 	assert.Equal(t, model.DockerPruneDefaultKeepRecent, MustState(result).KeepRecent)
 }
 
+func TestDockerPruneRegistry(t *testing.T) {
+	f := NewFixture(t)
+	f.File("Tiltfile", `
+docker_prune_settings(registry=True)
+`)
+	result, err := f.ExecFile("Tiltfile")
+	assert.NoError(t, err)
+	assert.True(t, MustState(result).Registry)
+
+	f.File("Tiltfile.empty", `
+`)
+	result, err = f.ExecFile("Tiltfile.empty")
+	assert.NoError(t, err)
+	assert.False(t, MustState(result).Registry)
+}
+
 func NewFixture(tb testing.TB) *starkit.Fixture {
 	return starkit.NewFixture(tb, NewPlugin())
 }
@@ -28,6 +28,15 @@ func (tfl *FakeTiltfileLoader) Load(ctx context.Context, tf *v1alpha1.Tiltfile)
 	return tfl.Result
 }
 
+func (tfl *FakeTiltfileLoader) LoadUnitTest(ctx context.Context, tf *v1alpha1.Tiltfile) TiltfileLoadResult {
+	userConfigState := model.NewUserConfigState(tf.Spec.Args)
+	tfl.userConfigState = userConfigState
+	if tfl.Delegate != nil {
+		return tfl.Delegate.LoadUnitTest(ctx, tf)
+	}
+	return tfl.Result
+}
+
 // the UserConfigState that was passed to the last invocation of Load
 func (tfl *FakeTiltfileLoader) PassedUserConfigState() model.UserConfigState {
 	return tfl.userConfigState
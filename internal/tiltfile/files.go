@@ -6,7 +6,6 @@ import (
 	"io"
 	"io/ioutil"
 	"os"
-	"os/exec"
 	"path/filepath"
 	"strings"
 
@@ -14,6 +13,7 @@ import (
 	"github.com/tilt-dev/tilt/internal/localexec"
 	tiltfile_io "github.com/tilt-dev/tilt/internal/tiltfile/io"
 	"github.com/tilt-dev/tilt/internal/tiltfile/starkit"
+	"github.com/tilt-dev/tilt/internal/tiltfile/unittest"
 	"github.com/tilt-dev/tilt/internal/tiltfile/value"
 	"github.com/tilt-dev/tilt/pkg/logger"
 	"github.com/tilt-dev/tilt/pkg/model"
@@ -57,6 +57,13 @@ func (s *tiltfileState) local(thread *starlark.Thread, fn *starlark.Builtin, arg
 		return nil, err
 	}
 
+	if out, ok, err := unittest.LocalResult(thread, cmd); ok {
+		if err != nil {
+			return nil, err
+		}
+		return tiltfile_io.NewBlob(out, fmt.Sprintf("local: %s", cmd)), nil
+	}
+
 	out, err := s.execLocalCmd(thread, cmd, execCommandOptions{
 		logOutput:        !quiet,
 		logCommand:       !echoOff,
@@ -136,38 +143,15 @@ func (s *tiltfileState) kustomize(thread *starlark.Thread, fn *starlark.Builtin,
 		return nil, fmt.Errorf("Argument 0 (paths): %v", err)
 	}
 
-	// NOTE(nick): There's a bug in kustomize where it doesn't properly
-	// handle absolute paths. Convert to relative paths instead:
-	// https://github.com/kubernetes-sigs/kustomize/issues/2789
-	relKustomizePath, err := filepath.Rel(starkit.AbsWorkingDir(thread), absKustomizePath)
-	if err != nil {
-		return nil, err
-	}
-
-	cmd := model.Cmd{Argv: []string{"kustomize", "build", relKustomizePath}, Dir: starkit.AbsWorkingDir(thread)}
-
-	_, err = exec.LookPath(cmd.Argv[0])
-	if err != nil {
-		s.logger.Infof("Falling back to `kubectl kustomize` since `%s` was not found in PATH", cmd.Argv[0])
-		cmd.Argv = []string{"kubectl", "kustomize", relKustomizePath}
-	}
-
-	yaml, err := s.execLocalCmd(thread, cmd, execCommandOptions{
-		logOutput:  false,
-		logCommand: false,
-	})
-	if err != nil {
-		return nil, err
+	// Watch the files that kustomize read, even if the build failed,
+	// so that we reload the Tiltfile when the user fixes them.
+	yaml, deps, err := kustomize.Build(absKustomizePath, s.kustomizeCache)
+	recordErr := tiltfile_io.RecordReadPath(thread, tiltfile_io.WatchFileOnly, deps...)
+	if recordErr != nil {
+		return nil, recordErr
 	}
-	deps, err := kustomize.Deps(absKustomizePath)
 	if err != nil {
-		return nil, fmt.Errorf("resolving deps: %v", err)
-	}
-	for _, d := range deps {
-		err := tiltfile_io.RecordReadPath(thread, tiltfile_io.WatchRecursive, d)
-		if err != nil {
-			return nil, err
-		}
+		return nil, fmt.Errorf("kustomize %s: %v", absKustomizePath, err)
 	}
 
 	return tiltfile_io.NewBlob(yaml, fmt.Sprintf("kustomize: %s", absKustomizePath)), nil
@@ -220,13 +204,6 @@ func (s *tiltfileState) helm(thread *starlark.Thread, fn *starlark.Builtin, args
 		}
 	}
 
-	version, err := getHelmVersion()
-	if err != nil {
-		return nil, err
-	}
-
-	var cmd []string
-
 	if name == "" {
 		// Use 'chart' as the release name, so that the release name is stable
 		// across Tiltfile loads.
@@ -235,6 +212,45 @@ func (s *tiltfileState) helm(thread *starlark.Thread, fn *starlark.Builtin, args
 		name = "chart"
 	}
 
+	yaml, faked, err := unittest.HelmResult(thread, localPath)
+	if !faked {
+		yaml, err = s.helmTemplate(thread, localPath, name, namespace, valueFiles, set)
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	if namespace != "" {
+		// helm template --namespace doesn't inject the namespace, nor provide
+		// YAML that defines the namespace, so we have to do both ourselves :\
+		// https://github.com/helm/helm/issues/5465
+		parsed, err := k8s.ParseYAMLFromString(yaml)
+		if err != nil {
+			return nil, err
+		}
+
+		for i, e := range parsed {
+			parsed[i] = e.WithNamespace(e.NamespaceOrDefault(namespace))
+		}
+
+		yaml, err = k8s.SerializeSpecYAML(parsed)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	return tiltfile_io.NewBlob(yaml, fmt.Sprintf("helm: %s", localPath)), nil
+}
+
+// Runs `helm template` on the chart.
+func (s *tiltfileState) helmTemplate(thread *starlark.Thread, localPath, name, namespace string,
+	valueFiles, set value.StringOrStringList) (string, error) {
+	version, err := getHelmVersion()
+	if err != nil {
+		return "", err
+	}
+
+	var cmd []string
 	if version == helmV3_1andAbove {
 		cmd = []string{"helm", "template", name, localPath, "--include-crds"}
 	} else if version == helmV3_0 {
@@ -250,7 +266,7 @@ func (s *tiltfileState) helm(thread *starlark.Thread, fn *starlark.Builtin, args
 		cmd = append(cmd, "--values", valueFile)
 		err := tiltfile_io.RecordReadPath(thread, tiltfile_io.WatchFileOnly, starkit.AbsPath(thread, valueFile))
 		if err != nil {
-			return nil, err
+			return "", err
 		}
 	}
 	for _, setArg := range set.Values {
@@ -262,7 +278,7 @@ func (s *tiltfileState) helm(thread *starlark.Thread, fn *starlark.Builtin, args
 		logCommand: true,
 	})
 	if err != nil {
-		return nil, err
+		return "", err
 	}
 
 	yaml := filterHelmTestYAML(string(stdout))
@@ -272,31 +288,12 @@ func (s *tiltfileState) helm(thread *starlark.Thread, fn *starlark.Builtin, args
 		// https://github.com/tilt-dev/tilt/issues/3605
 		crds, err := getHelmCRDs(localPath)
 		if err != nil {
-			return nil, err
+			return "", err
 		}
 		yaml = strings.Join(append([]string{yaml}, crds...), "\n---\n")
 	}
 
-	if namespace != "" {
-		// helm template --namespace doesn't inject the namespace, nor provide
-		// YAML that defines the namespace, so we have to do both ourselves :\
-		// https://github.com/helm/helm/issues/5465
-		parsed, err := k8s.ParseYAMLFromString(yaml)
-		if err != nil {
-			return nil, err
-		}
-
-		for i, e := range parsed {
-			parsed[i] = e.WithNamespace(e.NamespaceOrDefault(namespace))
-		}
-
-		yaml, err = k8s.SerializeSpecYAML(parsed)
-		if err != nil {
-			return nil, err
-		}
-	}
-
-	return tiltfile_io.NewBlob(yaml, fmt.Sprintf("helm: %s", localPath)), nil
+	return yaml, nil
 }
 
 // NOTE(nick): This isn't perfect. For example, it doesn't handle chart deps
@@ -0,0 +1,120 @@
+package tiltfile
+
+import (
+	"fmt"
+	"os"
+
+	"go.starlark.net/starlark"
+
+	"github.com/tilt-dev/tilt/internal/k8s"
+	"github.com/tilt-dev/tilt/internal/tiltfile/starkit"
+	"github.com/tilt-dev/tilt/internal/tiltfile/unittest"
+	"github.com/tilt-dev/tilt/internal/tiltfile/value"
+	"github.com/tilt-dev/tilt/pkg/apis/core/v1alpha1"
+)
+
+type k8sHelmRelease struct {
+	spec v1alpha1.KubernetesHelmRelease
+	deps []string
+
+	// The objects the chart renders, for matching images.
+	//
+	// These are only added to the resource when we assemble it,
+	// so that they see all the images in the Tiltfile.
+	entities []k8s.K8sEntity
+}
+
+func (s *tiltfileState) helmRelease(thread *starlark.Thread, fn *starlark.Builtin, args starlark.Tuple, kwargs []starlark.Tuple) (starlark.Value, error) {
+	var name string
+	var chart starlark.Value
+	var releaseName string
+	var namespace string
+	var valueFiles value.StringOrStringList
+	var set value.StringOrStringList
+	err := s.unpackArgs(fn.Name(), args, kwargs,
+		"name", &name,
+		"chart", &chart,
+		"release_name?", &releaseName,
+		"namespace?", &namespace,
+		"values?", &valueFiles,
+		"set?", &set)
+	if err != nil {
+		return nil, err
+	}
+
+	chartPath, err := value.ValueToAbsPath(thread, chart)
+	if err != nil {
+		return nil, fmt.Errorf("Argument 1 (chart): %v", err)
+	}
+
+	info, err := os.Stat(chartPath)
+	if err != nil {
+		if os.IsNotExist(err) {
+			return nil, fmt.Errorf("Could not read Helm chart directory %q: does not exist", chartPath)
+		}
+		return nil, fmt.Errorf("Could not read Helm chart directory %q: %v", chartPath, err)
+	} else if !info.IsDir() {
+		return nil, fmt.Errorf("helm_release() may only be called on directories with Chart.yaml: %q", chartPath)
+	}
+
+	// The release is upgraded when these change, so we don't need to
+	// reload the Tiltfile.
+	deps := []string{chartPath}
+	subcharts, err := localSubchartDependenciesFromPath(chartPath)
+	if err != nil {
+		return nil, err
+	}
+	for _, d := range subcharts {
+		deps = append(deps, starkit.AbsPath(thread, d))
+	}
+
+	var absValueFiles []string
+	for _, valueFile := range valueFiles.Values {
+		absValueFiles = append(absValueFiles, starkit.AbsPath(thread, valueFile))
+	}
+	deps = append(deps, absValueFiles...)
+
+	if releaseName == "" {
+		releaseName = name
+	}
+
+	spec := v1alpha1.KubernetesHelmRelease{
+		ReleaseName: releaseName,
+		Chart:       chartPath,
+		Namespace:   namespace,
+		ValueFiles:  absValueFiles,
+		Set:         set.Values,
+	}
+
+	// Render the chart now, so that we know which images it uses.
+	yaml, faked, err := unittest.HelmResult(thread, chartPath)
+	if !faked {
+		yaml, err = k8s.TemplateHelmRelease(spec)
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	entities, err := k8s.ParseYAMLFromString(yaml)
+	if err != nil {
+		return nil, err
+	}
+	if namespace != "" {
+		for i, e := range entities {
+			entities[i] = e.WithNamespace(e.NamespaceOrDefault(namespace))
+		}
+	}
+
+	res, err := s.makeK8sResource(name)
+	if err != nil {
+		return nil, fmt.Errorf("error making resource for %s: %v", name, err)
+	}
+
+	res.helmRelease = &k8sHelmRelease{
+		spec:     spec,
+		deps:     deps,
+		entities: entities,
+	}
+
+	return starlark.None, nil
+}
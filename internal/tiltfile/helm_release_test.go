@@ -0,0 +1,121 @@
+package tiltfile
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/tilt-dev/tilt/pkg/model"
+)
+
+const releaseChartYAML = `apiVersion: v2
+name: foo
+version: 0.1.0
+`
+
+const releaseValuesYAML = `replicas: 1
+image: gcr.io/foo
+`
+
+const releaseDeploymentYAML = `apiVersion: apps/v1
+kind: Deployment
+metadata:
+  name: {{ .Release.Name }}
+  labels:
+    app: foo
+spec:
+  replicas: {{ .Values.replicas }}
+  selector:
+    matchLabels:
+      app: foo
+  template:
+    metadata:
+      labels:
+        app: foo
+    spec:
+      containers:
+      - name: foo
+        image: {{ .Values.image }}
+`
+
+func (f *fixture) setupHelmRelease() {
+	f.dockerfile("foo/Dockerfile")
+	f.file("chart/Chart.yaml", releaseChartYAML)
+	f.file("chart/values.yaml", releaseValuesYAML)
+	f.file("chart/templates/deployment.yaml", releaseDeploymentYAML)
+}
+
+func TestHelmRelease(t *testing.T) {
+	f := newFixture(t)
+	defer f.TearDown()
+
+	f.setupHelmRelease()
+	f.file("Tiltfile", `
+docker_build('gcr.io/foo', 'foo')
+helm_release('foo', './chart', set=['replicas=2'])
+`)
+
+	f.load("foo")
+
+	m := f.assertNextManifest("foo", db(image("gcr.io/foo")))
+	kt := m.K8sTarget()
+	require.NotNil(t, kt.HelmRelease)
+	assert.Equal(t, "foo", kt.HelmRelease.ReleaseName)
+	assert.Equal(t, f.JoinPath("chart"), kt.HelmRelease.Chart)
+	assert.Equal(t, []string{"replicas=2"}, kt.HelmRelease.Set)
+	assert.Equal(t, "", kt.YAML)
+	assert.Equal(t, []string{f.JoinPath("chart")}, kt.Dependencies())
+	assert.Equal(t, []model.TargetID{m.ImageTargetAt(0).ID()}, kt.DependencyIDs())
+
+	// The chart isn't a Tiltfile dependency, because the release
+	// is upgraded when it changes.
+	f.assertConfigFiles("Tiltfile", ".tiltignore", "foo/Dockerfile", "foo/.dockerignore")
+}
+
+func TestHelmReleaseArgs(t *testing.T) {
+	f := newFixture(t)
+	defer f.TearDown()
+
+	f.setupHelmRelease()
+	f.file("dev/values-dev.yaml", "replicas: 3\n")
+	f.file("Tiltfile", `
+helm_release('foo', './chart', release_name='rose-quartz', namespace='garnet', values=['./dev/values-dev.yaml'])
+k8s_resource('foo', port_forwards=8000)
+`)
+
+	f.load()
+
+	m := f.assertNextManifest("foo")
+	kt := m.K8sTarget()
+	require.NotNil(t, kt.HelmRelease)
+	assert.Equal(t, "rose-quartz", kt.HelmRelease.ReleaseName)
+	assert.Equal(t, "garnet", kt.HelmRelease.Namespace)
+	assert.Equal(t, []string{f.JoinPath("dev/values-dev.yaml")}, kt.HelmRelease.ValueFiles)
+	assert.Equal(t, []string{f.JoinPath("chart"), f.JoinPath("dev/values-dev.yaml")}, kt.Dependencies())
+	require.NotNil(t, kt.PortForwardTemplateSpec)
+	assert.Equal(t, int32(8000), kt.PortForwardTemplateSpec.Forwards[0].LocalPort)
+}
+
+func TestHelmReleaseMissingChart(t *testing.T) {
+	f := newFixture(t)
+	defer f.TearDown()
+
+	f.file("Tiltfile", `
+helm_release('foo', './chart')
+`)
+
+	f.loadErrString("Could not read Helm chart directory", "does not exist")
+}
+
+func TestHelmReleaseBadValues(t *testing.T) {
+	f := newFixture(t)
+	defer f.TearDown()
+
+	f.setupHelmRelease()
+	f.file("Tiltfile", `
+helm_release('foo', './chart', values=['./missing.yaml'])
+`)
+
+	f.loadErrString("helm chart", "missing.yaml")
+}
@@ -54,6 +54,10 @@ type k8sResource struct {
 
 	discoveryStrategy v1alpha1.KubernetesDiscoveryStrategy
 
+	applyMode v1alpha1.KubernetesApplyMode
+
+	driftDetection *v1alpha1.KubernetesDriftDetection
+
 	dependencyIDs []model.TargetID
 
 	triggerMode triggerMode
@@ -68,6 +72,7 @@ type k8sResource struct {
 	labels map[string]string
 
 	customDeploy *k8sCustomDeploy
+	helmRelease  *k8sHelmRelease
 }
 
 // holds options passed to `k8s_resource` until assembly happens
@@ -85,6 +90,8 @@ type k8sResourceOptions struct {
 	manuallyGrouped   bool
 	podReadinessMode  model.PodReadinessMode
 	discoveryStrategy v1alpha1.KubernetesDiscoveryStrategy
+	applyMode         v1alpha1.KubernetesApplyMode
+	driftDetection    *v1alpha1.KubernetesDriftDetection
 	links             []model.Link
 	labels            map[string]string
 }
@@ -164,6 +171,10 @@ func (s *tiltfileState) extractSecrets() model.SecretSet {
 			result.AddAll(secrets)
 		}
 	}
+
+	if s.secretSettings.ScrubSecrets {
+		result.AddAll(s.secretSettings.ExtraSecrets)
+	}
 	return result
 }
 
@@ -276,6 +287,8 @@ func (s *tiltfileState) k8sResource(thread *starlark.Thread, fn *starlark.Builti
 	var autoInit = value.BoolOrNone{Value: true}
 	var labels value.LabelSet
 	var discoveryStrategy tiltfile_k8s.DiscoveryStrategy
+	var applyMode tiltfile_k8s.ApplyMode
+	var driftDetection tiltfile_k8s.DriftDetection
 
 	if err := s.unpackArgs(fn.Name(), args, kwargs,
 		"workload?", &workload,
@@ -290,6 +303,8 @@ func (s *tiltfileState) k8sResource(thread *starlark.Thread, fn *starlark.Builti
 		"links?", &links,
 		"labels?", &labels,
 		"discovery_strategy?", &discoveryStrategy,
+		"apply_mode?", &applyMode,
+		"drift_detection?", &driftDetection,
 	); err != nil {
 		return nil, err
 	}
@@ -350,6 +365,8 @@ func (s *tiltfileState) k8sResource(thread *starlark.Thread, fn *starlark.Builti
 		links:             links.Links,
 		labels:            labelMap,
 		discoveryStrategy: v1alpha1.KubernetesDiscoveryStrategy(discoveryStrategy),
+		applyMode:         v1alpha1.KubernetesApplyMode(applyMode),
+		driftDetection:    driftDetection.Value,
 	})
 
 	return starlark.None, nil
@@ -30,3 +30,56 @@ func (ds *DiscoveryStrategy) Unpack(v starlark.Value) error {
 	*ds = DiscoveryStrategy(kdStrategy)
 	return nil
 }
+
+// Deserializing apply mode from starlark values.
+type ApplyMode v1alpha1.KubernetesApplyMode
+
+func (m *ApplyMode) Unpack(v starlark.Value) error {
+	s, ok := value.AsString(v)
+	if !ok {
+		return fmt.Errorf("Must be a string. Got: %s", v.Type())
+	}
+
+	applyMode := v1alpha1.KubernetesApplyMode(s)
+	if !(applyMode == "" ||
+		applyMode == v1alpha1.KubernetesApplyModeDefault ||
+		applyMode == v1alpha1.KubernetesApplyModeServerSide) {
+		return fmt.Errorf("Invalid. Must be one of: %q, %q",
+			v1alpha1.KubernetesApplyModeDefault,
+			v1alpha1.KubernetesApplyModeServerSide)
+	}
+
+	*m = ApplyMode(applyMode)
+	return nil
+}
+
+const (
+	DriftDetectionWarn    = "warn"
+	DriftDetectionReapply = "reapply"
+)
+
+// Deserializing drift detection from starlark values.
+//
+// "warn" reports drift, and "reapply" also reapplies the YAML when it drifts.
+type DriftDetection struct {
+	Value *v1alpha1.KubernetesDriftDetection
+}
+
+func (d *DriftDetection) Unpack(v starlark.Value) error {
+	s, ok := value.AsString(v)
+	if !ok {
+		return fmt.Errorf("Must be a string. Got: %s", v.Type())
+	}
+
+	switch s {
+	case "":
+		d.Value = nil
+	case DriftDetectionWarn:
+		d.Value = &v1alpha1.KubernetesDriftDetection{}
+	case DriftDetectionReapply:
+		d.Value = &v1alpha1.KubernetesDriftDetection{Reapply: true}
+	default:
+		return fmt.Errorf("Invalid. Must be one of: %q, %q", DriftDetectionWarn, DriftDetectionReapply)
+	}
+	return nil
+}
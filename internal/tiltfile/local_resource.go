@@ -3,6 +3,8 @@ package tiltfile
 import (
 	"fmt"
 	"path/filepath"
+	"strings"
+	"time"
 
 	"github.com/pkg/errors"
 	"go.starlark.net/starlark"
@@ -37,6 +39,10 @@ type localResource struct {
 	isTest bool
 
 	readinessProbe *v1alpha1.Probe
+
+	serveRestartPolicy          v1alpha1.CmdRestartPolicy
+	serveStopSignal             string
+	serveTerminationGracePeriod time.Duration
 }
 
 func (s *tiltfileState) localResource(thread *starlark.Thread, fn *starlark.Builtin, args starlark.Tuple, kwargs []starlark.Tuple) (starlark.Value, error) {
@@ -45,6 +51,9 @@ func (s *tiltfileState) localResource(thread *starlark.Thread, fn *starlark.Buil
 	var updateEnv, serveEnv value.StringStringMap
 	var triggerMode triggerMode
 	var readinessProbe probe.Probe
+	var serveRestartPolicy string
+	var serveStopSignal string
+	var serveTerminationGracePeriod value.Duration
 	var updateCmdDirVal, serveCmdDirVal starlark.Value
 
 	deps := value.NewLocalPathListUnpacker(thread)
@@ -89,6 +98,9 @@ func (s *tiltfileState) localResource(thread *starlark.Thread, fn *starlark.Buil
 		"readiness_probe?", &readinessProbe,
 		"dir?", &updateCmdDirVal,
 		"serve_dir?", &serveCmdDirVal,
+		"serve_restart_policy?", &serveRestartPolicy,
+		"serve_stop_signal?", &serveStopSignal,
+		"serve_termination_grace_period?", &serveTerminationGracePeriod,
 	); err != nil {
 		return nil, err
 	}
@@ -122,6 +134,28 @@ func (s *tiltfileState) localResource(thread *starlark.Thread, fn *starlark.Buil
 		return nil, fmt.Errorf("local_resource must have a cmd and/or a serve_cmd, but both were empty")
 	}
 
+	restartPolicy, err := parseRestartPolicy(serveRestartPolicy)
+	if err != nil {
+		return nil, errors.Wrapf(err, "%s: serve_restart_policy", fn.Name())
+	}
+	if restartPolicy != "" && serveCmd.Empty() {
+		return nil, fmt.Errorf("%s: serve_restart_policy requires a serve_cmd", fn.Name())
+	}
+
+	stopSignal, err := parseStopSignal(serveStopSignal)
+	if err != nil {
+		return nil, errors.Wrapf(err, "%s: serve_stop_signal", fn.Name())
+	}
+	if stopSignal != "" && serveCmd.Empty() {
+		return nil, fmt.Errorf("%s: serve_stop_signal requires a serve_cmd", fn.Name())
+	}
+	if serveTerminationGracePeriod.AsDuration() < 0 {
+		return nil, fmt.Errorf("%s: serve_termination_grace_period must be non-negative", fn.Name())
+	}
+	if !serveTerminationGracePeriod.IsZero() && serveCmd.Empty() {
+		return nil, fmt.Errorf("%s: serve_termination_grace_period requires a serve_cmd", fn.Name())
+	}
+
 	res := localResource{
 		name:           string(name),
 		updateCmd:      updateCmd,
@@ -139,6 +173,10 @@ func (s *tiltfileState) localResource(thread *starlark.Thread, fn *starlark.Buil
 		tags:           tags,
 		isTest:         isTest,
 		readinessProbe: readinessProbe.Spec(),
+
+		serveRestartPolicy:          restartPolicy,
+		serveStopSignal:             stopSignal,
+		serveTerminationGracePeriod: serveTerminationGracePeriod.AsDuration(),
 	}
 
 	// check for duplicate resources by name and throw error if found
@@ -151,3 +189,38 @@ func (s *tiltfileState) localResource(thread *starlark.Thread, fn *starlark.Buil
 
 	return starlark.None, nil
 }
+
+// Accepts the restart policy in the API form (e.g., "OnFailure")
+// or in snake case (e.g., "on_failure").
+func parseRestartPolicy(s string) (v1alpha1.CmdRestartPolicy, error) {
+	normalized := strings.ToLower(strings.NewReplacer("_", "", "-", "").Replace(s))
+	switch normalized {
+	case "":
+		return "", nil
+	case "never":
+		return v1alpha1.CmdRestartPolicyNever, nil
+	case "onfailure":
+		return v1alpha1.CmdRestartPolicyOnFailure, nil
+	case "always":
+		return v1alpha1.CmdRestartPolicyAlways, nil
+	}
+	return "", fmt.Errorf("invalid restart policy %q. Must be one of: 'never', 'on_failure', 'always'", s)
+}
+
+// Accepts the signal name with or without the SIG prefix,
+// in any case (e.g., "SIGINT", "int").
+func parseStopSignal(s string) (string, error) {
+	if s == "" {
+		return "", nil
+	}
+	sig := strings.ToUpper(s)
+	if !strings.HasPrefix(sig, "SIG") {
+		sig = "SIG" + sig
+	}
+	for _, valid := range v1alpha1.CmdStopSignals {
+		if sig == valid {
+			return sig, nil
+		}
+	}
+	return "", fmt.Errorf("invalid stop signal %q. Must be one of: %s", s, strings.Join(v1alpha1.CmdStopSignals, ", "))
+}
@@ -1,11 +1,21 @@
 package secretsettings
 
 import (
+	"bufio"
+	"bytes"
+	"fmt"
+	"os"
+	"path/filepath"
+	"regexp"
+	"strings"
+
 	"go.starlark.net/starlark"
 
 	"github.com/tilt-dev/tilt/pkg/model"
 
+	"github.com/tilt-dev/tilt/internal/tiltfile/io"
 	"github.com/tilt-dev/tilt/internal/tiltfile/starkit"
+	"github.com/tilt-dev/tilt/internal/tiltfile/value"
 )
 
 // Implements functions for dealing with k8s secret settings.
@@ -25,20 +35,112 @@ func (e Plugin) OnStart(env *starkit.Environment) error {
 }
 
 func (e Plugin) secretSettings(thread *starlark.Thread, fn *starlark.Builtin, args starlark.Tuple, kwargs []starlark.Tuple) (starlark.Value, error) {
-	var disable bool
+	var disable value.BoolOrNone
+	var values, envVars, patterns value.StringOrStringList
+	files := value.NewLocalPathListUnpacker(thread)
 	if err := starkit.UnpackArgs(thread, fn.Name(), args, kwargs,
-		"disable_scrub", &disable); err != nil {
+		"disable_scrub?", &disable,
+		"values?", &values,
+		"env_vars?", &envVars,
+		"files?", &files,
+		"patterns?", &patterns); err != nil {
 		return nil, err
 	}
 
+	extras := model.SecretSet{}
+	for _, v := range values.Values {
+		extras.AddSecret("secret_settings", "value", []byte(v))
+	}
+
+	// An unset env var isn't an error, because the same Tiltfile
+	// often runs in environments where the secret isn't available.
+	for _, name := range envVars.Values {
+		v, ok := os.LookupEnv(name)
+		if ok {
+			extras.AddSecret("env", name, []byte(v))
+		}
+	}
+
+	for _, f := range files.Value {
+		contents, err := io.ReadFile(thread, f)
+		if err != nil {
+			return nil, fmt.Errorf("%s: reading %s: %v", fn.Name(), f, err)
+		}
+		extras.AddAll(secretsFromFile(filepath.Base(f), contents))
+	}
+
+	for _, p := range patterns.Values {
+		re, err := regexp.Compile(p)
+		if err != nil {
+			return nil, fmt.Errorf("%s: invalid pattern %q: %v", fn.Name(), p, err)
+		}
+		extras.AddPattern("secret_settings", re)
+	}
+
 	err := starkit.SetState(thread, func(settings model.SecretSettings) model.SecretSettings {
-		settings.ScrubSecrets = !disable
+		if disable.IsSet {
+			settings.ScrubSecrets = !disable.Value
+		}
+
+		// Don't mutate the old state.
+		secrets := model.SecretSet{}
+		secrets.AddAll(settings.ExtraSecrets)
+		secrets.AddAll(extras)
+		settings.ExtraSecrets = secrets
 		return settings
 	})
 
 	return starlark.None, err
 }
 
+// Reads secrets from a file.
+//
+// Each KEY=VALUE line (as in a .env file) is a secret VALUE.
+// Any other line is a secret in its entirety (as in a token file).
+func secretsFromFile(name string, contents []byte) model.SecretSet {
+	result := model.SecretSet{}
+	scanner := bufio.NewScanner(bytes.NewReader(contents))
+	lineNum := 0
+	for scanner.Scan() {
+		lineNum++
+		line := strings.TrimSpace(scanner.Text())
+		if line == "" || strings.HasPrefix(line, "#") {
+			continue
+		}
+
+		key, v, ok := parseEnvLine(line)
+		if !ok {
+			result.AddSecret(name, fmt.Sprintf("line %d", lineNum), []byte(line))
+			continue
+		}
+		if v != "" {
+			result.AddSecret(name, key, []byte(v))
+		}
+	}
+	return result
+}
+
+var envKeyRe = regexp.MustCompile(`^[A-Za-z_][A-Za-z0-9_.]*$`)
+
+func parseEnvLine(line string) (key, v string, ok bool) {
+	line = strings.TrimPrefix(line, "export ")
+	i := strings.Index(line, "=")
+	if i == -1 {
+		return "", "", false
+	}
+
+	key = strings.TrimSpace(line[:i])
+	if !envKeyRe.MatchString(key) {
+		return "", "", false
+	}
+
+	v = strings.TrimSpace(line[i+1:])
+	if len(v) >= 2 && (v[0] == '"' || v[0] == '\'') && v[len(v)-1] == v[0] {
+		v = v[1 : len(v)-1]
+	}
+	return key, v, true
+}
+
 var _ starkit.StatefulPlugin = Plugin{}
 
 func MustState(model starkit.Model) model.SecretSettings {
@@ -11,32 +11,60 @@ import (
 	"github.com/tilt-dev/go-get"
 )
 
+const DefaultExtensionRegistry = "https://github.com/tilt-dev/tilt-extensions"
+
+const defaultExtensionRepoImportPath = "github.com/tilt-dev/tilt-extensions"
+
 type Downloader interface {
 	RootDir() string
+	DestinationPath(pkg string) string
 	Download(pkg string) (string, error)
+	HeadRef(pkg string) (string, error)
+	RefSync(pkg string, ref string) error
 }
 
-type TempDirDownloader struct {
+type DirDownloader struct {
 	rootDir string
 }
 
-func NewTempDirDownloader() (*TempDirDownloader, error) {
+func NewTempDirDownloader() (*DirDownloader, error) {
 	dir, err := ioutil.TempDir("", "tilt-extensions")
 	if err != nil {
 		return nil, err
 	}
-	return &TempDirDownloader{rootDir: dir}, nil
+	return &DirDownloader{rootDir: dir}, nil
 }
 
-func (d *TempDirDownloader) RootDir() string {
+// Downloads into an existing directory, e.g., the directory where
+// the ExtensionRepo controller keeps its checkouts.
+func NewDirDownloader(dir string) *DirDownloader {
+	return &DirDownloader{rootDir: dir}
+}
+
+func (d *DirDownloader) RootDir() string {
 	return d.rootDir
 }
 
-func (d *TempDirDownloader) Download(pkg string) (string, error) {
+func (d *DirDownloader) DestinationPath(pkg string) string {
+	dlr := get.NewDownloader(d.rootDir)
+	return dlr.DestinationPath(pkg)
+}
+
+func (d *DirDownloader) Download(pkg string) (string, error) {
 	dlr := get.NewDownloader(d.rootDir)
 	return dlr.Download(pkg)
 }
 
+func (d *DirDownloader) HeadRef(pkg string) (string, error) {
+	dlr := get.NewDownloader(d.rootDir)
+	return dlr.HeadRef(pkg)
+}
+
+func (d *DirDownloader) RefSync(pkg string, ref string) error {
+	dlr := get.NewDownloader(d.rootDir)
+	return dlr.RefSync(pkg, ref)
+}
+
 type GithubFetcher struct {
 	dlr Downloader
 }
@@ -49,16 +77,32 @@ func (f *GithubFetcher) CleanUp() error {
 	return os.RemoveAll(f.dlr.RootDir())
 }
 
-func (f *GithubFetcher) Fetch(ctx context.Context, moduleName string) (ModuleContents, error) {
-	dir, err := f.dlr.Download(path.Join("github.com/tilt-dev/tilt-extensions", moduleName))
+// Fetches the extension from tilt-extensions at the given ref.
+// If the ref is empty, fetches the latest version.
+func (f *GithubFetcher) Fetch(ctx context.Context, moduleName string, ref string) (ModuleContents, error) {
+	pkg := path.Join(defaultExtensionRepoImportPath, moduleName)
+	dir, err := f.dlr.Download(pkg)
 	if err != nil {
 		return ModuleContents{}, fmt.Errorf("Fetching tilt-extensions: %v", err)
 	}
 
+	if ref != "" {
+		err := f.dlr.RefSync(pkg, ref)
+		if err != nil {
+			return ModuleContents{}, fmt.Errorf("Fetching tilt-extensions: sync to ref %s: %v", ref, err)
+		}
+	}
+
+	checkoutRef, err := f.dlr.HeadRef(pkg)
+	if err != nil {
+		return ModuleContents{}, fmt.Errorf("Fetching tilt-extensions: determining head: %v", err)
+	}
+
 	return ModuleContents{
 		Name:              moduleName,
 		Dir:               dir,
-		ExtensionRegistry: "https://github.com/tilt-dev/tilt-extensions",
+		ExtensionRegistry: DefaultExtensionRegistry,
+		CheckoutRef:       checkoutRef,
 		TimeFetched:       time.Now(),
 	}, nil
 }
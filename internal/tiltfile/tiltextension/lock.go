@@ -0,0 +1,224 @@
+package tiltextension
+
+import (
+	"crypto/sha256"
+	"encoding/json"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"sort"
+
+	"github.com/pkg/errors"
+
+	"github.com/tilt-dev/tilt/internal/controllers/apiset"
+	"github.com/tilt-dev/tilt/pkg/apis/core/v1alpha1"
+)
+
+const lockFileName = "extensions.lock"
+
+// The lock file pins every extension to the exact contents that
+// were loaded, so that builds are reproducible across machines.
+//
+// It lives at tilt_modules/extensions.lock, next to the main Tiltfile,
+// and is meant to be checked in.
+type LockFile struct {
+	Repos      []LockedRepo      `json:"repos,omitempty"`
+	Extensions []LockedExtension `json:"extensions,omitempty"`
+}
+
+// An ExtensionRepo, pinned to a commit.
+type LockedRepo struct {
+	Name        string `json:"name"`
+	URL         string `json:"url"`
+	CheckoutRef string `json:"checkoutRef"`
+}
+
+// An extension, pinned to the hash of its contents.
+type LockedExtension struct {
+	Name string `json:"name"`
+
+	// The ExtensionRepo the extension was loaded from.
+	//
+	// Empty for extensions loaded with ext://, which come from
+	// the default tilt-extensions repo.
+	RepoName string `json:"repoName,omitempty"`
+	RepoPath string `json:"repoPath"`
+
+	// The commit the extension was fetched at.
+	//
+	// Only set for ext:// extensions. Other extensions are pinned by their repo.
+	CheckoutRef string `json:"checkoutRef,omitempty"`
+
+	ContentHash string `json:"contentHash"`
+}
+
+// Returns the path of the lock file for the Tiltfile in the given directory.
+func LockFilePath(tiltfileDir string) string {
+	return filepath.Join(tiltfileDir, extensionDirName, lockFileName)
+}
+
+// Reads the lock file. A missing lock file is treated as empty.
+func ReadLockFile(path string) (LockFile, error) {
+	var lf LockFile
+	b, err := ioutil.ReadFile(path)
+	if os.IsNotExist(err) {
+		return lf, nil
+	} else if err != nil {
+		return lf, errors.Wrapf(err, "reading %s", path)
+	}
+
+	err = json.Unmarshal(b, &lf)
+	if err != nil {
+		return lf, errors.Wrapf(err, "parsing %s", path)
+	}
+	return lf, nil
+}
+
+// Writes the lock file, sorted so that it diffs cleanly.
+func WriteLockFile(path string, lf LockFile) error {
+	sort.Slice(lf.Repos, func(i, j int) bool {
+		return lf.Repos[i].Name < lf.Repos[j].Name
+	})
+	sort.Slice(lf.Extensions, func(i, j int) bool {
+		if lf.Extensions[i].RepoName != lf.Extensions[j].RepoName {
+			return lf.Extensions[i].RepoName < lf.Extensions[j].RepoName
+		}
+		return lf.Extensions[i].Name < lf.Extensions[j].Name
+	})
+
+	js, err := json.MarshalIndent(lf, "", "  ")
+	if err != nil {
+		return errors.Wrap(err, "internal error: unable to marshal lock file as JSON")
+	}
+
+	err = os.MkdirAll(filepath.Dir(path), os.FileMode(0700))
+	if err != nil {
+		return err
+	}
+
+	err = ioutil.WriteFile(path, append(js, '\n'), 0644)
+	if err != nil {
+		return errors.Wrapf(err, "unable to write lock file at path %s", path)
+	}
+	return nil
+}
+
+func (lf LockFile) Repo(name string) (LockedRepo, bool) {
+	for _, r := range lf.Repos {
+		if r.Name == name {
+			return r, true
+		}
+	}
+	return LockedRepo{}, false
+}
+
+func (lf LockFile) Extension(repoName, name string) (LockedExtension, bool) {
+	for _, e := range lf.Extensions {
+		if e.RepoName == repoName && e.Name == name {
+			return e, true
+		}
+	}
+	return LockedExtension{}, false
+}
+
+// Adds the repo to the lock, replacing any repo with the same name.
+func (lf *LockFile) SetRepo(repo LockedRepo) {
+	for i, r := range lf.Repos {
+		if r.Name == repo.Name {
+			lf.Repos[i] = repo
+			return
+		}
+	}
+	lf.Repos = append(lf.Repos, repo)
+}
+
+// Adds the extension to the lock, replacing any extension with the same name
+// from the same repo.
+func (lf *LockFile) SetExtension(ext LockedExtension) {
+	for i, e := range lf.Extensions {
+		if e.RepoName == ext.RepoName && e.Name == ext.Name {
+			lf.Extensions[i] = ext
+			return
+		}
+	}
+	lf.Extensions = append(lf.Extensions, ext)
+}
+
+// Computes a hash of the contents of an extension directory:
+// the path, type, and contents of every file (in lexical order).
+//
+// VCS metadata is skipped, so the hash of a vendored extension
+// matches the hash of a fresh checkout.
+func HashDir(dir string) (string, error) {
+	h := sha256.New()
+	err := filepath.Walk(dir, func(path string, info os.FileInfo, err error) error {
+		if err != nil {
+			return err
+		}
+
+		if info.IsDir() && info.Name() == ".git" {
+			return filepath.SkipDir
+		}
+
+		rel, err := filepath.Rel(dir, path)
+		if err != nil {
+			return err
+		}
+		rel = filepath.ToSlash(rel)
+
+		switch {
+		case info.IsDir():
+			_, err = fmt.Fprintf(h, "dir:%s\n", rel)
+			return err
+		case info.Mode()&os.ModeSymlink != 0:
+			target, err := os.Readlink(path)
+			if err != nil {
+				return err
+			}
+			_, err = fmt.Fprintf(h, "symlink:%s:%s\n", rel, target)
+			return err
+		case info.Mode().IsRegular():
+			_, err = fmt.Fprintf(h, "file:%s:%d\n", rel, info.Size())
+			if err != nil {
+				return err
+			}
+			f, err := os.Open(path)
+			if err != nil {
+				return err
+			}
+			defer func() { _ = f.Close() }()
+			_, err = io.Copy(h, f)
+			return err
+		}
+		return nil
+	})
+	if err != nil {
+		return "", errors.Wrapf(err, "hashing %s", dir)
+	}
+	return fmt.Sprintf("sha256:%x", h.Sum(nil)), nil
+}
+
+// Pins the ExtensionRepo and Extension objects in the set to the lock.
+//
+// Repos without an explicit ref are checked out at the locked commit,
+// and extensions fail to load if their contents don't match the locked hash.
+// A lock entry only applies if the object still points to the same place.
+func ApplyLock(lf LockFile, set apiset.ObjectSet) {
+	for name, obj := range set.GetSetForType(&v1alpha1.ExtensionRepo{}) {
+		repo := obj.(*v1alpha1.ExtensionRepo)
+		locked, ok := lf.Repo(name)
+		if ok && repo.Spec.Ref == "" && repo.Spec.URL == locked.URL {
+			repo.Spec.Ref = locked.CheckoutRef
+		}
+	}
+
+	for name, obj := range set.GetSetForType(&v1alpha1.Extension{}) {
+		ext := obj.(*v1alpha1.Extension)
+		locked, ok := lf.Extension(ext.Spec.RepoName, name)
+		if ok && ext.Spec.ContentHash == "" && ext.Spec.RepoPath == locked.RepoPath {
+			ext.Spec.ContentHash = locked.ContentHash
+		}
+	}
+}
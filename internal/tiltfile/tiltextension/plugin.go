@@ -6,7 +6,9 @@ package tiltextension
 
 import (
 	"context"
+	"fmt"
 	"os"
+	"path/filepath"
 	"strings"
 
 	"go.starlark.net/starlark"
@@ -39,7 +41,8 @@ func (e Plugin) NewState() interface{} {
 }
 
 type Fetcher interface {
-	Fetch(ctx context.Context, moduleName string) (ModuleContents, error)
+	// Fetches the module at the given ref. If the ref is empty, fetches the latest version.
+	Fetch(ctx context.Context, moduleName string, ref string) (ModuleContents, error)
 	CleanUp() error
 }
 
@@ -78,25 +81,52 @@ func (e *Plugin) LocalPath(t *starlark.Thread, arg string) (localPath string, er
 		}
 	}()
 
+	lockPath := e.store.LockFilePath()
+	lock, err := ReadLockFile(lockPath)
+	if err != nil {
+		return "", err
+	}
+	locked, isLocked := lock.Extension("", moduleName)
+
 	// If the module can't be found we fetch it below
 	localPath, err = e.store.ModulePath(ctx, moduleName)
 	if err != nil && !os.IsNotExist(err) {
 		return "", err
 	}
-	if localPath != "" {
+
+	if localPath == "" {
+		contents, err := e.fetcher.Fetch(ctx, moduleName, locked.CheckoutRef)
+		if err != nil {
+			return "", err
+		}
+
+		defer func() {
+			_ = e.fetcher.CleanUp()
+		}()
+
+		localPath, err = e.store.Write(ctx, contents)
+		if err != nil {
+			return "", err
+		}
+	}
+
+	// The lock file is only written by `tilt ext vendor` and `tilt ext update`,
+	// so an extension that isn't locked loads whatever version we have.
+	if !isLocked {
 		return localPath, nil
 	}
 
-	contents, err := e.fetcher.Fetch(ctx, moduleName)
+	hash, err := HashDir(filepath.Dir(localPath))
 	if err != nil {
 		return "", err
 	}
-
-	defer func() {
-		_ = e.fetcher.CleanUp()
-	}()
-
-	return e.store.Write(ctx, contents)
+	if hash != locked.ContentHash {
+		return "", fmt.Errorf("extension %s doesn't match %s "+
+			"(expected content hash %s, got %s).\n"+
+			"To restore the locked version, run `tilt ext vendor`. To upgrade it, run `tilt ext update %s`",
+			moduleName, lockPath, locked.ContentHash, hash, moduleName)
+	}
+	return localPath, nil
 }
 
 var _ starkit.LoadInterceptor = (*Plugin)(nil)
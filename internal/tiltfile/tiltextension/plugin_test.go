@@ -3,11 +3,13 @@ package tiltextension
 import (
 	"context"
 	"fmt"
+	"os"
 	"path/filepath"
 	"strings"
 	"testing"
 
 	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
 
 	"github.com/tilt-dev/tilt/internal/testutils/tempdir"
 	"github.com/tilt-dev/tilt/internal/tiltfile/include"
@@ -122,25 +124,83 @@ printFoo()
 	f.assertLoadRecorded(res, "unfetchable")
 }
 
+func TestFetchDoesntWriteLock(t *testing.T) {
+	f := newExtensionFixture(t)
+	defer f.tearDown()
+
+	f.tiltfile(`
+load("ext://fetchable", "printFoo")
+printFoo()
+`)
+
+	f.assertExecOutput("foo")
+
+	_, err := os.Stat(f.store.LockFilePath())
+	assert.True(t, os.IsNotExist(err))
+}
+
+func TestFetchAtLockedRef(t *testing.T) {
+	f := newExtensionFixture(t)
+	defer f.tearDown()
+
+	f.tiltfile(`
+load("ext://fetchable", "printFoo")
+printFoo()
+`)
+	f.writeLock(LockFile{Extensions: []LockedExtension{{
+		Name:        "fetchable",
+		RepoPath:    "fetchable",
+		CheckoutRef: "locked-ref",
+		ContentHash: hashOfTiltfile(t, libText),
+	}}})
+
+	f.assertExecOutput("foo")
+	assert.Equal(t, "locked-ref", f.fetcher.lastRef)
+}
+
+func TestModifiedLockedExtensionFails(t *testing.T) {
+	f := newExtensionFixture(t)
+	defer f.tearDown()
+
+	f.tiltfile(`
+load("ext://unfetchable", "printFoo")
+printFoo()
+`)
+	f.writeModuleLocally("unfetchable", libText)
+	f.writeLock(LockFile{Extensions: []LockedExtension{{
+		Name:        "unfetchable",
+		RepoPath:    "unfetchable",
+		CheckoutRef: "locked-ref",
+		ContentHash: hashOfTiltfile(t, libText),
+	}}})
+	f.assertExecOutput("foo")
+
+	f.writeModuleLocally("unfetchable", libText+"\nprint('tampered')\n")
+	f.assertError("extension unfetchable doesn't match")
+}
+
 type extensionFixture struct {
-	t   *testing.T
-	skf *starkit.Fixture
-	tmp *tempdir.TempDirFixture
+	t       *testing.T
+	skf     *starkit.Fixture
+	tmp     *tempdir.TempDirFixture
+	fetcher *fakeFetcher
+	store   *LocalStore
 }
 
 func newExtensionFixture(t *testing.T) *extensionFixture {
 	tmp := tempdir.NewTempDirFixture(t)
-	ext := NewPlugin(
-		&fakeFetcher{t: t},
-		NewLocalStore(tmp.JoinPath("project")),
-	)
+	fetcher := &fakeFetcher{t: t}
+	store := NewLocalStore(tmp.JoinPath("project"))
+	ext := NewPlugin(fetcher, store)
 	skf := starkit.NewFixture(t, ext, include.IncludeFn{})
 	skf.UseRealFS()
 
 	return &extensionFixture{
-		t:   t,
-		skf: skf,
-		tmp: tmp,
+		t:       t,
+		skf:     skf,
+		tmp:     tmp,
+		fetcher: fetcher,
+		store:   store,
 	}
 }
 
@@ -190,6 +250,24 @@ func (f *extensionFixture) assertNoLoadsRecorded(model starkit.Model) {
 	f.assertLoadRecorded(model)
 }
 
+func (f *extensionFixture) readLock() LockFile {
+	lock, err := ReadLockFile(f.store.LockFilePath())
+	require.NoError(f.t, err)
+	return lock
+}
+
+func (f *extensionFixture) writeLock(lock LockFile) {
+	require.NoError(f.t, WriteLockFile(f.store.LockFilePath(), lock))
+}
+
+func hashOfTiltfile(t *testing.T, contents string) string {
+	dir := dirWithTiltfile(t, contents)
+	defer func() { _ = os.RemoveAll(dir) }()
+	hash, err := HashDir(dir)
+	require.NoError(t, err)
+	return hash
+}
+
 func (f *extensionFixture) writeModuleLocally(name string, contents string) {
 	f.tmp.WriteFile(filepath.Join("project", "tilt_modules", name, "Tiltfile"), contents)
 }
@@ -213,17 +291,25 @@ def printFoo():
 `
 
 type fakeFetcher struct {
-	t *testing.T
+	t       *testing.T
+	lastRef string
 }
 
-func (f *fakeFetcher) Fetch(ctx context.Context, moduleName string) (ModuleContents, error) {
+func (f *fakeFetcher) Fetch(ctx context.Context, moduleName string, ref string) (ModuleContents, error) {
+	f.lastRef = ref
 	if moduleName != "fetchable" {
 		return ModuleContents{}, fmt.Errorf("module %s can't be fetched because... reasons", moduleName)
 	}
 
+	checkoutRef := ref
+	if checkoutRef == "" {
+		checkoutRef = "fake-head"
+	}
+
 	return ModuleContents{
-		Name: "fetchable",
-		Dir:  dirWithTiltfile(f.t, libText),
+		Name:        "fetchable",
+		Dir:         dirWithTiltfile(f.t, libText),
+		CheckoutRef: checkoutRef,
 	}, nil
 }
 
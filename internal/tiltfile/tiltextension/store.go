@@ -21,6 +21,9 @@ type Store interface {
 	// Returns ErrNotExist if module doesn't exist
 	ModulePath(ctx context.Context, moduleName string) (string, error)
 	Write(ctx context.Context, contents ModuleContents) (string, error)
+
+	// The path of the lock file that pins the modules in this store.
+	LockFilePath() string
 }
 
 type ModuleContents struct {
@@ -29,13 +32,10 @@ type ModuleContents struct {
 	ExtensionRegistry string
 	TimeFetched       time.Time
 
-	// NOTE(nick): Currently this is missing any kind of integrity hashing or versioning.
-	// This used to have a non-functional versioning stub, which we deleted in
-	// https://github.com/tilt-dev/tilt/pull/3779
+	// The commit of the extension registry that the module was fetched at.
 	//
-	// If we decide to add versioning semantics, we should try to adapt something from
-	// an existing implementation (e.g., go-get's versioning sematnics) rather
-	// than trying to implement versioning from scratch.
+	// Integrity checking is handled by the lock file (see LockFile).
+	CheckoutRef string
 }
 
 type LocalStore struct {
@@ -58,6 +58,10 @@ func NewLocalStore(baseDir string) *LocalStore {
 	}
 }
 
+func (s *LocalStore) LockFilePath() string {
+	return filepath.Join(s.baseDir, lockFileName)
+}
+
 func (s *LocalStore) ModulePath(ctx context.Context, moduleName string) (string, error) {
 	tiltfilePath := filepath.Join(s.baseDir, moduleName, extensionFileName)
 
@@ -76,6 +80,12 @@ func (s *LocalStore) ModulePath(ctx context.Context, moduleName string) (string,
 // one that already exists?
 func (s *LocalStore) Write(ctx context.Context, contents ModuleContents) (string, error) {
 	moduleDir := filepath.Join(s.baseDir, contents.Name)
+
+	// Clear out any previous version of the module, so that
+	// files deleted upstream don't linger.
+	if err := os.RemoveAll(moduleDir); err != nil {
+		return "", errors.Wrapf(err, "couldn't clear module directory %s at path %s", contents.Name, moduleDir)
+	}
 	if err := os.MkdirAll(moduleDir, os.FileMode(0700)); err != nil {
 		return "", errors.Wrapf(err, "couldn't create module directory %s at path %s", contents.Name, moduleDir)
 	}
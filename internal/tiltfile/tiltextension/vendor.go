@@ -0,0 +1,354 @@
+package tiltextension
+
+import (
+	"context"
+	"fmt"
+	"path/filepath"
+	"sort"
+	"strings"
+
+	"github.com/tilt-dev/tilt/internal/controllers/apiset"
+	"github.com/tilt-dev/tilt/internal/git"
+	"github.com/tilt-dev/tilt/internal/ospath"
+	"github.com/tilt-dev/tilt/pkg/apis"
+	"github.com/tilt-dev/tilt/pkg/apis/core/v1alpha1"
+)
+
+// Makes sure every ext:// extension in the lock file is in the store,
+// at its locked version, so that the Tiltfile loads without network access.
+//
+// Returns the names of the extensions that had to be fetched.
+func Vendor(ctx context.Context, store Store, fetcher Fetcher) ([]string, error) {
+	lockPath := store.LockFilePath()
+	lock, err := ReadLockFile(lockPath)
+	if err != nil {
+		return nil, err
+	}
+
+	defer func() {
+		_ = fetcher.CleanUp()
+	}()
+
+	var fetched []string
+	for _, locked := range lock.Extensions {
+		if locked.RepoName != "" {
+			// Vendored with its repo by VendorRepos.
+			continue
+		}
+
+		localPath, err := store.ModulePath(ctx, locked.Name)
+		if err == nil {
+			hash, err := HashDir(filepath.Dir(localPath))
+			if err != nil {
+				return fetched, err
+			}
+			if hash == locked.ContentHash {
+				continue
+			}
+		}
+
+		if locked.CheckoutRef == "" {
+			return fetched, fmt.Errorf("extension %s has no locked commit in %s. Run `tilt ext update %s` to lock it",
+				locked.Name, lockPath, locked.Name)
+		}
+
+		contents, err := fetcher.Fetch(ctx, locked.Name, locked.CheckoutRef)
+		if err != nil {
+			return fetched, err
+		}
+
+		localPath, err = store.Write(ctx, contents)
+		if err != nil {
+			return fetched, err
+		}
+
+		hash, err := HashDir(filepath.Dir(localPath))
+		if err != nil {
+			return fetched, err
+		}
+		if hash != locked.ContentHash {
+			return fetched, fmt.Errorf("extension %s at %s doesn't match %s (expected content hash %s, got %s)",
+				locked.Name, locked.CheckoutRef, lockPath, locked.ContentHash, hash)
+		}
+		fetched = append(fetched, locked.Name)
+	}
+	return fetched, nil
+}
+
+// Makes sure every ExtensionRepo in the lock file is checked out at its
+// locked commit under the downloader's root dir (where the ExtensionRepo
+// controller looks for it), and that the extensions in it match the lock.
+//
+// Returns the names of the repos that had to be fetched.
+func VendorRepos(dlr Downloader, lockPath string) ([]string, error) {
+	lock, err := ReadLockFile(lockPath)
+	if err != nil {
+		return nil, err
+	}
+
+	for _, locked := range lock.Extensions {
+		if locked.RepoName == "" {
+			continue
+		}
+		if _, ok := lock.Repo(locked.RepoName); !ok {
+			return nil, fmt.Errorf("extension %s is from repo %s, which has no locked commit in %s. Run `tilt ext update %s` to lock it",
+				locked.Name, locked.RepoName, lockPath, locked.RepoName)
+		}
+	}
+
+	var fetched []string
+	for _, repo := range lock.Repos {
+		repoDlr, importPath := repoDownloaderFor(dlr, repo.URL)
+		dir := repoDlr.DestinationPath(importPath)
+		head, err := repoDlr.HeadRef(importPath)
+		if err != nil || head != repo.CheckoutRef {
+			dir, err = repoDlr.Download(importPath)
+			if err != nil {
+				return fetched, fmt.Errorf("fetching repo %s: %v", repo.Name, err)
+			}
+			err = repoDlr.RefSync(importPath, repo.CheckoutRef)
+			if err != nil {
+				return fetched, fmt.Errorf("fetching repo %s: sync to ref %s: %v", repo.Name, repo.CheckoutRef, err)
+			}
+			fetched = append(fetched, repo.Name)
+		}
+
+		for _, locked := range lock.Extensions {
+			if locked.RepoName != repo.Name {
+				continue
+			}
+
+			extDir := filepath.Join(dir, locked.RepoPath)
+			if !ospath.IsChild(dir, extDir) {
+				return fetched, fmt.Errorf("extension %s: invalid repo path: %s", locked.Name, locked.RepoPath)
+			}
+			hash, err := HashDir(extDir)
+			if err != nil {
+				return fetched, fmt.Errorf("extension %s: %v", locked.Name, err)
+			}
+			if hash != locked.ContentHash {
+				return fetched, fmt.Errorf("extension %s in repo %s at %s doesn't match %s (expected content hash %s, got %s)",
+					locked.Name, repo.Name, repo.CheckoutRef, lockPath, locked.ContentHash, hash)
+			}
+		}
+	}
+	return fetched, nil
+}
+
+// Fetches the latest version of the given ext:// extensions into the store,
+// and records them in the lock file.
+//
+// If no names are given, updates every ext:// extension in the lock file.
+func Update(ctx context.Context, store Store, fetcher Fetcher, names []string) ([]LockedExtension, error) {
+	lockPath := store.LockFilePath()
+	lock, err := ReadLockFile(lockPath)
+	if err != nil {
+		return nil, err
+	}
+
+	if len(names) == 0 {
+		for _, locked := range lock.Extensions {
+			if locked.RepoName == "" {
+				names = append(names, locked.Name)
+			}
+		}
+	}
+
+	defer func() {
+		_ = fetcher.CleanUp()
+	}()
+
+	var updated []LockedExtension
+	for _, name := range names {
+		contents, err := fetcher.Fetch(ctx, name, "")
+		if err != nil {
+			return updated, err
+		}
+
+		localPath, err := store.Write(ctx, contents)
+		if err != nil {
+			return updated, err
+		}
+
+		hash, err := HashDir(filepath.Dir(localPath))
+		if err != nil {
+			return updated, err
+		}
+
+		locked := LockedExtension{
+			Name:        name,
+			RepoPath:    name,
+			CheckoutRef: contents.CheckoutRef,
+			ContentHash: hash,
+		}
+		lock.SetExtension(locked)
+		updated = append(updated, locked)
+	}
+
+	return updated, WriteLockFile(lockPath, lock)
+}
+
+// Locks the given ext:// extensions that aren't in the lock file yet,
+// at their latest version.
+func LockNew(ctx context.Context, store Store, fetcher Fetcher, names []string) ([]LockedExtension, error) {
+	lock, err := ReadLockFile(store.LockFilePath())
+	if err != nil {
+		return nil, err
+	}
+
+	var unlocked []string
+	for _, name := range names {
+		if _, ok := lock.Extension("", name); !ok {
+			unlocked = append(unlocked, name)
+		}
+	}
+	if len(unlocked) == 0 {
+		return nil, nil
+	}
+	return Update(ctx, store, fetcher, unlocked)
+}
+
+// Locks the ExtensionRepos in the set, and the Extensions that load from them.
+//
+// With upgrade, moves every repo to its latest commit (or the ref in its spec).
+// Otherwise, only locks the repos and extensions that aren't in the lock yet,
+// and leaves locked repos at their locked commit.
+//
+// If names is non-empty, only locks the repos with those names,
+// or with extensions with those names.
+//
+// Returns the repos that were locked.
+func LockRepos(dlr Downloader, set apiset.ObjectSet, lock *LockFile, upgrade bool, names []string) ([]LockedRepo, error) {
+	selected := make(map[string]bool, len(names))
+	for _, name := range names {
+		selected[name] = true
+	}
+
+	var repos []*v1alpha1.ExtensionRepo
+	for _, obj := range set.GetSetForType(&v1alpha1.ExtensionRepo{}) {
+		repos = append(repos, obj.(*v1alpha1.ExtensionRepo))
+	}
+	sort.Slice(repos, func(i, j int) bool {
+		return repos[i].Name < repos[j].Name
+	})
+
+	exts := set.GetSetForType(&v1alpha1.Extension{})
+
+	var result []LockedRepo
+	for _, repo := range repos {
+		isSelected := len(selected) == 0 || selected[repo.Name]
+		isLocked := false
+		if locked, ok := lock.Repo(repo.Name); ok && locked.URL == repo.Spec.URL {
+			isLocked = true
+		}
+
+		var repoExts []*v1alpha1.Extension
+		for _, obj := range exts {
+			ext := obj.(*v1alpha1.Extension)
+			if ext.Spec.RepoName != repo.Name {
+				continue
+			}
+			repoExts = append(repoExts, ext)
+			if selected[ext.Name] {
+				isSelected = true
+			}
+			if locked, ok := lock.Extension(repo.Name, ext.Name); !ok || locked.RepoPath != ext.Spec.RepoPath {
+				isLocked = false
+			}
+		}
+
+		if !isSelected || (isLocked && !upgrade) {
+			continue
+		}
+
+		// Moving the repo to a new commit changes all of its extensions,
+		// so we need to lock all of them.
+		err := LockRepo(dlr, repo, repoExts, lock, upgrade)
+		if err != nil {
+			return result, err
+		}
+		if locked, ok := lock.Repo(repo.Name); ok {
+			result = append(result, locked)
+		}
+	}
+	return result, nil
+}
+
+type repoDownloader interface {
+	DestinationPath(pkg string) string
+	Download(pkg string) (string, error)
+	HeadRef(pkg string) (string, error)
+	RefSync(pkg string, ref string) error
+}
+
+// Picks the downloader for the repo URL, and the path to download with it.
+//
+// Git remotes are cloned with git directly, like the ExtensionRepo controller does.
+func repoDownloaderFor(dlr Downloader, url string) (repoDownloader, string) {
+	if apis.IsGitRemoteURL(url) {
+		return git.NewDownloader(dlr.RootDir()), url
+	}
+	return dlr, strings.TrimPrefix(strings.TrimPrefix(url, "https://"), "http://")
+}
+
+// Fetches an ExtensionRepo, and records its commit and the given
+// extensions from that repo in the lock.
+//
+// With upgrade, fetches the latest commit, unless the repo spec has a ref.
+// Otherwise, keeps the repo at its locked commit.
+//
+// Repos on the local filesystem (file://) can't be locked, and are skipped.
+func LockRepo(dlr Downloader, repo *v1alpha1.ExtensionRepo, exts []*v1alpha1.Extension, lock *LockFile, upgrade bool) error {
+	if strings.HasPrefix(repo.Spec.URL, "file://") {
+		return nil
+	}
+
+	repoDlr, importPath := repoDownloaderFor(dlr, repo.Spec.URL)
+	dir, err := repoDlr.Download(importPath)
+	if err != nil {
+		return fmt.Errorf("fetching repo %s: %v", repo.Name, err)
+	}
+
+	// If we're upgrading and the ref came from the lock itself (see ApplyLock),
+	// ignore it, so that we pick up the latest commit.
+	ref := repo.Spec.Ref
+	if locked, ok := lock.Repo(repo.Name); ok && upgrade && locked.CheckoutRef == ref {
+		ref = ""
+	}
+	if ref != "" {
+		err := repoDlr.RefSync(importPath, ref)
+		if err != nil {
+			return fmt.Errorf("fetching repo %s: sync to ref %s: %v", repo.Name, ref, err)
+		}
+	}
+
+	checkoutRef, err := repoDlr.HeadRef(importPath)
+	if err != nil {
+		return fmt.Errorf("fetching repo %s: determining head: %v", repo.Name, err)
+	}
+
+	lock.SetRepo(LockedRepo{
+		Name:        repo.Name,
+		URL:         repo.Spec.URL,
+		CheckoutRef: checkoutRef,
+	})
+
+	for _, ext := range exts {
+		extDir := filepath.Join(dir, ext.Spec.RepoPath)
+		if !ospath.IsChild(dir, extDir) {
+			return fmt.Errorf("extension %s: invalid repo path: %s", ext.Name, ext.Spec.RepoPath)
+		}
+
+		hash, err := HashDir(extDir)
+		if err != nil {
+			return fmt.Errorf("extension %s: %v", ext.Name, err)
+		}
+		lock.SetExtension(LockedExtension{
+			Name:        ext.Name,
+			RepoName:    repo.Name,
+			RepoPath:    ext.Spec.RepoPath,
+			ContentHash: hash,
+		})
+	}
+	return nil
+}
@@ -0,0 +1,299 @@
+package tiltextension
+
+import (
+	"context"
+	"fmt"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	"github.com/tilt-dev/tilt/internal/controllers/apiset"
+	"github.com/tilt-dev/tilt/pkg/apis/core/v1alpha1"
+)
+
+func TestVendorFetchesMissing(t *testing.T) {
+	f := newExtensionFixture(t)
+	defer f.tearDown()
+
+	f.writeLock(LockFile{Extensions: []LockedExtension{{
+		Name:        "fetchable",
+		RepoPath:    "fetchable",
+		CheckoutRef: "locked-ref",
+		ContentHash: hashOfTiltfile(t, libText),
+	}}})
+
+	fetched, err := Vendor(context.Background(), f.store, f.fetcher)
+	require.NoError(t, err)
+	assert.Equal(t, []string{"fetchable"}, fetched)
+	assert.Equal(t, "locked-ref", f.fetcher.lastRef)
+
+	// Vendoring again is a no-op.
+	f.fetcher.lastRef = ""
+	fetched, err = Vendor(context.Background(), f.store, f.fetcher)
+	require.NoError(t, err)
+	assert.Empty(t, fetched)
+	assert.Equal(t, "", f.fetcher.lastRef)
+}
+
+func TestVendorHashMismatch(t *testing.T) {
+	f := newExtensionFixture(t)
+	defer f.tearDown()
+
+	f.writeLock(LockFile{Extensions: []LockedExtension{{
+		Name:        "fetchable",
+		RepoPath:    "fetchable",
+		CheckoutRef: "locked-ref",
+		ContentHash: "sha256:locked",
+	}}})
+
+	_, err := Vendor(context.Background(), f.store, f.fetcher)
+	if assert.Error(t, err) {
+		assert.Contains(t, err.Error(), "extension fetchable at locked-ref doesn't match")
+	}
+}
+
+func TestUpdate(t *testing.T) {
+	f := newExtensionFixture(t)
+	defer f.tearDown()
+
+	f.writeLock(LockFile{Extensions: []LockedExtension{{
+		Name:        "fetchable",
+		RepoPath:    "fetchable",
+		CheckoutRef: "old-ref",
+		ContentHash: "sha256:old",
+	}}})
+
+	updated, err := Update(context.Background(), f.store, f.fetcher, nil)
+	require.NoError(t, err)
+	require.Len(t, updated, 1)
+	assert.Equal(t, "", f.fetcher.lastRef)
+
+	locked, ok := f.readLock().Extension("", "fetchable")
+	require.True(t, ok)
+	assert.Equal(t, "fake-head", locked.CheckoutRef)
+	assert.Equal(t, hashOfTiltfile(t, libText), locked.ContentHash)
+}
+
+func TestLockNew(t *testing.T) {
+	f := newExtensionFixture(t)
+	defer f.tearDown()
+
+	f.writeLock(LockFile{Extensions: []LockedExtension{{
+		Name:        "unfetchable",
+		RepoPath:    "unfetchable",
+		CheckoutRef: "locked-ref",
+		ContentHash: "sha256:locked",
+	}}})
+
+	locked, err := LockNew(context.Background(), f.store, f.fetcher, []string{"fetchable", "unfetchable"})
+	require.NoError(t, err)
+	require.Len(t, locked, 1)
+	assert.Equal(t, "fetchable", locked[0].Name)
+
+	lock := f.readLock()
+	fetchable, ok := lock.Extension("", "fetchable")
+	require.True(t, ok)
+	assert.Equal(t, "fake-head", fetchable.CheckoutRef)
+	assert.Equal(t, hashOfTiltfile(t, libText), fetchable.ContentHash)
+
+	unfetchable, ok := lock.Extension("", "unfetchable")
+	require.True(t, ok)
+	assert.Equal(t, "locked-ref", unfetchable.CheckoutRef)
+
+	// Once everything is locked, there's nothing to do.
+	locked, err = LockNew(context.Background(), f.store, f.fetcher, []string{"fetchable", "unfetchable"})
+	require.NoError(t, err)
+	assert.Empty(t, locked)
+}
+
+func TestLockRepos(t *testing.T) {
+	dlr := &fakeRepoDownloader{t: t, head: "ref-1"}
+	repo := &v1alpha1.ExtensionRepo{
+		ObjectMeta: metav1.ObjectMeta{Name: "my-repo"},
+		Spec:       v1alpha1.ExtensionRepoSpec{URL: "https://github.com/org/repo"},
+	}
+	ext := &v1alpha1.Extension{
+		ObjectMeta: metav1.ObjectMeta{Name: "my-ext"},
+		Spec:       v1alpha1.ExtensionSpec{RepoName: "my-repo", RepoPath: "my-ext"},
+	}
+	set := apiset.ObjectSet{}
+	set.Add(repo)
+	set.Add(ext)
+
+	lock := LockFile{}
+	locked, err := LockRepos(dlr, set, &lock, false, nil)
+	require.NoError(t, err)
+	require.Len(t, locked, 1)
+	assert.Equal(t, "ref-1", locked[0].CheckoutRef)
+	lockedExt, ok := lock.Extension("my-repo", "my-ext")
+	require.True(t, ok)
+	assert.Equal(t, hashOfTiltfile(t, libText), lockedExt.ContentHash)
+
+	// Without upgrade, a locked repo stays where it is.
+	ApplyLock(lock, set)
+	dlr.head = "ref-2"
+	locked, err = LockRepos(dlr, set, &lock, false, nil)
+	require.NoError(t, err)
+	assert.Empty(t, locked)
+
+	// Only the selected repos are upgraded.
+	locked, err = LockRepos(dlr, set, &lock, true, []string{"other-ext"})
+	require.NoError(t, err)
+	assert.Empty(t, locked)
+
+	locked, err = LockRepos(dlr, set, &lock, true, []string{"my-ext"})
+	require.NoError(t, err)
+	require.Len(t, locked, 1)
+	assert.Equal(t, "ref-2", locked[0].CheckoutRef)
+}
+
+func TestVendorRepos(t *testing.T) {
+	f := newExtensionFixture(t)
+	defer f.tearDown()
+
+	f.writeLock(LockFile{
+		Repos: []LockedRepo{{Name: "my-repo", URL: "https://github.com/org/repo", CheckoutRef: "locked-ref"}},
+		Extensions: []LockedExtension{{
+			Name:        "my-ext",
+			RepoName:    "my-repo",
+			RepoPath:    "my-ext",
+			ContentHash: hashOfTiltfile(t, libText),
+		}},
+	})
+
+	dlr := &fakeRepoDownloader{t: t, head: "ref-1"}
+	fetched, err := VendorRepos(dlr, f.store.LockFilePath())
+	require.NoError(t, err)
+	assert.Equal(t, []string{"my-repo"}, fetched)
+	assert.Equal(t, "locked-ref", dlr.head)
+
+	// Vendoring again is a no-op.
+	fetched, err = VendorRepos(dlr, f.store.LockFilePath())
+	require.NoError(t, err)
+	assert.Empty(t, fetched)
+	assert.Equal(t, 1, dlr.downloadCount)
+}
+
+func TestVendorReposHashMismatch(t *testing.T) {
+	f := newExtensionFixture(t)
+	defer f.tearDown()
+
+	f.writeLock(LockFile{
+		Repos: []LockedRepo{{Name: "my-repo", URL: "https://github.com/org/repo", CheckoutRef: "locked-ref"}},
+		Extensions: []LockedExtension{{
+			Name:        "my-ext",
+			RepoName:    "my-repo",
+			RepoPath:    "my-ext",
+			ContentHash: "sha256:locked",
+		}},
+	})
+
+	_, err := VendorRepos(&fakeRepoDownloader{t: t}, f.store.LockFilePath())
+	if assert.Error(t, err) {
+		assert.Contains(t, err.Error(), "extension my-ext in repo my-repo at locked-ref doesn't match")
+	}
+}
+
+func TestVendorReposMissingRepo(t *testing.T) {
+	f := newExtensionFixture(t)
+	defer f.tearDown()
+
+	f.writeLock(LockFile{
+		Extensions: []LockedExtension{{
+			Name:        "my-ext",
+			RepoName:    "my-repo",
+			RepoPath:    "my-ext",
+			ContentHash: hashOfTiltfile(t, libText),
+		}},
+	})
+
+	_, err := VendorRepos(&fakeRepoDownloader{t: t}, f.store.LockFilePath())
+	if assert.Error(t, err) {
+		assert.Contains(t, err.Error(), "extension my-ext is from repo my-repo, which has no locked commit")
+	}
+}
+
+func TestApplyLock(t *testing.T) {
+	lock := LockFile{
+		Repos: []LockedRepo{
+			{Name: "my-repo", URL: "https://github.com/org/repo", CheckoutRef: "locked-ref"},
+			{Name: "my-pinned-repo", URL: "https://github.com/org/repo", CheckoutRef: "locked-ref"},
+		},
+		Extensions: []LockedExtension{
+			{Name: "my-ext", RepoName: "my-repo", RepoPath: "my-ext", ContentHash: "sha256:locked"},
+			{Name: "moved-ext", RepoName: "my-repo", RepoPath: "old-path", ContentHash: "sha256:locked"},
+		},
+	}
+
+	repo := &v1alpha1.ExtensionRepo{
+		ObjectMeta: metav1.ObjectMeta{Name: "my-repo"},
+		Spec:       v1alpha1.ExtensionRepoSpec{URL: "https://github.com/org/repo"},
+	}
+	pinnedRepo := &v1alpha1.ExtensionRepo{
+		ObjectMeta: metav1.ObjectMeta{Name: "my-pinned-repo"},
+		Spec:       v1alpha1.ExtensionRepoSpec{URL: "https://github.com/org/repo", Ref: "v1.0"},
+	}
+	ext := &v1alpha1.Extension{
+		ObjectMeta: metav1.ObjectMeta{Name: "my-ext"},
+		Spec:       v1alpha1.ExtensionSpec{RepoName: "my-repo", RepoPath: "my-ext"},
+	}
+	movedExt := &v1alpha1.Extension{
+		ObjectMeta: metav1.ObjectMeta{Name: "moved-ext"},
+		Spec:       v1alpha1.ExtensionSpec{RepoName: "my-repo", RepoPath: "new-path"},
+	}
+
+	set := apiset.ObjectSet{}
+	set.Add(repo)
+	set.Add(pinnedRepo)
+	set.Add(ext)
+	set.Add(movedExt)
+	ApplyLock(lock, set)
+
+	assert.Equal(t, "locked-ref", repo.Spec.Ref)
+	assert.Equal(t, "v1.0", pinnedRepo.Spec.Ref)
+	assert.Equal(t, "sha256:locked", ext.Spec.ContentHash)
+	assert.Equal(t, "", movedExt.Spec.ContentHash)
+}
+
+type fakeRepoDownloader struct {
+	t             *testing.T
+	head          string
+	dir           string
+	downloadCount int
+}
+
+func (d *fakeRepoDownloader) RootDir() string {
+	return d.dir
+}
+
+func (d *fakeRepoDownloader) DestinationPath(pkg string) string {
+	return d.dir
+}
+
+func (d *fakeRepoDownloader) Download(pkg string) (string, error) {
+	d.downloadCount++
+	if d.dir == "" {
+		d.dir = d.t.TempDir()
+		extDir := filepath.Join(d.dir, "my-ext")
+		require.NoError(d.t, os.MkdirAll(extDir, 0755))
+		require.NoError(d.t, ioutil.WriteFile(filepath.Join(extDir, "Tiltfile"), []byte(libText), 0644))
+	}
+	return d.dir, nil
+}
+
+func (d *fakeRepoDownloader) HeadRef(pkg string) (string, error) {
+	if d.dir == "" {
+		return "", fmt.Errorf("repo not downloaded: %s", pkg)
+	}
+	return d.head, nil
+}
+
+func (d *fakeRepoDownloader) RefSync(pkg string, ref string) error {
+	d.head = ref
+	return nil
+}
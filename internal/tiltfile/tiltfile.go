@@ -5,6 +5,7 @@ import (
 	"fmt"
 	"os"
 	"path/filepath"
+	"sort"
 	"strconv"
 	"time"
 
@@ -19,6 +20,7 @@ import (
 	"github.com/tilt-dev/tilt/internal/dockercompose"
 	"github.com/tilt-dev/tilt/internal/feature"
 	"github.com/tilt-dev/tilt/internal/k8s"
+	"github.com/tilt-dev/tilt/internal/kustomize"
 	"github.com/tilt-dev/tilt/internal/localexec"
 	"github.com/tilt-dev/tilt/internal/ospath"
 	"github.com/tilt-dev/tilt/internal/sliceutils"
@@ -34,6 +36,7 @@ import (
 	"github.com/tilt-dev/tilt/internal/tiltfile/value"
 	"github.com/tilt-dev/tilt/internal/tiltfile/version"
 	"github.com/tilt-dev/tilt/internal/tiltfile/watch"
+	"github.com/tilt-dev/tilt/internal/xdg"
 	corev1alpha1 "github.com/tilt-dev/tilt/pkg/apis/core/v1alpha1"
 	"github.com/tilt-dev/tilt/pkg/model"
 )
@@ -56,21 +59,44 @@ type TiltfileLoadResult struct {
 	WatchSettings       model.WatchSettings
 	ObjectSet           apiset.ObjectSet
 
+	// The ext:// extensions that the Tiltfile loaded, sorted by name.
+	Extensions []string
+
 	// For diagnostic purposes only
 	BuiltinCalls []starkit.BuiltinCall `json:"-"`
 }
 
+// Orchestrator returns the orchestrator whose Docker daemon images should be built on.
+//
+// Docker Compose services always run on the local Docker daemon, so if any
+// manifest uses Docker Compose, we build there and push to (or load into)
+// the cluster for Kubernetes manifests.
 func (r TiltfileLoadResult) Orchestrator() model.Orchestrator {
+	hasK8s := false
 	for _, manifest := range r.Manifests {
-		if manifest.IsK8s() {
-			return model.OrchestratorK8s
-		} else if manifest.IsDC() {
+		switch manifest.Orchestrator() {
+		case model.OrchestratorDC:
 			return model.OrchestratorDC
+		case model.OrchestratorK8s:
+			hasK8s = true
 		}
 	}
+	if hasK8s {
+		return model.OrchestratorK8s
+	}
 	return model.OrchestratorUnknown
 }
 
+// HasOrchestrator returns true if any manifest is deployed by the given orchestrator.
+func (r TiltfileLoadResult) HasOrchestrator(orc model.Orchestrator) bool {
+	for _, manifest := range r.Manifests {
+		if manifest.Orchestrator() == orc {
+			return true
+		}
+	}
+	return false
+}
+
 type TiltfileLoader interface {
 	// Load the Tiltfile.
 	//
@@ -79,6 +105,9 @@ type TiltfileLoader interface {
 	// Because even if the Tiltfile has errors, we might need to watch files
 	// or return partial results (like enabled features).
 	Load(ctx context.Context, tf *corev1alpha1.Tiltfile) TiltfileLoadResult
+
+	// Load a Tiltfile unit test, with the assert and fake builtins.
+	LoadUnitTest(ctx context.Context, tf *corev1alpha1.Tiltfile) TiltfileLoadResult
 }
 
 func ProvideTiltfileLoader(
@@ -91,18 +120,20 @@ func ProvideTiltfileLoader(
 	webHost model.WebHost,
 	execer localexec.Execer,
 	fDefaults feature.Defaults,
-	env k8s.Env) TiltfileLoader {
+	env k8s.Env,
+	base xdg.Base) TiltfileLoader {
 	return tiltfileLoader{
-		analytics:     analytics,
-		kCli:          kCli,
-		k8sContextExt: k8sContextExt,
-		versionExt:    versionExt,
-		configExt:     configExt,
-		dcCli:         dcCli,
-		webHost:       webHost,
-		execer:        execer,
-		fDefaults:     fDefaults,
-		env:           env,
+		analytics:      analytics,
+		kCli:           kCli,
+		k8sContextExt:  k8sContextExt,
+		versionExt:     versionExt,
+		configExt:      configExt,
+		dcCli:          dcCli,
+		webHost:        webHost,
+		execer:         execer,
+		fDefaults:      fDefaults,
+		env:            env,
+		kustomizeCache: kustomize.NewRemoteCache(base),
 	}
 }
 
@@ -118,10 +149,21 @@ type tiltfileLoader struct {
 	configExt     *config.Plugin
 	fDefaults     feature.Defaults
 	env           k8s.Env
+
+	// Shared across loads, so that remote kustomize bases are only fetched once.
+	kustomizeCache *kustomize.RemoteCache
+
+	// Set by LoadUnitTest.
+	unitTest bool
 }
 
 var _ TiltfileLoader = &tiltfileLoader{}
 
+func (tfl tiltfileLoader) LoadUnitTest(ctx context.Context, tf *corev1alpha1.Tiltfile) TiltfileLoadResult {
+	tfl.unitTest = true
+	return tfl.Load(ctx, tf)
+}
+
 // Load loads the Tiltfile in `filename`
 func (tfl tiltfileLoader) Load(ctx context.Context, tf *corev1alpha1.Tiltfile) TiltfileLoadResult {
 	start := time.Now()
@@ -160,6 +202,8 @@ func (tfl tiltfileLoader) Load(ctx context.Context, tf *corev1alpha1.Tiltfile) T
 
 	s := newTiltfileState(ctx, tfl.dcCli, tfl.webHost, tfl.execer, tfl.k8sContextExt, tfl.versionExt,
 		tfl.configExt, localRegistry, feature.FromDefaults(tfl.fDefaults))
+	s.unitTest = tfl.unitTest
+	s.kustomizeCache = tfl.kustomizeCache
 
 	manifests, result, err := s.loadManifests(tf)
 
@@ -195,8 +239,19 @@ func (tfl tiltfileLoader) Load(ctx context.Context, tf *corev1alpha1.Tiltfile) T
 	tlr.TeamID = s.teamID
 
 	objectSet, _ := v1alpha1.GetState(result)
+	lock, lockErr := tiltextension.ReadLockFile(tiltextension.LockFilePath(filepath.Dir(absFilename)))
+	if lockErr != nil && tlr.Error == nil {
+		tlr.Error = lockErr
+	}
+	tiltextension.ApplyLock(lock, objectSet)
 	tlr.ObjectSet = objectSet
 
+	extState, _ := tiltextension.GetState(result)
+	for name := range extState.ExtsLoaded {
+		tlr.Extensions = append(tlr.Extensions, name)
+	}
+	sort.Strings(tlr.Extensions)
+
 	vs, _ := version.GetState(result)
 	tlr.VersionSettings = vs
 
@@ -210,7 +265,6 @@ func (tfl tiltfileLoader) Load(ctx context.Context, tf *corev1alpha1.Tiltfile) T
 	if tlr.Error == nil {
 		s.logger.Infof("Successfully loaded Tiltfile (%s)", duration)
 	}
-	extState, _ := tiltextension.GetState(result)
 	tfl.reportTiltfileLoaded(s.builtinCallCounts, s.builtinArgCounts, duration, extState.ExtsLoaded)
 
 	if len(aSettings.CustomTagsToReport) > 0 {
@@ -273,7 +273,56 @@ docker_compose('docker-compose2.yml')`
 	assert.Equal(t, 2, len(f.loadResult.Manifests))
 }
 
-func TestDockerComposeAndK8sNotSupported(t *testing.T) {
+func TestDockerComposeAndK8s(t *testing.T) {
+	f := newFixture(t)
+	defer f.TearDown()
+
+	f.dockerfile(filepath.Join("foo", "Dockerfile"))
+	f.file("docker-compose.yml", simpleConfig)
+	f.dockerfile(filepath.Join("bar", "Dockerfile"))
+	f.yaml("bar.yaml", deployment("bar", image("gcr.io/bar")))
+	tf := `docker_compose('docker-compose.yml')
+docker_build('gcr.io/bar', 'bar')
+k8s_yaml('bar.yaml')
+k8s_resource('bar', resource_deps=['foo'])`
+	f.file("Tiltfile", tf)
+
+	f.load()
+
+	assert.Equal(t, model.OrchestratorDC, f.loadResult.Orchestrator())
+	assert.True(t, f.loadResult.HasOrchestrator(model.OrchestratorK8s))
+
+	bar := f.assertNextManifest("bar", deployment("bar"))
+	assert.Equal(t, model.OrchestratorK8s, bar.Orchestrator())
+	assert.Equal(t, []model.ManifestName{"foo"}, bar.ResourceDependencies)
+
+	foo := f.assertNextManifest("foo")
+	assert.Equal(t, model.OrchestratorDC, foo.Orchestrator())
+}
+
+func TestDockerComposeAndK8sDefaultRegistry(t *testing.T) {
+	f := newFixture(t)
+	defer f.TearDown()
+
+	f.dockerfile(filepath.Join("foo", "Dockerfile"))
+	f.file("docker-compose.yml", simpleConfig)
+	f.dockerfile(filepath.Join("bar", "Dockerfile"))
+	f.yaml("bar.yaml", deployment("bar", image("gcr.io/bar")))
+	tf := `docker_compose('docker-compose.yml')
+default_registry('localhost:5000')
+docker_build('gcr.io/bar', 'bar')
+k8s_yaml('bar.yaml')`
+	f.file("Tiltfile", tf)
+
+	f.load()
+
+	bar := f.assertNextManifest("bar", deployment("bar"))
+	assert.Equal(t, "localhost:5000/gcr.io_bar",
+		bar.ImageTargets[0].Refs.ClusterRef().String())
+	f.assertNextManifest("foo")
+}
+
+func TestDockerComposeAndK8sDuplicateName(t *testing.T) {
 	f := newFixture(t)
 	defer f.TearDown()
 
@@ -284,8 +333,7 @@ docker_build('gcr.io/foo', 'foo')
 k8s_yaml('foo.yaml')`
 	f.file("Tiltfile", tf)
 
-	f.loadErrString("can't declare both k8s " +
-		"resources/entities and docker-compose resources")
+	f.loadErrString(`resource name "foo" is used by both a Kubernetes resource and a Docker Compose service`)
 }
 
 func TestDockerComposeResourceCreationFromAbsPath(t *testing.T) {
@@ -30,6 +30,7 @@ import (
 	"github.com/tilt-dev/tilt/internal/dockercompose"
 	"github.com/tilt-dev/tilt/internal/feature"
 	"github.com/tilt-dev/tilt/internal/k8s"
+	"github.com/tilt-dev/tilt/internal/kustomize"
 	"github.com/tilt-dev/tilt/internal/ospath"
 	"github.com/tilt-dev/tilt/internal/sliceutils"
 	"github.com/tilt-dev/tilt/internal/tiltfile/analytics"
@@ -49,6 +50,7 @@ import (
 	"github.com/tilt-dev/tilt/internal/tiltfile/starkit"
 	"github.com/tilt-dev/tilt/internal/tiltfile/starlarkstruct"
 	"github.com/tilt-dev/tilt/internal/tiltfile/telemetry"
+	"github.com/tilt-dev/tilt/internal/tiltfile/unittest"
 	"github.com/tilt-dev/tilt/internal/tiltfile/updatesettings"
 	tfv1alpha1 "github.com/tilt-dev/tilt/internal/tiltfile/v1alpha1"
 	"github.com/tilt-dev/tilt/internal/tiltfile/version"
@@ -83,6 +85,12 @@ type tiltfileState struct {
 	localRegistry container.Registry
 	features      feature.FeatureSet
 
+	// Set when running a Tiltfile unit test, which never deploys anything.
+	unitTest bool
+
+	// Where kustomize() clones remote bases.
+	kustomizeCache *kustomize.RemoteCache
+
 	// added to during execution
 	buildIndex     *buildIndex
 	k8sObjectIndex *tiltfile_k8s.State
@@ -201,7 +209,7 @@ func (s *tiltfileState) loadManifests(tf *v1alpha1.Tiltfile) ([]model.Manifest,
 	}
 	fetcher := tiltextension.NewGithubFetcher(dlr)
 
-	result, err := starkit.ExecFile(tf,
+	plugins := []starkit.Plugin{
 		s,
 		include.IncludeFn{},
 		git.NewPlugin(),
@@ -227,7 +235,12 @@ func (s *tiltfileState) loadManifests(tf *v1alpha1.Tiltfile) ([]model.Manifest,
 		print.NewPlugin(),
 		probe.NewPlugin(),
 		tfv1alpha1.NewPlugin(),
-	)
+	}
+	if s.unitTest {
+		plugins = append(plugins, unittest.NewPlugin())
+	}
+
+	result, err := starkit.ExecFile(tf, plugins...)
 	if err != nil {
 		return nil, result, starkit.UnpackBacktrace(err)
 	}
@@ -254,12 +267,12 @@ func (s *tiltfileState) loadManifests(tf *v1alpha1.Tiltfile) ([]model.Manifest,
 	}
 
 	if len(resources.k8s) > 0 || len(unresourced) > 0 {
-		manifests, err = s.translateK8s(resources.k8s, us)
+		k8sManifests, err := s.translateK8s(resources.k8s, us)
 		if err != nil {
 			return nil, result, err
 		}
 
-		isAllowed := k8sContextState.IsAllowed(tf)
+		isAllowed := s.unitTest || k8sContextState.IsAllowed(tf)
 		if !isAllowed {
 			kubeContext := k8sContextState.KubeContext()
 			return nil, result, fmt.Errorf(`Stop! %s might be production.
@@ -267,17 +280,19 @@ If you're sure you want to deploy there, add:
 	allow_k8s_contexts('%s')
 to your Tiltfile. Otherwise, switch k8s contexts and restart Tilt.`, kubeContext, kubeContext)
 		}
-	} else {
-		if !resources.dc.Empty() {
-			if err := s.validateDockerComposeVersion(); err != nil {
-				return nil, result, err
-			}
+		manifests = append(manifests, k8sManifests...)
+	}
+
+	if !resources.dc.Empty() {
+		if err := s.validateDockerComposeVersion(); err != nil {
+			return nil, result, err
 		}
 
-		manifests, err = s.translateDC(resources.dc)
+		dcManifests, err := s.translateDC(resources.dc)
 		if err != nil {
 			return nil, result, err
 		}
+		manifests = append(manifests, dcManifests...)
 	}
 
 	err = s.validateLiveUpdatesForManifests(manifests)
@@ -318,6 +333,11 @@ to your Tiltfile. Otherwise, switch k8s contexts and restart Tilt.`, kubeContext
 		manifests = append(manifests, yamlManifest)
 	}
 
+	manifests, err = inferObjectDependencies(manifests)
+	if err != nil {
+		return nil, starkit.Model{}, err
+	}
+
 	err = validateResourceDependencies(manifests)
 	if err != nil {
 		return nil, starkit.Model{}, err
@@ -364,6 +384,7 @@ const (
 	k8sImageJSONPathN           = "k8s_image_json_path"
 	workloadToResourceFunctionN = "workload_to_resource_function"
 	k8sCustomDeployN            = "k8s_custom_deploy"
+	helmReleaseN                = "helm_release"
 
 	// local resource functions
 	localResourceN = "local_resource"
@@ -486,7 +507,7 @@ func (s *tiltfileState) potentiallyK8sUnsafeBuiltin(f starkit.Function) starkit.
 			return nil, err
 		}
 
-		isAllowed := k8sContextState.IsAllowed(tf)
+		isAllowed := s.unitTest || k8sContextState.IsAllowed(tf)
 		if !isAllowed {
 			kubeContext := k8sContextState.KubeContext()
 			return nil, fmt.Errorf(`Refusing to run '%s' because %s might be a production kube context.
@@ -546,6 +567,7 @@ func (s *tiltfileState) OnStart(e *starkit.Environment) error {
 		{filterYamlN, s.filterYaml},
 		{k8sResourceN, s.k8sResource},
 		{k8sCustomDeployN, s.k8sCustomDeploy},
+		{helmReleaseN, s.helmRelease},
 		{localResourceN, s.localResource},
 		{testN, s.localResource}, // test is just a fork of local resource, w/ some switches based on fn.Name()
 		{portForwardN, s.portForward},
@@ -586,15 +608,12 @@ func (s *tiltfileState) OnStart(e *starkit.Environment) error {
 	return nil
 }
 
-// Returns the current orchestrator.
+// Returns true if the Tiltfile has declared any Kubernetes resources or entities.
 //
-// Note that assemble() will eventually error out if this has
-// both DC and K8s resources.
-func (s *tiltfileState) orchestrator() model.Orchestrator {
-	if !s.dc.Empty() {
-		return model.OrchestratorDC
-	}
-	return model.OrchestratorK8s
+// A Tiltfile may declare both Kubernetes and Docker Compose resources;
+// each manifest has its own orchestrator.
+func (s *tiltfileState) hasK8s() bool {
+	return len(s.k8s) > 0 || len(s.k8sUnresourced) > 0
 }
 
 func (s *tiltfileState) assemble() (resourceSet, []k8s.K8sEntity, error) {
@@ -613,9 +632,9 @@ func (s *tiltfileState) assemble() (resourceSet, []k8s.K8sEntity, error) {
 		return resourceSet{}, nil, err
 	}
 
-	if !s.dc.Empty() && (len(s.k8s) > 0 || len(s.k8sUnresourced) > 0) {
-		return resourceSet{}, nil, fmt.Errorf("can't declare both k8s " +
-			"resources/entities and docker-compose resources")
+	err = s.assertUniqueResourceNames()
+	if err != nil {
+		return resourceSet{}, nil, err
 	}
 
 	return resourceSet{
@@ -624,6 +643,22 @@ func (s *tiltfileState) assemble() (resourceSet, []k8s.K8sEntity, error) {
 	}, s.k8sUnresourced, nil
 }
 
+// Kubernetes and Docker Compose resources share a single namespace
+// of resource names, so that resource_deps can refer to either.
+func (s *tiltfileState) assertUniqueResourceNames() error {
+	k8sNames := make(map[string]bool, len(s.k8s))
+	for _, r := range s.k8s {
+		k8sNames[r.name] = true
+	}
+	for _, svc := range s.dc.services {
+		if k8sNames[svc.Name] {
+			return fmt.Errorf("resource name %q is used by both a Kubernetes resource "+
+				"and a Docker Compose service. Rename one of them with k8s_resource(new_name=...)", svc.Name)
+		}
+	}
+	return nil
+}
+
 // Emit an error if there are unmatches images.
 //
 // There are 4 mistakes people commonly make if they
@@ -656,7 +691,11 @@ func (s *tiltfileState) assertAllImagesMatched(us model.UpdateSettings) error {
 
 	configType := "Kubernetes"
 	if len(s.dc.services) > 0 {
-		configType = "Docker Compose"
+		if s.hasK8s() {
+			configType = "Kubernetes or Docker Compose"
+		} else {
+			configType = "Docker Compose"
+		}
 	}
 	return s.buildIndex.unmatchedImageWarning(unmatchedImages[0], configType)
 }
@@ -685,7 +724,9 @@ func (s *tiltfileState) assembleImages() error {
 }
 
 func (s *tiltfileState) assembleDC() error {
-	if len(s.dc.services) > 0 && !s.defaultReg.Empty() {
+	// When Kubernetes resources are present, default_registry applies only
+	// to their images; Docker Compose images always stay on the local daemon.
+	if len(s.dc.services) > 0 && !s.defaultReg.Empty() && !s.hasK8s() {
 		return errors.New("default_registry is not supported with docker compose")
 	}
 
@@ -704,7 +745,12 @@ func (s *tiltfileState) assembleDC() error {
 }
 
 func (s *tiltfileState) assembleK8s() error {
-	err := s.assembleK8sByWorkload()
+	err := s.assembleK8sHelmReleases()
+	if err != nil {
+		return err
+	}
+
+	err = s.assembleK8sByWorkload()
 	if err != nil {
 		return err
 	}
@@ -746,6 +792,12 @@ func (s *tiltfileState) assembleK8s() error {
 			if opts.discoveryStrategy != "" {
 				r.discoveryStrategy = opts.discoveryStrategy
 			}
+			if opts.applyMode != "" {
+				r.applyMode = opts.applyMode
+			}
+			if opts.driftDetection != nil {
+				r.driftDetection = opts.driftDetection
+			}
 			r.portForwards = append(r.portForwards, opts.portForwards...)
 			if opts.triggerMode != TriggerModeUnset {
 				r.triggerMode = opts.triggerMode
@@ -860,6 +912,21 @@ func (s *tiltfileState) addEntityToResourceAndRemoveFromUnresourced(e k8s.K8sEnt
 	panic("Unable to find entity in unresourced YAML after checking that it was there. This should never happen")
 }
 
+// Adds the rendered objects of each helm_release() to its resource.
+func (s *tiltfileState) assembleK8sHelmReleases() error {
+	locators := s.k8sImageLocatorsList()
+	for _, r := range s.k8sByName {
+		if r.helmRelease == nil {
+			continue
+		}
+		err := r.addEntities(r.helmRelease.entities, locators, s.envVarImages())
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
 func (s *tiltfileState) assembleK8sByWorkload() error {
 	locators := s.k8sImageLocatorsList()
 
@@ -970,8 +1037,8 @@ func (s *tiltfileState) assembleK8sUnresourced() error {
 }
 
 func (s *tiltfileState) validateK8s(r *k8sResource) error {
-	if len(r.entities) == 0 && r.customDeploy == nil {
-		return fmt.Errorf("resource %q: could not associate any k8s_yaml() or k8s_custom_deploy() with this resource", r.name)
+	if len(r.entities) == 0 && r.customDeploy == nil && r.helmRelease == nil {
+		return fmt.Errorf("resource %q: could not associate any k8s_yaml(), k8s_custom_deploy(), or helm_release() with this resource", r.name)
 	}
 
 	for _, ref := range r.imageRefs {
@@ -1000,7 +1067,7 @@ func (s *tiltfileState) k8sResourceForName(name string) (*k8sResource, error) {
 // Otherwise, we'll return the zero value of `s.defaultReg`, which is an empty registry.
 // It has side-effects (a log line) and so should only be called once.
 func (s *tiltfileState) decideRegistry() container.Registry {
-	if s.orchestrator() == model.OrchestratorK8s && !s.localRegistry.Empty() {
+	if s.hasK8s() && !s.localRegistry.Empty() {
 		// If we've found a local registry in the cluster at run-time, use that
 		// instead of the default_registry (if any) declared in the Tiltfile
 		s.logger.Infof("Auto-detected local registry from environment: %s", s.localRegistry)
@@ -1087,18 +1154,7 @@ func (s *tiltfileState) translateK8s(resources []*k8sResource, updateSettings mo
 			return nil, errors.Wrapf(err, "getting image build info for %s", r.name)
 		}
 
-		// Currently, only Kubernetes ImageTargets support the reconciler,
-		// and only if the user has flagged it on.
-		if s.features.Get(feature.LiveUpdateV2) {
-			for i, iTarget := range iTargets {
-				if liveupdate.IsEmptySpec(iTarget.LiveUpdateSpec) {
-					continue
-				}
-				iTarget.LiveUpdateReconciler = true
-				iTargets[i] = iTarget
-			}
-		}
-
+		iTargets = s.maybeUseLiveUpdateReconciler(iTargets)
 		m = m.WithImageTargets(iTargets)
 
 		k8sTarget, err := s.k8sDeployTarget(mn.TargetName(), r, iTargets, updateSettings)
@@ -1131,6 +1187,8 @@ func (s *tiltfileState) k8sDeployTarget(targetName model.TargetName, r *k8sResou
 		Timeout:                         metav1.Duration{Duration: updateSettings.K8sUpsertTimeout()},
 		PortForwardTemplateSpec:         k8s.PortForwardTemplateSpec(s.defaultedPortForwards(r.portForwards)),
 		DiscoveryStrategy:               r.discoveryStrategy,
+		ApplyMode:                       r.applyMode,
+		DriftDetection:                  r.driftDetection,
 		KubernetesDiscoveryTemplateSpec: kdTemplateSpec,
 		PodLogStreamTemplateSpec: &v1alpha1.PodLogStreamTemplateSpec{
 			SinceTime: &sinceTime,
@@ -1149,6 +1207,18 @@ func (s *tiltfileState) k8sDeployTarget(targetName model.TargetName, r *k8sResou
 		applySpec.RestartOn = &v1alpha1.RestartOnSpec{
 			FileWatches: []string{apis.SanitizeName(fmt.Sprintf("%s:apply", targetName.String()))},
 		}
+	} else if r.helmRelease != nil {
+		deps = r.helmRelease.deps
+		applySpec.HelmRelease = r.helmRelease.spec.DeepCopy()
+		applySpec.RestartOn = &v1alpha1.RestartOnSpec{
+			FileWatches: []string{apis.SanitizeName(fmt.Sprintf("%s:apply", targetName.String()))},
+		}
+
+		for _, locator := range s.k8sImageLocatorsList() {
+			if k8s.LocatorMatchesOne(locator, r.entities) {
+				applySpec.ImageLocators = append(applySpec.ImageLocators, locator.ToSpec())
+			}
+		}
 	} else {
 		entities := k8s.SortedEntities(r.entities)
 		var err error
@@ -1475,6 +1545,7 @@ func (s *tiltfileState) translateDC(dc dcResourceSet) ([]model.Manifest, error)
 			}
 		}
 
+		iTargets = s.maybeUseLiveUpdateReconciler(iTargets)
 		m = m.WithImageTargets(iTargets)
 
 		result = append(result, m)
@@ -1483,6 +1554,22 @@ func (s *tiltfileState) translateDC(dc dcResourceSet) ([]model.Manifest, error)
 	return result, nil
 }
 
+// ImageTargets only use the live update reconciler if the user has flagged it on.
+func (s *tiltfileState) maybeUseLiveUpdateReconciler(iTargets []model.ImageTarget) []model.ImageTarget {
+	if !s.features.Get(feature.LiveUpdateV2) {
+		return iTargets
+	}
+
+	for i, iTarget := range iTargets {
+		if liveupdate.IsEmptySpec(iTarget.LiveUpdateSpec) {
+			continue
+		}
+		iTarget.LiveUpdateReconciler = true
+		iTargets[i] = iTarget
+	}
+	return iTargets
+}
+
 type claim int
 
 const (
@@ -1559,7 +1646,10 @@ func (s *tiltfileState) translateLocal() ([]model.Manifest, error) {
 			WithLinks(r.links).
 			WithTags(r.tags).
 			WithIsTest(r.isTest).
-			WithReadinessProbe(r.readinessProbe)
+			WithReadinessProbe(r.readinessProbe).
+			WithServeRestartPolicy(r.serveRestartPolicy).
+			WithServeStopSignal(r.serveStopSignal).
+			WithServeTerminationGracePeriod(r.serveTerminationGracePeriod)
 		var mds []model.ManifestName
 		for _, md := range r.resourceDeps {
 			mds = append(mds, model.ManifestName(md))
@@ -1578,6 +1668,38 @@ func (s *tiltfileState) translateLocal() ([]model.Manifest, error) {
 	return result, nil
 }
 
+// Analyze the Kubernetes objects in each manifest to figure out which
+// manifests need to deploy before others (e.g., Namespaces and CRDs).
+func inferObjectDependencies(ms []model.Manifest) ([]model.Manifest, error) {
+	// Uncategorized YAML deploys first, so it should win any cycles.
+	var groups []k8s.EntityGroup
+	for _, m := range ms {
+		if !m.IsK8s() {
+			continue
+		}
+
+		entities, err := k8s.ParseYAMLFromString(m.K8sTarget().YAML)
+		if err != nil {
+			return nil, errors.Wrapf(err, "parsing YAML of resource %s", m.Name)
+		}
+
+		g := k8s.EntityGroup{Name: m.Name.String(), Entities: entities}
+		if m.Name == model.UnresourcedYAMLManifestName {
+			groups = append([]k8s.EntityGroup{g}, groups...)
+		} else {
+			groups = append(groups, g)
+		}
+	}
+
+	deps := k8s.InferGroupDependencies(groups)
+	for i, m := range ms {
+		if names, ok := deps[m.Name.String()]; ok {
+			ms[i].ObjectDependencies = model.ManifestNames(names)
+		}
+	}
+	return ms, nil
+}
+
 func validateResourceDependencies(ms []model.Manifest) error {
 	// make sure that:
 	// 1. all deps exist
@@ -0,0 +1,90 @@
+package unittest
+
+import (
+	"fmt"
+	"strings"
+
+	"go.starlark.net/starlark"
+	"go.starlark.net/syntax"
+
+	"github.com/tilt-dev/tilt/internal/tiltfile/starkit"
+)
+
+func (p Plugin) equals(thread *starlark.Thread, fn *starlark.Builtin, args starlark.Tuple, kwargs []starlark.Tuple) (starlark.Value, error) {
+	var expected, actual starlark.Value
+	var msg string
+	err := starkit.UnpackArgs(thread, fn.Name(), args, kwargs,
+		"expected", &expected,
+		"actual", &actual,
+		"msg?", &msg)
+	if err != nil {
+		return nil, err
+	}
+
+	eq, err := starlark.Equal(expected, actual)
+	if err != nil {
+		return nil, fmt.Errorf("%s: %v", fn.Name(), err)
+	}
+	if !eq {
+		return nil, assertionError(msg, "expected %s, got %s", expected, actual)
+	}
+	return starlark.None, nil
+}
+
+func (p Plugin) contains(thread *starlark.Thread, fn *starlark.Builtin, args starlark.Tuple, kwargs []starlark.Tuple) (starlark.Value, error) {
+	var container, item starlark.Value
+	var msg string
+	err := starkit.UnpackArgs(thread, fn.Name(), args, kwargs,
+		"container", &container,
+		"item", &item,
+		"msg?", &msg)
+	if err != nil {
+		return nil, err
+	}
+
+	in, err := starlark.Binary(syntax.IN, item, container)
+	if err != nil {
+		return nil, fmt.Errorf("%s: %v", fn.Name(), err)
+	}
+	if !in.Truth() {
+		return nil, assertionError(msg, "%s does not contain %s", container, item)
+	}
+	return starlark.None, nil
+}
+
+// Calls a function that takes no arguments, and asserts that it fails.
+//
+// Returns the error message, so that the test can make more assertions about it.
+func (p Plugin) fails(thread *starlark.Thread, fn *starlark.Builtin, args starlark.Tuple, kwargs []starlark.Tuple) (starlark.Value, error) {
+	var f starlark.Callable
+	var expectedErr, msg string
+	err := starkit.UnpackArgs(thread, fn.Name(), args, kwargs,
+		"fn", &f,
+		"error?", &expectedErr,
+		"msg?", &msg)
+	if err != nil {
+		return nil, err
+	}
+
+	_, err = starlark.Call(thread, f, nil, nil)
+	if err == nil {
+		return nil, assertionError(msg, "expected %s to fail", f.Name())
+	}
+
+	errMsg := err.Error()
+	if evalErr, ok := err.(*starlark.EvalError); ok {
+		errMsg = evalErr.Msg
+	}
+	if !strings.Contains(errMsg, expectedErr) {
+		return nil, assertionError(msg, "expected %s to fail with %q, got: %s", f.Name(), expectedErr, errMsg)
+	}
+	return starlark.String(errMsg), nil
+}
+
+func assertionError(msg string, format string, a ...interface{}) error {
+	result := fmt.Sprintf("assertion failed: "+format, a...)
+	if msg != "" {
+		result = fmt.Sprintf("%s: %s", result, msg)
+	}
+	return fmt.Errorf("%s", result)
+}
@@ -0,0 +1,167 @@
+// Package unittest adds the builtins for Tiltfile unit tests, run with
+// `tilt alpha tiltfile test`.
+//
+// Tests can make assertions with assert.equals(), assert.contains(), and
+// assert.fails(), and replace the results of local() and helm() with
+// fake.local() and fake.helm(), so that a test never runs commands.
+package unittest
+
+import (
+	"fmt"
+
+	"go.starlark.net/starlark"
+
+	"github.com/tilt-dev/tilt/internal/tiltfile/starkit"
+	"github.com/tilt-dev/tilt/internal/tiltfile/value"
+	"github.com/tilt-dev/tilt/pkg/model"
+)
+
+// The fake result of a local() command.
+type FakeLocal struct {
+	Stdout string
+
+	// If non-empty, local() fails with this error.
+	Error string
+}
+
+type State struct {
+	// Fake local() results, indexed by command.
+	Local map[string]FakeLocal
+
+	// Fake helm() YAML, indexed by the absolute path of the chart.
+	Helm map[string]string
+}
+
+type Plugin struct{}
+
+func NewPlugin() Plugin {
+	return Plugin{}
+}
+
+func (Plugin) NewState() interface{} {
+	return State{
+		Local: make(map[string]FakeLocal),
+		Helm:  make(map[string]string),
+	}
+}
+
+var _ starkit.StatefulPlugin = Plugin{}
+
+func (p Plugin) OnStart(env *starkit.Environment) error {
+	for _, b := range []struct {
+		name string
+		f    starkit.Function
+	}{
+		{"assert.equals", p.equals},
+		{"assert.contains", p.contains},
+		{"assert.fails", p.fails},
+		{"fake.local", p.fakeLocal},
+		{"fake.helm", p.fakeHelm},
+	} {
+		err := env.AddBuiltin(b.name, b.f)
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func (p Plugin) fakeLocal(thread *starlark.Thread, fn *starlark.Builtin, args starlark.Tuple, kwargs []starlark.Tuple) (starlark.Value, error) {
+	var command starlark.Value
+	var fake FakeLocal
+	err := starkit.UnpackArgs(thread, fn.Name(), args, kwargs,
+		"command", &command,
+		"stdout?", &fake.Stdout,
+		"error?", &fake.Error)
+	if err != nil {
+		return nil, err
+	}
+
+	cmd, err := value.ValueToHostCmd(thread, command, nil, nil)
+	if err != nil {
+		return nil, fmt.Errorf("%s: %v", fn.Name(), err)
+	}
+	if cmd.Empty() {
+		return nil, fmt.Errorf("%s: command must not be empty", fn.Name())
+	}
+
+	err = starkit.SetState(thread, func(s State) State {
+		s.Local[cmd.String()] = fake
+		return s
+	})
+	return starlark.None, err
+}
+
+func (p Plugin) fakeHelm(thread *starlark.Thread, fn *starlark.Builtin, args starlark.Tuple, kwargs []starlark.Tuple) (starlark.Value, error) {
+	var path starlark.Value
+	var yaml value.Stringable
+	err := starkit.UnpackArgs(thread, fn.Name(), args, kwargs,
+		"paths", &path,
+		"yaml", &yaml)
+	if err != nil {
+		return nil, err
+	}
+
+	chartPath, err := value.ValueToAbsPath(thread, path)
+	if err != nil {
+		return nil, fmt.Errorf("Argument 0 (paths): %v", err)
+	}
+
+	err = starkit.SetState(thread, func(s State) State {
+		s.Helm[chartPath] = yaml.Value
+		return s
+	})
+	return starlark.None, err
+}
+
+// Looks up the fake result of a local() command.
+//
+// Returns ok=false if the Tiltfile isn't a unit test, and the command should run.
+// In a unit test, every command must be faked, so an unfaked command is an error.
+func LocalResult(t *starlark.Thread, cmd model.Cmd) (stdout string, ok bool, err error) {
+	state, ok := getState(t)
+	if !ok {
+		return "", false, nil
+	}
+
+	fake, ok := state.Local[cmd.String()]
+	if !ok {
+		return "", true, fmt.Errorf("Tiltfile tests don't run commands. Fake the result of local(%q) with:\n"+
+			"  fake.local(%q, stdout='...')", cmd.String(), cmd.String())
+	}
+	if fake.Error != "" {
+		return "", true, fmt.Errorf("command %q failed.\nerror: %s", cmd.String(), fake.Error)
+	}
+	return fake.Stdout, true, nil
+}
+
+// Looks up the fake output of `helm template` for a chart.
+//
+// Returns ok=false if the Tiltfile isn't a unit test, and helm should run.
+func HelmResult(t *starlark.Thread, chartPath string) (yaml string, ok bool, err error) {
+	state, ok := getState(t)
+	if !ok {
+		return "", false, nil
+	}
+
+	yaml, ok = state.Helm[chartPath]
+	if !ok {
+		return "", true, fmt.Errorf("Tiltfile tests don't run helm. Fake the output of helm(%q) with:\n"+
+			"  fake.helm(%q, yaml='...')", chartPath, chartPath)
+	}
+	return yaml, true, nil
+}
+
+// Loads the state, if this plugin is registered.
+func getState(t *starlark.Thread) (State, bool) {
+	m, err := starkit.ModelFromThread(t)
+	if err != nil {
+		return State{}, false
+	}
+	var state State
+	err = m.Load(&state)
+	if err != nil {
+		return State{}, false
+	}
+	return state, true
+}
@@ -0,0 +1,141 @@
+package unittest
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.starlark.net/starlark"
+
+	"github.com/tilt-dev/tilt/internal/tiltfile/starkit"
+	"github.com/tilt-dev/tilt/pkg/model"
+)
+
+func TestAssertions(t *testing.T) {
+	f := starkit.NewFixture(t, NewPlugin())
+	defer f.TearDown()
+
+	f.File("Tiltfile", `
+assert.equals({'a': [1, 2]}, {'a': [1, 2]})
+assert.contains([1, 2, 3], 2)
+assert.contains('hello world', 'world')
+assert.contains({'a': 1}, 'a')
+
+def boom():
+  fail('kaboom')
+
+msg = assert.fails(boom, error='kaboom')
+assert.contains(msg, 'kaboom')
+`)
+
+	_, err := f.ExecFile("Tiltfile")
+	require.NoError(t, err)
+}
+
+func TestAssertionFailures(t *testing.T) {
+	for _, tc := range []struct {
+		name     string
+		tiltfile string
+		expected string
+	}{
+		{"equals", "assert.equals('a', 'b')", `assertion failed: expected "a", got "b"`},
+		{"equals msg", "assert.equals(1, 2, msg='one is two')", `assertion failed: expected 1, got 2: one is two`},
+		{"contains", "assert.contains([1], 2)", `assertion failed: [1] does not contain 2`},
+		{"contains type", "assert.contains(1, 2)", `assert.contains: unknown binary op: int in int`},
+		{"fails", "def ok():\n  pass\nassert.fails(ok)", `assertion failed: expected ok to fail`},
+		{"fails error", "def boom():\n  fail('kaboom')\nassert.fails(boom, error='fizzle')",
+			`assertion failed: expected boom to fail with "fizzle", got: fail: kaboom`},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			f := starkit.NewFixture(t, NewPlugin())
+			defer f.TearDown()
+
+			f.File("Tiltfile", tc.tiltfile)
+			_, err := f.ExecFile("Tiltfile")
+			if assert.Error(t, err) {
+				assert.Contains(t, err.Error(), tc.expected)
+			}
+		})
+	}
+}
+
+func TestFakes(t *testing.T) {
+	f := starkit.NewFixture(t, NewPlugin(), fakeResultPlugin{})
+	defer f.TearDown()
+
+	f.File("Tiltfile", `
+fake.local('git rev-parse HEAD', stdout='abc123\n')
+fake.local(['kubectl', 'version'], error='connection refused')
+fake.helm('./chart', yaml='kind: Service')
+
+print(local_result('git rev-parse HEAD'))
+print(local_result('kubectl version'))
+print(local_result('whoami'))
+print(helm_result('./chart'))
+print(helm_result('./other'))
+`)
+
+	_, err := f.ExecFile("Tiltfile")
+	require.NoError(t, err)
+	assert.Equal(t, `abc123
+
+error: command "kubectl version" failed.
+error: connection refused
+error: Tiltfile tests don't run commands. Fake the result of local("whoami") with:
+  fake.local("whoami", stdout='...')
+kind: Service
+error: Tiltfile tests don't run helm. Fake the output of helm("`+f.JoinPath("other")+`") with:
+  fake.helm("`+f.JoinPath("other")+`", yaml='...')
+`, f.PrintOutput())
+}
+
+func TestNoFakesOutsideOfTests(t *testing.T) {
+	f := starkit.NewFixture(t, fakeResultPlugin{})
+	defer f.TearDown()
+
+	f.File("Tiltfile", `
+print(local_result('whoami'))
+`)
+
+	_, err := f.ExecFile("Tiltfile")
+	require.NoError(t, err)
+	assert.Equal(t, "not faked\n", f.PrintOutput())
+}
+
+// Exposes the fake lookups to the Tiltfile, the way local() and helm() use them.
+type fakeResultPlugin struct{}
+
+func (fakeResultPlugin) OnStart(env *starkit.Environment) error {
+	err := env.AddBuiltin("local_result", func(thread *starlark.Thread, fn *starlark.Builtin, args starlark.Tuple, kwargs []starlark.Tuple) (starlark.Value, error) {
+		var cmd string
+		err := starkit.UnpackArgs(thread, fn.Name(), args, kwargs, "cmd", &cmd)
+		if err != nil {
+			return nil, err
+		}
+		stdout, ok, err := LocalResult(thread, model.ToHostCmd(cmd))
+		return fakeResult(stdout, ok, err), nil
+	})
+	if err != nil {
+		return err
+	}
+
+	return env.AddBuiltin("helm_result", func(thread *starlark.Thread, fn *starlark.Builtin, args starlark.Tuple, kwargs []starlark.Tuple) (starlark.Value, error) {
+		var path string
+		err := starkit.UnpackArgs(thread, fn.Name(), args, kwargs, "path", &path)
+		if err != nil {
+			return nil, err
+		}
+		yaml, ok, err := HelmResult(thread, starkit.AbsPath(thread, path))
+		return fakeResult(yaml, ok, err), nil
+	})
+}
+
+func fakeResult(out string, ok bool, err error) starlark.Value {
+	if !ok {
+		return starlark.String("not faked")
+	}
+	if err != nil {
+		return starlark.String("error: " + err.Error())
+	}
+	return starlark.String(out)
+}
@@ -0,0 +1,234 @@
+package tiltfile
+
+import (
+	"context"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+
+	"github.com/pmezard/go-difflib/difflib"
+	"sigs.k8s.io/yaml"
+
+	ctrltiltfile "github.com/tilt-dev/tilt/internal/controllers/apis/tiltfile"
+	"github.com/tilt-dev/tilt/pkg/model"
+)
+
+// Tiltfile unit tests end with this suffix, e.g., frontend_test.tilt
+const UnitTestSuffix = "_test.tilt"
+
+const goldenHeader = "# Generated by `tilt alpha tiltfile test --update`. DO NOT EDIT.\n"
+
+type UnitTestResult struct {
+	Path       string
+	GoldenPath string
+
+	// The test failed to load, or one of its assertions failed.
+	Error error
+
+	// How the result differs from the golden file.
+	// Empty if the result matched, or if the test has no golden file.
+	GoldenDiff string
+
+	// The golden file was (re)written.
+	Updated bool
+}
+
+func (r UnitTestResult) Failed() bool {
+	return r.Error != nil || r.GoldenDiff != ""
+}
+
+// The golden file that records the result of a test, e.g.,
+// frontend_test.tilt is compared against frontend_test.golden
+func GoldenPath(testPath string) string {
+	return strings.TrimSuffix(testPath, ".tilt") + ".golden"
+}
+
+// Finds the Tiltfile unit tests in the given files and directories.
+//
+// Directories are searched recursively, skipping hidden directories and tilt_modules.
+func FindUnitTests(paths []string) ([]string, error) {
+	var result []string
+	for _, p := range paths {
+		info, err := os.Stat(p)
+		if err != nil {
+			return nil, err
+		}
+		if !info.IsDir() {
+			result = append(result, p)
+			continue
+		}
+
+		err = filepath.Walk(p, func(path string, info os.FileInfo, err error) error {
+			if err != nil {
+				return err
+			}
+			if info.IsDir() {
+				name := info.Name()
+				if path != p && (strings.HasPrefix(name, ".") || name == "tilt_modules") {
+					return filepath.SkipDir
+				}
+				return nil
+			}
+			if strings.HasSuffix(path, UnitTestSuffix) {
+				result = append(result, path)
+			}
+			return nil
+		})
+		if err != nil {
+			return nil, err
+		}
+	}
+	sort.Strings(result)
+	return result, nil
+}
+
+// Runs a Tiltfile unit test, and compares the result against its golden file.
+//
+// The test runs with the assert and fake builtins, and never deploys anything.
+// If there's no golden file, the test only checks its assertions.
+// With update, (re)writes the golden file instead of comparing against it.
+func RunUnitTest(ctx context.Context, tfl TiltfileLoader, path string, update bool) UnitTestResult {
+	result := UnitTestResult{Path: path, GoldenPath: GoldenPath(path)}
+
+	absPath, err := filepath.Abs(path)
+	if err != nil {
+		result.Error = err
+		return result
+	}
+
+	tlr := tfl.LoadUnitTest(ctx, ctrltiltfile.MainTiltfile(absPath, nil))
+	if tlr.Error != nil {
+		result.Error = tlr.Error
+		return result
+	}
+
+	actual, err := GoldenResult(tlr, filepath.Dir(absPath))
+	if err != nil {
+		result.Error = err
+		return result
+	}
+
+	if update {
+		err = ioutil.WriteFile(result.GoldenPath, []byte(actual), 0644)
+		if err != nil {
+			result.Error = err
+			return result
+		}
+		result.Updated = true
+		return result
+	}
+
+	expected, err := ioutil.ReadFile(result.GoldenPath)
+	if os.IsNotExist(err) {
+		return result
+	} else if err != nil {
+		result.Error = err
+		return result
+	}
+
+	if string(expected) != actual {
+		result.GoldenDiff, err = difflib.GetUnifiedDiffString(difflib.UnifiedDiff{
+			A:        difflib.SplitLines(string(expected)),
+			B:        difflib.SplitLines(actual),
+			FromFile: result.GoldenPath,
+			ToFile:   "actual",
+			Context:  3,
+		})
+		if err != nil {
+			result.Error = err
+		}
+	}
+	return result
+}
+
+type goldenResult struct {
+	Manifests []goldenManifest `json:"manifests"`
+}
+
+type goldenManifest struct {
+	Name         string            `json:"name"`
+	Labels       map[string]string `json:"labels,omitempty"`
+	ResourceDeps []string          `json:"resourceDeps,omitempty"`
+	Images       []goldenImage     `json:"images,omitempty"`
+	UpdateCmd    string            `json:"updateCmd,omitempty"`
+	ServeCmd     string            `json:"serveCmd,omitempty"`
+	YAML         string            `json:"yaml,omitempty"`
+}
+
+type goldenImage struct {
+	Ref           string   `json:"ref"`
+	Context       string   `json:"context,omitempty"`
+	Dockerfile    string   `json:"dockerfile,omitempty"`
+	Args          []string `json:"args,omitempty"`
+	Target        string   `json:"target,omitempty"`
+	CustomCommand string   `json:"customCommand,omitempty"`
+	CustomDeps    []string `json:"customDeps,omitempty"`
+}
+
+// Renders the manifests, image targets, and Kubernetes YAML of a Tiltfile
+// in a stable format for golden files.
+//
+// Paths are relative to dir, so that golden files don't depend on
+// where the repo is checked out.
+func GoldenResult(tlr TiltfileLoadResult, dir string) (string, error) {
+	rel := func(path string) string {
+		r, err := filepath.Rel(dir, path)
+		if err != nil {
+			return path
+		}
+		return filepath.ToSlash(r)
+	}
+
+	result := goldenResult{Manifests: []goldenManifest{}}
+	for _, m := range tlr.Manifests {
+		gm := goldenManifest{
+			Name:   m.Name.String(),
+			Labels: m.Labels,
+		}
+		if len(gm.Labels) == 0 {
+			gm.Labels = nil
+		}
+		for _, dep := range m.ResourceDependencies {
+			gm.ResourceDeps = append(gm.ResourceDeps, dep.String())
+		}
+
+		for _, iTarget := range m.ImageTargets {
+			gi := goldenImage{Ref: iTarget.Refs.ConfigurationRef.String()}
+			if iTarget.IsDockerBuild() {
+				db := iTarget.DockerBuildInfo()
+				gi.Context = rel(db.Context)
+				gi.Dockerfile = db.DockerfileContents
+				gi.Args = db.Args
+				gi.Target = db.Target
+			} else if iTarget.IsCustomBuild() {
+				cb := iTarget.CustomBuildInfo()
+				gi.CustomCommand = cb.Command.String()
+				for _, dep := range cb.Deps {
+					gi.CustomDeps = append(gi.CustomDeps, rel(dep))
+				}
+			}
+			gm.Images = append(gm.Images, gi)
+		}
+
+		if m.IsK8s() {
+			gm.YAML = m.K8sTarget().YAML
+		}
+		if m.IsLocal() {
+			lt := m.LocalTarget()
+			if lt.UpdateCmdSpec != nil {
+				gm.UpdateCmd = model.Cmd{Argv: lt.UpdateCmdSpec.Args}.String()
+			}
+			gm.ServeCmd = lt.ServeCmd.String()
+		}
+
+		result.Manifests = append(result.Manifests, gm)
+	}
+
+	out, err := yaml.Marshal(result)
+	if err != nil {
+		return "", err
+	}
+	return goldenHeader + string(out), nil
+}
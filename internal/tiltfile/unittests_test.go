@@ -0,0 +1,155 @@
+package tiltfile
+
+import (
+	"io/ioutil"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+const unitTestTiltfile = `
+tag = str(local('git rev-parse --short HEAD', quiet=True)).strip()
+docker_build('gcr.io/foo', 'foo', build_args={'TAG': tag})
+k8s_yaml(helm('./chart', namespace='foo'))
+local_resource('lint', 'make lint', labels=['checks'])
+`
+
+const unitTestChartYAML = `apiVersion: v1
+kind: Pod
+metadata:
+  name: foo
+spec:
+  containers:
+  - name: foo
+    image: gcr.io/foo
+`
+
+func TestUnitTestGolden(t *testing.T) {
+	f := newUnitTestFixture(t)
+	defer f.TearDown()
+
+	f.file("Tiltfile_test.tilt", `
+include('./Tiltfile')
+`)
+	f.file("foo_test.tilt", `
+fake.local('git rev-parse --short HEAD', stdout='abc123\n')
+fake.helm('./chart', yaml='''`+unitTestChartYAML+`''')
+load('./Tiltfile', 'tag')
+
+assert.equals('abc123', tag)
+assert.fails(lambda: local('rm -rf /'), error="Tiltfile tests don't run commands")
+`)
+
+	result := RunUnitTest(f.ctx, f.newTiltfileLoader(), f.JoinPath("foo_test.tilt"), true)
+	require.NoError(t, result.Error)
+	assert.True(t, result.Updated)
+	assert.Equal(t, f.JoinPath("foo_test.golden"), result.GoldenPath)
+
+	golden, err := ioutil.ReadFile(result.GoldenPath)
+	require.NoError(t, err)
+	assert.Equal(t, goldenHeader+`manifests:
+- images:
+  - args:
+    - TAG=abc123
+    context: foo
+    dockerfile: FROM golang:1.10
+    ref: gcr.io/foo
+  name: foo
+  yaml: |
+    apiVersion: v1
+    kind: Pod
+    metadata:
+      name: foo
+      namespace: foo
+    spec:
+      containers:
+      - image: gcr.io/foo
+        name: foo
+        resources: {}
+- labels:
+    checks: checks
+  name: lint
+  updateCmd: make lint
+`, string(golden))
+
+	result = RunUnitTest(f.ctx, f.newTiltfileLoader(), f.JoinPath("foo_test.tilt"), false)
+	require.NoError(t, result.Error)
+	assert.False(t, result.Failed())
+
+	f.file("Tiltfile", unitTestTiltfile+"local_resource('fmt', 'make fmt')\n")
+	result = RunUnitTest(f.ctx, f.newTiltfileLoader(), f.JoinPath("foo_test.tilt"), false)
+	require.NoError(t, result.Error)
+	assert.True(t, result.Failed())
+	assert.Contains(t, result.GoldenDiff, `
++- name: fmt
++  updateCmd: make fmt
+`)
+
+	// The test without fakes fails on the first command.
+	result = RunUnitTest(f.ctx, f.newTiltfileLoader(), f.JoinPath("Tiltfile_test.tilt"), false)
+	if assert.Error(t, result.Error) {
+		assert.Contains(t, result.Error.Error(),
+			`fake.local("git rev-parse --short HEAD", stdout='...')`)
+	}
+}
+
+func TestUnitTestAssertionFailure(t *testing.T) {
+	f := newUnitTestFixture(t)
+	defer f.TearDown()
+
+	f.file("foo_test.tilt", `
+assert.contains(['a', 'b'], 'c')
+`)
+
+	result := RunUnitTest(f.ctx, f.newTiltfileLoader(), f.JoinPath("foo_test.tilt"), true)
+	if assert.Error(t, result.Error) {
+		assert.Contains(t, result.Error.Error(), `assertion failed: ["a", "b"] does not contain "c"`)
+	}
+	assert.False(t, result.Updated)
+	assert.NoFileExists(t, f.JoinPath("foo_test.golden"))
+}
+
+func TestUnitTestSkipsK8sContextCheck(t *testing.T) {
+	f := newUnitTestFixture(t)
+	defer f.TearDown()
+
+	f.k8sContext = "gke_prod"
+	f.k8sEnv = "gke"
+	f.file("foo_test.tilt", `
+fake.local('git rev-parse --short HEAD', stdout='abc123')
+fake.helm('./chart', yaml='''`+unitTestChartYAML+`''')
+include('./Tiltfile')
+`)
+
+	result := RunUnitTest(f.ctx, f.newTiltfileLoader(), f.JoinPath("foo_test.tilt"), false)
+	assert.NoError(t, result.Error)
+}
+
+func TestFindUnitTests(t *testing.T) {
+	f := newFixture(t)
+	defer f.TearDown()
+
+	f.file("a_test.tilt", "")
+	f.file("Tiltfile", "")
+	f.file("sub/b_test.tilt", "")
+	f.file("sub/test.tilt", "")
+	f.file("tilt_modules/ext/c_test.tilt", "")
+	f.file(".git/d_test.tilt", "")
+
+	tests, err := FindUnitTests([]string{f.Path()})
+	require.NoError(t, err)
+	assert.Equal(t, []string{f.JoinPath("a_test.tilt"), f.JoinPath("sub", "b_test.tilt")}, tests)
+
+	tests, err = FindUnitTests([]string{f.JoinPath("sub", "test.tilt")})
+	require.NoError(t, err)
+	assert.Equal(t, []string{f.JoinPath("sub", "test.tilt")}, tests)
+}
+
+func newUnitTestFixture(t *testing.T) *fixture {
+	f := newFixture(t)
+	f.file("Tiltfile", unitTestTiltfile)
+	f.dockerfile("foo/Dockerfile")
+	f.file("chart/Chart.yaml", "name: foo\nversion: 0.1.0\n")
+	return f
+}
@@ -63,7 +63,7 @@ v1alpha1.extension_repo(name='default', url='ftp://github.com/tilt-dev/tilt-exte
 `)
 	_, err := f.ExecFile("Tiltfile")
 	require.Error(t, err)
-	require.Contains(t, err.Error(), "URLs must start with http(s):// or file://")
+	require.Contains(t, err.Error(), "URLs must start with http(s)://, ssh://, git@, or file://")
 }
 
 func TestFileWatchAsDict(t *testing.T) {
@@ -168,6 +168,28 @@ v1alpha1.cmd(
 	})
 }
 
+func TestCmdStopSignal(t *testing.T) {
+	f := newFixture(t)
+	defer f.TearDown()
+
+	f.File("Tiltfile", `
+v1alpha1.cmd(
+  name='my-cmd',
+  args=['./server'],
+  stop_signal='SIGINT',
+  termination_grace_period='1m')
+`)
+	result, err := f.ExecFile("Tiltfile")
+	require.NoError(t, err)
+
+	set := MustState(result)
+
+	cmd := set.GetSetForType(&v1alpha1.Cmd{})["my-cmd"].(*v1alpha1.Cmd)
+	require.NotNil(t, cmd)
+	require.Equal(t, "SIGINT", cmd.Spec.StopSignal)
+	require.Equal(t, metav1.Duration{Duration: time.Minute}, cmd.Spec.TerminationGracePeriod)
+}
+
 func TestUIButton(t *testing.T) {
 	f := newFixture(t)
 	defer f.TearDown()
@@ -254,6 +276,36 @@ v1alpha1.config_map(
 	})
 }
 
+func TestDockerImage(t *testing.T) {
+	f := newFixture(t)
+	defer f.TearDown()
+
+	f.File("Tiltfile", `
+v1alpha1.docker_image(
+  name='my-image',
+  ref='gcr.io/my-image',
+  dockerfile_contents='FROM alpine',
+  args=['FOO=bar'],
+  pull=True,
+  image_map='my-image')
+`)
+	result, err := f.ExecFile("Tiltfile")
+	require.NoError(t, err)
+
+	set := MustState(result)
+
+	obj := set.GetSetForType(&v1alpha1.DockerImage{})["my-image"].(*v1alpha1.DockerImage)
+	require.NotNil(t, obj)
+	require.Equal(t, v1alpha1.DockerImageSpec{
+		Ref:                "gcr.io/my-image",
+		DockerfileContents: "FROM alpine",
+		Context:            f.Path(),
+		Args:               []string{"FOO=bar"},
+		Pull:               true,
+		ImageMap:           "my-image",
+	}, obj.Spec)
+}
+
 func TestKubernetesApply(t *testing.T) {
 	f := newFixture(t)
 	defer f.TearDown()
@@ -26,6 +26,10 @@ func (p Plugin) registerSymbols(env *starkit.Environment) error {
 	if err != nil {
 		return err
 	}
+	err = env.AddBuiltin("v1alpha1.docker_image", p.dockerImage)
+	if err != nil {
+		return err
+	}
 	err = env.AddBuiltin("v1alpha1.extension", p.extension)
 	if err != nil {
 		return err
@@ -90,6 +94,14 @@ func (p Plugin) registerSymbols(env *starkit.Environment) error {
 	if err != nil {
 		return err
 	}
+	err = env.AddBuiltin("v1alpha1.kubernetes_drift_detection", p.kubernetesDriftDetection)
+	if err != nil {
+		return err
+	}
+	err = env.AddBuiltin("v1alpha1.kubernetes_helm_release", p.kubernetesHelmRelease)
+	if err != nil {
+		return err
+	}
 	err = env.AddBuiltin("v1alpha1.kubernetes_image_locator", p.kubernetesImageLocator)
 	if err != nil {
 		return err
@@ -178,6 +190,9 @@ func (p Plugin) cmd(t *starlark.Thread, fn *starlark.Builtin, args starlark.Tupl
 	var restartOn RestartOnSpec = RestartOnSpec{t: t}
 	var startOn StartOnSpec = StartOnSpec{t: t}
 	var disableSource DisableSource = DisableSource{t: t}
+	var restartPolicy string
+	var maxRestarts int
+	var terminationGracePeriod value.Duration
 	var labels value.StringStringMap
 	var annotations value.StringStringMap
 	err = starkit.UnpackArgs(t, fn.Name(), args, kwargs,
@@ -191,6 +206,10 @@ func (p Plugin) cmd(t *starlark.Thread, fn *starlark.Builtin, args starlark.Tupl
 		"restart_on?", &restartOn,
 		"start_on?", &startOn,
 		"disable_source?", &disableSource,
+		"restart_policy?", &restartPolicy,
+		"max_restarts?", &maxRestarts,
+		"stop_signal?", &obj.Spec.StopSignal,
+		"termination_grace_period?", &terminationGracePeriod,
 	)
 	if err != nil {
 		return nil, err
@@ -211,6 +230,9 @@ func (p Plugin) cmd(t *starlark.Thread, fn *starlark.Builtin, args starlark.Tupl
 	if disableSource.isUnpacked {
 		obj.Spec.DisableSource = (*v1alpha1.DisableSource)(&disableSource.Value)
 	}
+	obj.Spec.RestartPolicy = v1alpha1.CmdRestartPolicy(restartPolicy)
+	obj.Spec.MaxRestarts = int32(maxRestarts)
+	obj.Spec.TerminationGracePeriod = metav1.Duration{Duration: time.Duration(terminationGracePeriod)}
 	obj.ObjectMeta.Labels = labels
 	obj.ObjectMeta.Annotations = annotations
 	return p.register(t, obj)
@@ -240,6 +262,58 @@ func (p Plugin) configMap(t *starlark.Thread, fn *starlark.Builtin, args starlar
 	return p.register(t, obj)
 }
 
+func (p Plugin) dockerImage(t *starlark.Thread, fn *starlark.Builtin, args starlark.Tuple, kwargs []starlark.Tuple) (starlark.Value, error) {
+	var err error
+	obj := &v1alpha1.DockerImage{
+		ObjectMeta: metav1.ObjectMeta{},
+		Spec:       v1alpha1.DockerImageSpec{},
+	}
+	var context value.LocalPath = value.NewLocalPathUnpacker(t)
+	err = context.Unpack(starlark.String(""))
+	if err != nil {
+		return nil, err
+	}
+
+	var specArgs value.StringList
+	var sshAgentConfigs value.StringList
+	var secrets value.StringList
+	var cacheFrom value.StringList
+	var extraTags value.StringList
+	var labels value.StringStringMap
+	var annotations value.StringStringMap
+	err = starkit.UnpackArgs(t, fn.Name(), args, kwargs,
+		"name", &obj.ObjectMeta.Name,
+		"labels?", &labels,
+		"annotations?", &annotations,
+		"ref?", &obj.Spec.Ref,
+		"dockerfile_contents?", &obj.Spec.DockerfileContents,
+		"context?", &context,
+		"args?", &specArgs,
+		"target?", &obj.Spec.Target,
+		"ssh_agent_configs?", &sshAgentConfigs,
+		"secrets?", &secrets,
+		"network?", &obj.Spec.Network,
+		"pull?", &obj.Spec.Pull,
+		"cache_from?", &cacheFrom,
+		"platform?", &obj.Spec.Platform,
+		"extra_tags?", &extraTags,
+		"image_map?", &obj.Spec.ImageMap,
+	)
+	if err != nil {
+		return nil, err
+	}
+
+	obj.Spec.Context = context.Value
+	obj.Spec.Args = specArgs
+	obj.Spec.SSHAgentConfigs = sshAgentConfigs
+	obj.Spec.Secrets = secrets
+	obj.Spec.CacheFrom = cacheFrom
+	obj.Spec.ExtraTags = extraTags
+	obj.ObjectMeta.Labels = labels
+	obj.ObjectMeta.Annotations = annotations
+	return p.register(t, obj)
+}
+
 func (p Plugin) extension(t *starlark.Thread, fn *starlark.Builtin, args starlark.Tuple, kwargs []starlark.Tuple) (starlark.Value, error) {
 	var err error
 	obj := &v1alpha1.Extension{
@@ -341,6 +415,9 @@ func (p Plugin) kubernetesApply(t *starlark.Thread, fn *starlark.Builtin, args s
 	var applyCmd KubernetesApplyCmd = KubernetesApplyCmd{t: t}
 	var restartOn RestartOnSpec = RestartOnSpec{t: t}
 	var deleteCmd KubernetesApplyCmd = KubernetesApplyCmd{t: t}
+	var applyMode string
+	var driftDetection KubernetesDriftDetection = KubernetesDriftDetection{t: t}
+	var helmRelease KubernetesHelmRelease = KubernetesHelmRelease{t: t}
 	var labels value.StringStringMap
 	var annotations value.StringStringMap
 	err = starkit.UnpackArgs(t, fn.Name(), args, kwargs,
@@ -359,6 +436,9 @@ func (p Plugin) kubernetesApply(t *starlark.Thread, fn *starlark.Builtin, args s
 		"apply_cmd?", &applyCmd,
 		"restart_on?", &restartOn,
 		"delete_cmd?", &deleteCmd,
+		"apply_mode?", &applyMode,
+		"drift_detection?", &driftDetection,
+		"helm_release?", &helmRelease,
 	)
 	if err != nil {
 		return nil, err
@@ -389,6 +469,13 @@ func (p Plugin) kubernetesApply(t *starlark.Thread, fn *starlark.Builtin, args s
 	if deleteCmd.isUnpacked {
 		obj.Spec.DeleteCmd = (*v1alpha1.KubernetesApplyCmd)(&deleteCmd.Value)
 	}
+	obj.Spec.ApplyMode = v1alpha1.KubernetesApplyMode(applyMode)
+	if driftDetection.isUnpacked {
+		obj.Spec.DriftDetection = (*v1alpha1.KubernetesDriftDetection)(&driftDetection.Value)
+	}
+	if helmRelease.isUnpacked {
+		obj.Spec.HelmRelease = (*v1alpha1.KubernetesHelmRelease)(&helmRelease.Value)
+	}
 	obj.ObjectMeta.Labels = labels
 	obj.ObjectMeta.Annotations = annotations
 	return p.register(t, obj)
@@ -1757,6 +1844,281 @@ func (o *KubernetesDiscoveryTemplateSpecList) Unpack(v starlark.Value) error {
 	return nil
 }
 
+type KubernetesDriftDetection struct {
+	*starlark.Dict
+	Value      v1alpha1.KubernetesDriftDetection
+	isUnpacked bool
+	t          *starlark.Thread // instantiation thread for computing abspath
+}
+
+func (p Plugin) kubernetesDriftDetection(t *starlark.Thread, fn *starlark.Builtin, args starlark.Tuple, kwargs []starlark.Tuple) (starlark.Value, error) {
+	var reapply starlark.Value
+	err := starkit.UnpackArgs(t, fn.Name(), args, kwargs,
+		"reapply?", &reapply,
+	)
+	if err != nil {
+		return nil, err
+	}
+
+	dict := starlark.NewDict(1)
+
+	if reapply != nil {
+		err := dict.SetKey(starlark.String("reapply"), reapply)
+		if err != nil {
+			return nil, err
+		}
+	}
+	var obj *KubernetesDriftDetection = &KubernetesDriftDetection{t: t}
+	err = obj.Unpack(dict)
+	if err != nil {
+		return nil, err
+	}
+	return obj, nil
+}
+
+func (o *KubernetesDriftDetection) Unpack(v starlark.Value) error {
+	obj := v1alpha1.KubernetesDriftDetection{}
+
+	starlarkObj, ok := v.(*KubernetesDriftDetection)
+	if ok {
+		*o = *starlarkObj
+		return nil
+	}
+
+	mapObj, ok := v.(*starlark.Dict)
+	if !ok {
+		return fmt.Errorf("expected dict, actual: %v", v.Type())
+	}
+
+	for _, item := range mapObj.Items() {
+		keyV, val := item[0], item[1]
+		key, ok := starlark.AsString(keyV)
+		if !ok {
+			return fmt.Errorf("key must be string. Got: %s", keyV.Type())
+		}
+
+		if key == "reapply" {
+			v, ok := val.(starlark.Bool)
+			if !ok {
+				return fmt.Errorf("Expected bool, got: %v", val.Type())
+			}
+			obj.Reapply = bool(v)
+			continue
+		}
+		return fmt.Errorf("Unexpected attribute name: %s", key)
+	}
+
+	mapObj.Freeze()
+	o.Dict = mapObj
+	o.Value = obj
+	o.isUnpacked = true
+
+	return nil
+}
+
+type KubernetesDriftDetectionList struct {
+	*starlark.List
+	Value []v1alpha1.KubernetesDriftDetection
+	t     *starlark.Thread
+}
+
+func (o *KubernetesDriftDetectionList) Unpack(v starlark.Value) error {
+	items := []v1alpha1.KubernetesDriftDetection{}
+
+	listObj, ok := v.(*starlark.List)
+	if !ok {
+		return fmt.Errorf("expected list, actual: %v", v.Type())
+	}
+
+	for i := 0; i < listObj.Len(); i++ {
+		v := listObj.Index(i)
+
+		item := KubernetesDriftDetection{t: o.t}
+		err := item.Unpack(v)
+		if err != nil {
+			return fmt.Errorf("at index %d: %v", i, err)
+		}
+		items = append(items, v1alpha1.KubernetesDriftDetection(item.Value))
+	}
+
+	listObj.Freeze()
+	o.List = listObj
+	o.Value = items
+
+	return nil
+}
+
+type KubernetesHelmRelease struct {
+	*starlark.Dict
+	Value      v1alpha1.KubernetesHelmRelease
+	isUnpacked bool
+	t          *starlark.Thread // instantiation thread for computing abspath
+}
+
+func (p Plugin) kubernetesHelmRelease(t *starlark.Thread, fn *starlark.Builtin, args starlark.Tuple, kwargs []starlark.Tuple) (starlark.Value, error) {
+	var releaseName starlark.Value
+	var chart starlark.Value
+	var namespace starlark.Value
+	var valueFiles starlark.Value
+	var set starlark.Value
+	err := starkit.UnpackArgs(t, fn.Name(), args, kwargs,
+		"release_name?", &releaseName,
+		"chart?", &chart,
+		"namespace?", &namespace,
+		"value_files?", &valueFiles,
+		"set?", &set,
+	)
+	if err != nil {
+		return nil, err
+	}
+
+	dict := starlark.NewDict(5)
+
+	if releaseName != nil {
+		err := dict.SetKey(starlark.String("release_name"), releaseName)
+		if err != nil {
+			return nil, err
+		}
+	}
+	if chart != nil {
+		err := dict.SetKey(starlark.String("chart"), chart)
+		if err != nil {
+			return nil, err
+		}
+	}
+	if namespace != nil {
+		err := dict.SetKey(starlark.String("namespace"), namespace)
+		if err != nil {
+			return nil, err
+		}
+	}
+	if valueFiles != nil {
+		err := dict.SetKey(starlark.String("value_files"), valueFiles)
+		if err != nil {
+			return nil, err
+		}
+	}
+	if set != nil {
+		err := dict.SetKey(starlark.String("set"), set)
+		if err != nil {
+			return nil, err
+		}
+	}
+	var obj *KubernetesHelmRelease = &KubernetesHelmRelease{t: t}
+	err = obj.Unpack(dict)
+	if err != nil {
+		return nil, err
+	}
+	return obj, nil
+}
+
+func (o *KubernetesHelmRelease) Unpack(v starlark.Value) error {
+	obj := v1alpha1.KubernetesHelmRelease{}
+
+	starlarkObj, ok := v.(*KubernetesHelmRelease)
+	if ok {
+		*o = *starlarkObj
+		return nil
+	}
+
+	mapObj, ok := v.(*starlark.Dict)
+	if !ok {
+		return fmt.Errorf("expected dict, actual: %v", v.Type())
+	}
+
+	for _, item := range mapObj.Items() {
+		keyV, val := item[0], item[1]
+		key, ok := starlark.AsString(keyV)
+		if !ok {
+			return fmt.Errorf("key must be string. Got: %s", keyV.Type())
+		}
+
+		if key == "release_name" {
+			v, ok := starlark.AsString(val)
+			if !ok {
+				return fmt.Errorf("Expected string, actual: %s", val.Type())
+			}
+			obj.ReleaseName = string(v)
+			continue
+		}
+		if key == "chart" {
+			v := value.NewLocalPathUnpacker(o.t)
+			err := v.Unpack(val)
+			if err != nil {
+				return fmt.Errorf("unpacking %s: %v", key, err)
+			}
+			obj.Chart = v.Value
+			continue
+		}
+		if key == "namespace" {
+			v, ok := starlark.AsString(val)
+			if !ok {
+				return fmt.Errorf("Expected string, actual: %s", val.Type())
+			}
+			obj.Namespace = string(v)
+			continue
+		}
+		if key == "value_files" {
+			v := value.NewLocalPathListUnpacker(o.t)
+			err := v.Unpack(val)
+			if err != nil {
+				return fmt.Errorf("unpacking %s: %v", key, err)
+			}
+			obj.ValueFiles = v.Value
+			continue
+		}
+		if key == "set" {
+			var v value.StringList
+			err := v.Unpack(val)
+			if err != nil {
+				return fmt.Errorf("unpacking %s: %v", key, err)
+			}
+			obj.Set = v
+			continue
+		}
+		return fmt.Errorf("Unexpected attribute name: %s", key)
+	}
+
+	mapObj.Freeze()
+	o.Dict = mapObj
+	o.Value = obj
+	o.isUnpacked = true
+
+	return nil
+}
+
+type KubernetesHelmReleaseList struct {
+	*starlark.List
+	Value []v1alpha1.KubernetesHelmRelease
+	t     *starlark.Thread
+}
+
+func (o *KubernetesHelmReleaseList) Unpack(v starlark.Value) error {
+	items := []v1alpha1.KubernetesHelmRelease{}
+
+	listObj, ok := v.(*starlark.List)
+	if !ok {
+		return fmt.Errorf("expected list, actual: %v", v.Type())
+	}
+
+	for i := 0; i < listObj.Len(); i++ {
+		v := listObj.Index(i)
+
+		item := KubernetesHelmRelease{t: o.t}
+		err := item.Unpack(v)
+		if err != nil {
+			return fmt.Errorf("at index %d: %v", i, err)
+		}
+		items = append(items, v1alpha1.KubernetesHelmRelease(item.Value))
+	}
+
+	listObj.Freeze()
+	o.List = listObj
+	o.Value = items
+
+	return nil
+}
+
 type KubernetesImageLocator struct {
 	*starlark.Dict
 	Value      v1alpha1.KubernetesImageLocator
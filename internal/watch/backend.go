@@ -0,0 +1,253 @@
+package watch
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"github.com/pkg/errors"
+
+	"github.com/tilt-dev/tilt/internal/ospath"
+	"github.com/tilt-dev/tilt/pkg/logger"
+)
+
+// A strategy for watching the filesystem.
+type Backend string
+
+const (
+	// Use the native backend, but fall back to another backend
+	// if the native backend hits OS watch limits.
+	BackendAuto Backend = "auto"
+
+	// inotify on Linux, FSEvents on macOS, ReadDirectoryChangesW on Windows.
+	BackendNative Backend = "native"
+
+	// One fanotify mark for each watched filesystem, instead of one inotify
+	// watch for each directory. Linux only, and requires root.
+	BackendFanotify Backend = "fanotify"
+
+	// Subscribes to changes from a watchman server.
+	BackendWatchman Backend = "watchman"
+
+	// Periodically walks the watched paths and compares a hash of each file's stat.
+	BackendPoll Backend = "poll"
+)
+
+// If the native backend hits OS watch limits, we try these in order.
+var fallbackBackends = []Backend{BackendWatchman, BackendFanotify, BackendPoll}
+
+const BackendEnvVar = "TILT_WATCH_BACKEND"
+
+const PollIntervalEnvVar = "TILT_WATCH_POLL_INTERVAL"
+
+const defaultPollInterval = time.Second
+
+func DesiredBackend() (Backend, error) {
+	envVar := strings.ToLower(os.Getenv(BackendEnvVar))
+	switch Backend(envVar) {
+	case "":
+		return BackendAuto, nil
+	case BackendAuto, BackendNative, BackendFanotify, BackendWatchman, BackendPoll:
+		return Backend(envVar), nil
+	}
+	return "", fmt.Errorf("Invalid %s %q. Must be one of: %s, %s, %s, %s, %s",
+		BackendEnvVar, envVar, BackendAuto, BackendNative, BackendFanotify, BackendWatchman, BackendPoll)
+}
+
+func DesiredPollInterval() time.Duration {
+	envVar := os.Getenv(PollIntervalEnvVar)
+	if envVar != "" {
+		interval, err := time.ParseDuration(envVar)
+		if err == nil && interval > 0 {
+			return interval
+		}
+	}
+	return defaultPollInterval
+}
+
+func newBackendWatcher(backend Backend, paths []string, ignore PathMatcher, l logger.Logger) (Notify, error) {
+	switch backend {
+	case BackendNative:
+		return newNativeWatcher(paths, ignore, l)
+	case BackendFanotify:
+		return newFanotifyWatcher(paths, ignore, l)
+	case BackendWatchman:
+		return newWatchmanWatcher(paths, ignore, l)
+	case BackendPoll:
+		return newPollWatcher(paths, ignore, l, DesiredPollInterval())
+	default:
+		return newAutoWatcher(paths, ignore, l)
+	}
+}
+
+// Returns an interface, so that a nil watcher is a nil interface.
+var newNativeWatcher = func(paths []string, ignore PathMatcher, l logger.Logger) (Notify, error) {
+	w, err := newWatcher(paths, ignore, l)
+	if err != nil {
+		return nil, err
+	}
+	return w, nil
+}
+
+// A watcher that starts with the native backend.
+//
+// If the native backend runs out of OS watches, we'd rather switch to a
+// backend that scales than silently miss changes.
+type autoNotify struct {
+	Notify
+
+	paths  []string
+	ignore PathMatcher
+	log    logger.Logger
+}
+
+func newAutoWatcher(paths []string, ignore PathMatcher, l logger.Logger) (*autoNotify, error) {
+	native, err := newNativeWatcher(paths, ignore, l)
+	if err != nil {
+		return nil, err
+	}
+	return &autoNotify{
+		Notify: native,
+		paths:  paths,
+		ignore: ignore,
+		log:    l,
+	}, nil
+}
+
+func (d *autoNotify) Start() error {
+	err := d.Notify.Start()
+	if err == nil || !isWatchLimitError(err) {
+		return err
+	}
+
+	// Count the watches before we close the native watcher and free them.
+	used := numberOfWatches.Value()
+	_ = d.Notify.Close()
+
+	for _, backend := range fallbackBackends {
+		fallback, fallbackErr := newBackendWatcher(backend, d.paths, d.ignore, d.log)
+		if fallbackErr == nil {
+			fallbackErr = fallback.Start()
+			if fallbackErr != nil {
+				_ = fallback.Close()
+			}
+		}
+		if fallbackErr != nil {
+			d.log.Debugf("Not using %s file watcher: %v", backend, fallbackErr)
+			continue
+		}
+
+		msg := fmt.Sprintf("%s\nSwitched to the %s file watcher.", watchLimitMessage(used), backend)
+		if backend == BackendPoll {
+			msg += fmt.Sprintf(" It checks for changes every %s; to change how often, set %s (e.g., %s=5s).",
+				DesiredPollInterval(), PollIntervalEnvVar, PollIntervalEnvVar)
+		}
+		d.log.Warnf("%s", msg)
+		d.Notify = fallback
+		return nil
+	}
+	return err
+}
+
+func watchLimitMessage(used int64) string {
+	msg := fmt.Sprintf("Hit the OS limit on file watches while using %d watches.", used)
+	limit, ok := watchLimit()
+	if ok {
+		msg = fmt.Sprintf("Hit the OS limit on file watches while using %d of %d watches "+
+			"(the limit is shared by all processes for this user).", used, limit)
+	}
+	return msg + fmt.Sprintf("\nTo raise the limit, run 'sudo sysctl fs.inotify.max_user_watches=<limit>'."+
+		"\nTo choose a file watcher, set %s to one of: %s, %s, %s, %s.",
+		BackendEnvVar, BackendNative, BackendFanotify, BackendWatchman, BackendPoll)
+}
+
+// Decides which changed paths to pass up to the caller,
+// for backends that see more of the filesystem than the caller asked for.
+type notifyFilter struct {
+	// Paths that we're watching that should be passed up to the caller.
+	notifyList map[string]bool
+
+	ignore PathMatcher
+	log    logger.Logger
+}
+
+func newNotifyFilter(paths []string, ignore PathMatcher, l logger.Logger) (notifyFilter, error) {
+	if ignore == nil {
+		return notifyFilter{}, fmt.Errorf("newWatcher: ignore is nil")
+	}
+
+	notifyList := make(map[string]bool, len(paths))
+	for _, path := range paths {
+		path, err := filepath.Abs(path)
+		if err != nil {
+			return notifyFilter{}, errors.Wrap(err, "newWatcher")
+		}
+		notifyList[path] = true
+	}
+	return notifyFilter{notifyList: notifyList, ignore: ignore, log: l}, nil
+}
+
+// The watched paths, minus any paths inside other watched paths.
+func (f notifyFilter) paths() []string {
+	result := make([]string, 0, len(f.notifyList))
+	for path := range f.notifyList {
+		result = append(result, path)
+	}
+	return dedupePathsForRecursiveWatcher(result)
+}
+
+// The directories to watch to see changes to all the watched paths,
+// including paths that don't exist yet.
+func (f notifyFilter) dirsToWatch() ([]string, error) {
+	ancestors, err := greatestExistingAncestors(f.paths())
+	if err != nil {
+		return nil, err
+	}
+
+	result := make([]string, 0, len(ancestors))
+	for _, path := range ancestors {
+		if !ospath.IsDir(path) {
+			path = filepath.Dir(path)
+		}
+		result = append(result, path)
+	}
+	return dedupePathsForRecursiveWatcher(result), nil
+}
+
+func (f notifyFilter) shouldNotify(path string) bool {
+	ignore, err := f.ignore.Matches(path)
+	if err != nil {
+		f.log.Infof("Error matching path %q: %v", path, err)
+	} else if ignore {
+		return false
+	}
+
+	if _, ok := f.notifyList[path]; ok {
+		// We generally don't care when directories change at the root of an ADD
+		stat, err := os.Lstat(path)
+		isDir := err == nil && stat.IsDir()
+		return !isDir
+	}
+
+	for root := range f.notifyList {
+		if ospath.IsChild(root, path) {
+			return true
+		}
+	}
+	return false
+}
+
+func (f notifyFilter) shouldSkipDir(path string) (bool, error) {
+	// If path is directly in the notifyList, we should always watch it.
+	if f.notifyList[path] {
+		return false, nil
+	}
+
+	skip, err := f.ignore.MatchesEntireDir(path)
+	if err != nil {
+		return false, errors.Wrap(err, "shouldSkipDir")
+	}
+	return skip, nil
+}
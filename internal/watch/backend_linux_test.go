@@ -0,0 +1,106 @@
+// +build linux
+
+package watch
+
+import (
+	"bytes"
+	"fmt"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"golang.org/x/sys/unix"
+
+	"github.com/tilt-dev/tilt/pkg/logger"
+)
+
+func TestAutoFallsBackWhenOutOfWatches(t *testing.T) {
+	origNative, origFallbacks := newNativeWatcher, fallbackBackends
+	defer func() { newNativeWatcher, fallbackBackends = origNative, origFallbacks }()
+
+	newNativeWatcher = func(paths []string, ignore PathMatcher, l logger.Logger) (Notify, error) {
+		return &outOfWatchesNotify{}, nil
+	}
+	fallbackBackends = []Backend{BackendWatchman, BackendPoll}
+
+	// Make sure watchman isn't available, so that we fall back to polling.
+	t.Setenv(watchmanSockEnvVar, filepath.Join(t.TempDir(), "does-not-exist.sock"))
+	t.Setenv(BackendEnvVar, string(BackendAuto))
+	t.Setenv(PollIntervalEnvVar, "10ms")
+
+	f := newNotifyFixture(t)
+	defer f.tearDown()
+
+	numberOfWatches.Set(12)
+	f.out.Reset()
+
+	root := f.TempDir("root")
+	f.watch(root)
+	assert.Equal(t, BackendPoll, f.notify.Backend())
+
+	out := f.out.String()
+	assert.Contains(t, out, "Hit the OS limit on file watches while using 12")
+	assert.Contains(t, out, "sysctl fs.inotify.max_user_watches")
+	assert.Contains(t, out, "Switched to the poll file watcher.")
+	assert.Contains(t, out, "It checks for changes every 10ms; to change how often, set TILT_WATCH_POLL_INTERVAL")
+
+	file := filepath.Join(root, "file.txt")
+	f.WriteFile(file, "hello")
+	f.assertEvents(file)
+}
+
+func TestAutoDoesNotFallBackOnOtherErrors(t *testing.T) {
+	origNative := newNativeWatcher
+	defer func() { newNativeWatcher = origNative }()
+
+	newNativeWatcher = func(paths []string, ignore PathMatcher, l logger.Logger) (Notify, error) {
+		return &outOfWatchesNotify{err: fmt.Errorf("permission denied")}, nil
+	}
+
+	notify, err := newAutoWatcher([]string{t.TempDir()}, EmptyMatcher{}, logger.NewTestLogger(&bytes.Buffer{}))
+	assert.NoError(t, err)
+	err = notify.Start()
+	if assert.Error(t, err) {
+		assert.Equal(t, "permission denied", err.Error())
+	}
+	assert.Equal(t, BackendNative, notify.Backend())
+}
+
+func TestFanotify(t *testing.T) {
+	probe, err := newFanotifyWatcher([]string{t.TempDir()}, EmptyMatcher{}, logger.NewTestLogger(&bytes.Buffer{}))
+	assert.NoError(t, err)
+	err = probe.Start()
+	_ = probe.Close()
+	if err != nil {
+		t.Skipf("fanotify is not available: %v", err)
+	}
+
+	t.Setenv(BackendEnvVar, string(BackendFanotify))
+	f := newNotifyFixture(t)
+	defer f.tearDown()
+	assert.Equal(t, BackendFanotify, f.notify.Backend())
+
+	root := f.TempDir("root")
+	f.watch(root)
+
+	file := filepath.Join(root, "a", "file.txt")
+	f.WriteFile(file, "hello")
+	f.assertEvents(filepath.Join(root, "a"), file)
+}
+
+// A native watcher that fails to start, like inotify does when the user is out of watches.
+type outOfWatchesNotify struct {
+	err error
+}
+
+func (n *outOfWatchesNotify) Start() error {
+	if n.err != nil {
+		return n.err
+	}
+	return fmt.Errorf("notify.Add(%q): %w", "/some/dir", unix.ENOSPC)
+}
+
+func (n *outOfWatchesNotify) Close() error           { return nil }
+func (n *outOfWatchesNotify) Events() chan FileEvent { return nil }
+func (n *outOfWatchesNotify) Errors() chan error     { return nil }
+func (n *outOfWatchesNotify) Backend() Backend       { return BackendNative }
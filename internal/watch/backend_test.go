@@ -0,0 +1,266 @@
+package watch
+
+import (
+	"bufio"
+	"encoding/json"
+	"net"
+	"os"
+	"path/filepath"
+	"runtime"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/tilt-dev/tilt/internal/dockerignore"
+	"github.com/tilt-dev/tilt/pkg/logger"
+)
+
+func TestDesiredBackend(t *testing.T) {
+	for _, tc := range []struct {
+		envVar   string
+		expected Backend
+	}{
+		{"", BackendAuto},
+		{"auto", BackendAuto},
+		{"native", BackendNative},
+		{"Fanotify", BackendFanotify},
+		{"watchman", BackendWatchman},
+		{"POLL", BackendPoll},
+	} {
+		t.Run(tc.envVar, func(t *testing.T) {
+			t.Setenv(BackendEnvVar, tc.envVar)
+			backend, err := DesiredBackend()
+			require.NoError(t, err)
+			assert.Equal(t, tc.expected, backend)
+		})
+	}
+
+	t.Setenv(BackendEnvVar, "inotify")
+	_, err := DesiredBackend()
+	if assert.Error(t, err) {
+		assert.Contains(t, err.Error(), `Invalid TILT_WATCH_BACKEND "inotify"`)
+	}
+}
+
+func TestDesiredPollInterval(t *testing.T) {
+	t.Setenv(PollIntervalEnvVar, "")
+	assert.Equal(t, defaultPollInterval, DesiredPollInterval())
+
+	t.Setenv(PollIntervalEnvVar, "a")
+	assert.Equal(t, defaultPollInterval, DesiredPollInterval())
+
+	t.Setenv(PollIntervalEnvVar, "-1s")
+	assert.Equal(t, defaultPollInterval, DesiredPollInterval())
+
+	t.Setenv(PollIntervalEnvVar, "250ms")
+	assert.Equal(t, 250*time.Millisecond, DesiredPollInterval())
+}
+
+func TestPollCreateModifyDelete(t *testing.T) {
+	f := newPollFixture(t)
+	defer f.tearDown()
+
+	root := f.TempDir("root")
+	f.watch(root)
+	assert.Equal(t, BackendPoll, f.notify.Backend())
+
+	file := filepath.Join(root, "a", "file.txt")
+	f.WriteFile(file, "hello")
+	f.assertEvents(file)
+
+	f.events = nil
+	f.WriteFile(file, "hello world")
+	f.assertEvents(file)
+
+	f.events = nil
+	require.NoError(t, os.Remove(file))
+	f.assertEvents(file)
+}
+
+func TestPollIgnore(t *testing.T) {
+	f := newPollFixture(t)
+	defer f.tearDown()
+
+	root := f.TempDir("root")
+	ignore, _ := dockerignore.NewDockerPatternMatcher(root, []string{"a/b"})
+	f.setIgnore(ignore)
+	f.watch(root)
+
+	f.WriteFile(filepath.Join(root, "a", "b", "ignored.txt"), "hello")
+	notIgnored := filepath.Join(root, "a", "c.txt")
+	f.WriteFile(notIgnored, "hello")
+	f.assertEvents(notIgnored)
+}
+
+func TestPollNonExistentPath(t *testing.T) {
+	f := newPollFixture(t)
+	defer f.tearDown()
+
+	root := f.JoinPath("root")
+	f.watch(root)
+
+	file := filepath.Join(root, "file.txt")
+	f.WriteFile(file, "hello")
+	f.assertEvents(file)
+}
+
+func newPollFixture(t *testing.T) *notifyFixture {
+	t.Setenv(BackendEnvVar, string(BackendPoll))
+	t.Setenv(PollIntervalEnvVar, "10ms")
+	return newNotifyFixture(t)
+}
+
+func TestWatchmanEvents(t *testing.T) {
+	if runtime.GOOS == "windows" {
+		t.Skip("The fake watchman server uses unix sockets")
+	}
+
+	f := newNotifyFixture(t)
+	defer f.tearDown()
+
+	root := f.TempDir("root")
+	sub := filepath.Join(root, "sub")
+	f.MkdirAll(sub)
+
+	server := newFakeWatchmanServer(t, root)
+	t.Setenv(watchmanSockEnvVar, server.sockname)
+	t.Setenv(BackendEnvVar, string(BackendWatchman))
+
+	ignore, _ := dockerignore.NewDockerPatternMatcher(sub, []string{"ignored.txt"})
+
+	notify, err := NewWatcher([]string{sub}, ignore, logger.NewTestLogger(f.out))
+	require.NoError(t, err)
+	require.NoError(t, notify.Start())
+	defer func() { _ = notify.Close() }()
+	assert.Equal(t, BackendWatchman, notify.Backend())
+
+	// watchman watches the whole project, and we subscribe to the relative path we care about.
+	assert.Equal(t, []string{"watch-project", sub}, server.nextCommand())
+	subscribe := server.nextCommand()
+	assert.Equal(t, "subscribe", subscribe[0])
+	assert.Equal(t, root, subscribe[1])
+
+	server.send(t, map[string]interface{}{
+		"subscription": subscribe[2],
+		"root":         root,
+		"files":        []string{"ignored.txt", "a.txt", "b/c.txt"},
+	})
+
+	var events []string
+	timeout := time.After(time.Second)
+	for len(events) < 2 {
+		select {
+		case e := <-notify.Events():
+			events = append(events, e.Path())
+		case err := <-notify.Errors():
+			t.Fatal(err)
+		case <-timeout:
+			t.Fatalf("Timed out waiting for events. Got: %v", events)
+		}
+	}
+	assert.Equal(t, []string{filepath.Join(sub, "a.txt"), filepath.Join(sub, "b", "c.txt")}, events)
+}
+
+func TestWatchmanNotRunning(t *testing.T) {
+	f := newNotifyFixture(t)
+	defer f.tearDown()
+
+	t.Setenv(watchmanSockEnvVar, f.JoinPath("does-not-exist.sock"))
+	notify, err := newWatchmanWatcher([]string{f.TempDir("root")}, EmptyMatcher{}, logger.NewTestLogger(f.out))
+	require.NoError(t, err)
+
+	err = notify.Start()
+	if assert.Error(t, err) {
+		assert.Contains(t, err.Error(), "connecting to watchman")
+	}
+	require.NoError(t, notify.Close())
+}
+
+// A watchman server that accepts one client,
+// and pretends that root is the root of the project.
+type fakeWatchmanServer struct {
+	sockname string
+	root     string
+	conn     chan net.Conn
+	commands chan []string
+}
+
+func newFakeWatchmanServer(t *testing.T, root string) *fakeWatchmanServer {
+	// Unix socket paths have a short max length, so we don't put them in the test's temp dir.
+	dir, err := os.MkdirTemp("", "watchman")
+	require.NoError(t, err)
+	t.Cleanup(func() { _ = os.RemoveAll(dir) })
+
+	sockname := filepath.Join(dir, "sock")
+	listener, err := net.Listen("unix", sockname)
+	require.NoError(t, err)
+	t.Cleanup(func() { _ = listener.Close() })
+
+	s := &fakeWatchmanServer{
+		sockname: sockname,
+		root:     root,
+		conn:     make(chan net.Conn, 1),
+		commands: make(chan []string, 10),
+	}
+	go s.serve(listener)
+	return s
+}
+
+func (s *fakeWatchmanServer) serve(listener net.Listener) {
+	conn, err := listener.Accept()
+	if err != nil {
+		return
+	}
+	s.conn <- conn
+
+	scanner := bufio.NewScanner(conn)
+	for scanner.Scan() {
+		var cmd []interface{}
+		err := json.Unmarshal(scanner.Bytes(), &cmd)
+		if err != nil {
+			return
+		}
+
+		var strs []string
+		for _, arg := range cmd {
+			if str, ok := arg.(string); ok {
+				strs = append(strs, str)
+			}
+		}
+		s.commands <- strs
+
+		var resp map[string]interface{}
+		switch strs[0] {
+		case "watch-project":
+			rel, _ := filepath.Rel(s.root, strs[1])
+			resp = map[string]interface{}{"watch": s.root, "relative_path": rel}
+		case "subscribe":
+			resp = map[string]interface{}{"subscribe": strs[2]}
+		default:
+			resp = map[string]interface{}{"error": "unknown command " + strs[0]}
+		}
+		b, _ := json.Marshal(resp)
+		_, _ = conn.Write(append(b, '\n'))
+	}
+}
+
+func (s *fakeWatchmanServer) nextCommand() []string {
+	select {
+	case cmd := <-s.commands:
+		return cmd
+	case <-time.After(time.Second):
+		return nil
+	}
+}
+
+func (s *fakeWatchmanServer) send(t *testing.T, pdu map[string]interface{}) {
+	conn := <-s.conn
+	s.conn <- conn
+
+	b, err := json.Marshal(pdu)
+	require.NoError(t, err)
+	_, err = conn.Write(append(b, '\n'))
+	require.NoError(t, err)
+}
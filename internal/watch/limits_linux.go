@@ -0,0 +1,32 @@
+// +build linux
+
+package watch
+
+import (
+	"errors"
+	"io/ioutil"
+	"strconv"
+	"strings"
+
+	"golang.org/x/sys/unix"
+)
+
+const maxUserWatchesPath = "/proc/sys/fs/inotify/max_user_watches"
+
+// inotify_add_watch returns ENOSPC when the user is out of watches.
+func isWatchLimitError(err error) bool {
+	return err != nil && errors.Is(err, unix.ENOSPC)
+}
+
+// The max number of inotify watches for this user.
+func watchLimit() (int, bool) {
+	contents, err := ioutil.ReadFile(maxUserWatchesPath)
+	if err != nil {
+		return 0, false
+	}
+	limit, err := strconv.Atoi(strings.TrimSpace(string(contents)))
+	if err != nil {
+		return 0, false
+	}
+	return limit, true
+}
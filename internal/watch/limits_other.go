@@ -0,0 +1,13 @@
+// +build !linux
+
+package watch
+
+// FSEvents and ReadDirectoryChangesW watch recursively,
+// so we don't expect to run out of watches.
+func isWatchLimitError(err error) bool {
+	return false
+}
+
+func watchLimit() (int, bool) {
+	return 0, false
+}
@@ -43,6 +43,12 @@ type Notify interface {
 
 	// A channel to read off show-stopping errors
 	Errors() chan error
+
+	// The backend that watches the filesystem.
+	//
+	// Only meaningful after Start(), because some watchers pick a
+	// different backend if they hit OS limits while starting.
+	Backend() Backend
 }
 
 // When we specify directories to watch, we often want to
@@ -69,7 +75,11 @@ func (EmptyMatcher) MatchesEntireDir(f string) (bool, error) { return false, nil
 var _ PathMatcher = EmptyMatcher{}
 
 func NewWatcher(paths []string, ignore PathMatcher, l logger.Logger) (Notify, error) {
-	return newWatcher(paths, ignore, l)
+	backend, err := DesiredBackend()
+	if err != nil {
+		return nil, err
+	}
+	return newBackendWatcher(backend, paths, ignore, l)
 }
 
 const WindowsBufferSizeEnvVar = "TILT_WATCH_WINDOWS_BUFFER_SIZE"
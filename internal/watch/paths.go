@@ -28,6 +28,18 @@ func greatestExistingAncestor(path string) (string, error) {
 	return path, nil
 }
 
+func greatestExistingAncestors(paths []string) ([]string, error) {
+	result := []string{}
+	for _, p := range paths {
+		newP, err := greatestExistingAncestor(p)
+		if err != nil {
+			return nil, fmt.Errorf("Finding ancestor of %s: %v", p, err)
+		}
+		result = append(result, newP)
+	}
+	return result, nil
+}
+
 // If we're recursively watching a path, it doesn't
 // make sense to watch any of its descendants.
 func dedupePathsForRecursiveWatcher(paths []string) []string {
@@ -111,6 +111,10 @@ func (d *darwinNotify) Errors() chan error {
 	return d.errors
 }
 
+func (d *darwinNotify) Backend() Backend {
+	return BackendNative
+}
+
 func newWatcher(paths []string, ignore PathMatcher, l logger.Logger) (*darwinNotify, error) {
 	dw := &darwinNotify{
 		ignore: ignore,
@@ -0,0 +1,318 @@
+// +build linux
+
+package watch
+
+import (
+	"bytes"
+	"encoding/binary"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strconv"
+	"sync"
+	"unsafe"
+
+	"github.com/pkg/errors"
+	"golang.org/x/sys/unix"
+
+	"github.com/tilt-dev/tilt/pkg/logger"
+)
+
+// A file watcher that uses one fanotify mark for each watched filesystem.
+//
+// inotify needs one watch for each directory, so large trees can run out
+// of watches. fanotify can watch an entire filesystem with a single mark,
+// and reports the directory and name of each change. The tradeoff is that we
+// see every change on the filesystem, and have to filter them down ourselves.
+//
+// Requires Linux 5.9+ (for FAN_REPORT_DFID_NAME) and CAP_SYS_ADMIN.
+type fanotifyNotify struct {
+	filter notifyFilter
+
+	file *os.File
+
+	// Filesystem ID -> an open directory on that filesystem,
+	// which we need to resolve file handles into paths.
+	mountFds map[unix.Fsid]int
+
+	events chan FileEvent
+	errors chan error
+	stop   chan struct{}
+
+	mu      sync.Mutex
+	started bool
+	closed  bool
+}
+
+const fanotifyEventMask = unix.FAN_MODIFY | unix.FAN_ATTRIB |
+	unix.FAN_CREATE | unix.FAN_DELETE | unix.FAN_MOVED_FROM | unix.FAN_MOVED_TO |
+	unix.FAN_ONDIR
+
+func newFanotifyWatcher(paths []string, ignore PathMatcher, l logger.Logger) (Notify, error) {
+	filter, err := newNotifyFilter(paths, ignore, l)
+	if err != nil {
+		return nil, err
+	}
+	return &fanotifyNotify{
+		filter:   filter,
+		mountFds: make(map[unix.Fsid]int),
+		events:   make(chan FileEvent),
+		errors:   make(chan error),
+		stop:     make(chan struct{}),
+	}, nil
+}
+
+func (d *fanotifyNotify) Start() error {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	if len(d.filter.notifyList) == 0 || d.closed {
+		return nil
+	}
+
+	dirs, err := d.filter.dirsToWatch()
+	if err != nil {
+		return err
+	}
+
+	fd, err := unix.FanotifyInit(
+		unix.FAN_CLASS_NOTIF|unix.FAN_CLOEXEC|unix.FAN_NONBLOCK|unix.FAN_REPORT_DFID_NAME,
+		unix.O_RDONLY|unix.O_CLOEXEC)
+	if err != nil {
+		return fanotifyError("fanotify_init", err)
+	}
+
+	// Because the fd is non-blocking, the os.File uses the runtime poller,
+	// so closing it unblocks any pending reads.
+	d.file = os.NewFile(uintptr(fd), "fanotify")
+
+	for _, dir := range dirs {
+		err := d.mark(dir)
+		if err != nil {
+			d.closeFds()
+			return err
+		}
+	}
+
+	d.started = true
+	go d.loop()
+	return nil
+}
+
+func (d *fanotifyNotify) mark(dir string) error {
+	var stat unix.Statfs_t
+	err := unix.Statfs(dir, &stat)
+	if err != nil {
+		return errors.Wrapf(err, "statfs(%q)", dir)
+	}
+
+	if _, ok := d.mountFds[stat.Fsid]; ok {
+		// We're already watching this filesystem.
+		return nil
+	}
+
+	mountFd, err := unix.Open(dir, unix.O_RDONLY|unix.O_DIRECTORY|unix.O_CLOEXEC, 0)
+	if err != nil {
+		return errors.Wrapf(err, "open(%q)", dir)
+	}
+	d.mountFds[stat.Fsid] = mountFd
+
+	err = unix.FanotifyMark(int(d.file.Fd()), unix.FAN_MARK_ADD|unix.FAN_MARK_FILESYSTEM,
+		fanotifyEventMask, unix.AT_FDCWD, dir)
+	if err != nil {
+		return fanotifyError(fmt.Sprintf("fanotify_mark(%q)", dir), err)
+	}
+	return nil
+}
+
+func fanotifyError(op string, err error) error {
+	switch {
+	case errors.Is(err, unix.EPERM):
+		return fmt.Errorf("%s: the fanotify file watcher requires root (CAP_SYS_ADMIN): %v", op, err)
+	case errors.Is(err, unix.EINVAL):
+		return fmt.Errorf("%s: the fanotify file watcher requires Linux 5.9+: %v", op, err)
+	}
+	return errors.Wrap(err, op)
+}
+
+func (d *fanotifyNotify) loop() {
+	defer func() {
+		close(d.events)
+		close(d.errors)
+
+		d.mu.Lock()
+		d.closeFds()
+		d.mu.Unlock()
+	}()
+
+	buf := make([]byte, 64*1024)
+	for {
+		n, err := d.file.Read(buf)
+		if err != nil {
+			select {
+			case <-d.stop:
+			case d.errors <- errors.Wrap(err, "reading fanotify events"):
+			}
+			return
+		}
+
+		for _, path := range d.parseEvents(buf[:n]) {
+			if !d.filter.shouldNotify(path) {
+				continue
+			}
+			select {
+			case <-d.stop:
+				return
+			case d.events <- NewFileEvent(path):
+			}
+		}
+	}
+}
+
+const fanotifyMetadataSize = int(unsafe.Sizeof(unix.FanotifyEventMetadata{}))
+
+// The fixed-size part of struct fanotify_event_info_fid:
+// the info header (4 bytes), the fsid (8 bytes),
+// and the file_handle header (8 bytes).
+const fanotifyFidInfoSize = 20
+
+// Parses the paths out of a buffer of fanotify events.
+func (d *fanotifyNotify) parseEvents(buf []byte) []string {
+	var result []string
+	for len(buf) >= fanotifyMetadataSize {
+		meta := (*unix.FanotifyEventMetadata)(unsafe.Pointer(&buf[0]))
+		eventLen := int(meta.Event_len)
+		if eventLen < fanotifyMetadataSize || eventLen > len(buf) {
+			break
+		}
+
+		if meta.Vers != unix.FANOTIFY_METADATA_VERSION {
+			d.filter.log.Debugf("fanotify: unexpected metadata version %d", meta.Vers)
+		} else if meta.Mask&unix.FAN_Q_OVERFLOW != 0 {
+			// Don't block the read loop if no one is listening.
+			select {
+			case d.errors <- fmt.Errorf("fanotify: event queue overflowed, some file changes were lost"):
+			default:
+			}
+		} else {
+			path, ok := d.parseInfo(buf[meta.Metadata_len:eventLen])
+			if ok {
+				result = append(result, path)
+			}
+		}
+
+		if meta.Fd >= 0 {
+			_ = unix.Close(int(meta.Fd))
+		}
+		buf = buf[eventLen:]
+	}
+	return result
+}
+
+// Parses the path out of the info records of a single event.
+func (d *fanotifyNotify) parseInfo(info []byte) (string, bool) {
+	for len(info) >= fanotifyFidInfoSize {
+		infoType := info[0]
+		infoLen := int(binary.LittleEndian.Uint16(info[2:4]))
+		if infoLen < fanotifyFidInfoSize || infoLen > len(info) {
+			return "", false
+		}
+		record := info[:infoLen]
+		info = info[infoLen:]
+
+		if infoType != unix.FAN_EVENT_INFO_TYPE_DFID_NAME {
+			continue
+		}
+
+		var fsid unix.Fsid
+		fsid.Val[0] = int32(binary.LittleEndian.Uint32(record[4:8]))
+		fsid.Val[1] = int32(binary.LittleEndian.Uint32(record[8:12]))
+		handleBytes := int(binary.LittleEndian.Uint32(record[12:16]))
+		handleType := int32(binary.LittleEndian.Uint32(record[16:20]))
+		if fanotifyFidInfoSize+handleBytes > len(record) {
+			return "", false
+		}
+		handle := record[fanotifyFidInfoSize : fanotifyFidInfoSize+handleBytes]
+
+		name := record[fanotifyFidInfoSize+handleBytes:]
+		if i := bytes.IndexByte(name, 0); i >= 0 {
+			name = name[:i]
+		}
+
+		// We see changes to the whole filesystem, so directories that
+		// were deleted before we could resolve them are common, and
+		// not worth logging.
+		dir, err := d.resolveHandle(fsid, handleType, handle)
+		if err != nil {
+			return "", false
+		}
+
+		if len(name) == 0 || string(name) == "." {
+			return dir, true
+		}
+		return filepath.Join(dir, string(name)), true
+	}
+	return "", false
+}
+
+// Converts a directory file handle into a path.
+func (d *fanotifyNotify) resolveHandle(fsid unix.Fsid, handleType int32, handle []byte) (string, error) {
+	mountFd, ok := d.mountFds[fsid]
+	if !ok {
+		return "", fmt.Errorf("unknown filesystem %v", fsid.Val)
+	}
+
+	fd, err := unix.OpenByHandleAt(mountFd, unix.NewFileHandle(handleType, handle), unix.O_PATH|unix.O_CLOEXEC)
+	if err != nil {
+		return "", errors.Wrap(err, "open_by_handle_at")
+	}
+	defer func() { _ = unix.Close(fd) }()
+
+	return os.Readlink(filepath.Join("/proc/self/fd", strconv.Itoa(fd)))
+}
+
+// Expects the caller to hold the lock.
+func (d *fanotifyNotify) closeFds() {
+	for fsid, fd := range d.mountFds {
+		_ = unix.Close(fd)
+		delete(d.mountFds, fsid)
+	}
+	if d.file != nil {
+		_ = d.file.Close()
+		d.file = nil
+	}
+}
+
+func (d *fanotifyNotify) Close() error {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	if d.closed {
+		return nil
+	}
+	d.closed = true
+	close(d.stop)
+
+	if d.started {
+		// Unblocks the loop, which closes the channels
+		// and the mount fds on its way out.
+		_ = d.file.Close()
+	} else {
+		d.closeFds()
+		close(d.events)
+		close(d.errors)
+	}
+	return nil
+}
+
+func (d *fanotifyNotify) Events() chan FileEvent {
+	return d.events
+}
+
+func (d *fanotifyNotify) Errors() chan error {
+	return d.errors
+}
+
+func (d *fanotifyNotify) Backend() Backend {
+	return BackendFanotify
+}
+
+var _ Notify = &fanotifyNotify{}
@@ -0,0 +1,13 @@
+// +build !linux
+
+package watch
+
+import (
+	"fmt"
+
+	"github.com/tilt-dev/tilt/pkg/logger"
+)
+
+func newFanotifyWatcher(paths []string, ignore PathMatcher, l logger.Logger) (Notify, error) {
+	return nil, fmt.Errorf("the fanotify file watcher is only supported on Linux")
+}
@@ -13,7 +13,6 @@ import (
 	"github.com/pkg/errors"
 	"github.com/tilt-dev/fsnotify"
 
-	"github.com/tilt-dev/tilt/internal/ospath"
 	"github.com/tilt-dev/tilt/pkg/logger"
 )
 
@@ -22,13 +21,9 @@ import (
 //
 // All OS-specific codepaths are handled by fsnotify.
 type naiveNotify struct {
-	// Paths that we're watching that should be passed up to the caller.
-	// Note that we may have to watch ancestors of these paths
-	// in order to fulfill the API promise.
-	notifyList map[string]bool
-
-	ignore PathMatcher
-	log    logger.Logger
+	// Note that we may have to watch ancestors of the paths in the filter's
+	// notifyList in order to fulfill the API promise.
+	notifyFilter
 
 	isWatcherRecursive bool
 	watcher            *fsnotify.Watcher
@@ -36,9 +31,22 @@ type naiveNotify struct {
 	wrappedEvents      chan FileEvent
 	errors             chan error
 	numWatches         int64
+
+	// Whether we've warned about hitting OS watch limits since we started.
+	warnedWatchLimit bool
 }
 
 func (d *naiveNotify) Start() error {
+	err := d.start()
+	if isWatchLimitError(err) {
+		// Keep the original error in the chain, so that callers can
+		// detect the limit and switch backends.
+		return fmt.Errorf("%w\n%s", err, watchLimitMessage(numberOfWatches.Value()))
+	}
+	return err
+}
+
+func (d *naiveNotify) start() error {
 	if len(d.notifyList) == 0 {
 		return nil
 	}
@@ -136,6 +144,21 @@ func (d *naiveNotify) Errors() chan error {
 	return d.errors
 }
 
+func (d *naiveNotify) Backend() Backend {
+	return BackendNative
+}
+
+// If we run out of watches after we've started, we can't switch backends
+// without missing events, so we tell the user how to fix it.
+func (d *naiveNotify) warnWatchLimit() {
+	if d.warnedWatchLimit {
+		return
+	}
+	d.warnedWatchLimit = true
+	d.log.Warnf("%s\nTilt will miss changes to new directories until you restart it.",
+		watchLimitMessage(numberOfWatches.Value()))
+}
+
 func (d *naiveNotify) loop() {
 	defer close(d.wrappedEvents)
 	for e := range d.events {
@@ -196,7 +219,9 @@ func (d *naiveNotify) loop() {
 			}
 			if shouldWatch {
 				err := d.add(path)
-				if err != nil && !os.IsNotExist(err) {
+				if isWatchLimitError(err) {
+					d.warnWatchLimit()
+				} else if err != nil && !os.IsNotExist(err) {
 					d.log.Infof("Error watching path %s: %s", e.Name, err)
 				}
 			}
@@ -208,45 +233,6 @@ func (d *naiveNotify) loop() {
 	}
 }
 
-func (d *naiveNotify) shouldNotify(path string) bool {
-	ignore, err := d.ignore.Matches(path)
-	if err != nil {
-		d.log.Infof("Error matching path %q: %v", path, err)
-	} else if ignore {
-		return false
-	}
-
-	if _, ok := d.notifyList[path]; ok {
-		// We generally don't care when directories change at the root of an ADD
-		stat, err := os.Lstat(path)
-		isDir := err == nil && stat.IsDir()
-		if isDir {
-			return false
-		}
-		return true
-	}
-	// TODO(dmiller): maybe use a prefix tree here?
-	for root := range d.notifyList {
-		if ospath.IsChild(root, path) {
-			return true
-		}
-	}
-	return false
-}
-
-func (d *naiveNotify) shouldSkipDir(path string) (bool, error) {
-	// If path is directly in the notifyList, we should always watch it.
-	if d.notifyList[path] {
-		return false, nil
-	}
-
-	skip, err := d.ignore.MatchesEntireDir(path)
-	if err != nil {
-		return false, errors.Wrap(err, "shouldSkipDir")
-	}
-	return skip, nil
-}
-
 func (d *naiveNotify) add(path string) error {
 	err := d.watcher.Add(path)
 	if err != nil {
@@ -277,22 +263,16 @@ func newWatcher(paths []string, ignore PathMatcher, l logger.Logger) (*naiveNoti
 	isWatcherRecursive := err == nil
 
 	wrappedEvents := make(chan FileEvent)
-	notifyList := make(map[string]bool, len(paths))
 	if isWatcherRecursive {
 		paths = dedupePathsForRecursiveWatcher(paths)
 	}
-	for _, path := range paths {
-		path, err := filepath.Abs(path)
-		if err != nil {
-			return nil, errors.Wrap(err, "newWatcher")
-		}
-		notifyList[path] = true
+	filter, err := newNotifyFilter(paths, ignore, l)
+	if err != nil {
+		return nil, err
 	}
 
 	wmw := &naiveNotify{
-		notifyList:         notifyList,
-		ignore:             ignore,
-		log:                l,
+		notifyFilter:       filter,
 		watcher:            fsw,
 		events:             fsw.Events,
 		wrappedEvents:      wrappedEvents,
@@ -304,15 +284,3 @@ func newWatcher(paths []string, ignore PathMatcher, l logger.Logger) (*naiveNoti
 }
 
 var _ Notify = &naiveNotify{}
-
-func greatestExistingAncestors(paths []string) ([]string, error) {
-	result := []string{}
-	for _, p := range paths {
-		newP, err := greatestExistingAncestor(p)
-		if err != nil {
-			return nil, fmt.Errorf("Finding ancestor of %s: %v", p, err)
-		}
-		result = append(result, newP)
-	}
-	return result, nil
-}
@@ -0,0 +1,208 @@
+package watch
+
+import (
+	"encoding/binary"
+	"hash/fnv"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/tilt-dev/tilt/pkg/logger"
+)
+
+// A file watcher that periodically walks the watched paths.
+//
+// It doesn't use any OS watch resources, so it works on arbitrarily large
+// trees and on filesystems that don't support notifications (like some
+// network mounts), at the cost of latency and CPU.
+//
+// To keep memory bounded on large trees, we store a hash of each file's stat
+// rather than the stat itself. We don't hash file contents, because that
+// would mean reading every file on every poll.
+type pollNotify struct {
+	filter   notifyFilter
+	interval time.Duration
+
+	events chan FileEvent
+	errors chan error
+	stop   chan struct{}
+
+	mu      sync.Mutex
+	started bool
+	closed  bool
+
+	// Hashes of the stat of each file, from the last poll.
+	hashes map[string]uint64
+}
+
+func newPollWatcher(paths []string, ignore PathMatcher, l logger.Logger, interval time.Duration) (*pollNotify, error) {
+	filter, err := newNotifyFilter(paths, ignore, l)
+	if err != nil {
+		return nil, err
+	}
+	return &pollNotify{
+		filter:   filter,
+		interval: interval,
+		events:   make(chan FileEvent),
+		errors:   make(chan error),
+		stop:     make(chan struct{}),
+	}, nil
+}
+
+func (d *pollNotify) Start() error {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	if len(d.filter.notifyList) == 0 || d.closed {
+		return nil
+	}
+
+	hashes, err := d.scan()
+	if err != nil {
+		return err
+	}
+	d.hashes = hashes
+	d.started = true
+
+	go d.loop()
+	return nil
+}
+
+func (d *pollNotify) Close() error {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	if d.closed {
+		return nil
+	}
+	d.closed = true
+	close(d.stop)
+	if !d.started {
+		close(d.events)
+	}
+	close(d.errors)
+	return nil
+}
+
+func (d *pollNotify) Events() chan FileEvent {
+	return d.events
+}
+
+func (d *pollNotify) Errors() chan error {
+	return d.errors
+}
+
+func (d *pollNotify) Backend() Backend {
+	return BackendPoll
+}
+
+func (d *pollNotify) loop() {
+	defer close(d.events)
+
+	ticker := time.NewTicker(d.interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-d.stop:
+			return
+		case <-ticker.C:
+		}
+
+		hashes, err := d.scan()
+		if err != nil {
+			d.filter.log.Infof("Error polling for file changes: %v", err)
+			continue
+		}
+
+		changed := diffHashes(d.hashes, hashes)
+		d.hashes = hashes
+		for _, path := range changed {
+			if !d.filter.shouldNotify(path) {
+				continue
+			}
+			select {
+			case d.events <- NewFileEvent(path):
+			case <-d.stop:
+				return
+			}
+		}
+	}
+}
+
+// Walks the watched paths, and hashes the stat of every file we find.
+func (d *pollNotify) scan() (map[string]uint64, error) {
+	hashes := make(map[string]uint64, len(d.hashes))
+	for _, root := range d.filter.paths() {
+		err := filepath.WalkDir(root, func(path string, entry fs.DirEntry, err error) error {
+			if err != nil {
+				if os.IsNotExist(err) {
+					// Files may be deleted while we're walking,
+					// and watched paths may not exist yet.
+					return nil
+				}
+				return err
+			}
+
+			if entry.IsDir() {
+				skip, err := d.filter.shouldSkipDir(path)
+				if err != nil {
+					return err
+				}
+				if skip {
+					return filepath.SkipDir
+				}
+				return nil
+			}
+
+			info, err := entry.Info()
+			if err != nil {
+				if os.IsNotExist(err) {
+					return nil
+				}
+				return err
+			}
+			hashes[path] = hashStat(info)
+			return nil
+		})
+		if err != nil {
+			return nil, err
+		}
+	}
+	return hashes, nil
+}
+
+func hashStat(info fs.FileInfo) uint64 {
+	h := fnv.New64a()
+	buf := make([]byte, 8)
+	for _, v := range []uint64{
+		uint64(info.Size()),
+		uint64(info.ModTime().UnixNano()),
+		uint64(info.Mode()),
+	} {
+		binary.LittleEndian.PutUint64(buf, v)
+		_, _ = h.Write(buf)
+	}
+	return h.Sum64()
+}
+
+// Returns the paths that were created, modified, or deleted, in sorted order.
+func diffHashes(old, new map[string]uint64) []string {
+	var changed []string
+	for path, hash := range new {
+		oldHash, ok := old[path]
+		if !ok || oldHash != hash {
+			changed = append(changed, path)
+		}
+	}
+	for path := range old {
+		if _, ok := new[path]; !ok {
+			changed = append(changed, path)
+		}
+	}
+	sort.Strings(changed)
+	return changed
+}
+
+var _ Notify = &pollNotify{}
@@ -0,0 +1,303 @@
+package watch
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"net"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"sync"
+	"time"
+
+	"github.com/pkg/errors"
+
+	"github.com/tilt-dev/tilt/pkg/logger"
+)
+
+// A file watcher that subscribes to changes from a watchman server.
+//
+// Watchman shares one set of OS watches between all its clients, and many
+// large monorepos already run it for other tools. We speak the JSON protocol
+// over watchman's unix socket, so we don't need any client libraries.
+//
+// https://facebook.github.io/watchman/docs/socket-interface
+type watchmanNotify struct {
+	filter notifyFilter
+
+	conn    net.Conn
+	decoder *json.Decoder
+
+	// Subscription name -> the directory that the subscription's file names are relative to.
+	subscriptions map[string]string
+
+	// Subscription PDUs that we read while waiting for a command response.
+	pending []watchmanPDU
+
+	events chan FileEvent
+	errors chan error
+	stop   chan struct{}
+
+	mu     sync.Mutex
+	closed bool
+}
+
+const watchmanSockEnvVar = "WATCHMAN_SOCK"
+
+// We don't want to hang forever on a wedged watchman server while we set up.
+const watchmanCommandTimeout = 30 * time.Second
+
+type watchmanPDU struct {
+	Error string `json:"error"`
+
+	// watch-project response
+	Watch        string `json:"watch"`
+	RelativePath string `json:"relative_path"`
+
+	// Unilateral subscription PDU
+	Subscription    string   `json:"subscription"`
+	Root            string   `json:"root"`
+	Files           []string `json:"files"`
+	IsFreshInstance bool     `json:"is_fresh_instance"`
+	Canceled        bool     `json:"canceled"`
+}
+
+func newWatchmanWatcher(paths []string, ignore PathMatcher, l logger.Logger) (*watchmanNotify, error) {
+	filter, err := newNotifyFilter(paths, ignore, l)
+	if err != nil {
+		return nil, err
+	}
+	return &watchmanNotify{
+		filter:        filter,
+		subscriptions: make(map[string]string),
+		events:        make(chan FileEvent),
+		errors:        make(chan error),
+		stop:          make(chan struct{}),
+	}, nil
+}
+
+func (d *watchmanNotify) Start() error {
+	if len(d.filter.notifyList) == 0 {
+		return nil
+	}
+
+	dirs, err := d.filter.dirsToWatch()
+	if err != nil {
+		return err
+	}
+
+	sockname, err := watchmanSockname()
+	if err != nil {
+		return err
+	}
+
+	conn, err := net.Dial("unix", sockname)
+	if err != nil {
+		return errors.Wrap(err, "connecting to watchman")
+	}
+
+	d.mu.Lock()
+	d.conn = conn
+	d.decoder = json.NewDecoder(conn)
+	d.mu.Unlock()
+
+	_ = conn.SetDeadline(time.Now().Add(watchmanCommandTimeout))
+	for i, dir := range dirs {
+		err := d.subscribe(fmt.Sprintf("tilt-%d-%d", os.Getpid(), i), dir)
+		if err != nil {
+			d.mu.Lock()
+			d.conn = nil
+			d.mu.Unlock()
+			_ = conn.Close()
+			return err
+		}
+	}
+	_ = conn.SetDeadline(time.Time{})
+
+	go d.loop()
+	return nil
+}
+
+// Watchman watches whole projects (e.g., the root of a git repo),
+// so we subscribe to the part of the project that we care about.
+func (d *watchmanNotify) subscribe(name, dir string) error {
+	resp, err := d.command("watch-project", dir)
+	if err != nil {
+		return err
+	}
+
+	query := map[string]interface{}{
+		"fields": []string{"name"},
+
+		// Watchman sends every file in the project when we first subscribe,
+		// which we don't need.
+		"empty_on_fresh_instance": true,
+	}
+	base := resp.Watch
+	if resp.RelativePath != "" {
+		query["relative_root"] = resp.RelativePath
+		base = filepath.Join(resp.Watch, resp.RelativePath)
+	}
+
+	_, err = d.command("subscribe", resp.Watch, name, query)
+	if err != nil {
+		return err
+	}
+	d.subscriptions[name] = base
+	return nil
+}
+
+// Sends a command and waits for its response.
+func (d *watchmanNotify) command(args ...interface{}) (watchmanPDU, error) {
+	b, err := json.Marshal(args)
+	if err != nil {
+		return watchmanPDU{}, err
+	}
+	_, err = d.conn.Write(append(b, '\n'))
+	if err != nil {
+		return watchmanPDU{}, errors.Wrapf(err, "watchman %s", args[0])
+	}
+
+	for {
+		var pdu watchmanPDU
+		err := d.decoder.Decode(&pdu)
+		if err != nil {
+			return watchmanPDU{}, errors.Wrapf(err, "watchman %s", args[0])
+		}
+
+		// Subscriptions can send changes at any time.
+		if pdu.Subscription != "" {
+			d.pending = append(d.pending, pdu)
+			continue
+		}
+
+		if pdu.Error != "" {
+			return watchmanPDU{}, fmt.Errorf("watchman %s: %s", args[0], pdu.Error)
+		}
+		return pdu, nil
+	}
+}
+
+func (d *watchmanNotify) loop() {
+	defer close(d.errors)
+	defer close(d.events)
+
+	for _, pdu := range d.pending {
+		if !d.handle(pdu) {
+			return
+		}
+	}
+	d.pending = nil
+
+	for {
+		var pdu watchmanPDU
+		err := d.decoder.Decode(&pdu)
+		if err != nil {
+			select {
+			case <-d.stop:
+			case d.errors <- errors.Wrap(err, "reading from watchman"):
+			}
+			return
+		}
+		if !d.handle(pdu) {
+			return
+		}
+	}
+}
+
+// Returns false if the watcher was closed.
+func (d *watchmanNotify) handle(pdu watchmanPDU) bool {
+	base, ok := d.subscriptions[pdu.Subscription]
+	if !ok || pdu.IsFreshInstance {
+		return true
+	}
+
+	if pdu.Canceled {
+		d.filter.log.Infof("watchman canceled the subscription for %s (was the directory deleted?)", base)
+		return true
+	}
+
+	for _, name := range pdu.Files {
+		path := filepath.Join(base, filepath.FromSlash(name))
+		if !d.filter.shouldNotify(path) {
+			continue
+		}
+		select {
+		case <-d.stop:
+			return false
+		case d.events <- NewFileEvent(path):
+		}
+	}
+	return true
+}
+
+func (d *watchmanNotify) Close() error {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	if d.closed {
+		return nil
+	}
+	d.closed = true
+	close(d.stop)
+
+	// Closing the connection also ends our subscriptions,
+	// and the loop closes the channels when it sees that.
+	if d.conn != nil {
+		_ = d.conn.Close()
+	} else {
+		close(d.events)
+		close(d.errors)
+	}
+	return nil
+}
+
+func (d *watchmanNotify) Events() chan FileEvent {
+	return d.events
+}
+
+func (d *watchmanNotify) Errors() chan error {
+	return d.errors
+}
+
+func (d *watchmanNotify) Backend() Backend {
+	return BackendWatchman
+}
+
+// Finds the watchman socket, starting the watchman server if necessary.
+func watchmanSockname() (string, error) {
+	sockname := os.Getenv(watchmanSockEnvVar)
+	if sockname != "" {
+		return sockname, nil
+	}
+
+	_, err := exec.LookPath("watchman")
+	if err != nil {
+		return "", fmt.Errorf("watchman not found in PATH")
+	}
+
+	stdout := &bytes.Buffer{}
+	stderr := &bytes.Buffer{}
+	cmd := exec.Command("watchman", "--output-encoding=json", "--no-pretty", "get-sockname")
+	cmd.Stdout = stdout
+	cmd.Stderr = stderr
+	err = cmd.Run()
+	if err != nil {
+		return "", fmt.Errorf("watchman get-sockname: %v\n%s", err, stderr.String())
+	}
+
+	var resp struct {
+		Sockname string `json:"sockname"`
+		Error    string `json:"error"`
+	}
+	err = json.Unmarshal(stdout.Bytes(), &resp)
+	if err != nil {
+		return "", errors.Wrap(err, "watchman get-sockname")
+	}
+	if resp.Error != "" {
+		return "", fmt.Errorf("watchman get-sockname: %s", resp.Error)
+	}
+	return resp.Sockname, nil
+}
+
+var _ Notify = &watchmanNotify{}
@@ -108,8 +108,62 @@ type CmdSpec struct {
 	//
 	// +optional
 	DisableSource *DisableSource `json:"disableSource,omitempty" protobuf:"bytes,7,opt,name=disableSource"`
+
+	// Specifies whether to restart the command when it exits on its own.
+	//
+	// One of Never, OnFailure (restart when the command exits with a non-zero
+	// exit code), or Always. Defaults to Never.
+	//
+	// Restarts are delayed with an exponential backoff, starting at 1s and
+	// capped at 5m. The backoff resets once the command stays up for 5m.
+	//
+	// +optional
+	RestartPolicy CmdRestartPolicy `json:"restartPolicy,omitempty" protobuf:"bytes,8,opt,name=restartPolicy,casttype=CmdRestartPolicy"`
+
+	// The maximum number of times the command is restarted by its RestartPolicy
+	// before Tilt gives up. Starting the command with a spec change or a
+	// RestartOn/StartOn trigger resets the count.
+	//
+	// Zero means no limit.
+	//
+	// +optional
+	MaxRestarts int32 `json:"maxRestarts,omitempty" protobuf:"varint,9,opt,name=maxRestarts"`
+
+	// The signal sent to the process group to ask the command to stop.
+	//
+	// One of SIGTERM, SIGINT, SIGHUP, SIGQUIT, SIGKILL, SIGUSR1, or SIGUSR2.
+	// Defaults to SIGTERM. Ignored on Windows.
+	//
+	// +optional
+	StopSignal string `json:"stopSignal,omitempty" protobuf:"bytes,10,opt,name=stopSignal"`
+
+	// How long to wait for the command to exit after sending the StopSignal.
+	// If the command is still running after the grace period, Tilt kills
+	// the process group with SIGKILL.
+	//
+	// Defaults to 30s.
+	//
+	// +optional
+	TerminationGracePeriod metav1.Duration `json:"terminationGracePeriod,omitempty" protobuf:"bytes,11,opt,name=terminationGracePeriod"`
 }
 
+// CmdRestartPolicy describes when a command should be restarted after it exits.
+type CmdRestartPolicy string
+
+const (
+	// Never restart the command after it exits.
+	CmdRestartPolicyNever CmdRestartPolicy = "Never"
+
+	// Restart the command if it exits with a non-zero exit code.
+	CmdRestartPolicyOnFailure CmdRestartPolicy = "OnFailure"
+
+	// Restart the command whenever it exits.
+	CmdRestartPolicyAlways CmdRestartPolicy = "Always"
+)
+
+// The signals that a Cmd can be stopped with.
+var CmdStopSignals = []string{"SIGTERM", "SIGINT", "SIGHUP", "SIGQUIT", "SIGKILL", "SIGUSR1", "SIGUSR2"}
+
 var _ resource.Object = &Cmd{}
 var _ resourcestrategy.Validater = &Cmd{}
 
@@ -146,8 +200,36 @@ func (in *Cmd) IsStorageVersion() bool {
 }
 
 func (in *Cmd) Validate(ctx context.Context) field.ErrorList {
-	// TODO(user): Modify it, adding your API validation here.
-	return nil
+	var fieldErrors field.ErrorList
+	switch in.Spec.RestartPolicy {
+	case "", CmdRestartPolicyNever, CmdRestartPolicyOnFailure, CmdRestartPolicyAlways:
+	default:
+		fieldErrors = append(fieldErrors, field.NotSupported(field.NewPath("spec", "restartPolicy"),
+			in.Spec.RestartPolicy,
+			[]string{string(CmdRestartPolicyNever), string(CmdRestartPolicyOnFailure), string(CmdRestartPolicyAlways)}))
+	}
+	if in.Spec.MaxRestarts < 0 {
+		fieldErrors = append(fieldErrors, field.Invalid(field.NewPath("spec", "maxRestarts"),
+			in.Spec.MaxRestarts, "must be non-negative"))
+	}
+	if in.Spec.StopSignal != "" && !isCmdStopSignal(in.Spec.StopSignal) {
+		fieldErrors = append(fieldErrors, field.NotSupported(field.NewPath("spec", "stopSignal"),
+			in.Spec.StopSignal, CmdStopSignals))
+	}
+	if in.Spec.TerminationGracePeriod.Duration < 0 {
+		fieldErrors = append(fieldErrors, field.Invalid(field.NewPath("spec", "terminationGracePeriod"),
+			in.Spec.TerminationGracePeriod.String(), "must be non-negative"))
+	}
+	return fieldErrors
+}
+
+func isCmdStopSignal(sig string) bool {
+	for _, s := range CmdStopSignals {
+		if s == sig {
+			return true
+		}
+	}
+	return false
 }
 
 var _ resource.ObjectList = &CmdList{}
@@ -184,6 +266,12 @@ type CmdStatus struct {
 	// Details about whether/why this is disabled.
 	// +optional
 	DisableStatus *DisableStatus `json:"disableStatus,omitempty" protobuf:"bytes,5,opt,name=disableStatus"`
+
+	// The number of times the command has been restarted by its RestartPolicy
+	// since it was last started by a spec change or a RestartOn/StartOn trigger.
+	//
+	// +optional
+	Restarts int32 `json:"restarts,omitempty" protobuf:"varint,6,opt,name=restarts"`
 }
 
 // CmdStateWaiting is a waiting state of a local command.
@@ -217,6 +305,10 @@ type CmdStateTerminated struct {
 	FinishedAt metav1.MicroTime `json:"finishedAt,omitempty" protobuf:"bytes,4,opt,name=finishedAt"`
 
 	// (brief) reason the process is terminated
+	//
+	// If Tilt stopped the process, the reason names the signal that killed it
+	// (e.g., "killed by SIGINT").
+	//
 	// +optional
 	Reason string `json:"reason,omitempty" protobuf:"bytes,5,opt,name=reason"`
 }
@@ -34,6 +34,7 @@ import (
 
 // DockerImage describes an image to build with Docker.
 // +k8s:openapi-gen=true
+// +tilt:starlark-gen=true
 type DockerImage struct {
 	metav1.TypeMeta   `json:",inline"`
 	metav1.ObjectMeta `json:"metadata,omitempty" protobuf:"bytes,1,opt,name=metadata"`
@@ -140,11 +141,23 @@ type DockerImageSpec struct {
 	//
 	// +optional
 	ExtraTags []string `json:"extraTags,omitempty" protobuf:"bytes,11,rep,name=extraTags"`
+
+	// The name of the ImageMap to write the result of the build to.
+	//
+	// If specified, the ImageMap status will be updated with the
+	// built image reference when the build succeeds.
+	//
+	// +optional
+	ImageMap string `json:"imageMap,omitempty" protobuf:"bytes,13,opt,name=imageMap"`
 }
 
 var _ resource.Object = &DockerImage{}
 var _ resourcestrategy.Validater = &DockerImage{}
 
+func (in *DockerImage) GetSpec() interface{} {
+	return in.Spec
+}
+
 func (in *DockerImage) GetObjectMeta() *metav1.ObjectMeta {
 	return &in.ObjectMeta
 }
@@ -174,8 +187,14 @@ func (in *DockerImage) IsStorageVersion() bool {
 }
 
 func (in *DockerImage) Validate(ctx context.Context) field.ErrorList {
-	// TODO(user): Modify it, adding your API validation here.
-	return nil
+	var fieldErrors field.ErrorList
+	if in.Spec.Ref == "" {
+		fieldErrors = append(fieldErrors, field.Required(field.NewPath("spec", "ref"), "image ref is required"))
+	}
+	if in.Spec.Context == "" {
+		fieldErrors = append(fieldErrors, field.Required(field.NewPath("spec", "context"), "build context is required"))
+	}
+	return fieldErrors
 }
 
 var _ resource.ObjectList = &DockerImageList{}
@@ -186,6 +205,58 @@ func (in *DockerImageList) GetListMeta() *metav1.ListMeta {
 
 // DockerImageStatus defines the observed state of DockerImage
 type DockerImageStatus struct {
+	// A fully-qualified image reference of a built image, as seen from the local
+	// network.
+	//
+	// Usually includes a name and an immutable tag.
+	//
+	// +optional
+	Ref string `json:"ref,omitempty" protobuf:"bytes,1,opt,name=ref"`
+
+	// Details about a waiting image build.
+	//
+	// +optional
+	Waiting *DockerImageStateWaiting `json:"waiting,omitempty" protobuf:"bytes,2,opt,name=waiting"`
+
+	// Details about a building image.
+	//
+	// +optional
+	Building *DockerImageStateBuilding `json:"building,omitempty" protobuf:"bytes,3,opt,name=building"`
+
+	// Details about a finished image build.
+	//
+	// +optional
+	Completed *DockerImageStateCompleted `json:"completed,omitempty" protobuf:"bytes,4,opt,name=completed"`
+}
+
+// DockerImageStateWaiting expresses what we're waiting on to build an image.
+type DockerImageStateWaiting struct {
+	// (brief) reason the image build is waiting.
+	Reason string `json:"reason" protobuf:"bytes,1,opt,name=reason"`
+}
+
+// DockerImageStateBuilding expresses that an image build is in-progress.
+type DockerImageStateBuilding struct {
+	// The reason why the image is building.
+	Reason string `json:"reason" protobuf:"bytes,1,opt,name=reason"`
+
+	// Time when the build started.
+	StartedAt metav1.MicroTime `json:"startedAt,omitempty" protobuf:"bytes,2,opt,name=startedAt"`
+}
+
+// DockerImageStateCompleted expresses when the image build is finished and
+// no new images need to be built.
+type DockerImageStateCompleted struct {
+	// Error message if the build failed.
+	//
+	// +optional
+	Error string `json:"error,omitempty" protobuf:"bytes,1,opt,name=error"`
+
+	// Time when we started building an image.
+	StartedAt metav1.MicroTime `json:"startedAt,omitempty" protobuf:"bytes,2,opt,name=startedAt"`
+
+	// Time when the image finished building.
+	FinishedAt metav1.MicroTime `json:"finishedAt,omitempty" protobuf:"bytes,3,opt,name=finishedAt"`
 }
 
 // DockerImage implements ObjectWithStatusSubResource interface.
@@ -74,6 +74,14 @@ type ExtensionSpec struct {
 	//
 	// +optional
 	Args []string `json:"args,omitempty" protobuf:"bytes,3,rep,name=args"`
+
+	// The expected hash of the extension directory contents, as recorded in
+	// tilt_modules/extensions.lock.
+	//
+	// If set, the extension fails to load when its contents don't match.
+	//
+	// +optional
+	ContentHash string `json:"contentHash,omitempty" protobuf:"bytes,4,opt,name=contentHash"`
 }
 
 var _ resource.Object = &Extension{}
@@ -134,6 +142,9 @@ type ExtensionStatus struct {
 	// The path to the extension on disk. This location should be shared
 	// and readable by all Tilt instances.
 	Path string `json:"path,omitempty" protobuf:"bytes,2,opt,name=path"`
+
+	// The hash of the extension directory contents.
+	ContentHash string `json:"contentHash,omitempty" protobuf:"bytes,3,opt,name=contentHash"`
 }
 
 // Extension implements ObjectWithStatusSubResource interface.
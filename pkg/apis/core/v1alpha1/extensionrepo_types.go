@@ -28,6 +28,7 @@ import (
 
 	"github.com/tilt-dev/tilt-apiserver/pkg/server/builder/resource"
 	"github.com/tilt-dev/tilt-apiserver/pkg/server/builder/resource/resourcestrategy"
+	"github.com/tilt-dev/tilt/pkg/apis"
 )
 
 // +genclient
@@ -59,7 +60,13 @@ type ExtensionRepoSpec struct {
 	// The URL of the repo.
 	//
 	// Allowed:
-	// https: URLs that point to a public git repo
+	// https: URLs that point to a git repo. Public repos are resolved like
+	//   go-get import paths. URLs that end in .git are cloned directly with git,
+	//   so they work on any host, and private repos can authenticate with
+	//   git credential helpers.
+	// ssh: URLs (ssh://git@example.com/org/repo.git) and scp-like
+	//   remotes (git@example.com:org/repo.git) that point to a git repo,
+	//   authenticated with the user's SSH keys.
 	// file: URLs that point to a location on disk.
 	URL string `json:"url" protobuf:"bytes,1,opt,name=url"`
 
@@ -111,13 +118,14 @@ func (in *ExtensionRepo) IsStorageVersion() bool {
 func (in *ExtensionRepo) Validate(ctx context.Context) field.ErrorList {
 	var fieldErrors field.ErrorList
 	url := in.Spec.URL
-	isWeb := strings.HasPrefix(url, "https://") || strings.HasPrefix(url, "http://")
+	isWeb := apis.IsWebURL(url)
+	isGit := apis.IsGitTransportURL(url)
 	isFile := strings.HasPrefix(url, "file://")
-	if !isWeb && !isFile {
+	if !isWeb && !isGit && !isFile {
 		fieldErrors = append(fieldErrors, field.Invalid(
 			field.NewPath("spec.url"),
 			url,
-			"URLs must start with http(s):// or file://"))
+			"URLs must start with http(s)://, ssh://, git@, or file://"))
 	} else if isFile && !filepath.IsAbs(strings.TrimPrefix(url, "file://")) {
 		fieldErrors = append(fieldErrors, field.Invalid(
 			field.NewPath("spec.url"),
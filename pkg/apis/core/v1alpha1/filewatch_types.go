@@ -158,6 +158,12 @@ type FileWatchStatus struct {
 	// Details about whether/why this is disabled.
 	// +optional
 	DisableStatus *DisableStatus `json:"disableStatus,omitempty" protobuf:"bytes,5,opt,name=disableStatus"`
+	// Backend is the strategy used to watch the filesystem (e.g., native, fanotify, watchman, or poll).
+	//
+	// Tilt may switch away from the native backend if it runs out of OS file watches, so this
+	// can differ from the backend that was requested.
+	// +optional
+	Backend string `json:"backend,omitempty" protobuf:"bytes,6,opt,name=backend"`
 }
 
 type FileEvent struct {
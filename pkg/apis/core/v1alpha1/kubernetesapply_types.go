@@ -159,7 +159,7 @@ type KubernetesApplySpec struct {
 	//
 	// The ApplyCmd MUST return valid Kubernetes YAML for the entities it applied to the cluster.
 	//
-	// Exactly one of YAML OR ApplyCmd MUST be provided.
+	// Exactly one of YAML, ApplyCmd, OR HelmRelease MUST be provided.
 	//
 	// +optional
 	ApplyCmd *KubernetesApplyCmd `json:"applyCmd,omitempty" protobuf:"bytes,10,opt,name=applyCmd"`
@@ -174,6 +174,31 @@ type KubernetesApplySpec struct {
 	//
 	// +optional
 	DeleteCmd *KubernetesApplyCmd `json:"deleteCmd,omitempty" protobuf:"bytes,12,opt,name=deleteCmd"`
+
+	// ApplyMode describes how the YAML is sent to the cluster.
+	//
+	// Ignored if ApplyCmd is set.
+	//
+	// +optional
+	ApplyMode KubernetesApplyMode `json:"applyMode,omitempty" protobuf:"bytes,13,opt,name=applyMode,casttype=KubernetesApplyMode"`
+
+	// DriftDetection periodically compares the objects in the cluster
+	// against the last applied result, and reports when they've diverged.
+	//
+	// If not set, the reconciler doesn't check for drift.
+	//
+	// +optional
+	DriftDetection *KubernetesDriftDetection `json:"driftDetection,omitempty" protobuf:"bytes,14,opt,name=driftDetection"`
+
+	// HelmRelease installs and upgrades a Helm release from a local chart.
+	//
+	// Unlike rendering the chart to YAML, the release runs hooks, keeps a
+	// release history, and is uninstalled when the object is deleted.
+	//
+	// Exactly one of YAML, ApplyCmd, OR HelmRelease MUST be provided.
+	//
+	// +optional
+	HelmRelease *KubernetesHelmRelease `json:"helmRelease,omitempty" protobuf:"bytes,15,opt,name=helmRelease"`
 }
 
 var _ resource.Object = &KubernetesApply{}
@@ -232,19 +257,46 @@ func (in *KubernetesApply) Validate(ctx context.Context) field.ErrorList {
 			}))
 	}
 
+	applyMode := in.Spec.ApplyMode
+	if !(applyMode == "" ||
+		applyMode == KubernetesApplyModeDefault ||
+		applyMode == KubernetesApplyModeServerSide) {
+		fieldErrors = append(fieldErrors, field.NotSupported(
+			field.NewPath("spec.applyMode"),
+			applyMode,
+			[]string{
+				string(KubernetesApplyModeDefault),
+				string(KubernetesApplyModeServerSide),
+			}))
+	}
+
 	if in.Spec.YAML != "" {
 		if in.Spec.ApplyCmd != nil {
 			fieldErrors = append(fieldErrors, field.Invalid(
 				field.NewPath("spec.applyCmd"),
 				in.Spec.ApplyCmd,
-				"must specify exactly ONE of .spec.yaml or .spec.applyCmd"))
+				"must specify exactly ONE of .spec.yaml, .spec.applyCmd, or .spec.helmRelease"))
+		}
+		if in.Spec.HelmRelease != nil {
+			fieldErrors = append(fieldErrors, field.Invalid(
+				field.NewPath("spec.helmRelease"),
+				in.Spec.HelmRelease,
+				"must specify exactly ONE of .spec.yaml, .spec.applyCmd, or .spec.helmRelease"))
 		}
 	} else if in.Spec.ApplyCmd != nil {
 		fieldErrors = append(fieldErrors, in.Spec.ApplyCmd.validateAsSubfield(ctx, field.NewPath("spec.applyCmd"))...)
+		if in.Spec.HelmRelease != nil {
+			fieldErrors = append(fieldErrors, field.Invalid(
+				field.NewPath("spec.helmRelease"),
+				in.Spec.HelmRelease,
+				"must specify exactly ONE of .spec.yaml, .spec.applyCmd, or .spec.helmRelease"))
+		}
+	} else if in.Spec.HelmRelease != nil {
+		fieldErrors = append(fieldErrors, in.Spec.HelmRelease.validateAsSubfield(ctx, field.NewPath("spec.helmRelease"))...)
 	} else {
 		fieldErrors = append(fieldErrors, field.Required(
 			field.NewPath("spec.yaml"),
-			"must specify exactly ONE of .spec.yaml or .spec.applyCmd"))
+			"must specify exactly ONE of .spec.yaml, .spec.applyCmd, or .spec.helmRelease"))
 	}
 
 	return fieldErrors
@@ -301,6 +353,12 @@ type KubernetesApplyStatus struct {
 	// +optional
 	DisableStatus *DisableStatus `json:"disableStatus,omitempty" protobuf:"bytes,5,opt,name=disableStatus"`
 
+	// Conditions based on the last observed state of the applied objects,
+	// e.g., whether they've drifted from the last applied result.
+	//
+	// +optional
+	Conditions []KubernetesApplyCondition `json:"conditions,omitempty" protobuf:"bytes,7,rep,name=conditions"`
+
 	// TODO(nick): We should also add some sort of status field to this
 	// status (like waiting, active, done).
 }
@@ -374,6 +432,59 @@ var (
 	KubernetesDiscoveryStrategySelectorsOnly KubernetesDiscoveryStrategy = "selectors-only"
 )
 
+type KubernetesApplyMode string
+
+var (
+	// In the default mode, we apply objects client-side, the same way
+	// `kubectl apply` does.
+	KubernetesApplyModeDefault KubernetesApplyMode = "default"
+
+	// In the server-side mode, we send objects to the server as apply patches,
+	// owned by the `tilt` field manager.
+	//
+	// https://kubernetes.io/docs/reference/using-api/server-side-apply/
+	KubernetesApplyModeServerSide KubernetesApplyMode = "server-side"
+)
+
+type KubernetesDriftDetection struct {
+	// Reapply the YAML when the objects in the cluster drift from
+	// the last applied result.
+	//
+	// +optional
+	Reapply bool `json:"reapply,omitempty" protobuf:"varint,1,opt,name=reapply"`
+}
+
+type KubernetesApplyConditionType string
+
+const (
+	// The objects in the cluster no longer match the last applied result,
+	// e.g., because someone edited or deleted them.
+	KubernetesApplyConditionDrifted KubernetesApplyConditionType = "Drifted"
+)
+
+type KubernetesApplyCondition struct {
+	// Type of KubernetesApply condition.
+	Type KubernetesApplyConditionType `json:"type" protobuf:"bytes,1,opt,name=type,casttype=KubernetesApplyConditionType"`
+
+	// Status of the condition, one of True, False, Unknown.
+	Status metav1.ConditionStatus `json:"status" protobuf:"bytes,2,opt,name=status,casttype=k8s.io/apimachinery/pkg/apis/meta/v1.ConditionStatus"`
+
+	// Last time the condition transitioned from one status to another.
+	//
+	// +optional
+	LastTransitionTime metav1.MicroTime `json:"lastTransitionTime,omitempty" protobuf:"bytes,3,opt,name=lastTransitionTime"`
+
+	// The reason for the condition's last transition.
+	//
+	// +optional
+	Reason string `json:"reason,omitempty" protobuf:"bytes,4,opt,name=reason"`
+
+	// A human readable message indicating details about the transition.
+	//
+	// +optional
+	Message string `json:"message,omitempty" protobuf:"bytes,5,opt,name=message"`
+}
+
 type KubernetesApplyCmd struct {
 	// Args are the command-line arguments for the apply command. Must have length >= 1.
 	Args []string `json:"args" protobuf:"bytes,1,rep,name=args"`
@@ -407,3 +518,41 @@ func (c *KubernetesApplyCmd) validateAsSubfield(_ context.Context, rootField *fi
 	}
 	return fieldErrors
 }
+
+type KubernetesHelmRelease struct {
+	// The name of the release.
+	ReleaseName string `json:"releaseName" protobuf:"bytes,1,opt,name=releaseName"`
+
+	// The path to a local chart directory.
+	Chart string `json:"chart" protobuf:"bytes,2,opt,name=chart"`
+
+	// The namespace to install the release into.
+	//
+	// If not specified, uses the namespace of the current kubectl context.
+	//
+	// +optional
+	Namespace string `json:"namespace,omitempty" protobuf:"bytes,3,opt,name=namespace"`
+
+	// Paths to values files, like `helm --values`.
+	//
+	// Later files override earlier ones.
+	//
+	// +optional
+	ValueFiles []string `json:"valueFiles,omitempty" protobuf:"bytes,4,rep,name=valueFiles"`
+
+	// Values to set on the command line, like `helm --set key=value`.
+	//
+	// +optional
+	Set []string `json:"set,omitempty" protobuf:"bytes,5,rep,name=set"`
+}
+
+func (r *KubernetesHelmRelease) validateAsSubfield(_ context.Context, rootField *field.Path) field.ErrorList {
+	var fieldErrors field.ErrorList
+	if r.ReleaseName == "" {
+		fieldErrors = append(fieldErrors, field.Required(rootField.Child("releaseName"), "release name cannot be empty"))
+	}
+	if r.Chart == "" {
+		fieldErrors = append(fieldErrors, field.Required(rootField.Child("chart"), "chart cannot be empty"))
+	}
+	return fieldErrors
+}
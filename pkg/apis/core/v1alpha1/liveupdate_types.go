@@ -227,11 +227,17 @@ type LiveUpdateSource struct {
 // Specifies how to select containers to live update.
 //
 // Every live update must be associated with some object for finding
-// containers. In the future, we expect there to be other types
-// of container discovery objects (like Docker Compose container discovery).
+// containers. Exactly one of the selectors must be set.
 type LiveUpdateSelector struct {
 	// Finds containers in Kubernetes.
+	//
+	// +optional
 	Kubernetes *LiveUpdateKubernetesSelector `json:"kubernetes,omitempty" protobuf:"bytes,1,opt,name=kubernetes"`
+
+	// Finds containers in Docker Compose.
+	//
+	// +optional
+	DockerCompose *LiveUpdateDockerComposeSelector `json:"dockerCompose,omitempty" protobuf:"bytes,2,opt,name=dockerCompose"`
 }
 
 // Specifies how to select containers to live update inside K8s.
@@ -253,6 +259,49 @@ type LiveUpdateKubernetesSelector struct {
 	Image string `json:"image,omitempty" protobuf:"bytes,2,opt,name=image"`
 }
 
+// Specifies how to select containers to live update inside Docker Compose.
+type LiveUpdateDockerComposeSelector struct {
+	// The name of the Docker Compose service to live-update.
+	//
+	// Each service runs in a single container.
+	Service string `json:"service" protobuf:"bytes,1,opt,name=service"`
+
+	// The Docker Compose project that the service belongs to.
+	Project DockerComposeProject `json:"project" protobuf:"bytes,2,opt,name=project"`
+
+	// Image specifies the name of the image that the service runs,
+	// and that we're copying files into.
+	//
+	// +optional
+	Image string `json:"image,omitempty" protobuf:"bytes,3,opt,name=image"`
+}
+
+// Describes a Docker Compose project.
+type DockerComposeProject struct {
+	// Configuration files to load.
+	//
+	// If both ConfigPaths and ProjectPath/YAML are specified,
+	// the YAML is the source of truth, and the ConfigPaths
+	// are used to print diagnostic information.
+	//
+	// +optional
+	ConfigPaths []string `json:"configPaths,omitempty" protobuf:"bytes,1,rep,name=configPaths"`
+
+	// The base path of the docker-compose project.
+	//
+	// Expressed in docker-compose as --project-directory.
+	//
+	// +optional
+	ProjectPath string `json:"projectPath,omitempty" protobuf:"bytes,2,opt,name=projectPath"`
+
+	// The docker-compose config YAML.
+	//
+	// Usually contains multiple services.
+	//
+	// +optional
+	YAML string `json:"yaml,omitempty" protobuf:"bytes,3,opt,name=yaml"`
+}
+
 // Determines how a local path maps into a container image.
 type LiveUpdateSync struct {
 	// A relative path to local files. Required.
@@ -344,8 +393,33 @@ type LiveUpdateContainerStatus struct {
 	// A live update is waiting when the reconciler is aware of file changes
 	// that need to be synced to the container, but has decided not to sync them yet.
 	Waiting *LiveUpdateContainerStateWaiting `json:"waiting,omitempty" protobuf:"bytes,7,opt,name=waiting"`
+
+	// How files were copied into the container on the most recent update.
+	//
+	// Tilt prefers to run `tar` in the container. If the container image doesn't
+	// have `tar` (e.g., distroless or scratch images), Tilt falls back to another
+	// transport automatically.
+	//
+	// +optional
+	FileTransport LiveUpdateFileTransport `json:"fileTransport,omitempty" protobuf:"bytes,8,opt,name=fileTransport,casttype=LiveUpdateFileTransport"`
 }
 
+// How the live-updater copies files into a container.
+type LiveUpdateFileTransport string
+
+var (
+	// Run `tar` in the container.
+	LiveUpdateFileTransportTar LiveUpdateFileTransport = "tar"
+
+	// Use the Docker copy API. Only available when Tilt talks to the container
+	// runtime directly (e.g., Docker Compose or Docker Desktop).
+	LiveUpdateFileTransportDockerCopy LiveUpdateFileTransport = "docker-copy"
+
+	// Run `tar` in an ephemeral helper container that shares the process
+	// namespace of the container, and unpack the files through /proc.
+	LiveUpdateFileTransportEphemeralContainer LiveUpdateFileTransport = "ephemeral-container"
+)
+
 // If any of the containers are currently failing to process updates, the
 // LiveUpdateStateFailed surfaces information about what's happening and what
 // the live-updater is doing to fix the problem.
@@ -53,9 +53,13 @@ type PortForwardList struct {
 }
 
 // PortForwardSpec defines the desired state of PortForward
+//
+// Exactly one of PodName, ServiceName, or PodSelector must be set.
 type PortForwardSpec struct {
-	// The name of the pod to port forward to/from. Required.
-	PodName string `json:"podName" protobuf:"bytes,1,opt,name=podName"`
+	// The name of the pod to port forward to/from.
+	//
+	// +optional
+	PodName string `json:"podName,omitempty" protobuf:"bytes,1,opt,name=podName"`
 
 	// The namespace of the pod to port forward to/from. Defaults to the kubecontext default namespace.
 	//
@@ -64,6 +68,23 @@ type PortForwardSpec struct {
 
 	// One or more port forwards to execute on the given pod. Required.
 	Forwards []Forward `json:"forwards" protobuf:"bytes,3,rep,name=forwards"`
+
+	// The name of a Service to port forward to/from.
+	//
+	// Tilt forwards to a ready pod that backs the Service, and switches to
+	// another ready pod if that pod goes away. The ContainerPort of each
+	// Forward is the Service port, which Tilt maps to the target port.
+	//
+	// +optional
+	ServiceName string `json:"serviceName,omitempty" protobuf:"bytes,4,opt,name=serviceName"`
+
+	// Labels of the pods to port forward to/from.
+	//
+	// Tilt forwards to a ready pod that matches the labels, and switches to
+	// another ready pod if that pod goes away.
+	//
+	// +optional
+	PodSelector map[string]string `json:"podSelector,omitempty" protobuf:"bytes,5,rep,name=podSelector"`
 }
 
 // Forward defines a port forward to execute on a given pod.
@@ -133,8 +154,22 @@ func (in *PortForward) IsStorageVersion() bool {
 
 func (in *PortForward) Validate(_ context.Context) field.ErrorList {
 	var fieldErrors field.ErrorList
-	if in.Spec.PodName == "" {
-		fieldErrors = append(fieldErrors, field.Required(field.NewPath("spec.podName"), "PodName cannot be empty"))
+	targetCount := 0
+	if in.Spec.PodName != "" {
+		targetCount++
+	}
+	if in.Spec.ServiceName != "" {
+		targetCount++
+	}
+	if len(in.Spec.PodSelector) != 0 {
+		targetCount++
+	}
+	if targetCount == 0 {
+		fieldErrors = append(fieldErrors, field.Required(field.NewPath("spec.podName"),
+			"One of PodName, ServiceName, or PodSelector is required"))
+	} else if targetCount > 1 {
+		fieldErrors = append(fieldErrors, field.Invalid(field.NewPath("spec.podName"), in.Spec.PodName,
+			"Only one of PodName, ServiceName, or PodSelector may be set"))
 	}
 	forwardsPath := field.NewPath("spec.forwards")
 	if len(in.Spec.Forwards) == 0 {
@@ -200,6 +235,26 @@ type ForwardStatus struct {
 	// Error is a human-readable description if a problem was encountered
 	// while initializing the forward.
 	Error string `json:"error,omitempty" protobuf:"bytes,5,opt,name=error"`
+
+	// PodName is the name of the pod currently being forwarded to.
+	//
+	// For forwards to a Service or pod selector, this changes when Tilt
+	// switches to a different pod.
+	//
+	// +optional
+	PodName string `json:"podName,omitempty" protobuf:"bytes,6,opt,name=podName"`
+
+	// ActiveConnections is the number of local connections that are
+	// currently open through the forward.
+	//
+	// +optional
+	ActiveConnections int32 `json:"activeConnections,omitempty" protobuf:"varint,7,opt,name=activeConnections"`
+
+	// Reconnects is the number of times the forward has been re-established
+	// since it first started (e.g., because the pod went away).
+	//
+	// +optional
+	Reconnects int32 `json:"reconnects,omitempty" protobuf:"varint,8,opt,name=reconnects"`
 }
 
 // PortForward implements ObjectWithStatusSubResource interface.
@@ -58,6 +58,47 @@ type SessionSpec struct {
 	TiltfilePath string `json:"tiltfilePath" protobuf:"bytes,1,opt,name=tiltfilePath"`
 	// ExitCondition defines the criteria for Tilt to exit.
 	ExitCondition ExitCondition `json:"exitCondition" protobuf:"bytes,2,opt,name=exitCondition,casttype=ExitCondition"`
+
+	// CI refines the criteria for exiting when the ExitCondition is ExitConditionCI.
+	//
+	// +optional
+	CI *SessionCISpec `json:"ci,omitempty" protobuf:"bytes,3,opt,name=ci"`
+}
+
+// SessionCISpec refines the criteria for exiting a CI session.
+//
+// By default, a CI session waits for every resource and fails on the first error.
+type SessionCISpec struct {
+	// Timeout is the maximum duration of the session.
+	//
+	// If the session hasn't finished by then, it fails.
+	//
+	// +optional
+	Timeout metav1.Duration `json:"timeout,omitempty" protobuf:"bytes,1,opt,name=timeout"`
+
+	// Resources restricts the session to only wait for the named resources.
+	//
+	// +optional
+	Resources []string `json:"resources,omitempty" protobuf:"bytes,2,rep,name=resources"`
+
+	// Labels restricts the session to only wait for resources with at least one of these labels.
+	//
+	// +optional
+	Labels []string `json:"labels,omitempty" protobuf:"bytes,3,rep,name=labels"`
+
+	// TestsOnly restricts the session to only wait for test() resources.
+	//
+	// +optional
+	TestsOnly bool `json:"testsOnly,omitempty" protobuf:"varint,4,opt,name=testsOnly"`
+
+	// MaxFailures is the number of resource failures to tolerate.
+	//
+	// Each tolerated failure retries the resource that failed. Once the number
+	// of failures exceeds MaxFailures, the session fails. Failures of the
+	// Tiltfile itself are never tolerated.
+	//
+	// +optional
+	MaxFailures int32 `json:"maxFailures,omitempty" protobuf:"varint,5,opt,name=maxFailures"`
 }
 
 type ExitCondition string
@@ -134,6 +175,17 @@ func (in *Session) Validate(_ context.Context) field.ErrorList {
 			in.Spec.ExitCondition,
 			detailMsg.String()))
 	}
+	if ci := in.Spec.CI; ci != nil {
+		if ci.Timeout.Duration < 0 {
+			fieldErrors = append(fieldErrors, field.Invalid(
+				field.NewPath("ci", "timeout"), ci.Timeout.Duration.String(), "cannot be negative"))
+		}
+		if ci.MaxFailures < 0 {
+			fieldErrors = append(fieldErrors, field.Invalid(
+				field.NewPath("ci", "maxFailures"), ci.MaxFailures, "cannot be negative"))
+		}
+	}
+
 	return fieldErrors
 }
 
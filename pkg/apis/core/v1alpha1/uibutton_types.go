@@ -122,6 +122,9 @@ type UIComponentLocationResource struct {
 // used by the backend to indicate to the frontend that this button is special
 const AnnotationButtonType = "tilt.dev/uibutton-type"
 
+// The button type of the buttons that enable and disable a resource.
+const UIButtonTypeDisableToggle = "DisableToggle"
+
 var _ resource.Object = &UIButton{}
 var _ resourcestrategy.Validater = &UIButton{}
 
@@ -0,0 +1,32 @@
+package apis
+
+import (
+	"regexp"
+	"strings"
+)
+
+// Matches scp-like git remotes, e.g., git@example.com:org/repo.git
+var scpLikeGitURLRe = regexp.MustCompile(`^[A-Za-z0-9._-]+@[A-Za-z0-9.-]+:[^/]`)
+
+// Reports whether the URL should be cloned directly with git,
+// rather than resolved as a go-get style import path.
+//
+// This includes SSH remotes (ssh://, git@host:path), git:// remotes,
+// and http(s) remotes that end in .git. Git can clone these
+// from any host, authenticating with the user's SSH keys and credential helpers.
+func IsGitRemoteURL(url string) bool {
+	if IsGitTransportURL(url) {
+		return true
+	}
+	return IsWebURL(url) && strings.HasSuffix(strings.TrimSuffix(url, "/"), ".git")
+}
+
+// Reports whether the URL uses one of git's own transports (ssh://, git://, git@host:path).
+func IsGitTransportURL(url string) bool {
+	return strings.HasPrefix(url, "ssh://") || strings.HasPrefix(url, "git://") ||
+		strings.HasPrefix(url, "git+ssh://") || scpLikeGitURLRe.MatchString(url)
+}
+
+func IsWebURL(url string) bool {
+	return strings.HasPrefix(url, "https://") || strings.HasPrefix(url, "http://")
+}
@@ -0,0 +1,30 @@
+package apis_test
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/tilt-dev/tilt/pkg/apis"
+)
+
+func TestIsGitRemoteURL(t *testing.T) {
+	for _, tc := range []struct {
+		url      string
+		expected bool
+	}{
+		{"git@example.com:org/repo.git", true},
+		{"git@example.com:org/repo", true},
+		{"ssh://git@example.com/org/repo.git", true},
+		{"ssh://git@example.com:2222/org/repo", true},
+		{"git://example.com/org/repo", true},
+		{"https://example.com/org/repo.git", true},
+		{"https://github.com/tilt-dev/tilt-extensions", false},
+		{"file:///home/user/repo", false},
+		{"example.com/org/repo", false},
+	} {
+		t.Run(tc.url, func(t *testing.T) {
+			assert.Equal(t, tc.expected, apis.IsGitRemoteURL(tc.url))
+		})
+	}
+}
@@ -2,8 +2,10 @@ package logger
 
 import (
 	"context"
+	"fmt"
 	"io"
 	"os"
+	"strings"
 
 	"github.com/fatih/color"
 
@@ -84,6 +86,51 @@ var (
 	ErrorLvl   = Level{id: 5, severity: 500}
 )
 
+// Converts the ID from Level.ToProtoID() back to a Level.
+//
+// Unknown IDs are treated as InfoLvl.
+func LevelFromProtoID(id int32) Level {
+	for _, l := range []Level{NoneLvl, DebugLvl, VerboseLvl, InfoLvl, WarnLvl, ErrorLvl} {
+		if l.id == id {
+			return l
+		}
+	}
+	return InfoLvl
+}
+
+func (l Level) String() string {
+	switch l {
+	case DebugLvl:
+		return "debug"
+	case VerboseLvl:
+		return "verbose"
+	case InfoLvl:
+		return "info"
+	case WarnLvl:
+		return "warn"
+	case ErrorLvl:
+		return "error"
+	}
+	return "none"
+}
+
+// Parses a level name (e.g., "warn") as entered by a user.
+func ParseLevel(s string) (Level, error) {
+	switch strings.ToLower(s) {
+	case "debug":
+		return DebugLvl, nil
+	case "verbose":
+		return VerboseLvl, nil
+	case "info":
+		return InfoLvl, nil
+	case "warn", "warning":
+		return WarnLvl, nil
+	case "error":
+		return ErrorLvl, nil
+	}
+	return NoneLvl, fmt.Errorf("unknown log level %q (expected one of: debug, verbose, info, warn, error)", s)
+}
+
 type contextKey struct{}
 
 var LoggerContextKey = contextKey{}
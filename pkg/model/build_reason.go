@@ -29,6 +29,11 @@ const (
 	// Building manifestA will mark imageB
 	// with changed dependencies.
 	BuildReasonFlagChangedDeps
+
+	// The user pressed the trigger key in the terminal HUD.
+	//
+	// Declared last so that the flag values the web UI sends don't change.
+	BuildReasonFlagTriggerHUD
 )
 
 func (r BuildReason) With(flag BuildReason) BuildReason {
@@ -59,6 +64,7 @@ var translations = map[BuildReason]string{
 	BuildReasonFlagInit:           "Initial Build",
 	BuildReasonFlagTriggerWeb:     "Web Trigger",
 	BuildReasonFlagTriggerCLI:     "CLI Trigger",
+	BuildReasonFlagTriggerHUD:     "HUD Trigger",
 	BuildReasonFlagTriggerUnknown: "Unknown Trigger",
 	BuildReasonFlagTiltfileArgs:   "Tilt Args",
 	BuildReasonFlagChangedDeps:    "Dependency Updated",
@@ -67,6 +73,7 @@ var translations = map[BuildReason]string{
 var triggerBuildReasons = []BuildReason{
 	BuildReasonFlagTriggerWeb,
 	BuildReasonFlagTriggerCLI,
+	BuildReasonFlagTriggerHUD,
 	BuildReasonFlagTriggerUnknown,
 }
 
@@ -77,6 +84,7 @@ var allBuildReasons = []BuildReason{
 	BuildReasonFlagCrash,
 	BuildReasonFlagTriggerWeb,
 	BuildReasonFlagTriggerCLI,
+	BuildReasonFlagTriggerHUD,
 	BuildReasonFlagChangedDeps,
 	BuildReasonFlagTriggerUnknown,
 	BuildReasonFlagTiltfileArgs,
@@ -17,6 +17,7 @@ type DockerPruneSettings struct {
 	NumBuilds  int           // "prune every Y builds" (takes precedence over "prune every Z hours")
 	Interval   time.Duration // "prune every Z hours"
 	KeepRecent int           // Keep the most recent N builds of a tag.
+	Registry   bool          // Also prune images that this Tilt pushed to a registry.
 }
 
 func DefaultDockerPruneSettings() DockerPruneSettings {
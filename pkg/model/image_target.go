@@ -73,6 +73,9 @@ func (i ImageTarget) WithLiveUpdateSpec(name string, luSpec v1alpha1.LiveUpdateS
 	if luSpec.Selector.Kubernetes == nil {
 		luSpec.Selector.Kubernetes = i.LiveUpdateSpec.Selector.Kubernetes
 	}
+	if luSpec.Selector.DockerCompose == nil {
+		luSpec.Selector.DockerCompose = i.LiveUpdateSpec.Selector.DockerCompose
+	}
 	i.LiveUpdateName = name
 	i.LiveUpdateSpec = luSpec
 	return i
@@ -2,6 +2,7 @@ package model
 
 import (
 	"fmt"
+	"time"
 
 	"github.com/tilt-dev/tilt/internal/sliceutils"
 	"github.com/tilt-dev/tilt/pkg/apis"
@@ -29,6 +30,13 @@ type LocalTarget struct {
 
 	ReadinessProbe *v1alpha1.Probe
 
+	// Whether to restart the serve_cmd when it exits on its own.
+	ServeRestartPolicy v1alpha1.CmdRestartPolicy
+
+	// How to stop the serve_cmd. Empty/zero values use the Cmd defaults.
+	ServeStopSignal             string
+	ServeTerminationGracePeriod time.Duration
+
 	// Move this to CmdServerSpec when we move CmdServer to API
 	ServeCmdDisableSource *v1alpha1.DisableSource
 }
@@ -99,6 +107,21 @@ func (lt LocalTarget) WithReadinessProbe(probeSpec *v1alpha1.Probe) LocalTarget
 	return lt
 }
 
+func (lt LocalTarget) WithServeRestartPolicy(policy v1alpha1.CmdRestartPolicy) LocalTarget {
+	lt.ServeRestartPolicy = policy
+	return lt
+}
+
+func (lt LocalTarget) WithServeStopSignal(sig string) LocalTarget {
+	lt.ServeStopSignal = sig
+	return lt
+}
+
+func (lt LocalTarget) WithServeTerminationGracePeriod(d time.Duration) LocalTarget {
+	lt.ServeTerminationGracePeriod = d
+	return lt
+}
+
 func (lt LocalTarget) ID() TargetID {
 	return TargetID{
 		Name: lt.Name,
@@ -0,0 +1,442 @@
+package logstore
+
+import (
+	"bufio"
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/pkg/errors"
+
+	"github.com/tilt-dev/tilt/pkg/logger"
+	"github.com/tilt-dev/tilt/pkg/model"
+)
+
+const historyFilePrefix = "segments-"
+const historyFileSuffix = ".jsonl"
+
+// We rotate files at a fixed size so that retention can delete
+// old logs a file at a time.
+const maxHistoryFileSizeInBytes = 4 * 1000 * 1000
+
+type HistoryOptions struct {
+	// The maximum number of bytes to keep on disk. Zero means no limit.
+	MaxBytes int64
+
+	// The maximum age of logs to keep on disk. Zero means no limit.
+	MaxAge time.Duration
+}
+
+// An on-disk archive of log segments.
+//
+// When the LogStore truncates segments to keep its memory footprint small,
+// it hands them off to the History, so that they can still be found with a Query.
+// The segments are written to disk in the background, so that the LogStore
+// doesn't do file I/O while the engine state is locked.
+//
+// Segments are stored as JSON lines in a series of files, one segment per line.
+// Thread-safe.
+type History struct {
+	dir  string
+	opts HistoryOptions
+	now  func() time.Time
+
+	// Held while reading or writing files.
+	mu sync.Mutex
+
+	// Protected by mu, sorted oldest first.
+	// The last file is the one we're currently writing to.
+	files []historyFile
+
+	// Records waiting to be written by the background writer.
+	// Protected by pendingMu.
+	pendingMu   sync.Mutex
+	pendingCond *sync.Cond
+	pending     []historyRecord
+	writing     bool
+
+	// Secrets to scrub from archived logs, including logs that were
+	// archived before we knew about the secret. Protected by pendingMu.
+	secrets model.SecretSet
+}
+
+type historyFile struct {
+	seq     int
+	size    int64
+	modTime time.Time
+}
+
+// The serialized form of a LogSegment.
+type historyRecord struct {
+	SpanID       SpanID             `json:"spanID"`
+	ManifestName model.ManifestName `json:"manifestName,omitempty"`
+	Time         time.Time          `json:"time"`
+	Level        int32              `json:"level,omitempty"`
+	Text         string             `json:"text"`
+	Fields       logger.Fields      `json:"fields,omitempty"`
+	Anchor       bool               `json:"anchor,omitempty"`
+}
+
+func NewHistory(dir string, opts HistoryOptions) (*History, error) {
+	err := os.MkdirAll(dir, 0755)
+	if err != nil {
+		return nil, errors.Wrap(err, "creating log history dir")
+	}
+
+	h := &History{
+		dir:  dir,
+		opts: opts,
+		now:  time.Now,
+	}
+	h.pendingCond = sync.NewCond(&h.pendingMu)
+	h.secrets = model.SecretSet{}
+
+	entries, err := os.ReadDir(dir)
+	if err != nil {
+		return nil, errors.Wrap(err, "reading log history dir")
+	}
+
+	for _, entry := range entries {
+		seq, ok := historyFileSeq(entry.Name())
+		if !ok || entry.IsDir() {
+			continue
+		}
+		info, err := entry.Info()
+		if err != nil {
+			continue
+		}
+		h.files = append(h.files, historyFile{seq: seq, size: info.Size(), modTime: info.ModTime()})
+	}
+	sort.Slice(h.files, func(i, j int) bool { return h.files[i].seq < h.files[j].seq })
+
+	// Always start a new file, so that logs from different Tilt sessions
+	// don't share a file.
+	h.rotate()
+
+	err = h.enforceRetention()
+	if err != nil {
+		return nil, err
+	}
+	return h, nil
+}
+
+func (h *History) Dir() string {
+	return h.dir
+}
+
+func historyFileSeq(name string) (int, bool) {
+	if !strings.HasPrefix(name, historyFilePrefix) || !strings.HasSuffix(name, historyFileSuffix) {
+		return 0, false
+	}
+	seq, err := strconv.Atoi(strings.TrimSuffix(strings.TrimPrefix(name, historyFilePrefix), historyFileSuffix))
+	if err != nil {
+		return 0, false
+	}
+	return seq, true
+}
+
+func (h *History) path(f historyFile) string {
+	return filepath.Join(h.dir, fmt.Sprintf("%s%08d%s", historyFilePrefix, f.seq, historyFileSuffix))
+}
+
+func (h *History) fileSizeLimit() int64 {
+	limit := int64(maxHistoryFileSizeInBytes)
+	if h.opts.MaxBytes > 0 && h.opts.MaxBytes/4 < limit {
+		limit = h.opts.MaxBytes / 4
+	}
+	return limit
+}
+
+func (h *History) rotate() {
+	seq := 0
+	if len(h.files) > 0 {
+		seq = h.files[len(h.files)-1].seq + 1
+	}
+	h.files = append(h.files, historyFile{seq: seq, modTime: h.now()})
+}
+
+// Delete old files until we're under the byte and age limits.
+//
+// The current file is never deleted.
+func (h *History) enforceRetention() error {
+	total := int64(0)
+	for _, f := range h.files {
+		total += f.size
+	}
+
+	cutoff := time.Time{}
+	if h.opts.MaxAge > 0 {
+		cutoff = h.now().Add(-h.opts.MaxAge)
+	}
+
+	for len(h.files) > 1 {
+		oldest := h.files[0]
+		overSize := h.opts.MaxBytes > 0 && total > h.opts.MaxBytes
+		tooOld := !cutoff.IsZero() && oldest.modTime.Before(cutoff)
+		if !overSize && !tooOld {
+			break
+		}
+
+		err := os.Remove(h.path(oldest))
+		if err != nil && !os.IsNotExist(err) {
+			return errors.Wrap(err, "deleting log history")
+		}
+		total -= oldest.size
+		h.files = h.files[1:]
+	}
+	return nil
+}
+
+// Queue the given segments to be archived by the background writer.
+//
+// The spans are used to look up the manifest name of each segment.
+func (h *History) archive(segments []LogSegment, spans map[SpanID]*Span) {
+	if len(segments) == 0 {
+		return
+	}
+
+	records := make([]historyRecord, 0, len(segments))
+	for _, segment := range segments {
+		record := historyRecord{
+			SpanID: segment.SpanID,
+			Time:   segment.Time,
+			Level:  segment.Level.ToProtoID(),
+			Text:   string(segment.Text),
+			Fields: segment.Fields,
+			Anchor: segment.Anchor,
+		}
+		span, ok := spans[segment.SpanID]
+		if ok {
+			record.ManifestName = span.ManifestName
+		}
+		records = append(records, record)
+	}
+
+	h.pendingMu.Lock()
+	defer h.pendingMu.Unlock()
+	h.pending = append(h.pending, records...)
+	if !h.writing {
+		h.writing = true
+		go h.writePending()
+	}
+}
+
+// Writes queued records until the queue is empty.
+func (h *History) writePending() {
+	for {
+		h.pendingMu.Lock()
+		records := h.pending
+		if len(records) == 0 {
+			h.writing = false
+			h.pendingCond.Broadcast()
+			h.pendingMu.Unlock()
+			return
+		}
+		h.pendingMu.Unlock()
+
+		h.mu.Lock()
+		// If we can't archive the logs, there's not much we can do about it,
+		// and it's better to keep the in-memory logs small.
+		_ = h.write(records)
+
+		// Dequeue the records while holding the file lock, so that a reader
+		// sees each record either in a file or in the queue, but not both.
+		h.pendingMu.Lock()
+		h.pending = h.pending[len(records):]
+		h.pendingMu.Unlock()
+		h.mu.Unlock()
+	}
+}
+
+// Scrub the given secrets from all archived segments.
+//
+// Rewriting the files on disk would be slow, so segments that
+// have already been written are scrubbed when they're read.
+func (h *History) scrubSecrets(secrets model.SecretSet) {
+	if len(secrets) == 0 {
+		return
+	}
+
+	h.pendingMu.Lock()
+	defer h.pendingMu.Unlock()
+	h.secrets.AddAll(secrets)
+
+	// Copy the queue, because the background writer may be reading it.
+	pending := make([]historyRecord, 0, len(h.pending))
+	for _, record := range h.pending {
+		record.Text = string(secrets.Scrub([]byte(record.Text)))
+		pending = append(pending, record)
+	}
+	h.pending = pending
+}
+
+func (h *History) currentSecrets() model.SecretSet {
+	h.pendingMu.Lock()
+	defer h.pendingMu.Unlock()
+	secrets := model.SecretSet{}
+	secrets.AddAll(h.secrets)
+	return secrets
+}
+
+// Blocks until all the queued segments have been written to disk.
+func (h *History) Flush() {
+	h.pendingMu.Lock()
+	defer h.pendingMu.Unlock()
+	for h.writing {
+		h.pendingCond.Wait()
+	}
+}
+
+// Must hold the file lock.
+func (h *History) write(records []historyRecord) error {
+	buf := &strings.Builder{}
+	encoder := json.NewEncoder(buf)
+	encoder.SetEscapeHTML(false)
+	for _, record := range records {
+		err := encoder.Encode(record)
+		if err != nil {
+			return errors.Wrap(err, "encoding log history")
+		}
+	}
+
+	current := &h.files[len(h.files)-1]
+	f, err := os.OpenFile(h.path(*current), os.O_CREATE|os.O_APPEND|os.O_WRONLY, 0644)
+	if err != nil {
+		return errors.Wrap(err, "writing log history")
+	}
+
+	n, err := f.WriteString(buf.String())
+	current.size += int64(n)
+	current.modTime = h.now()
+	if err != nil {
+		_ = f.Close()
+		return errors.Wrap(err, "writing log history")
+	}
+
+	err = f.Close()
+	if err != nil {
+		return errors.Wrap(err, "writing log history")
+	}
+
+	if current.size >= h.fileSizeLimit() {
+		h.rotate()
+	}
+	return h.enforceRetention()
+}
+
+// Reads the archived segments that might match the query, one file at a time,
+// so that we never hold the whole archive in memory. Segments that are still
+// waiting to be written come last.
+//
+// Each chunk is passed to the visitor along with the spans of its segments.
+//
+// Does not filter by text; that needs to happen after segments
+// are assembled into lines.
+func (h *History) read(q Query, visit func(segments []LogSegment, spans map[SpanID]*Span)) error {
+	// Hold the file lock so that the background writer doesn't
+	// move records from the queue to a file while we're reading.
+	h.mu.Lock()
+	defer h.mu.Unlock()
+
+	secrets := h.currentSecrets()
+	for _, f := range h.files {
+		if !q.Since.IsZero() && f.modTime.Before(q.Since) {
+			// Nothing has been written to this file since the start of the query.
+			continue
+		}
+
+		chunk := newHistoryChunk(q, secrets)
+		err := h.readFile(h.path(f), chunk.add)
+		if err != nil {
+			return err
+		}
+		chunk.visit(visit)
+	}
+
+	h.pendingMu.Lock()
+	pending := append([]historyRecord{}, h.pending...)
+	h.pendingMu.Unlock()
+
+	chunk := newHistoryChunk(q, secrets)
+	for _, record := range pending {
+		chunk.add(record)
+	}
+	chunk.visit(visit)
+	return nil
+}
+
+// The segments from one history file that match a query.
+type historyChunk struct {
+	q        Query
+	secrets  model.SecretSet
+	segments []LogSegment
+	spans    map[SpanID]*Span
+}
+
+func newHistoryChunk(q Query, secrets model.SecretSet) *historyChunk {
+	return &historyChunk{q: q, secrets: secrets, spans: make(map[SpanID]*Span)}
+}
+
+func (c *historyChunk) add(record historyRecord) {
+	segment := LogSegment{
+		SpanID: record.SpanID,
+		Time:   record.Time,
+		Text:   c.secrets.Scrub([]byte(record.Text)),
+		Level:  logger.LevelFromProtoID(record.Level),
+		Fields: record.Fields,
+		Anchor: record.Anchor,
+	}
+	if !c.q.matchesSegment(segment, record.ManifestName) {
+		return
+	}
+
+	if _, ok := c.spans[segment.SpanID]; !ok {
+		c.spans[segment.SpanID] = &Span{ManifestName: record.ManifestName}
+	}
+	c.segments = append(c.segments, segment)
+}
+
+func (c *historyChunk) visit(visit func(segments []LogSegment, spans map[SpanID]*Span)) {
+	if len(c.segments) > 0 {
+		visit(c.segments, c.spans)
+	}
+}
+
+func (h *History) readFile(path string, visit func(record historyRecord)) error {
+	f, err := os.Open(path)
+	if err != nil {
+		if os.IsNotExist(err) {
+			// The file may have been deleted by retention, or never written.
+			return nil
+		}
+		return errors.Wrap(err, "reading log history")
+	}
+	defer func() { _ = f.Close() }()
+
+	reader := bufio.NewReader(f)
+	for {
+		line, err := reader.ReadBytes(newlineByte)
+		if len(line) > 0 {
+			var record historyRecord
+			jsonErr := json.Unmarshal(line, &record)
+			if jsonErr == nil {
+				visit(record)
+			}
+			// Otherwise, skip corrupt records (e.g., a partial write when Tilt was killed).
+		}
+
+		if err == io.EOF {
+			return nil
+		}
+		if err != nil {
+			return errors.Wrap(err, "reading log history")
+		}
+	}
+}
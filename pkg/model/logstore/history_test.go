@@ -0,0 +1,216 @@
+package logstore
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"regexp"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/tilt-dev/tilt/pkg/logger"
+	"github.com/tilt-dev/tilt/pkg/model"
+)
+
+func TestQueryFindsTruncatedLogs(t *testing.T) {
+	h, err := NewHistory(t.TempDir(), HistoryOptions{})
+	require.NoError(t, err)
+
+	l := NewLogStore()
+	l.SetHistory(h)
+	l.maxLogLengthInBytes = 32
+
+	now := time.Now()
+	l.Append(newTestLogEvent("fe", now, "hello\n"), nil)
+	for i := 0; i < 10; i++ {
+		l.Append(newTestLogEvent("fe", now.Add(time.Duration(i+1)*time.Second), fmt.Sprintf("line %d\n", i)), nil)
+	}
+	assert.NotContains(t, l.String(), "hello")
+
+	lines, err := l.Query(Query{})
+	require.NoError(t, err)
+	assert.NotEqual(t, "hello\n", lines[0].Text, "history should only be read when asked for")
+
+	// Segments are written in the background, but we should find them
+	// whether or not they've been written yet.
+	lines, err = l.Query(Query{History: true})
+	require.NoError(t, err)
+	require.Len(t, lines, 11)
+	assert.Equal(t, "hello\n", lines[0].Text)
+	assert.Equal(t, model.ManifestName("fe"), lines[0].ManifestName)
+	assert.Equal(t, "line 9\n", lines[10].Text)
+
+	h.Flush()
+	lines, err = l.Query(Query{History: true})
+	require.NoError(t, err)
+	require.Len(t, lines, 11)
+	assert.Equal(t, "hello\n", lines[0].Text)
+}
+
+func TestQueryJoinsLinesSplitAcrossHistory(t *testing.T) {
+	h, err := NewHistory(t.TempDir(), HistoryOptions{})
+	require.NoError(t, err)
+
+	now := time.Now()
+	spans := map[SpanID]*Span{"fe": {ManifestName: "fe"}}
+	h.archive([]LogSegment{{SpanID: "fe", Time: now, Text: []byte("hel"), Level: logger.InfoLvl}}, spans)
+	h.Flush()
+	h.mu.Lock()
+	h.rotate()
+	h.mu.Unlock()
+	h.archive([]LogSegment{
+		{SpanID: "fe", Time: now.Add(time.Second), Text: []byte("lo\n"), Level: logger.InfoLvl},
+		{SpanID: "fe", Time: now.Add(time.Second), Text: []byte("wor"), Level: logger.InfoLvl},
+	}, spans)
+	h.Flush()
+
+	l := NewLogStore()
+	l.SetHistory(h)
+	l.Append(newTestLogEvent("fe", now.Add(2*time.Second), "ld\n"), nil)
+
+	lines, err := l.Query(Query{History: true})
+	require.NoError(t, err)
+	assert.Equal(t, "hello\nworld\n", linesToString(lines))
+}
+
+func TestQueryScrubsSecretsFromHistory(t *testing.T) {
+	h, err := NewHistory(t.TempDir(), HistoryOptions{})
+	require.NoError(t, err)
+
+	l := NewLogStore()
+	l.SetHistory(h)
+	l.maxLogLengthInBytes = 32
+
+	now := time.Now()
+	checkpoint := l.Checkpoint()
+	l.Append(newTestLogEvent("fe", now, "password: hunter2\n"), nil)
+	for i := 0; i < 10; i++ {
+		l.Append(newTestLogEvent("fe", now.Add(time.Duration(i+1)*time.Second), fmt.Sprintf("line %d\n", i)), nil)
+	}
+	h.Flush()
+
+	secrets := model.SecretSet{}
+	secrets.AddSecret("creds", "password", []byte("hunter2"))
+	l.ScrubSecretsStartingAt(secrets, checkpoint)
+
+	lines, err := l.Query(Query{History: true})
+	require.NoError(t, err)
+	require.Len(t, lines, 11)
+	assert.Equal(t, "password: [redacted secret creds:password]\n", lines[0].Text)
+}
+
+func TestQueryFilters(t *testing.T) {
+	l := NewLogStore()
+	now := time.Now()
+	l.Append(newTestLogEvent("fe", now, "fe: starting\n"), nil)
+	l.Append(testLogEvent{name: "be", level: logger.WarnLvl, ts: now.Add(time.Second), message: "be: disk almost full\n"}, nil)
+	l.Append(testLogEvent{name: "fe", level: logger.ErrorLvl, ts: now.Add(2 * time.Second), message: "fe: request 123 failed\n"}, nil)
+	l.Append(newTestLogEvent("be", now.Add(3*time.Second), "be: request 456 ok\n"), nil)
+
+	assertQuery := func(q Query, expected string) {
+		t.Helper()
+		lines, err := l.Query(q)
+		require.NoError(t, err)
+		assert.Equal(t, expected, linesToString(lines))
+	}
+
+	assertQuery(Query{ManifestNames: model.ManifestNameSet{"be": true}},
+		"WARNING: be: disk almost full\nbe: request 456 ok\n")
+	assertQuery(Query{Level: logger.WarnLvl},
+		"WARNING: be: disk almost full\nERROR: fe: request 123 failed\n")
+	assertQuery(Query{Since: now.Add(time.Second), Until: now.Add(2 * time.Second)},
+		"WARNING: be: disk almost full\nERROR: fe: request 123 failed\n")
+	assertQuery(Query{Substring: "request"},
+		"ERROR: fe: request 123 failed\nbe: request 456 ok\n")
+	assertQuery(Query{Regexp: regexp.MustCompile(`request \d+ ok$`)},
+		"be: request 456 ok\n")
+}
+
+func TestHistoryPersistsAcrossSessions(t *testing.T) {
+	dir := t.TempDir()
+	h, err := NewHistory(dir, HistoryOptions{})
+	require.NoError(t, err)
+
+	now := time.Now()
+	h.archive([]LogSegment{{SpanID: "fe", Time: now, Text: []byte("from last time\n"), Level: logger.WarnLvl}},
+		map[SpanID]*Span{"fe": {ManifestName: "fe"}})
+	h.Flush()
+
+	h, err = NewHistory(dir, HistoryOptions{})
+	require.NoError(t, err)
+
+	l := NewLogStore()
+	l.SetHistory(h)
+	lines, err := l.Query(Query{History: true, ManifestNames: model.ManifestNameSet{"fe": true}})
+	require.NoError(t, err)
+	require.Len(t, lines, 1)
+	assert.Equal(t, "from last time\n", lines[0].Text)
+	assert.Equal(t, logger.WarnLvl, lines[0].Level)
+}
+
+func TestHistoryRetentionMaxBytes(t *testing.T) {
+	dir := t.TempDir()
+	h, err := NewHistory(dir, HistoryOptions{MaxBytes: 1000})
+	require.NoError(t, err)
+
+	spans := map[SpanID]*Span{"fe": {ManifestName: "fe"}}
+	for i := 0; i < 100; i++ {
+		h.archive([]LogSegment{{SpanID: "fe", Time: time.Now(), Text: []byte(fmt.Sprintf("line %d\n", i))}}, spans)
+		h.Flush()
+	}
+
+	assert.LessOrEqual(t, historyDirSize(t, dir), int64(1000+h.fileSizeLimit()))
+
+	segments := readHistory(t, h)
+	require.NotEmpty(t, segments)
+	assert.NotEqual(t, "line 0\n", string(segments[0].Text))
+	assert.Equal(t, "line 99\n", string(segments[len(segments)-1].Text))
+}
+
+func TestHistoryRetentionMaxAge(t *testing.T) {
+	dir := t.TempDir()
+	h, err := NewHistory(dir, HistoryOptions{})
+	require.NoError(t, err)
+
+	h.archive([]LogSegment{{SpanID: "fe", Time: time.Now(), Text: []byte("old\n")}},
+		map[SpanID]*Span{"fe": {ManifestName: "fe"}})
+	h.Flush()
+
+	lastWeek := time.Now().Add(-7 * 24 * time.Hour)
+	matches, err := filepath.Glob(filepath.Join(dir, historyFilePrefix+"*"))
+	require.NoError(t, err)
+	require.Len(t, matches, 1)
+	require.NoError(t, os.Chtimes(matches[0], lastWeek, lastWeek))
+
+	h, err = NewHistory(dir, HistoryOptions{MaxAge: 24 * time.Hour})
+	require.NoError(t, err)
+
+	assert.Empty(t, readHistory(t, h))
+	_, err = os.Stat(matches[0])
+	assert.True(t, os.IsNotExist(err))
+}
+
+func readHistory(t *testing.T, h *History) []LogSegment {
+	var result []LogSegment
+	err := h.read(Query{}, func(segments []LogSegment, spans map[SpanID]*Span) {
+		result = append(result, segments...)
+	})
+	require.NoError(t, err)
+	return result
+}
+
+func historyDirSize(t *testing.T, dir string) int64 {
+	entries, err := os.ReadDir(dir)
+	require.NoError(t, err)
+
+	total := int64(0)
+	for _, entry := range entries {
+		info, err := entry.Info()
+		require.NoError(t, err)
+		total += info.Size()
+	}
+	return total
+}
@@ -5,6 +5,7 @@ import (
 	"time"
 
 	"github.com/tilt-dev/tilt/pkg/logger"
+	"github.com/tilt-dev/tilt/pkg/model"
 )
 
 type LogLine struct {
@@ -20,6 +21,9 @@ type LogLine struct {
 	ProgressMustPrint bool
 
 	Time time.Time
+
+	Level        logger.Level
+	ManifestName model.ManifestName
 }
 
 type logLineBuilder struct {
@@ -78,9 +82,11 @@ func (b *logLineBuilder) buildSpaceLine(options logOptions) LogLine {
 	sb.WriteString("\n")
 
 	return LogLine{
-		Text:   sb.String(),
-		SpanID: spanID,
-		Time:   time,
+		Text:         sb.String(),
+		SpanID:       spanID,
+		Time:         time,
+		Level:        segment.Level,
+		ManifestName: span.ManifestName,
 	}
 }
 
@@ -123,5 +129,7 @@ func (b *logLineBuilder) buildMainLine(options logOptions) LogLine {
 		ProgressID:        progressID,
 		ProgressMustPrint: progressMustPrint,
 		Time:              time,
+		Level:             segment.Level,
+		ManifestName:      span.ManifestName,
 	}
 }
@@ -156,6 +156,10 @@ type LogStore struct {
 
 	// If the log is truncated, we need to adjust all checkpoints
 	checkpointOffset Checkpoint
+
+	// If set, segments that are truncated from memory are archived here,
+	// so that they can still be found with Query.
+	history *History
 }
 
 func NewLogStoreForTesting(msg string) *LogStore {
@@ -173,6 +177,10 @@ func NewLogStore() *LogStore {
 	}
 }
 
+func (s *LogStore) SetHistory(h *History) {
+	s.history = h
+}
+
 func (s *LogStore) Checkpoint() Checkpoint {
 	return s.checkpointFromIndex(len(s.segments))
 }
@@ -235,6 +243,12 @@ func (s *LogStore) nextIndexMatchingManifests(index int, mns model.ManifestNameS
 }
 
 func (s *LogStore) ScrubSecretsStartingAt(secrets model.SecretSet, checkpoint Checkpoint) {
+	// Segments after the checkpoint may already have been archived,
+	// so scrub the whole archive.
+	if s.history != nil {
+		s.history.scrubSecrets(secrets)
+	}
+
 	index := s.checkpointToIndex(checkpoint)
 	for i := index; i < len(s.segments); i++ {
 		s.segments[i].Text = secrets.Scrub(s.segments[i].Text)
@@ -794,13 +808,13 @@ func (s *LogStore) ensureMaxLength() {
 	// Lastly, go through all the segments, and truncate the manifests
 	// where we said we would.
 	newSegments := make([]LogSegment, 0, len(s.segments)/2)
-	trimmedSegmentCount := 0
+	trimmedSegments := []LogSegment{}
 	for i := len(s.segments) - 1; i >= 0; i-- {
 		segment := s.segments[i]
 		mn := s.spans[segment.SpanID].ManifestName
 		manifestWeightMap[mn].byteCount -= segment.Len()
 		if manifestWeightMap[mn].byteCount < 0 {
-			trimmedSegmentCount++
+			trimmedSegments = append(trimmedSegments, segment)
 			continue
 		}
 
@@ -808,6 +822,12 @@ func (s *LogStore) ensureMaxLength() {
 	}
 
 	reverseLogSegments(newSegments)
+	reverseLogSegments(trimmedSegments)
+	if s.history != nil {
+		s.history.archive(trimmedSegments, s.spans)
+	}
+
+	trimmedSegmentCount := len(trimmedSegments)
 	s.checkpointOffset += Checkpoint(trimmedSegmentCount)
 	s.segments = newSegments
 	s.recomputeDerivedValues()
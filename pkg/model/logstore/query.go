@@ -0,0 +1,256 @@
+package logstore
+
+import (
+	"fmt"
+	"regexp"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/golang/protobuf/ptypes"
+	"github.com/pkg/errors"
+
+	"github.com/tilt-dev/tilt/pkg/logger"
+	"github.com/tilt-dev/tilt/pkg/model"
+	"github.com/tilt-dev/tilt/pkg/webview"
+)
+
+// A search over the logs.
+//
+// The zero value matches all the logs in memory.
+type Query struct {
+	// Also search logs that have been truncated from memory and archived
+	// in the History. Reading the archive can be slow, so it's opt-in.
+	History bool
+
+	ManifestNames model.ManifestNameSet // only match logs for these manifests
+
+	// Only match logs at least this severe.
+	Level logger.Level
+
+	// Only match lines that started in this time range (inclusive).
+	Since time.Time
+	Until time.Time
+
+	// Only match lines that contain this text.
+	Substring string
+
+	// Only match lines that match this expression.
+	Regexp *regexp.Regexp
+
+	// Only match lines from this source (build or runtime).
+	Source Source
+}
+
+// Where a log line came from.
+//
+// Mirrors the source filter in the web UI.
+type Source string
+
+const (
+	SourceAll     Source = ""
+	SourceBuild   Source = "build"
+	SourceRuntime Source = "runtime"
+)
+
+func ParseSource(s string) (Source, error) {
+	switch Source(strings.ToLower(s)) {
+	case SourceAll, "all":
+		return SourceAll, nil
+	case SourceBuild:
+		return SourceBuild, nil
+	case SourceRuntime:
+		return SourceRuntime, nil
+	}
+	return SourceAll, fmt.Errorf("invalid log source %q (must be %q or %q)", s, SourceBuild, SourceRuntime)
+}
+
+func SourceForSpanID(spanID SpanID) Source {
+	if strings.HasPrefix(string(spanID), "build:") {
+		return SourceBuild
+	}
+	return SourceRuntime
+}
+
+// Whether the line matches the query.
+func (q Query) Matches(line LogLine) bool {
+	if len(q.ManifestNames) != 0 && !q.ManifestNames[line.ManifestName] {
+		return false
+	}
+	if !line.Level.AsSevereAs(q.Level) {
+		return false
+	}
+	return q.matchesLine(line)
+}
+
+func (q Query) matchesSegment(segment LogSegment, mn model.ManifestName) bool {
+	if len(q.ManifestNames) != 0 && !q.ManifestNames[mn] {
+		return false
+	}
+	return segment.Level.AsSevereAs(q.Level)
+}
+
+func (q Query) matchesLine(line LogLine) bool {
+	if q.Source != SourceAll && SourceForSpanID(line.SpanID) != q.Source {
+		return false
+	}
+	if !q.Since.IsZero() && line.Time.Before(q.Since) {
+		return false
+	}
+	if !q.Until.IsZero() && line.Time.After(q.Until) {
+		return false
+	}
+
+	text := strings.TrimSuffix(line.Text, "\n")
+	if q.Substring != "" && !strings.Contains(text, q.Substring) {
+		return false
+	}
+	if q.Regexp != nil && !q.Regexp.MatchString(text) {
+		return false
+	}
+	return true
+}
+
+// A copy of the in-memory logs, so that we can run a query
+// without holding any locks on the LogStore.
+type querySnapshot struct {
+	spans    map[SpanID]*Span
+	segments []LogSegment
+	history  *History
+}
+
+func (s *LogStore) querySnapshot() querySnapshot {
+	return querySnapshot{
+		spans:    s.cloneSpanMap(),
+		segments: append([]LogSegment{}, s.segments...),
+		history:  s.history,
+	}
+}
+
+// Returns all the lines matching the query, oldest first.
+//
+// Lines do not include a manifest prefix.
+func (s *LogStore) Query(q Query) ([]LogLine, error) {
+	return s.querySnapshot().run(q)
+}
+
+func (snap querySnapshot) run(q Query) ([]LogLine, error) {
+	result := []LogLine{}
+
+	// Segments at the end of a chunk that don't finish a line yet,
+	// and need to be assembled with segments from the next chunk.
+	var unfinished []LogSegment
+	spans := make(map[SpanID]*Span)
+	if q.History && snap.history != nil {
+		err := snap.history.read(q, func(segments []LogSegment, chunkSpans map[SpanID]*Span) {
+			for spanID, span := range chunkSpans {
+				spans[spanID] = span
+			}
+
+			var finished []LogSegment
+			finished, unfinished = splitUnfinishedLines(append(unfinished, segments...))
+			result = append(result, q.linesMatching(finished, spans)...)
+		})
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	segments := unfinished
+	for _, segment := range snap.segments {
+		span := snap.spans[segment.SpanID]
+		if !q.matchesSegment(segment, span.ManifestName) {
+			continue
+		}
+		spans[segment.SpanID] = span
+		segments = append(segments, segment)
+	}
+	result = append(result, q.linesMatching(segments, spans)...)
+
+	// Archived logs aren't necessarily older than the in-memory logs,
+	// because the LogStore truncates the busiest manifests first.
+	sort.SliceStable(result, func(i, j int) bool {
+		return result[i].Time.Before(result[j].Time)
+	})
+	return result, nil
+}
+
+// Assembles the segments into lines, and returns the lines that match the query.
+func (q Query) linesMatching(segments []LogSegment, spans map[SpanID]*Span) []LogLine {
+	sort.SliceStable(segments, func(i, j int) bool {
+		return segments[i].Time.Before(segments[j].Time)
+	})
+
+	tempStore := &LogStore{spans: make(map[SpanID]*Span), segments: segments}
+	for _, segment := range segments {
+		tempStore.spans[segment.SpanID] = spans[segment.SpanID]
+	}
+	tempStore.recomputeDerivedValues()
+
+	result := []LogLine{}
+	for _, line := range tempStore.toLogLines(logOptions{spans: tempStore.spans}) {
+		if q.matchesLine(line) {
+			result = append(result, line)
+		}
+	}
+	return result
+}
+
+// Splits off the segments at the end of each span that don't finish a line.
+func splitUnfinishedLines(segments []LogSegment) (finished, unfinished []LogSegment) {
+	lastComplete := make(map[SpanID]int)
+	for i, segment := range segments {
+		if segment.IsComplete() {
+			lastComplete[segment.SpanID] = i
+		}
+	}
+
+	for i, segment := range segments {
+		last, ok := lastComplete[segment.SpanID]
+		if ok && i <= last {
+			finished = append(finished, segment)
+		} else {
+			unfinished = append(unfinished, segment)
+		}
+	}
+	return finished, unfinished
+}
+
+// Converts the result of a Query to the format that the web UI understands.
+func LinesToLogList(lines []LogLine) (*webview.LogList, error) {
+	spans := make(map[string]*webview.LogSpan)
+	segments := make([]*webview.LogSegment, 0, len(lines))
+	for _, line := range lines {
+		spans[string(line.SpanID)] = &webview.LogSpan{
+			ManifestName: line.ManifestName.String(),
+		}
+
+		time, err := ptypes.TimestampProto(line.Time)
+		if err != nil {
+			return nil, errors.Wrap(err, "LinesToLogList")
+		}
+
+		var fields map[string]string
+		if line.ProgressID != "" {
+			fields = map[string]string{logger.FieldNameProgressID: line.ProgressID}
+			if line.ProgressMustPrint {
+				fields[logger.FieldNameProgressMustPrint] = "1"
+			}
+		}
+
+		segments = append(segments, &webview.LogSegment{
+			SpanId: string(line.SpanID),
+			Level:  webview.LogLevel(line.Level.ToProtoID()),
+			Time:   time,
+			Text:   line.Text,
+			Fields: fields,
+		})
+	}
+
+	return &webview.LogList{
+		Spans:          spans,
+		Segments:       segments,
+		FromCheckpoint: 0,
+		ToCheckpoint:   int32(len(segments)),
+	}, nil
+}
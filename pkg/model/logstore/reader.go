@@ -91,3 +91,16 @@ func (r Reader) Warnings(spanID SpanID) []string {
 	defer r.mu.RUnlock()
 	return r.store.Warnings(spanID)
 }
+
+// Runs the query without holding the lock while
+// reading archived logs from disk.
+func (r Reader) Query(q Query) ([]LogLine, error) {
+	if r.store == nil {
+		return nil, nil
+	}
+
+	r.mu.RLock()
+	snap := r.store.querySnapshot()
+	r.mu.RUnlock()
+	return snap.run(q)
+}
@@ -60,6 +60,14 @@ type Manifest struct {
 	// ready at least once.
 	ResourceDependencies []ManifestName
 
+	// Resources that create Kubernetes objects this resource's objects need,
+	// like a Namespace, a CustomResourceDefinition, or a ConfigMap.
+	//
+	// These are inferred from the YAML. Unlike ResourceDependencies, they only
+	// order deploys: this resource won't deploy while they're deploying, or before
+	// their first deploy, but doesn't wait for them to be ready.
+	ObjectDependencies []ManifestName
+
 	SourceTiltfile ManifestName
 
 	Labels map[string]string
@@ -155,6 +163,17 @@ func (m Manifest) IsK8s() bool {
 	return ok
 }
 
+// Orchestrator returns the system that deploys this manifest's runtime,
+// or OrchestratorUnknown if it isn't deployed by an orchestrator (e.g., local resources).
+func (m Manifest) Orchestrator() Orchestrator {
+	if m.IsK8s() {
+		return OrchestratorK8s
+	} else if m.IsDC() {
+		return OrchestratorDC
+	}
+	return OrchestratorUnknown
+}
+
 func (m Manifest) PodReadinessMode() PodReadinessMode {
 	if k8sTarget, ok := m.DeployTarget.(K8sTarget); ok {
 		return k8sTarget.PodReadinessMode
@@ -275,7 +294,6 @@ func (m *Manifest) InferLiveUpdateSelectors() error {
 			continue
 		}
 
-		// TODO(nick): Also set docker-compose selectors once the model supports it.
 		if m.IsK8s() {
 			luSpec.Selector.Kubernetes = &v1alpha1.LiveUpdateKubernetesSelector{
 				Image:         reference.FamiliarName(iTarget.Refs.ClusterRef()),
@@ -285,6 +303,17 @@ func (m *Manifest) InferLiveUpdateSelectors() error {
 			if iTarget.IsLiveUpdateOnly {
 				luSpec.Selector.Kubernetes.Image = reference.FamiliarName(iTarget.Refs.WithoutRegistry().LocalRef())
 			}
+		} else if m.IsDC() {
+			dcSpec := m.DockerComposeTarget().Spec
+			luSpec.Selector.DockerCompose = &v1alpha1.LiveUpdateDockerComposeSelector{
+				Service: dcSpec.Service,
+				Image:   container.FamiliarString(iTarget.Refs.ConfigurationRef),
+				Project: v1alpha1.DockerComposeProject{
+					ConfigPaths: append([]string(nil), dcSpec.Project.ConfigPaths...),
+					ProjectPath: dcSpec.Project.ProjectPath,
+					YAML:        dcSpec.Project.YAML,
+				},
+			}
 		}
 
 		luSpec.Sources = nil
@@ -1,6 +1,6 @@
 package model
 
-// The current orchestrator we're running with (K8s or DockerCompose)
+// The orchestrator that deploys a manifest (K8s or DockerCompose)
 type Orchestrator string
 
 const OrchestratorUnknown = Orchestrator("")
@@ -3,7 +3,9 @@ package model
 import (
 	"bytes"
 	"encoding/base64"
+	"encoding/json"
 	"fmt"
+	"regexp"
 )
 
 // Don't scrub secrets less than 5 characters,
@@ -36,6 +38,21 @@ func (s SecretSet) AddSecret(name string, key string, value []byte) {
 	}
 }
 
+// Scrubs any text that matches the pattern, for secrets
+// that we can recognize by shape (e.g., AWS access keys)
+// but don't know the value of.
+//
+// The replacement deliberately leaves out the pattern itself, which
+// may contain characters that aren't safe in JSON or logs.
+func (s SecretSet) AddPattern(name string, pattern *regexp.Regexp) {
+	s[fmt.Sprintf("pattern:%s", pattern)] = Secret{
+		Name:        name,
+		Key:         pattern.String(),
+		Pattern:     pattern,
+		Replacement: []byte(fmt.Sprintf("[redacted secret %s]", name)),
+	}
+}
+
 func (s SecretSet) Scrub(text []byte) []byte {
 	for _, secret := range s {
 		text = secret.Scrub(text)
@@ -43,6 +60,76 @@ func (s SecretSet) Scrub(text []byte) []byte {
 	return text
 }
 
+// Scrubs the string literals in an encoded JSON document.
+//
+// Each string is decoded, scrubbed, and re-encoded on its own, so a secret
+// that straddles an escape sequence is still found, and a pattern can never
+// match across quotes and swallow the surrounding structure.
+func (s SecretSet) ScrubJSON(doc []byte) ([]byte, error) {
+	if len(s) == 0 {
+		return doc, nil
+	}
+
+	result := make([]byte, 0, len(doc))
+	for i := 0; i < len(doc); {
+		if doc[i] != '"' {
+			result = append(result, doc[i])
+			i++
+			continue
+		}
+
+		end, err := jsonStringEnd(doc, i)
+		if err != nil {
+			return nil, err
+		}
+
+		literal := doc[i:end]
+		var value string
+		err = json.Unmarshal(literal, &value)
+		if err != nil {
+			return nil, err
+		}
+
+		scrubbed := s.Scrub([]byte(value))
+		if bytes.Equal(scrubbed, []byte(value)) {
+			result = append(result, literal...)
+		} else {
+			encoded, err := marshalJSONString(string(scrubbed))
+			if err != nil {
+				return nil, err
+			}
+			result = append(result, encoded...)
+		}
+		i = end
+	}
+	return result, nil
+}
+
+// Returns the index just past the closing quote of the
+// JSON string literal that starts at doc[start].
+func jsonStringEnd(doc []byte, start int) (int, error) {
+	for i := start + 1; i < len(doc); i++ {
+		switch doc[i] {
+		case '\\':
+			i++
+		case '"':
+			return i + 1, nil
+		}
+	}
+	return 0, fmt.Errorf("unterminated JSON string at offset %d", start)
+}
+
+func marshalJSONString(v string) ([]byte, error) {
+	buf := &bytes.Buffer{}
+	encoder := json.NewEncoder(buf)
+	encoder.SetEscapeHTML(false)
+	err := encoder.Encode(v)
+	if err != nil {
+		return nil, err
+	}
+	return bytes.TrimSuffix(buf.Bytes(), []byte("\n")), nil
+}
+
 type Secret struct {
 	// The name of the secret in the kubernetes cluster, so the user
 	// can look it up themselves.
@@ -54,10 +141,21 @@ type Secret struct {
 
 	ValueEncoded []byte
 
+	// If set, we scrub text that matches the pattern, rather than the Value.
+	Pattern *regexp.Regexp
+
 	Replacement []byte
 }
 
 func (s Secret) Scrub(text []byte) []byte {
+	if s.Pattern != nil {
+		return s.Pattern.ReplaceAllFunc(text, func(match []byte) []byte {
+			if len(match) < minSecretLengthToScrub {
+				return match
+			}
+			return s.Replacement
+		})
+	}
 	if len(s.Value) < minSecretLengthToScrub {
 		return text
 	}
@@ -2,6 +2,10 @@ package model
 
 type SecretSettings struct {
 	ScrubSecrets bool // whether to scrub secrets in logs
+
+	// Secrets registered with secret_settings(), that we should scrub
+	// along with the secrets we find in Kubernetes Secret objects.
+	ExtraSecrets SecretSet
 }
 
 func DefaultSecretSettings() SecretSettings {
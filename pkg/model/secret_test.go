@@ -0,0 +1,60 @@
+package model
+
+import (
+	"encoding/json"
+	"regexp"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestScrubPatternReplacementOmitsPattern(t *testing.T) {
+	secrets := SecretSet{}
+	secrets.AddPattern("secret_settings", regexp.MustCompile(`ghp_\w{10}`))
+
+	assert.Equal(t, "token=[redacted secret secret_settings]",
+		string(secrets.Scrub([]byte("token=ghp_abcdefghij"))))
+}
+
+func TestScrubJSONPatternStaysValid(t *testing.T) {
+	secrets := SecretSet{}
+	secrets.AddPattern("secret_settings", regexp.MustCompile(`ghp_\w{10}`))
+
+	doc := []byte(`{"log": "token=ghp_abcdefghij\n", "count": 3}`)
+	scrubbed, err := secrets.ScrubJSON(doc)
+	require.NoError(t, err)
+
+	var out map[string]interface{}
+	require.NoError(t, json.Unmarshal(scrubbed, &out))
+	assert.Equal(t, "token=[redacted secret secret_settings]\n", out["log"])
+	assert.Equal(t, float64(3), out["count"])
+}
+
+func TestScrubJSONGreedyPatternStaysInsideString(t *testing.T) {
+	secrets := SecretSet{}
+	secrets.AddPattern("secret_settings", regexp.MustCompile(`secret.*`))
+
+	doc := []byte(`{"a": "my secret value", "b": "public"}`)
+	scrubbed, err := secrets.ScrubJSON(doc)
+	require.NoError(t, err)
+	assert.Equal(t, `{"a": "my [redacted secret secret_settings]", "b": "public"}`, string(scrubbed))
+}
+
+func TestScrubJSONValueWithEscapes(t *testing.T) {
+	secrets := SecretSet{}
+	secrets.AddSecret("creds", "password", []byte(`pa"ss<word>`))
+
+	doc := []byte(`{"log": "password: pa\"ss<word>"}`)
+	scrubbed, err := secrets.ScrubJSON(doc)
+	require.NoError(t, err)
+	assert.Equal(t, `{"log": "password: [redacted secret creds:password]"}`, string(scrubbed))
+}
+
+func TestScrubJSONUnterminatedString(t *testing.T) {
+	secrets := SecretSet{}
+	secrets.AddSecret("creds", "password", []byte("hunter22"))
+
+	_, err := secrets.ScrubJSON([]byte(`{"log": "hunter22`))
+	assert.Error(t, err)
+}
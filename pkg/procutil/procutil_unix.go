@@ -3,6 +3,7 @@
 package procutil
 
 import (
+	"fmt"
 	"os"
 	"os/exec"
 	"syscall"
@@ -21,10 +22,25 @@ func KillProcessGroup(cmd *exec.Cmd) {
 	_ = syscall.Kill(-cmd.Process.Pid, syscall.SIGKILL)
 }
 
-func GracefullyShutdownProcess(p *os.Process) error {
+var signalsByName = map[string]syscall.Signal{
+	"SIGTERM": syscall.SIGTERM,
+	"SIGINT":  syscall.SIGINT,
+	"SIGHUP":  syscall.SIGHUP,
+	"SIGQUIT": syscall.SIGQUIT,
+	"SIGKILL": syscall.SIGKILL,
+	"SIGUSR1": syscall.SIGUSR1,
+	"SIGUSR2": syscall.SIGUSR2,
+}
+
+// Sends the named signal (e.g., "SIGINT") to the process's entire group.
+func SignalProcessGroup(p *os.Process, name string) error {
 	if p == nil {
 		return nil
 	}
 
-	return syscall.Kill(-p.Pid, syscall.SIGTERM)
+	sig, ok := signalsByName[name]
+	if !ok {
+		return fmt.Errorf("unsupported signal %q", name)
+	}
+	return syscall.Kill(-p.Pid, sig)
 }
@@ -18,6 +18,15 @@ func KillProcessGroup(cmd *exec.Cmd) {
 	}
 }
 
-func GracefullyShutdownProcess(p *os.Process) error {
+// Windows doesn't have signals, so anything other than SIGKILL
+// asks the process tree to shut down gracefully.
+func SignalProcessGroup(p *os.Process, name string) error {
+	if p == nil {
+		return nil
+	}
+
+	if name == "SIGKILL" {
+		return exec.Command("TASKKILL", "/T", "/F", "/PID", fmt.Sprintf("%d", p.Pid)).Run()
+	}
 	return exec.Command("TASKKILL", "/T", "/PID", fmt.Sprintf("%d", p.Pid)).Run()
 }
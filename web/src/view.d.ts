@@ -458,6 +458,43 @@ declare namespace Proto {
      * +optional
      */
     waiting?: v1alpha1UIResourceStateWaiting;
+    /**
+     * Represents the latest available observations of a UIResource's current state.
+     *
+     * Designed for compatibility with 'wait' cli tools.
+     * https://cli.k8s.io/#wait
+     *
+     * +optional
+     */
+    conditions?: v1alpha1UIResourceCondition[];
+  }
+  export interface v1alpha1UIResourceCondition {
+    /**
+     * Type of UI Resource condition.
+     */
+    type?: string;
+    /**
+     * Status of the condition, one of True, False, Unknown.
+     */
+    status?: string;
+    /**
+     * Last time the condition transitioned from one status to another.
+     *
+     * +optional
+     */
+    lastTransitionTime?: string;
+    /**
+     * The reason for the condition's last transition.
+     *
+     * +optional
+     */
+    reason?: string;
+    /**
+     * A human readable message indicating details about the transition.
+     *
+     * +optional
+     */
+    message?: string;
   }
   export interface v1alpha1UIResourceStateWaitingOnRef {
     /**